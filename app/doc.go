@@ -0,0 +1,25 @@
+// Package app provides a [Runner] for game loops, with a fixed-rate update of simulation systems
+// and rendering of the world by drawing systems.
+//
+// The Runner implements the game interface of [Ebitengine] without depending on it,
+// through its type parameter for the screen:
+//
+//	runner := app.New[*ebiten.Image]()
+//	runner.AddSystem(&MoveSystem{})
+//	runner.AddDrawer(&SpriteDrawer{})
+//	runner.SetScreenSize(640, 480)
+//
+//	if err := ebiten.RunGame(runner); err != nil && !errors.Is(err, app.ErrStopped) {
+//		log.Fatal(err)
+//	}
+//
+// Other engines and headless servers can use [Runner.Update] and [Runner.Draw] directly,
+// or [Runner.Run] for a fixed number of steps without drawing.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+// [Ebitengine]: https://ebitengine.org
+package app
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+)
+
+// ErrStopped is returned by [Runner.Update] after [Runner.Stop] was called.
+// Ebitengine returns it from RunGame, so check for it using [errors.Is].
+var ErrStopped = errors.New("runner stopped")
+
+// Drawer is the interface for systems that render the world, like sprite or UI systems.
+//
+// Drawers are called once per rendered frame, after all simulation updates of the frame.
+// They should not modify the world.
+// Drawers are identified by equality, so implementations should be pointer types.
+type Drawer[S any] interface {
+	// Initialize the drawer. Called once, before the first update.
+	Initialize(w *ecs.World)
+	// Draw the world to the screen. Called once per frame.
+	Draw(w *ecs.World, screen S)
+	// Finalize the drawer. Called once, after the last update.
+	Finalize(w *ecs.World)
+}
+
+// Runner owns a [systems.Scheduler] with its world, and runs simulation systems and [Drawer] systems.
+//
+// The type parameter is the screen type passed to drawers, e.g. *ebiten.Image for Ebitengine.
+// With that, Runner implements Ebitengine's Game interface. See the package documentation for an example.
+//
+// Simulation systems run in [Runner.Update], which the host loop calls at a fixed rate.
+// The number of scheduler steps per update is controlled by the [systems.Speed] resource,
+// see [systems.Scheduler.Frame].
+// Drawers run in [Runner.Draw], which the host loop calls once per rendered frame.
+type Runner[S any] struct {
+	Scheduler   *systems.Scheduler // The scheduler for simulation systems. Owns the world.
+	drawers     []Drawer[S]
+	width       int
+	height      int
+	initialized bool
+	stopped     bool
+	finalized   bool
+}
+
+// New creates a new [Runner] with a new [systems.Scheduler], created from an optional [ecs.Config].
+func New[S any](config ...ecs.Config) *Runner[S] {
+	return &Runner[S]{
+		Scheduler: systems.New(config...),
+	}
+}
+
+// World returns the runner's world.
+func (r *Runner[S]) World() *ecs.World {
+	return &r.Scheduler.World
+}
+
+// AddSystem adds a simulation system to the end of the schedule. See [systems.Scheduler.AddSystem].
+func (r *Runner[S]) AddSystem(sys systems.System) {
+	r.Scheduler.AddSystem(sys)
+}
+
+// AddDrawer adds a [Drawer] after all previously added drawers.
+// Drawers added after initialization are initialized immediately.
+//
+// Panics if the drawer is already added, or if the runner is stopped.
+func (r *Runner[S]) AddDrawer(d Drawer[S]) {
+	r.checkStopped()
+	for _, other := range r.drawers {
+		if other == d {
+			panic("drawer is already added to the runner")
+		}
+	}
+	r.drawers = append(r.drawers, d)
+	if r.initialized {
+		d.Initialize(r.World())
+	}
+}
+
+// Drawers returns the drawers, in drawing order.
+//
+// The returned slice must not be modified.
+func (r *Runner[S]) Drawers() []Drawer[S] {
+	return r.drawers
+}
+
+// SetScreenSize sets a fixed logical screen size, returned by [Runner.Layout].
+// With a size of zero, the outside size is used.
+func (r *Runner[S]) SetScreenSize(width, height int) {
+	r.width, r.height = width, height
+}
+
+// Initialize initializes all simulation systems and drawers.
+// Called automatically by the first call to [Runner.Update] or [Runner.Run].
+//
+// Panics if the runner is already initialized, or if it is stopped.
+func (r *Runner[S]) Initialize() {
+	if r.initialized {
+		panic("runner is already initialized")
+	}
+	r.checkStopped()
+	r.initialized = true
+	r.Scheduler.Initialize()
+	for _, d := range r.drawers {
+		d.Initialize(r.World())
+	}
+}
+
+// Update runs the simulation systems for one fixed-rate tick of the host loop,
+// with the number of steps determined by [systems.Scheduler.Frame].
+// Initializes the runner on the first call.
+//
+// After [Runner.Stop], finalizes all systems and drawers, and returns [ErrStopped].
+// The runner can't be restarted.
+func (r *Runner[S]) Update() error {
+	if r.stopped {
+		r.finalize()
+		return ErrStopped
+	}
+	if !r.initialized {
+		r.Initialize()
+	}
+	r.Scheduler.Frame()
+	return nil
+}
+
+// Draw runs all drawers, in the order they were added.
+// Does nothing before the runner is initialized, and after it is finalized.
+func (r *Runner[S]) Draw(screen S) {
+	if !r.initialized || r.finalized {
+		return
+	}
+	for _, d := range r.drawers {
+		d.Draw(r.World(), screen)
+	}
+}
+
+// Layout returns the logical screen size set with [Runner.SetScreenSize],
+// or the outside size if no size was set.
+func (r *Runner[S]) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if r.width <= 0 || r.height <= 0 {
+		return outsideWidth, outsideHeight
+	}
+	return r.width, r.height
+}
+
+// Stop requests the runner to stop.
+// The next call to [Runner.Update] finalizes all systems and drawers and returns [ErrStopped].
+// Systems can call it, e.g. via a resource holding the runner.
+func (r *Runner[S]) Stop() {
+	r.stopped = true
+}
+
+// Run initializes the runner if required, runs the given number of updates without drawing,
+// and finalizes all systems and drawers. Intended for headless servers and tests.
+//
+// Panics if the runner is stopped.
+func (r *Runner[S]) Run(updates int) {
+	r.checkStopped()
+	if !r.initialized {
+		r.Initialize()
+	}
+	for i := 0; i < updates && !r.stopped; i++ {
+		r.Scheduler.Frame()
+	}
+	r.stopped = true
+	r.finalize()
+}
+
+// finalize finalizes all systems and drawers, if the runner is initialized and not yet finalized.
+func (r *Runner[S]) finalize() {
+	if !r.initialized || r.finalized {
+		return
+	}
+	r.finalized = true
+	r.Scheduler.Finalize()
+	for _, d := range r.drawers {
+		d.Finalize(r.World())
+	}
+}
+
+// checkStopped panics if the runner is stopped.
+func (r *Runner[S]) checkStopped() {
+	if r.stopped {
+		panic("runner is stopped")
+	}
+}
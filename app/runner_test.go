@@ -0,0 +1,105 @@
+package app_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/app"
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// image mimics the screen type of a game engine.
+type image struct {
+	Frames int
+}
+
+// game mimics the game interface of Ebitengine.
+type game interface {
+	Update() error
+	Draw(screen *image)
+	Layout(outsideWidth, outsideHeight int) (int, int)
+}
+
+var _ game = app.New[*image]()
+
+type recordSystem struct {
+	Name string
+	Log  *[]string
+}
+
+func (s *recordSystem) Initialize(w *ecs.World) { *s.Log = append(*s.Log, "init "+s.Name) }
+func (s *recordSystem) Update(w *ecs.World)     { *s.Log = append(*s.Log, "update "+s.Name) }
+func (s *recordSystem) Finalize(w *ecs.World)   { *s.Log = append(*s.Log, "final "+s.Name) }
+
+type recordDrawer struct {
+	Name string
+	Log  *[]string
+}
+
+func (d *recordDrawer) Initialize(w *ecs.World) { *d.Log = append(*d.Log, "init "+d.Name) }
+func (d *recordDrawer) Draw(w *ecs.World, screen *image) {
+	screen.Frames++
+	*d.Log = append(*d.Log, fmt.Sprintf("draw %s %d", d.Name, screen.Frames))
+}
+func (d *recordDrawer) Finalize(w *ecs.World) { *d.Log = append(*d.Log, "final "+d.Name) }
+
+func TestRunner(t *testing.T) {
+	log := []string{}
+	r := app.New[*image]()
+	r.AddSystem(&recordSystem{Name: "S", Log: &log})
+	d := &recordDrawer{Name: "D", Log: &log}
+	r.AddDrawer(d)
+	assert.PanicsWithValue(t, "drawer is already added to the runner", func() { r.AddDrawer(d) })
+
+	screen := &image{}
+	r.Draw(screen)
+	assert.Equal(t, []string{}, log)
+
+	assert.Nil(t, r.Update())
+	r.Draw(screen)
+	assert.Nil(t, r.Update())
+	assert.Equal(t, []string{"init S", "init D", "update S", "draw D 1", "update S"}, log)
+	assert.Equal(t, uint64(2), r.World().CurrentTick())
+
+	log = log[:0]
+	d2 := &recordDrawer{Name: "E", Log: &log}
+	r.AddDrawer(d2)
+	assert.Equal(t, []app.Drawer[*image]{d, d2}, r.Drawers())
+	r.Draw(screen)
+	assert.Equal(t, []string{"init E", "draw D 2", "draw E 3"}, log)
+
+	log = log[:0]
+	r.Stop()
+	assert.ErrorIs(t, r.Update(), app.ErrStopped)
+	assert.ErrorIs(t, r.Update(), app.ErrStopped)
+	r.Draw(screen)
+	assert.Equal(t, []string{"final S", "final D", "final E"}, log)
+
+	assert.PanicsWithValue(t, "runner is stopped", func() { r.AddDrawer(&recordDrawer{}) })
+	assert.PanicsWithValue(t, "runner is stopped", func() { r.Run(1) })
+}
+
+func TestRunnerRun(t *testing.T) {
+	log := []string{}
+	r := app.New[*image]()
+	r.AddSystem(&recordSystem{Name: "S", Log: &log})
+	r.AddDrawer(&recordDrawer{Name: "D", Log: &log})
+
+	r.Run(2)
+	assert.Equal(t, []string{"init S", "init D", "update S", "update S", "final S", "final D"}, log)
+	assert.ErrorIs(t, r.Update(), app.ErrStopped)
+	assert.PanicsWithValue(t, "runner is already initialized", func() { r.Initialize() })
+}
+
+func TestRunnerLayout(t *testing.T) {
+	r := app.New[*image]()
+	w, h := r.Layout(800, 600)
+	assert.Equal(t, 800, w)
+	assert.Equal(t, 600, h)
+
+	r.SetScreenSize(320, 240)
+	w, h = r.Layout(800, 600)
+	assert.Equal(t, 320, w)
+	assert.Equal(t, 240, h)
+}
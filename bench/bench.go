@@ -0,0 +1,227 @@
+package bench
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Setup creates a world for a benchmark, and returns it together with the IDs of the components to benchmark with.
+//
+// Setup is called for each benchmark run, so that all benchmarks start from the same state.
+// It can configure the world, register components and create background entities.
+// Benchmarked entities are created with all returned components.
+type Setup func() (*ecs.World, []ecs.ID)
+
+// Benchmark is the name of a standardized benchmark.
+type Benchmark string
+
+// Standardized benchmarks.
+// Operations, as used for [Result.NsPerOp], are counted per benchmarked entity.
+const (
+	Iterate      Benchmark = "Iterate"      // Query iteration, reading the first component of each entity.
+	RandomAccess Benchmark = "RandomAccess" // Access to the first component of entities in random order, via [ecs.World.Get].
+	AddRemove    Benchmark = "AddRemove"    // Removing and re-adding the last component of each entity.
+	BatchCreate  Benchmark = "BatchCreate"  // Entity creation in batches, via [ecs.Builder.NewBatchQ]. Removal is not measured.
+)
+
+// Benchmarks returns all standardized benchmarks, in the order they are run by default.
+func Benchmarks() []Benchmark {
+	return []Benchmark{Iterate, RandomAccess, AddRemove, BatchCreate}
+}
+
+// Config provides configuration for [Run].
+type Config struct {
+	// Number of benchmarked entities. The default value is 10,000.
+	Entities int
+	// Minimum measured time per benchmark. The number of rounds is increased until it is reached.
+	// The default value is 100ms.
+	Duration time.Duration
+	// Benchmarks to run. The default value nil runs all [Benchmarks].
+	Benchmarks []Benchmark
+}
+
+// NewConfig creates a new default [Config].
+func NewConfig() Config {
+	return Config{
+		Entities: 10_000,
+		Duration: 100 * time.Millisecond,
+	}
+}
+
+// WithEntities return a new Config with Entities set.
+// Use with method chaining.
+func (c Config) WithEntities(entities int) Config {
+	c.Entities = entities
+	return c
+}
+
+// WithDuration return a new Config with Duration set.
+// Use with method chaining.
+func (c Config) WithDuration(d time.Duration) Config {
+	c.Duration = d
+	return c
+}
+
+// WithBenchmarks return a new Config with Benchmarks set.
+// Use with method chaining.
+func (c Config) WithBenchmarks(benchmarks ...Benchmark) Config {
+	c.Benchmarks = benchmarks
+	return c
+}
+
+// Result of a single benchmark.
+type Result struct {
+	Benchmark Benchmark     // The benchmark.
+	Entities  int           // Number of benchmarked entities.
+	Rounds    int           // Number of measured rounds over all entities.
+	Ops       int           // Total number of measured operations.
+	Time      time.Duration // Total measured time.
+	Allocs    uint64        // Total number of heap allocations during measurement.
+	Bytes     uint64        // Total number of bytes allocated during measurement.
+}
+
+// NsPerOp returns the average time per operation, in nanoseconds.
+func (r *Result) NsPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Time.Nanoseconds()) / float64(r.Ops)
+}
+
+// AllocsPerOp returns the average number of heap allocations per operation.
+func (r *Result) AllocsPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Allocs) / float64(r.Ops)
+}
+
+// BytesPerOp returns the average number of allocated bytes per operation.
+func (r *Result) BytesPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Bytes) / float64(r.Ops)
+}
+
+// String returns a formatted line for the result, similar to the output of go test -bench.
+func (r *Result) String() string {
+	return fmt.Sprintf("%-14s %8d entities %10d ops %12.2f ns/op %10.2f B/op %8.2f allocs/op",
+		r.Benchmark, r.Entities, r.Ops, r.NsPerOp(), r.BytesPerOp(), r.AllocsPerOp())
+}
+
+// Results of a run of multiple benchmarks, in the order they were run.
+type Results []Result
+
+// Get returns the result of a benchmark, and whether it was run.
+func (r Results) Get(b Benchmark) (Result, bool) {
+	for _, res := range r {
+		if res.Benchmark == b {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// String returns a formatted table of the results.
+func (r Results) String() string {
+	b := strings.Builder{}
+	for i := range r {
+		b.WriteString(r[i].String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Run runs the standardized benchmarks on worlds created by the given [Setup], with an optional [Config].
+//
+// Each benchmark uses a fresh world for each attempt to determine the number of rounds,
+// and reports the measurements of the final attempt only.
+//
+// Panics if more than one config is given, if the config is invalid,
+// or if setup returns no world or no components.
+func Run(setup Setup, config ...Config) Results {
+	if len(config) > 1 {
+		panic("can't use more than one Config")
+	}
+	conf := NewConfig()
+	if len(config) == 1 {
+		conf = config[0]
+	}
+	if conf.Entities < 1 {
+		panic("invalid number of entities in benchmark config, must be > 0")
+	}
+	if conf.Duration <= 0 {
+		panic("invalid duration in benchmark config, must be > 0")
+	}
+	benchmarks := conf.Benchmarks
+	if len(benchmarks) == 0 {
+		benchmarks = Benchmarks()
+	}
+
+	results := make(Results, 0, len(benchmarks))
+	for _, b := range benchmarks {
+		fn, ok := benchFuncs[b]
+		if !ok {
+			panic(fmt.Sprintf("unknown benchmark '%s'", b))
+		}
+		results = append(results, measure(b, fn, setup, &conf))
+	}
+	return results
+}
+
+// measure runs a benchmark with an increasing number of rounds, until the configured duration is reached.
+func measure(b Benchmark, fn benchFunc, setup Setup, conf *Config) Result {
+	rounds := 1
+	for {
+		world, ids := setup()
+		if world == nil {
+			panic("benchmark setup returned no world")
+		}
+		if len(ids) == 0 {
+			panic("benchmark setup returned no components")
+		}
+
+		t := timer{}
+		ops := fn(&t, world, ids, conf.Entities, rounds)
+
+		if t.elapsed >= conf.Duration || rounds >= maxRounds {
+			return Result{
+				Benchmark: b,
+				Entities:  conf.Entities,
+				Rounds:    rounds,
+				Ops:       ops,
+				Time:      t.elapsed,
+				Allocs:    t.allocs,
+				Bytes:     t.bytes,
+			}
+		}
+		rounds = nextRounds(rounds, t.elapsed, conf.Duration)
+	}
+}
+
+// maxRounds is the maximum number of rounds of a benchmark.
+const maxRounds = 1_000_000_000
+
+// nextRounds predicts the number of rounds required to reach the target duration,
+// similar to the testing package.
+func nextRounds(rounds int, elapsed, target time.Duration) int {
+	next := 100 * rounds
+	if elapsed > 0 {
+		// Overshoot by 20%, to avoid repeated attempts just below the target.
+		next = int(float64(rounds) * float64(target) / float64(elapsed) * 1.2)
+	}
+	if next > 100*rounds {
+		next = 100 * rounds
+	}
+	if next <= rounds {
+		next = rounds + 1
+	}
+	if next > maxRounds {
+		next = maxRounds
+	}
+	return next
+}
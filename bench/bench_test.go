@@ -0,0 +1,118 @@
+package bench_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/arche/bench"
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	X, Y float64
+}
+
+type velocity struct {
+	X, Y float64
+}
+
+type marker struct{}
+
+func setup(capacity int) bench.Setup {
+	return func() (*ecs.World, []ecs.ID) {
+		w := ecs.NewWorld(ecs.NewConfig().WithCapacityIncrement(capacity))
+		posID := ecs.ComponentID[position](&w)
+		velID := ecs.ComponentID[velocity](&w)
+		return &w, []ecs.ID{posID, velID}
+	}
+}
+
+func TestRun(t *testing.T) {
+	conf := bench.NewConfig().WithEntities(100).WithDuration(time.Millisecond)
+	results := bench.Run(setup(32), conf)
+
+	assert.Equal(t, 4, len(results))
+	for i, b := range bench.Benchmarks() {
+		res := results[i]
+		assert.Equal(t, b, res.Benchmark)
+		assert.Equal(t, 100, res.Entities)
+		assert.Equal(t, res.Rounds*100, res.Ops)
+		assert.GreaterOrEqual(t, res.Time, time.Millisecond)
+		assert.Greater(t, res.NsPerOp(), 0.0)
+	}
+
+	res, ok := results.Get(bench.Iterate)
+	assert.True(t, ok)
+	assert.Less(t, res.AllocsPerOp(), 0.001)
+
+	text := results.String()
+	assert.Equal(t, 4, strings.Count(text, "\n"))
+	assert.True(t, strings.HasPrefix(text, "Iterate "))
+	assert.Contains(t, text, "ns/op")
+}
+
+func TestRunConfig(t *testing.T) {
+	conf := bench.NewConfig().
+		WithEntities(10).
+		WithDuration(time.Millisecond).
+		WithBenchmarks(bench.AddRemove)
+
+	tags := func() (*ecs.World, []ecs.ID) {
+		w := ecs.NewWorld()
+		return &w, []ecs.ID{ecs.ComponentID[marker](&w)}
+	}
+	results := bench.Run(tags, conf)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, bench.AddRemove, results[0].Benchmark)
+
+	_, ok := results.Get(bench.Iterate)
+	assert.False(t, ok)
+
+	results = bench.Run(tags, conf.WithBenchmarks(bench.Iterate, bench.RandomAccess))
+	assert.Equal(t, 2, len(results))
+
+	assert.PanicsWithValue(t, "can't use more than one Config", func() { bench.Run(tags, conf, conf) })
+	assert.PanicsWithValue(t, "invalid number of entities in benchmark config, must be > 0",
+		func() { bench.Run(tags, conf.WithEntities(0)) })
+	assert.PanicsWithValue(t, "invalid duration in benchmark config, must be > 0",
+		func() { bench.Run(tags, conf.WithDuration(0)) })
+	assert.PanicsWithValue(t, "unknown benchmark 'Foo'",
+		func() { bench.Run(tags, conf.WithBenchmarks("Foo")) })
+	assert.PanicsWithValue(t, "benchmark setup returned no components",
+		func() {
+			bench.Run(func() (*ecs.World, []ecs.ID) {
+				w := ecs.NewWorld()
+				return &w, nil
+			}, conf)
+		})
+	assert.PanicsWithValue(t, "benchmark setup returned no world",
+		func() { bench.Run(func() (*ecs.World, []ecs.ID) { return nil, nil }, conf) })
+}
+
+func TestResult(t *testing.T) {
+	res := bench.Result{Benchmark: bench.Iterate, Entities: 10, Ops: 100, Time: 250 * time.Nanosecond, Allocs: 10, Bytes: 800}
+	assert.Equal(t, 2.5, res.NsPerOp())
+	assert.Equal(t, 0.1, res.AllocsPerOp())
+	assert.Equal(t, 8.0, res.BytesPerOp())
+
+	empty := bench.Result{}
+	assert.Equal(t, 0.0, empty.NsPerOp())
+	assert.Equal(t, 0.0, empty.AllocsPerOp())
+	assert.Equal(t, 0.0, empty.BytesPerOp())
+}
+
+func ExampleRun() {
+	setup := func() (*ecs.World, []ecs.ID) {
+		w := ecs.NewWorld(ecs.NewConfig().WithCapacityIncrement(1024))
+		posID := ecs.ComponentID[position](&w)
+		velID := ecs.ComponentID[velocity](&w)
+		return &w, []ecs.ID{posID, velID}
+	}
+
+	results := bench.Run(setup, bench.NewConfig().WithEntities(1000))
+	res, _ := results.Get(bench.Iterate)
+	_ = res.NsPerOp()
+	// Output:
+}
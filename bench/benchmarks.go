@@ -0,0 +1,141 @@
+package bench
+
+import (
+	"math/rand"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// benchFunc runs a benchmark for the given number of rounds, and returns the number of measured operations.
+// Only sections between [timer.Start] and [timer.Stop] are measured.
+type benchFunc func(t *timer, w *ecs.World, ids []ecs.ID, entities int, rounds int) int
+
+// benchFuncs maps benchmarks to their implementation.
+var benchFuncs = map[Benchmark]benchFunc{
+	Iterate:      benchIterate,
+	RandomAccess: benchRandomAccess,
+	AddRemove:    benchAddRemove,
+	BatchCreate:  benchBatchCreate,
+}
+
+// sink prevents the compiler from optimizing away component reads.
+var sink byte
+
+// timer measures time and heap allocations, similar to [testing.B].
+type timer struct {
+	start   time.Time
+	elapsed time.Duration
+	allocs  uint64
+	bytes   uint64
+	mem     runtime.MemStats
+}
+
+// Start starts the measurement.
+func (t *timer) Start() {
+	runtime.ReadMemStats(&t.mem)
+	t.allocs -= t.mem.Mallocs
+	t.bytes -= t.mem.TotalAlloc
+	t.start = time.Now()
+}
+
+// Stop stops the measurement, and adds to the totals.
+func (t *timer) Stop() {
+	t.elapsed += time.Since(t.start)
+	runtime.ReadMemStats(&t.mem)
+	t.allocs += t.mem.Mallocs
+	t.bytes += t.mem.TotalAlloc
+}
+
+// read reads the first byte of a component, if it has any data.
+func read(ptr unsafe.Pointer, hasData bool) byte {
+	if !hasData {
+		return 0
+	}
+	return *(*byte)(ptr)
+}
+
+// hasData reports whether a component has a non-zero size.
+func hasData(w *ecs.World, id ecs.ID) bool {
+	info, _ := ecs.ComponentInfo(w, id)
+	return info.Type.Size() > 0
+}
+
+func benchIterate(t *timer, w *ecs.World, ids []ecs.ID, entities int, rounds int) int {
+	ecs.NewBuilder(w, ids...).NewBatch(entities)
+	filter := ecs.All(ids...)
+	first, data := ids[0], hasData(w, ids[0])
+
+	var s byte
+	ops := 0
+	t.Start()
+	for r := 0; r < rounds; r++ {
+		query := w.Query(&filter)
+		ops += query.Count()
+		for query.Next() {
+			s += read(query.Get(first), data)
+		}
+	}
+	t.Stop()
+	sink = s
+	return ops
+}
+
+func benchRandomAccess(t *timer, w *ecs.World, ids []ecs.ID, entities int, rounds int) int {
+	list := ecs.NewBuilder(w, ids...).AppendBatch(nil, entities)
+	rng := rand.New(rand.NewSource(42))
+	rng.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+	first, data := ids[0], hasData(w, ids[0])
+
+	var s byte
+	t.Start()
+	for r := 0; r < rounds; r++ {
+		for _, e := range list {
+			s += read(w.Get(e, first), data)
+		}
+	}
+	t.Stop()
+	sink = s
+	return rounds * len(list)
+}
+
+func benchAddRemove(t *timer, w *ecs.World, ids []ecs.ID, entities int, rounds int) int {
+	list := ecs.NewBuilder(w, ids...).AppendBatch(nil, entities)
+	last := ids[len(ids)-1]
+
+	t.Start()
+	for r := 0; r < rounds; r++ {
+		for _, e := range list {
+			w.Remove(e, last)
+		}
+		for _, e := range list {
+			w.Add(e, last)
+		}
+	}
+	t.Stop()
+	return rounds * len(list)
+}
+
+func benchBatchCreate(t *timer, w *ecs.World, ids []ecs.ID, entities int, rounds int) int {
+	builder := ecs.NewBuilder(w, ids...)
+	list := make([]ecs.Entity, 0, entities)
+
+	ops := 0
+	for r := 0; r < rounds; r++ {
+		t.Start()
+		query := builder.NewBatchQ(entities)
+		t.Stop()
+
+		ops += query.Count()
+		for query.Next() {
+			list = append(list, query.Entity())
+		}
+		for _, e := range list {
+			w.RemoveEntity(e)
+		}
+		list = list[:0]
+	}
+	return ops
+}
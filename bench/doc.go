@@ -0,0 +1,13 @@
+// Package bench provides a micro-benchmark harness for [github.com/mlange-42/arche/ecs.World] configurations.
+//
+// Given a [Setup] function that creates a world and selects the components to benchmark with,
+// [Run] measures standardized operations (see [Benchmark]) and returns structured [Results].
+// This gives comparable numbers for tuning capacity increments, allocators and other world options
+// on the application's own component sets, independent of the application's systems.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package bench
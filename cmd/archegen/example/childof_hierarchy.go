@@ -0,0 +1,80 @@
+// Code generated by archegen. DO NOT EDIT.
+
+package example
+
+import "github.com/mlange-42/arche/ecs"
+
+// ChildOfHierarchy provides typed hierarchy helpers for the relation component ChildOf.
+//
+// Entities with a ChildOf relation are children of the relation's target entity.
+type ChildOfHierarchy struct {
+	world *ecs.World
+	id    ecs.ID
+}
+
+// NewChildOfHierarchy creates a new ChildOfHierarchy for a world.
+// Registers the relation component if it is not already registered.
+func NewChildOfHierarchy(w *ecs.World) ChildOfHierarchy {
+	return ChildOfHierarchy{
+		world: w,
+		id:    ecs.ComponentID[ChildOf](w),
+	}
+}
+
+// ID returns the component ID of the relation ChildOf.
+func (h *ChildOfHierarchy) ID() ecs.ID {
+	return h.id
+}
+
+// Parent returns the parent of an entity.
+// Returns the zero entity if the entity has no parent.
+func (h *ChildOfHierarchy) Parent(entity ecs.Entity) ecs.Entity {
+	if !h.world.Has(entity, h.id) {
+		return ecs.Entity{}
+	}
+	return h.world.Relations().Get(entity, h.id)
+}
+
+// Children returns the direct children of an entity.
+func (h *ChildOfHierarchy) Children(entity ecs.Entity) []ecs.Entity {
+	return h.appendChildren(nil, entity)
+}
+
+// Descendants returns all descendants of an entity, in breadth-first order.
+func (h *ChildOfHierarchy) Descendants(entity ecs.Entity) []ecs.Entity {
+	result := h.appendChildren(nil, entity)
+	for i := 0; i < len(result); i++ {
+		result = h.appendChildren(result, result[i])
+	}
+	return result
+}
+
+// SetParent sets the parent of an entity.
+// Adds the relation component if the entity does not have it yet.
+// Removes the relation component if the parent is the zero entity.
+//
+// Panics when called on a locked world.
+func (h *ChildOfHierarchy) SetParent(entity ecs.Entity, parent ecs.Entity) {
+	if h.world.Has(entity, h.id) {
+		if parent.IsZero() {
+			h.world.Remove(entity, h.id)
+			return
+		}
+		h.world.Relations().Set(entity, h.id, parent)
+		return
+	}
+	if parent.IsZero() {
+		return
+	}
+	h.world.Relations().Exchange(entity, []ecs.ID{h.id}, nil, h.id, parent)
+}
+
+// appendChildren appends the direct children of an entity to a slice.
+func (h *ChildOfHierarchy) appendChildren(children []ecs.Entity, entity ecs.Entity) []ecs.Entity {
+	filter := ecs.NewRelationFilter(ecs.All(h.id), entity)
+	query := h.world.Query(&filter)
+	for query.Next() {
+		children = append(children, query.Entity())
+	}
+	return children
+}
@@ -0,0 +1,11 @@
+// Package example demonstrates hierarchy helpers generated by archegen.
+package example
+
+import "github.com/mlange-42/arche/ecs"
+
+//go:generate go run .. -hierarchy ChildOf
+
+// ChildOf is a relation component from child to parent.
+type ChildOf struct {
+	ecs.Relation
+}
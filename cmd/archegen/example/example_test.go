@@ -0,0 +1,42 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChildOfHierarchy(t *testing.T) {
+	w := ecs.NewWorld()
+	h := NewChildOfHierarchy(&w)
+	assert.Equal(t, ecs.ComponentID[ChildOf](&w), h.ID())
+
+	root := w.NewEntity()
+	a := w.NewEntity()
+	b := w.NewEntity()
+	c := w.NewEntity()
+
+	h.SetParent(a, root)
+	h.SetParent(b, root)
+	h.SetParent(c, a)
+
+	assert.Equal(t, root, h.Parent(a))
+	assert.Equal(t, a, h.Parent(c))
+	assert.True(t, h.Parent(root).IsZero())
+
+	assert.ElementsMatch(t, []ecs.Entity{a, b}, h.Children(root))
+	assert.Equal(t, []ecs.Entity{c}, h.Children(a))
+	assert.Empty(t, h.Children(c))
+	assert.ElementsMatch(t, []ecs.Entity{a, b, c}, h.Descendants(root))
+	assert.Equal(t, c, h.Descendants(root)[2])
+
+	h.SetParent(c, b)
+	assert.Equal(t, b, h.Parent(c))
+	assert.Empty(t, h.Children(a))
+
+	h.SetParent(c, ecs.Entity{})
+	assert.False(t, w.Has(c, h.ID()))
+	h.SetParent(c, ecs.Entity{})
+	assert.ElementsMatch(t, []ecs.Entity{a, b}, h.Descendants(root))
+}
@@ -0,0 +1,78 @@
+// Command archegen generates typed helper code for Arche.
+//
+// Typed hierarchy helpers for a relation component are generated with flag -hierarchy.
+// For a relation component ChildOf, this generates a type ChildOfHierarchy with methods
+// Parent, Children, Descendants and SetParent, backed by [github.com/mlange-42/arche/ecs.Relations].
+//
+// Usage with go generate, in the package that defines the relation component:
+//
+//	//go:generate go run github.com/mlange-42/arche/cmd/archegen -hierarchy ChildOf
+//
+// By default, the output file is named after the type, like childof_hierarchy.go.
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strings"
+	"text/template"
+)
+
+//go:embed hierarchy.go.txt
+var hierarchyTemplate string
+
+type hierarchy struct {
+	Package string
+	Type    string
+}
+
+func main() {
+	relation := flag.String("hierarchy", "", "name of the relation component to generate hierarchy helpers for")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated code; defaults to $GOPACKAGE")
+	output := flag.String("output", "", "output file; defaults to <type>_hierarchy.go")
+	flag.Parse()
+
+	if *relation == "" {
+		fmt.Fprintln(os.Stderr, "archegen: flag -hierarchy is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *pkg == "" {
+		fmt.Fprintln(os.Stderr, "archegen: flag -package is required outside of go generate")
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = strings.ToLower(*relation) + "_hierarchy.go"
+	}
+
+	code, err := generateHierarchy(*pkg, *relation)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "archegen: %s\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "archegen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// generateHierarchy generates formatted hierarchy helper code for a relation type.
+func generateHierarchy(pkg, relation string) ([]byte, error) {
+	if !token.IsIdentifier(relation) {
+		return nil, fmt.Errorf("invalid type name '%s'", relation)
+	}
+	tmpl, err := template.New("hierarchy").Parse(hierarchyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	text := bytes.Buffer{}
+	if err := tmpl.Execute(&text, hierarchy{Package: pkg, Type: relation}); err != nil {
+		return nil, err
+	}
+	return format.Source(text.Bytes())
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHierarchy(t *testing.T) {
+	code, err := generateHierarchy("example", "ChildOf")
+	assert.Nil(t, err)
+
+	expected, err := os.ReadFile("example/childof_hierarchy.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(code))
+
+	_, err = generateHierarchy("example", "Child Of")
+	assert.EqualError(t, err, "invalid type name 'Child Of'")
+}
@@ -6,6 +6,13 @@
 //   - Generic queries -- [github.com/mlange-42/arche/generic]
 //   - Advanced filters -- [github.com/mlange-42/arche/filter]
 //   - Event listeners -- [github.com/mlange-42/arche/listener]
+//   - World serialization -- [github.com/mlange-42/arche/serde]
+//   - Systems and scheduling -- [github.com/mlange-42/arche/systems]
+//   - Game loop runner -- [github.com/mlange-42/arche/app]
+//   - HTTP debug inspector -- [github.com/mlange-42/arche/inspect]
+//   - Snapshot interpolation -- [github.com/mlange-42/arche/interp]
+//   - SQL-like debug queries -- [github.com/mlange-42/arche/sqlq]
+//   - Micro-benchmark harness -- [github.com/mlange-42/arche/bench]
 //   - Usage examples -- [github.com/mlange-42/arche/_examples]
 //
 // 🕮 Also read Arche's [User Guide]!
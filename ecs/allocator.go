@@ -0,0 +1,22 @@
+package ecs
+
+import "unsafe"
+
+// Allocator provides memory for archetype component columns, e.g. from an arena or an off-heap pool.
+//
+// Set it with [Config.Allocator]. Only columns of component types that contain no Go pointers
+// (i.e. no pointers, slices, maps, strings, interfaces, channels or functions) use the allocator,
+// as the garbage collector does not scan memory from custom allocators.
+// All other columns are allocated by Go as usual.
+//
+// Columns are allocated in chunks of a fixed number of entities.
+// Archetypes allocate chunks when they grow, and pass chunks to Free when they shrink.
+// Memory still used by a world is never freed by the world itself.
+// It is up to the allocator to release it when the world is discarded, e.g. by releasing the arena as a whole.
+type Allocator interface {
+	// Alloc returns a pointer to a block of memory with the given size and alignment.
+	// The memory does not need to be zeroed.
+	Alloc(size, align uintptr) unsafe.Pointer
+	// Free releases a block of memory obtained from Alloc, with the same size and alignment.
+	Free(ptr unsafe.Pointer, size, align uintptr)
+}
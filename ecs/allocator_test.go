@@ -0,0 +1,80 @@
+package ecs
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAllocator is an allocator backed by Go memory, that keeps track of allocated blocks.
+type testAllocator struct {
+	blocks map[unsafe.Pointer][]byte
+	allocs int
+	frees  int
+}
+
+func newTestAllocator() *testAllocator {
+	return &testAllocator{blocks: map[unsafe.Pointer][]byte{}}
+}
+
+func (a *testAllocator) Alloc(size, align uintptr) unsafe.Pointer {
+	block := make([]byte, size+align)
+	for i := range block {
+		block[i] = 0xff
+	}
+	ptr := unsafe.Pointer(&block[0])
+	ptr = unsafe.Add(ptr, (align-uintptr(ptr)%align)%align)
+	a.blocks[ptr] = block
+	a.allocs++
+	return ptr
+}
+
+func (a *testAllocator) Free(ptr unsafe.Pointer, size, align uintptr) {
+	block, ok := a.blocks[ptr]
+	if !ok {
+		panic("free of unknown block")
+	}
+	if uintptr(len(block)) != size+align {
+		panic("free with wrong size")
+	}
+	delete(a.blocks, ptr)
+	a.frees++
+}
+
+func TestWorldAllocator(t *testing.T) {
+	alloc := newTestAllocator()
+	w := NewWorld(NewConfig().WithCapacityIncrement(8).WithAllocator(alloc))
+	posID := ComponentID[Position](&w)
+	ptrID := ComponentID[snapshotPointer](&w)
+	labelID := ComponentID[label](&w)
+
+	e := w.NewEntity(posID, ptrID, labelID)
+	assert.Equal(t, 1, alloc.allocs)
+	assert.Equal(t, Position{}, *(*Position)(w.Get(e, posID)))
+
+	query := NewBuilder(&w, posID, ptrID).NewBatchQ(100)
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		pos.X = int(query.Entity().id)
+	}
+	// Growth adds chunks of 8 entities, and never frees or moves columns.
+	assert.Equal(t, 14, alloc.allocs)
+	assert.Equal(t, 0, alloc.frees)
+	assert.Equal(t, 14, len(alloc.blocks))
+
+	filter := All(posID, ptrID).Without(labelID)
+	query = w.Query(&filter)
+	for query.Next() {
+		assert.Equal(t, Position{X: int(query.Entity().id)}, *(*Position)(query.Get(posID)))
+	}
+	assert.Equal(t, Position{}, *(*Position)(w.Get(e, posID)))
+
+	w.Batch().RemoveEntities(&filter)
+	w.Reset()
+	assert.Equal(t, 14, len(alloc.blocks))
+
+	w.Compact()
+	assert.Equal(t, 12, alloc.frees)
+	assert.Equal(t, 2, len(alloc.blocks))
+}
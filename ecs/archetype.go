@@ -8,16 +8,27 @@ import (
 	"github.com/mlange-42/arche/ecs/stats"
 )
 
-const layoutChunkSize uint8 = 16
-
 // layoutSize is the size of an archetype column layout in bytes.
 var layoutSize uint32 = uint32(unsafe.Sizeof(layout{}))
 
+// chunkBytes is the maximum memory of a chunk of entities and their components, in bytes.
+const chunkBytes = 16 * 1024
+
+// zeroSizeValue is the shared storage of all zero-sized components (tags).
+var zeroSizeValue struct{}
+
+// tagChunks is the single chunk of all zero-sized components (tags).
+var tagChunks = []unsafe.Pointer{unsafe.Pointer(&zeroSizeValue)}
+
+// absentChunks is the single chunk of components that are absent from an archetype.
+// Pointers to absent components are nil.
+var absentChunks = []unsafe.Pointer{nil}
+
 // Helper for accessing data from an archetype
 type archetypeAccess struct {
 	Mask                 Mask           // Archetype's mask
 	basePointer          unsafe.Pointer // Pointer to the first component column layout.
-	entityPointer        unsafe.Pointer // Pointer to the entity storage
+	entities             *layout        // Layout of the entity column.
 	RelationTarget       Entity         // Target entity of the archetype (if it has a relation component)
 	RelationComponent    ID             // Relation component of the archetype
 	HasRelationComponent bool           // Whether the archetype has a relation
@@ -25,17 +36,23 @@ type archetypeAccess struct {
 
 // GetEntity returns the entity at the given index
 func (a *archetypeAccess) GetEntity(index uint32) Entity {
-	return *(*Entity)(unsafe.Add(a.entityPointer, entitySize*index))
+	return *(*Entity)(a.entities.Get(index))
 }
 
-// Get returns the component with the given ID at the given index
+// Get returns the component with the given ID at the given index.
+// Returns nil if the archetype does not contain the component.
+//
+// The presence check is required, as layouts are only allocated up to the highest component ID of the archetype.
 func (a *archetypeAccess) Get(index uint32, id ID) unsafe.Pointer {
+	if !a.Mask.Get(id) {
+		return nil
+	}
 	return a.getLayout(id).Get(index)
 }
 
 // HasComponent returns whether the archetype contains the given component ID.
 func (a *archetypeAccess) HasComponent(id ID) bool {
-	return a.getLayout(id).pointer != nil
+	return a.Mask.Get(id)
 }
 
 // HasRelation returns whether the archetype has a relation component.
@@ -44,85 +61,149 @@ func (a *archetypeAccess) HasRelation() bool {
 }
 
 // GetLayout returns the column layout for a component.
+//
+// Layouts are only allocated up to the highest component ID of the archetype.
+// Check with [archetypeAccess.HasComponent] before calling it for arbitrary components.
 func (a *archetypeAccess) getLayout(id ID) *layout {
 	return (*layout)(unsafe.Add(a.basePointer, layoutSize*uint32(id.id)))
 }
 
 // layout specification of a component column.
+//
+// Columns are stored in chunks of equal size, with a power of two number of items per chunk.
+// Growing a column adds chunks, so items never move in memory.
 type layout struct {
-	pointer  unsafe.Pointer // Pointer to the first element in the component column.
-	itemSize uint32         // Component/step size
+	chunks   []unsafe.Pointer // Pointers to the first item of each chunk.
+	itemSize uint32           // Component/step size
+	shift    uint32           // Log2 of the number of items per chunk. 31 for a single shared item, like for tags.
+	mask     uint32           // Number of items per chunk, minus one.
 }
 
 // Get returns a pointer to the item at the given index.
+//
+// Masking the shift lets the compiler omit the check for oversized shifts.
 func (l *layout) Get(index uint32) unsafe.Pointer {
-	if l.pointer == nil {
-		return nil
+	return unsafe.Add(l.chunks[index>>(l.shift&31)], (index&l.mask)*l.itemSize)
+}
+
+// run returns the number of items from the given index to the end of its chunk, but at most count.
+func (l *layout) run(index, count uint32) uint32 {
+	if n := l.mask + 1 - index&l.mask; n < count {
+		return n
+	}
+	return count
+}
+
+// bytes returns the raw bytes of count items, starting at the given index.
+// The items must not exceed the chunk of the index. See [layout.run].
+func (l *layout) bytes(index, count uint32) []byte {
+	return unsafe.Slice((*byte)(l.Get(index)), count*l.itemSize)
+}
+
+// appendBytes appends the raw bytes of count items, starting at the given index, to a buffer.
+func (l *layout) appendBytes(buf []byte, index, count uint32) []byte {
+	for end := index + count; index < end; {
+		n := l.run(index, end-index)
+		buf = append(buf, l.bytes(index, n)...)
+		index += n
+	}
+	return buf
+}
+
+// setBytes overwrites items, starting at the given index, with raw bytes.
+func (l *layout) setBytes(index uint32, data []byte) {
+	for len(data) > 0 {
+		n := l.run(index, uint32(len(data))/l.itemSize)
+		data = data[copy(l.bytes(index, n), data):]
+		index += n
+	}
+}
+
+// clear zeroes count items, starting at the given index.
+func (l *layout) clear(index, count uint32) {
+	for end := index + count; index < end; {
+		n := l.run(index, end-index)
+		clear(l.bytes(index, n))
+		index += n
+	}
+}
+
+// copyItems copies count items between columns with the same item size.
+// Source and destination may overlap if the destination index is not after the source index.
+func copyItems(dst *layout, dstIndex uint32, src *layout, srcIndex uint32, count uint32) {
+	for count > 0 {
+		n := dst.run(dstIndex, src.run(srcIndex, count))
+		copy(dst.bytes(dstIndex, n), src.bytes(srcIndex, n))
+		dstIndex += n
+		srcIndex += n
+		count -= n
 	}
-	return unsafe.Add(l.pointer, l.itemSize*index)
 }
 
 // archetype represents an ECS archetype
 type archetype struct {
 	archetypeAccess // Access helper, passed to queries.
 	*archetypeData
-	node *archNode // Node in the archetype graph.
-	len  uint32    // Current number of entities
-	cap  uint32    // Current capacity
+	node     *archNode // Node in the archetype graph.
+	len      uint32    // Current number of entities
+	cap      uint32    // Current capacity
+	idle     uint32    // Number of consecutive maintenance runs the archetype was under-used. See [World.Maintain].
+	disabled uint32    // Number of disabled entities. See [World.Disable].
 }
 
 type archetypeData struct {
-	layouts      []layout        // Column layouts by ID.
-	indices      idMap[uint32]   // Mapping from IDs to buffer indices.
-	buffers      []reflect.Value // Reflection arrays containing component data.
-	entityBuffer reflect.Value   // Reflection array containing entity data.
-	index        int32           // Index of the archetype in the world.
+	layouts      []layout // Column layouts by ID, up to the highest ID of the archetype.
+	entityLayout layout   // Layout of the entity column.
+	index        int32    // Index of the archetype in the world.
+	slots        []any    // User data slots. See [World.RegisterArchetypeSlot].
 }
 
 // Init initializes an archetype
-func (a *archetype) Init(node *archNode, data *archetypeData, index int32, forStorage bool, layouts uint8, relation Entity) {
+func (a *archetype) Init(node *archNode, data *archetypeData, index int32, forStorage bool, relation Entity) {
 	if !node.IsActive {
 		node.IsActive = true
 	}
 
+	a.node = node
 	a.archetypeData = data
-	a.buffers = make([]reflect.Value, len(node.Ids))
-	a.indices = newIDMap[uint32]()
 	a.index = index
-	a.layouts = make([]layout, layouts)
-
-	cap := 1
-	if forStorage {
-		cap = int(node.capacityIncrement)
+	a.layouts = make([]layout, node.layoutCount())
+	for i := range a.layouts {
+		a.layouts[i] = layout{chunks: absentChunks, shift: 31}
 	}
 
+	shift := node.chunkShift
+	mask := uint32(1)<<shift - 1
 	for i, id := range node.Ids {
 		tp := node.Types[i]
 		size, align := tp.Size(), uintptr(tp.Align())
+		if size == 0 {
+			// Tags have no column. All their pointers point to the same zero-sized value.
+			a.layouts[id.id] = layout{chunks: tagChunks, shift: 31}
+			continue
+		}
 		size = (size + (align - 1)) / align * align
 
-		a.buffers[i] = reflect.New(reflect.ArrayOf(cap, tp)).Elem()
-		a.layouts[id.id] = layout{
-			a.buffers[i].Addr().UnsafePointer(),
-			uint32(size),
-		}
-		a.indices.Set(id.id, uint32(i))
+		a.layouts[id.id] = layout{itemSize: uint32(size), shift: shift, mask: mask}
 	}
-	a.entityBuffer = reflect.New(reflect.ArrayOf(cap, entityType)).Elem()
+	a.entityLayout = layout{itemSize: entitySize, shift: shift, mask: mask}
 
 	a.archetypeAccess = archetypeAccess{
 		basePointer:          unsafe.Pointer(&a.layouts[0]),
-		entityPointer:        a.entityBuffer.Addr().UnsafePointer(),
+		entities:             &a.entityLayout,
 		Mask:                 node.Mask,
 		RelationTarget:       relation,
 		RelationComponent:    node.Relation,
 		HasRelationComponent: node.HasRelation,
 	}
 
-	a.node = node
-
 	a.len = 0
-	a.cap = uint32(cap)
+	a.cap = 0
+	a.idle = 0
+
+	if forStorage {
+		a.resize(node.capacityIncrement)
+	}
 }
 
 // Add adds an entity with optionally zeroed components to the archetype
@@ -173,15 +254,9 @@ func (a *archetype) Remove(index uint32) bool {
 	old := a.len - 1
 
 	if index != old {
-		for _, id := range a.node.Ids {
+		for _, id := range a.node.dataIds {
 			lay := a.getLayout(id)
-			size := lay.itemSize
-			if size == 0 {
-				continue
-			}
-			src := unsafe.Add(lay.pointer, old*size)
-			dst := unsafe.Add(lay.pointer, index*size)
-			a.copy(src, dst, size)
+			a.copy(lay.Get(old), lay.Get(index), lay.itemSize)
 		}
 	}
 	a.ZeroAll(old)
@@ -190,22 +265,38 @@ func (a *archetype) Remove(index uint32) bool {
 	return swapped
 }
 
+// RemoveStable removes an entity and its components from the archetype,
+// and shifts all subsequent entities to preserve their order.
+func (a *archetype) RemoveStable(index uint32) {
+	old := a.len - 1
+	if index != old {
+		shift := old - index
+		copyItems(a.entities, index, a.entities, index+1, shift)
+		for _, id := range a.node.dataIds {
+			lay := a.getLayout(id)
+			copyItems(lay, index, lay, index+1, shift)
+		}
+	}
+	a.ZeroAll(old)
+	a.len--
+}
+
 // ZeroAll resets a block of storage in all buffers.
+// Skips components of zero size, and components that implement [NoZero].
 func (a *archetype) ZeroAll(index uint32) {
-	for _, id := range a.node.Ids {
+	for _, id := range a.node.zeroIds {
 		a.Zero(index, id)
 	}
 }
 
-// ZeroAll resets a block of storage in one buffer.
+// Zero resets a block of storage in one buffer.
 func (a *archetype) Zero(index uint32, id ID) {
 	lay := a.getLayout(id)
 	size := lay.itemSize
 	if size == 0 {
 		return
 	}
-	dst := unsafe.Add(lay.pointer, index*size)
-	a.copy(a.node.zeroPointer, dst, size)
+	clear(unsafe.Slice((*byte)(lay.Get(index)), size))
 }
 
 // SetEntity overwrites an entity
@@ -248,10 +339,11 @@ func (a *archetype) Reset() {
 	if a.len == 0 {
 		return
 	}
-	a.len = 0
-	for _, buf := range a.buffers {
-		buf.SetZero()
+	a.disabled = 0
+	for _, id := range a.node.zeroIds {
+		a.getLayout(id).clear(0, a.len)
 	}
+	a.len = 0
 }
 
 // Deactivate the archetype for later re-use.
@@ -264,16 +356,7 @@ func (a *archetype) Deactivate() {
 func (a *archetype) Activate(target Entity, index int32) {
 	a.index = index
 	a.RelationTarget = target
-}
-
-func (a *archetype) ExtendLayouts(count uint8) {
-	if len(a.layouts) >= int(count) {
-		return
-	}
-	temp := a.layouts
-	a.layouts = make([]layout, count)
-	copy(a.layouts, temp)
-	a.archetypeAccess.basePointer = unsafe.Pointer(&a.layouts[0])
+	a.idle = 0
 }
 
 // IsActive returns whether the archetype is active.
@@ -292,11 +375,25 @@ func (a *archetype) Len() uint32 {
 	return a.len
 }
 
+// activeLen reports the number of entities in the archetype that are not disabled.
+func (a *archetype) activeLen() uint32 {
+	return a.len - a.disabled
+}
+
 // Cap reports the current capacity of the archetype
 func (a *archetype) Cap() uint32 {
 	return a.cap
 }
 
+// memoryPerEntity returns the memory per entity for the entity and its components, in bytes.
+func (a *archetype) memoryPerEntity() int {
+	mem := int(entitySize)
+	for _, id := range a.node.dataIds {
+		mem += int(a.getLayout(id).itemSize)
+	}
+	return mem
+}
+
 // Stats generates statistics for an archetype
 func (a *archetype) Stats(reg *componentRegistry) stats.Archetype {
 	ids := a.Components()
@@ -346,29 +443,63 @@ func (a *archetype) extend(by uint32) {
 	if a.cap >= required {
 		return
 	}
-	a.cap = capacityU32(required, a.node.capacityIncrement)
+	a.resize(capacityU32(required, a.node.capacityIncrement))
+}
 
-	old := a.entityBuffer
-	a.entityBuffer = reflect.New(reflect.ArrayOf(int(a.cap), entityType)).Elem()
-	a.entityPointer = a.entityBuffer.Addr().UnsafePointer()
-	reflect.Copy(a.entityBuffer, old)
+// resize the memory buffers to the given capacity, rounded up to whole chunks.
+// The capacity must not be smaller than the number of entities.
+//
+// Adds chunks at the end, or frees chunks at the end, so entities and components never move.
+func (a *archetype) resize(cap uint32) {
+	chunks := int((cap + a.entities.mask) >> a.entities.shift)
+	cap = uint32(chunks) << a.entities.shift
+	if cap == a.cap {
+		return
+	}
+	a.node.memory.Add((int(cap) - int(a.cap)) * a.memoryPerEntity())
+	a.cap = cap
 
-	for _, id := range a.node.Ids {
+	a.entities.chunks = a.resizeChunks(a.entities.chunks, chunks, entityType, nil)
+	for _, id := range a.node.dataIds {
 		lay := a.getLayout(id)
-		if lay.itemSize == 0 {
+		index, _ := a.node.indices.Get(id.id)
+		tp := a.node.Types[index]
+		var alloc Allocator
+		if !hasPointers(tp) {
+			alloc = a.node.allocator
+		}
+		lay.chunks = a.resizeChunks(lay.chunks, chunks, tp, alloc)
+	}
+}
+
+// resizeChunks adds or frees chunks of a column of the given type, to get the given number of chunks.
+// Uses the given [Allocator], or Go-managed memory if it is nil.
+func (a *archetype) resizeChunks(chunks []unsafe.Pointer, count int, tp reflect.Type, alloc Allocator) []unsafe.Pointer {
+	items := int(a.entities.mask) + 1
+	size, align := tp.Size()*uintptr(items), uintptr(tp.Align())
+	for len(chunks) > count {
+		last := len(chunks) - 1
+		if alloc != nil {
+			alloc.Free(chunks[last], size, align)
+		}
+		chunks[last] = nil
+		chunks = chunks[:last]
+	}
+	for len(chunks) < count {
+		if alloc == nil {
+			chunks = append(chunks, reflect.New(reflect.ArrayOf(items, tp)).UnsafePointer())
 			continue
 		}
-		index, _ := a.indices.Get(id.id)
-		old := a.buffers[index]
-		a.buffers[index] = reflect.New(reflect.ArrayOf(int(a.cap), old.Type().Elem())).Elem()
-		lay.pointer = a.buffers[index].Addr().UnsafePointer()
-		reflect.Copy(a.buffers[index], old)
+		ptr := alloc.Alloc(size, align)
+		clear(unsafe.Slice((*byte)(ptr), size))
+		chunks = append(chunks, ptr)
 	}
+	return chunks
 }
 
 // Adds an entity at the given index. Does not extend the entity buffer.
 func (a *archetype) addEntity(index uint32, entity *Entity) {
-	dst := unsafe.Add(a.entityPointer, entitySize*index)
+	dst := a.entities.Get(index)
 	src := unsafe.Pointer(entity)
 	a.copy(src, dst, entitySize)
 }
@@ -382,8 +513,8 @@ func (a *archetype) removeEntity(index uint32) bool {
 		return false
 	}
 
-	src := unsafe.Add(a.entityPointer, old*entitySize)
-	dst := unsafe.Add(a.entityPointer, index*entitySize)
+	src := a.entities.Get(old)
+	dst := a.entities.Get(index)
 	a.copy(src, dst, entitySize)
 
 	return true
@@ -1,8 +1,8 @@
 package ecs
 
 import (
+	"math/bits"
 	"reflect"
-	"unsafe"
 
 	"github.com/mlange-42/arche/ecs/stats"
 )
@@ -14,6 +14,9 @@ type archNode struct {
 	Relation    ID
 	HasRelation bool
 	IsActive    bool
+	IsDying     bool // Whether the node contains soft-deleted entities. See [World.Dying].
+	IsHidden    bool // Whether the node contains hidden entities. See [Hidden].
+	hiddenID    ID   // Component ID of the [Hidden] marker, if IsHidden.
 }
 
 type nodeData struct {
@@ -21,14 +24,18 @@ type nodeData struct {
 	Types             []reflect.Type        // Component type per column
 	TransitionAdd     idMap[*archNode]      // Mapping from component ID to add to the resulting archetype
 	TransitionRemove  idMap[*archNode]      // Mapping from component ID to remove to the resulting archetype
+	indices           idMap[uint32]         // Mapping from IDs to buffer indices, shared by all archetypes of the node
 	archetype         *archetype            // The single archetype for nodes without entity relation
 	archetypes        pagedSlice[archetype] // Storage for archetypes in nodes with entity relation
 	archetypeData     pagedSlice[archetypeData]
 	archetypeMap      map[Entity]*archetype // Mapping from relation targets to archetypes
 	freeIndices       []int32               // Indices of free/inactive archetypes
-	zeroValue         []byte                // Used as source for setting storage to zero
-	zeroPointer       unsafe.Pointer        // Points to zeroValue for fast access
+	zeroIds           []ID                  // Components that need zeroing on removal. See [NoZero].
+	dataIds           []ID                  // Components with non-zero size, i.e. with data columns. Tags are skipped.
 	capacityIncrement uint32                // Capacity increment
+	chunkShift        uint32                // Log2 of the number of entities per column chunk. See [chunkShift].
+	allocator         Allocator             // Allocator for columns of pointer-free components. Nil for Go-managed memory.
+	memory            *memoryTracker        // Memory tracker of the world.
 }
 
 // Creates a new archNode
@@ -40,7 +47,9 @@ func newArchNode(mask Mask, data *nodeData, relation ID, hasRelation bool, capac
 	ids := make([]ID, len(components))
 	types := make([]reflect.Type, len(components))
 
-	var maxSize uintptr = 0
+	zeroIds := []ID{}
+	dataIds := []ID{}
+	indices := newIDMap[uint32]()
 	prev := -1
 	for i, c := range components {
 		if int(c.ID.id) <= prev {
@@ -50,26 +59,23 @@ func newArchNode(mask Mask, data *nodeData, relation ID, hasRelation bool, capac
 
 		ids[i] = c.ID
 		types[i] = c.Type
-		size, align := c.Type.Size(), uintptr(c.Type.Align())
-		size = (size + (align - 1)) / align * align
-		if size > maxSize {
-			maxSize = size
+		indices.Set(c.ID.id, uint32(i))
+		if c.Type.Size() > 0 {
+			dataIds = append(dataIds, c.ID)
+			if !isNoZero(c.Type) {
+				zeroIds = append(zeroIds, c.ID)
+			}
 		}
 	}
 
-	var zeroValue []byte
-	var zeroPointer unsafe.Pointer
-	if maxSize > 0 {
-		zeroValue = make([]byte, maxSize)
-		zeroPointer = unsafe.Pointer(&zeroValue[0])
-	}
-
 	data.Ids = ids
 	data.Types = types
 	data.archetypeMap = arch
 	data.capacityIncrement = uint32(capacityIncrement)
-	data.zeroValue = zeroValue
-	data.zeroPointer = zeroPointer
+	data.chunkShift = chunkShift(uint32(capacityIncrement), types)
+	data.zeroIds = zeroIds
+	data.dataIds = dataIds
+	data.indices = indices
 	data.TransitionAdd = newIDMap[*archNode]()
 	data.TransitionRemove = newIDMap[*archNode]()
 
@@ -83,8 +89,17 @@ func newArchNode(mask Mask, data *nodeData, relation ID, hasRelation bool, capac
 
 // Matches the archetype node against a filter.
 // Ignores the relation target.
+//
+// Nodes of soft-deleted entities only match a [DyingFilter].
+// Nodes of hidden entities only match filters that require the [Hidden] component.
 func (a *archNode) Matches(f Filter) bool {
-	return f.Matches(&a.Mask)
+	if a.IsDying && !isDyingFilter(f) {
+		return false
+	}
+	if !f.Matches(&a.Mask) {
+		return false
+	}
+	return !a.IsHidden || requiresHidden(f, &a.Mask, a.hiddenID)
 }
 
 // Archetypes of the node.
@@ -107,6 +122,18 @@ func (a *archNode) GetArchetype(target Entity) *archetype {
 	return a.archetype
 }
 
+// targetArchetypes returns the archetypes of a node with relation for the given targets, in the order of the targets.
+// Targets without an archetype in the node are skipped.
+func targetArchetypes(node *archNode, targets []Entity) *pointers[archetype] {
+	arches := make([]*archetype, 0, len(targets))
+	for _, target := range targets {
+		if arch, ok := node.archetypeMap[target]; ok {
+			arches = append(arches, arch)
+		}
+	}
+	return &pointers[archetype]{pointers: arches}
+}
+
 // SetArchetype sets the archetype for a node without a relation.
 //
 // Do not use on nodes without a relation component!
@@ -115,7 +142,7 @@ func (a *archNode) SetArchetype(arch *archetype) {
 }
 
 // CreateArchetype creates a new archetype in nodes with relation component.
-func (a *archNode) CreateArchetype(layouts uint8, target Entity) *archetype {
+func (a *archNode) CreateArchetype(target Entity) *archetype {
 	var arch *archetype
 	var archIndex int32
 	lenFree := len(a.freeIndices)
@@ -129,24 +156,39 @@ func (a *archNode) CreateArchetype(layouts uint8, target Entity) *archetype {
 		a.archetypeData.Add(archetypeData{})
 		archIndex := a.archetypes.Len() - 1
 		arch = a.archetypes.Get(archIndex)
-		arch.Init(a, a.archetypeData.Get(archIndex), archIndex, true, layouts, target)
+		arch.Init(a, a.archetypeData.Get(archIndex), archIndex, true, target)
 	}
 	a.archetypeMap[target] = arch
 	return arch
 }
 
-func (a *archNode) ExtendArchetypeLayouts(count uint8) {
-	if !a.HasRelation {
-		a.archetype.ExtendLayouts(count)
-		return
+// chunkShift returns the log2 of the number of entities per column chunk, for archetypes of the given component types.
+//
+// Chunks hold a power of two number of entities, not more than the capacity increment,
+// and not more than fit into [chunkBytes] together with their components. But at least one entity.
+// The chunk size is fixed when the node is created, so it is not affected by later capacity hints.
+func chunkShift(capacityIncrement uint32, types []reflect.Type) uint32 {
+	mem := uint32(entitySize)
+	for _, tp := range types {
+		mem += uint32(tp.Size())
+	}
+	count := uint32(chunkBytes) / mem
+	if capacityIncrement < count {
+		count = capacityIncrement
 	}
+	if count <= 1 {
+		return 0
+	}
+	return uint32(bits.Len32(count) - 1)
+}
 
-	lenArches := a.archetypes.Len()
-	var j int32
-	for j = 0; j < lenArches; j++ {
-		arch := a.archetypes.Get(j)
-		arch.ExtendLayouts(count)
+// layoutCount returns the number of column layouts required by the node's archetypes.
+// This is the highest component ID plus one, and at least one.
+func (a *archNode) layoutCount() int {
+	if len(a.Ids) == 0 {
+		return 1
 	}
+	return int(a.Ids[len(a.Ids)-1].id) + 1
 }
 
 // RemoveArchetype de-activates an archetype.
@@ -0,0 +1,84 @@
+package ecs
+
+// ArchetypeSlot is a handle for user data attached to archetypes.
+//
+// Create one with [World.RegisterArchetypeSlot].
+type ArchetypeSlot struct {
+	index int
+}
+
+// archetypeSlot is the registration of an [ArchetypeSlot].
+type archetypeSlot struct {
+	filter   Filter
+	callback func(mask Mask, target Entity) any
+}
+
+// RegisterArchetypeSlot registers a slot for user data attached to archetypes,
+// like a GPU buffer handle or a render batch index.
+//
+// The callback is called for every existing archetype that matches the filter,
+// as well as for all matching archetypes created later.
+// Its return value is attached to the archetype,
+// and can be retrieved during iteration with [Query.ArchetypeData].
+// Arguments of the callback are the archetype's component [Mask] and its [Relation] target,
+// if the archetype has a relation.
+// Relation filters match the relation target in addition to components.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) RegisterArchetypeSlot(filter Filter, callback func(mask Mask, target Entity) any) ArchetypeSlot {
+	w.checkLocked()
+
+	slot := ArchetypeSlot{len(w.archetypeSlots)}
+	arches := w.getArchetypes(filter)
+	if cached, ok := filter.(*CachedFilter); ok {
+		filter = cached.filter
+	}
+	w.archetypeSlots = append(w.archetypeSlots, archetypeSlot{filter, callback})
+
+	for _, arch := range arches {
+		w.setArchetypeSlot(arch, slot.index)
+	}
+	return slot
+}
+
+// ArchetypeData returns the user data attached to the archetype
+// of the [Entity] at the iterator's current position.
+//
+// Returns nil if the archetype does not match the slot's filter.
+//
+// See [World.RegisterArchetypeSlot].
+func (q *Query) ArchetypeData(slot ArchetypeSlot) any {
+	q.checkGet()
+	data := q.archetype.slots
+	if slot.index >= len(data) {
+		return nil
+	}
+	return data[slot.index]
+}
+
+// initArchetypeSlots sets the user data of all matching slots for a newly created or re-activated archetype.
+func (w *World) initArchetypeSlots(arch *archetype) {
+	for i := range arch.slots {
+		arch.slots[i] = nil
+	}
+	for i := range w.archetypeSlots {
+		s := &w.archetypeSlots[i]
+		if !arch.node.Matches(s.filter) {
+			continue
+		}
+		if tf, ok := s.filter.(targetFilter); ok && !tf.matchesTarget(arch.RelationTarget) {
+			continue
+		}
+		w.setArchetypeSlot(arch, i)
+	}
+}
+
+// setArchetypeSlot calls the slot callback for an archetype and stores the result.
+func (w *World) setArchetypeSlot(arch *archetype, index int) {
+	for len(arch.slots) <= index {
+		arch.slots = append(arch.slots, nil)
+	}
+	s := &w.archetypeSlots[index]
+	arch.slots[index] = s.callback(arch.Mask, arch.RelationTarget)
+}
@@ -0,0 +1,80 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchetypeSlot(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	w.NewEntity(posID)
+	w.NewEntity(velID)
+
+	counter := 0
+	slot := w.RegisterArchetypeSlot(All(posID), func(mask Mask, target Entity) any {
+		counter++
+		return counter
+	})
+	assert.Equal(t, 1, counter)
+
+	parent := w.NewEntity()
+	relFilter := NewRelationFilter(All(relID), parent)
+	relSlot := w.RegisterArchetypeSlot(&relFilter, func(mask Mask, target Entity) any {
+		return target
+	})
+
+	w.NewEntity(posID, velID)
+	assert.Equal(t, 2, counter)
+
+	builder := NewBuilder(&w, posID, relID).WithRelation(relID)
+	builder.New(parent)
+	builder.New(w.NewEntity())
+	assert.Equal(t, 4, counter)
+
+	query := w.Query(All(posID))
+	values := map[any]bool{}
+	for query.Next() {
+		values[query.ArchetypeData(slot)] = true
+		if query.Has(relID) && query.Relation(relID) == parent {
+			assert.Equal(t, parent, query.ArchetypeData(relSlot))
+		} else {
+			assert.Nil(t, query.ArchetypeData(relSlot))
+		}
+	}
+	assert.Equal(t, map[any]bool{1: true, 2: true, 3: true, 4: true}, values)
+
+	noPos := All(velID).Without(posID)
+	query = w.Query(&noPos)
+	for query.Next() {
+		assert.Nil(t, query.ArchetypeData(slot))
+	}
+
+	cached := w.Cache().Register(All(velID))
+	velSlot := w.RegisterArchetypeSlot(&cached, func(mask Mask, target Entity) any {
+		return "vel"
+	})
+	query = w.Query(All(velID))
+	for query.Next() {
+		assert.Equal(t, "vel", query.ArchetypeData(velSlot))
+	}
+
+	// Re-used relation archetypes get fresh data.
+	w.Batch().RemoveEntities(&relFilter)
+	w.RemoveEntity(parent)
+	builder.New(w.NewEntity())
+	query = w.Query(All(relID))
+	for query.Next() {
+		assert.Nil(t, query.ArchetypeData(relSlot))
+	}
+	assert.Equal(t, 5, counter)
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world",
+		func() { w.RegisterArchetypeSlot(All(), func(mask Mask, target Entity) any { return nil }) })
+	query.Close()
+}
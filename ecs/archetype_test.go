@@ -16,7 +16,7 @@ func TestArchetype(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 32, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, false, 16, Entity{})
+	arch.Init(&node, &data, 0, false, Entity{})
 
 	arch.Add(
 		newEntity(0),
@@ -75,13 +75,13 @@ func TestNewArchetype(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 32, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, true, 16, Entity{})
+	arch.Init(&node, &data, 0, true, Entity{})
 	assert.Equal(t, 32, int(arch.Cap()))
 
 	arch = archetype{}
 	data = archetypeData{}
-	arch.Init(&node, &data, 0, false, 16, Entity{})
-	assert.Equal(t, 1, int(arch.Cap()))
+	arch.Init(&node, &data, 0, false, Entity{})
+	assert.Equal(t, 0, int(arch.Cap()))
 
 	comps = []componentType{
 		{ID: id(1), Type: reflect.TypeOf(rotation{})},
@@ -91,7 +91,7 @@ func TestNewArchetype(t *testing.T) {
 		node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 32, comps)
 		arch := archetype{}
 		data := archetypeData{}
-		arch.Init(&node, &data, 0, true, 16, Entity{})
+		arch.Init(&node, &data, 0, true, Entity{})
 	})
 }
 
@@ -104,7 +104,7 @@ func TestArchetypeExtend(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 8, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, true, 16, Entity{})
+	arch.Init(&node, &data, 0, true, Entity{})
 
 	assert.Equal(t, 8, int(arch.Cap()))
 	assert.Equal(t, 0, int(arch.Len()))
@@ -119,35 +119,105 @@ func TestArchetypeExtend(t *testing.T) {
 	assert.Equal(t, 24, int(arch.Cap()))
 }
 
-func TestArchetypeExtendLayouts(t *testing.T) {
+func TestArchetypeChunks(t *testing.T) {
+	comps := []componentType{
+		{ID: id(0), Type: reflect.TypeOf(Position{})},
+		{ID: id(1), Type: reflect.TypeOf(label{})},
+	}
+
+	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 4, comps)
+	assert.Equal(t, uint32(2), node.chunkShift)
+	arch := archetype{}
+	data := archetypeData{}
+	arch.Init(&node, &data, 0, true, Entity{})
+
+	for i := 0; i < 10; i++ {
+		arch.Add(newEntity(eid(i)), Component{ID: id(0), Comp: &Position{i, 0}}, Component{ID: id(1), Comp: &label{}})
+	}
+	first := arch.Get(0, id(0))
+	arch.Add(newEntity(10), Component{ID: id(0), Comp: &Position{10, 0}}, Component{ID: id(1), Comp: &label{}})
+	assert.Equal(t, uint32(12), arch.Cap())
+	assert.Equal(t, 3, len(arch.getLayout(id(0)).chunks))
+	assert.Equal(t, first, arch.Get(0, id(0)), "growth must not move components")
+
+	arch.RemoveStable(2)
+	assert.Equal(t, uint32(10), arch.Len())
+	for i := uint32(0); i < arch.Len(); i++ {
+		x := int(i)
+		if i >= 2 {
+			x++
+		}
+		assert.Equal(t, Position{x, 0}, *(*Position)(arch.Get(i, id(0))))
+		assert.Equal(t, eid(x), arch.GetEntity(i).id)
+	}
+
+	arch.Remove(1)
+	assert.Equal(t, Position{10, 0}, *(*Position)(arch.Get(1, id(0))))
+	assert.Equal(t, Position{}, *(*Position)(arch.Get(9, id(0))))
+
+	arch.resize(9)
+	assert.Equal(t, uint32(12), arch.Cap())
+	arch.RemoveStable(0)
+	arch.resize(9)
+	assert.Equal(t, uint32(12), arch.Cap())
+	arch.Remove(8)
+	arch.resize(8)
+	assert.Equal(t, uint32(8), arch.Cap())
+	assert.Equal(t, 2, len(arch.getLayout(id(0)).chunks))
+	assert.Equal(t, Position{3, 0}, *(*Position)(arch.Get(1, id(0))))
+
+	arch.Reset()
+	assert.Equal(t, Position{}, *(*Position)(arch.Get(5, id(0))))
+}
+
+func TestArchetypeChunkShift(t *testing.T) {
+	small := []reflect.Type{reflect.TypeOf(Position{})}
+	large := []reflect.Type{reflect.TypeOf([1000]byte{})}
+	huge := []reflect.Type{reflect.TypeOf([chunkBytes]byte{})}
+
+	assert.Equal(t, uint32(7), chunkShift(128, small))
+	assert.Equal(t, uint32(6), chunkShift(100, small))
+	assert.Equal(t, uint32(0), chunkShift(1, small))
+	assert.Equal(t, uint32(9), chunkShift(100000, small))
+	assert.Equal(t, uint32(4), chunkShift(128, large))
+	assert.Equal(t, uint32(0), chunkShift(128, huge))
+}
+
+func TestArchetypeLayouts(t *testing.T) {
 	comps := []componentType{
 		{ID: id(0), Type: reflect.TypeOf(Position{})},
 		{ID: id(1), Type: reflect.TypeOf(rotation{})},
-		{ID: id(2), Type: reflect.TypeOf(relationComp{})},
+		{ID: id(20), Type: reflect.TypeOf(relationComp{})},
 	}
 	entity := newEntity(1)
 
-	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 8, comps[:2])
+	node := newArchNode(All(), &nodeData{}, ID{}, false, 8, []componentType{})
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, true, 16, Entity{})
+	arch.Init(&node, &data, 0, true, Entity{})
+	assert.Equal(t, 1, len(arch.layouts))
+	assert.False(t, arch.HasComponent(id(0)))
+	assert.Nil(t, arch.Get(0, id(50)))
+
+	node = newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 8, comps[:2])
+	arch = archetype{}
+	data = archetypeData{}
+	arch.Init(&node, &data, 0, true, Entity{})
 	node.SetArchetype(&arch)
 
-	assert.Equal(t, len(arch.layouts), 16)
-	node.ExtendArchetypeLayouts(16)
-	assert.Equal(t, len(arch.layouts), 16)
-	node.ExtendArchetypeLayouts(32)
-	assert.Equal(t, len(arch.layouts), 32)
+	assert.Equal(t, 2, len(arch.layouts))
+	assert.True(t, arch.HasComponent(id(1)))
+	assert.False(t, arch.HasComponent(id(20)))
+	assert.Nil(t, arch.Get(0, id(20)))
 
-	node = newArchNode(All(id(0), id(1)), &nodeData{}, id(2), true, 8, comps)
-	node.CreateArchetype(16, entity)
+	node = newArchNode(All(id(0), id(1), id(20)), &nodeData{}, id(20), true, 8, comps)
+	node.CreateArchetype(entity)
 	arch2 := node.GetArchetype(entity)
 
-	assert.Equal(t, len(arch2.layouts), 16)
-	node.ExtendArchetypeLayouts(16)
-	assert.Equal(t, len(arch2.layouts), 16)
-	node.ExtendArchetypeLayouts(32)
-	assert.Equal(t, len(arch2.layouts), 32)
+	assert.Equal(t, 21, len(arch2.layouts))
+	assert.True(t, arch2.HasComponent(id(20)))
+	assert.False(t, arch2.HasComponent(id(2)))
+	assert.Nil(t, arch2.Get(0, id(2)))
 }
 
 func TestArchetypeAlloc(t *testing.T) {
@@ -158,7 +228,7 @@ func TestArchetypeAlloc(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 8, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, true, 16, Entity{})
+	arch.Init(&node, &data, 0, true, Entity{})
 
 	assert.Equal(t, 8, int(arch.Cap()))
 	assert.Equal(t, 0, int(arch.Len()))
@@ -184,7 +254,7 @@ func TestArchetypeAddGetSet(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 1, comps)
 	a := archetype{}
 	data := archetypeData{}
-	a.Init(&node, &data, 0, true, 16, Entity{})
+	a.Init(&node, &data, 0, true, Entity{})
 
 	assert.Equal(t, 1, int(a.Cap()))
 	assert.Equal(t, 0, int(a.Len()))
@@ -220,7 +290,7 @@ func TestArchetypeReset(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 32, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, false, 16, Entity{})
+	arch.Init(&node, &data, 0, false, Entity{})
 
 	arch.Add(
 		newEntity(0),
@@ -267,7 +337,7 @@ func TestArchetypeZero(t *testing.T) {
 	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 32, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, false, 16, Entity{})
+	arch.Init(&node, &data, 0, false, Entity{})
 
 	arch.Alloc(newEntity(0))
 	arch.Alloc(newEntity(1))
@@ -300,7 +370,7 @@ func BenchmarkIterArchetype_1000(b *testing.B) {
 	node := newArchNode(All(id(0)), &nodeData{}, ID{}, false, 32, comps)
 	arch := archetype{}
 	data := archetypeData{}
-	arch.Init(&node, &data, 0, true, 16, Entity{})
+	arch.Init(&node, &data, 0, true, Entity{})
 
 	for i := 0; i < 1000; i++ {
 		arch.Alloc(newEntity(eid(i)))
@@ -351,3 +421,29 @@ func BenchmarkIterSliceInterface_1000(b *testing.B) {
 		}
 	}
 }
+
+func TestArchetypeTags(t *testing.T) {
+	comps := []componentType{
+		{ID: id(0), Type: reflect.TypeOf(Position{})},
+		{ID: id(1), Type: reflect.TypeOf(label{})},
+	}
+
+	node := newArchNode(All(id(0), id(1)), &nodeData{}, ID{}, false, 1, comps)
+	assert.Equal(t, []ID{id(0)}, node.dataIds)
+
+	arch := archetype{}
+	data := archetypeData{}
+	arch.Init(&node, &data, 0, true, Entity{})
+	assert.Equal(t, tagChunks, arch.getLayout(id(1)).chunks)
+
+	arch.Add(newEntity(0), Component{ID: id(0), Comp: &Position{1, 2}}, Component{ID: id(1), Comp: &label{}})
+	arch.Add(newEntity(1), Component{ID: id(0), Comp: &Position{3, 4}}, Component{ID: id(1), Comp: &label{}})
+	assert.Equal(t, uint32(2), arch.cap)
+
+	assert.NotNil(t, arch.Get(0, id(1)))
+	assert.Equal(t, arch.Get(0, id(1)), arch.Get(1, id(1)))
+
+	arch.Remove(0)
+	assert.Equal(t, Position{3, 4}, *(*Position)(arch.Get(0, id(0))))
+	assert.NotNil(t, arch.Get(0, id(1)))
+}
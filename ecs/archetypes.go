@@ -52,6 +52,16 @@ func (s *batchArchetypes) Add(arch, oldArch *archetype, start, end uint32) {
 	s.EndIndex = append(s.EndIndex, end)
 }
 
+// AppendEntities appends all entities in the batch to the given slice.
+func (s *batchArchetypes) AppendEntities(entities []Entity) []Entity {
+	for i, arch := range s.Archetype {
+		for j := s.StartIndex[i]; j < s.EndIndex[i]; j++ {
+			entities = append(entities, arch.GetEntity(j))
+		}
+	}
+	return entities
+}
+
 // Implementation of an archetype iterator for pointers.
 // Implements [archetypes].
 //
@@ -85,6 +95,14 @@ func (a *pointers[T]) RemoveAt(index int) bool {
 	return true
 }
 
+// RemoveAtStable removes an element at a given index, and shifts all subsequent elements.
+func (a *pointers[T]) RemoveAtStable(index int) {
+	ln := len(a.pointers)
+	copy(a.pointers[index:], a.pointers[index+1:])
+	a.pointers[ln-1] = nil
+	a.pointers = a.pointers[:ln-1]
+}
+
 // Len returns the current number of items in the paged array.
 func (a *pointers[T]) Len() int32 {
 	return int32(len(a.pointers))
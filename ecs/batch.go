@@ -128,7 +128,71 @@ func (b *Batch) ExchangeQ(filter Filter, add []ID, rem []ID) Query {
 // Unlike with the other batch operations, it is not easily possible to provide a query version RemoveEntitiesQ.
 // However, one can simply query with the same filter before calling RemoveEntities.
 //
+// If soft-deletion is enabled via [Config.RemovalGracePeriod],
+// entities are only marked as dying, unless the filter is a [DyingFilter].
+// See [World.Dying] for details.
+//
 // See also [World.RemoveEntity]
 func (b *Batch) RemoveEntities(filter Filter) int {
 	return b.world.removeEntities(filter)
 }
+
+// CloneEntities creates the given number of copies of each entity matching a filter.
+// Returns the number of created entities.
+//
+// Clones are created like with [World.CloneEntity], including events.
+//
+// Panics:
+//   - when called with a count smaller than 1.
+//   - when called on a locked world. Do not use during [Query] iteration!
+func (b *Batch) CloneEntities(filter Filter, count int) int {
+	return b.world.cloneEntities(filter, count)
+}
+
+// Apply runs a function on component T of all entities matching a filter.
+// Returns the number of affected entities.
+//
+// Iterates component columns chunk by chunk, as plain slice loops.
+// This is a middle ground between a full system and hand-written query code for one-off bulk mutations.
+// Entities matching the filter, but without component T, are skipped.
+//
+// The world is locked during the operation, so the function must not perform structural changes.
+// If the [Listener] subscribes to [event.ComponentSet], events for all affected entities
+// are notified after the operation.
+// If T is tracked for change detection (see [World.TrackChanges]), all affected entities are marked as changed.
+//
+// Example:
+//
+//	ecs.Apply(world.Batch(), ecs.All(posID), func(pos *Position) {
+//		pos.X = 0
+//	})
+func Apply[T any](b *Batch, filter Filter, fn func(*T)) int {
+	id := ComponentID[T](b.world)
+	listen := b.world.listensSet(id)
+	changes := b.world.changes
+	track := changes != nil && changes.tracked.Get(id)
+	var entities []Entity
+	count := 0
+	query := b.world.Query(filter)
+	for query.NextChunk() {
+		slice := ColumnSlice[T](&query, id)
+		for i := range slice {
+			fn(&slice[i])
+		}
+		if track {
+			for i := range slice {
+				changes.Change(query.access.GetEntity(query.entityIndex+uint32(i)).id, id)
+			}
+		}
+		if listen {
+			for i := range slice {
+				entities = append(entities, query.access.GetEntity(query.entityIndex+uint32(i)))
+			}
+		}
+		count += len(slice)
+	}
+	for _, e := range entities {
+		b.world.notifySet(e, id)
+	}
+	return count
+}
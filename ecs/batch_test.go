@@ -0,0 +1,76 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchApply(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	NewBuilder(&w, posID).NewBatch(10)
+	NewBuilder(&w, posID, velID).NewBatch(5)
+	e := w.NewEntity(velID)
+	w.Disable(w.NewEntity(posID))
+
+	count := Apply(w.Batch(), All(posID), func(pos *Position) {
+		pos.X += 2
+	})
+	assert.Equal(t, 15, count)
+
+	count = Apply(w.Batch(), All(velID), func(pos *Position) {
+		pos.Y = 3
+	})
+	assert.Equal(t, 5, count)
+
+	query := w.Query(All(posID))
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		assert.Equal(t, 2, pos.X)
+		if query.Has(velID) {
+			assert.Equal(t, 3, pos.Y)
+		} else {
+			assert.Equal(t, 0, pos.Y)
+		}
+	}
+	assert.False(t, w.Has(e, posID))
+	assert.False(t, w.IsLocked())
+}
+
+func TestBatchApplyChanged(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	w.TrackChanges(posID)
+
+	NewBuilder(&w, posID).NewBatch(10)
+	NewBuilder(&w, posID, velID).NewBatch(5)
+
+	filter := NewChangeFilter(All()).Changed(posID)
+	countChanged := func() int {
+		query := w.Query(filter)
+		cnt := 0
+		for query.Next() {
+			cnt++
+		}
+		return cnt
+	}
+	assert.Equal(t, 15, countChanged())
+	assert.Equal(t, 0, countChanged())
+
+	count := Apply(w.Batch(), All(velID), func(pos *Position) {
+		pos.X++
+	})
+	assert.Equal(t, 5, count)
+	assert.Equal(t, 5, countChanged())
+	assert.Equal(t, 0, countChanged())
+
+	Apply(w.Batch(), All(posID), func(pos *Position) {})
+	assert.Equal(t, 15, countChanged())
+
+	Apply(w.Batch(), All(posID), func(vel *Velocity) {})
+	assert.Equal(t, 0, countChanged())
+}
@@ -7,6 +7,7 @@ type Builder struct {
 	comps       []Component
 	hasRelation bool
 	relationID  ID
+	origin      *quota
 }
 
 // NewBuilder creates a builder from component IDs.
@@ -38,11 +39,36 @@ func (b *Builder) WithRelation(comp ID) *Builder {
 	return b
 }
 
+// WithOrigin sets the origin label for entities created by the builder.
+//
+// The number of entities per origin can be limited with [World.SetQuota].
+// Only entities created with [Builder.New], [Builder.NewBatch] and [Builder.NewBatchQ] are assigned to the origin.
+func (b *Builder) WithOrigin(origin string) *Builder {
+	b.origin = b.world.quotaTracker().Get(origin)
+	return b
+}
+
 // New creates an entity.
 //
 // The optional argument can be used to set the target [Entity] for the Builder's [Relation].
 // See [Builder.WithRelation].
+//
+// With an origin set by [Builder.WithOrigin], returns the zero entity
+// if the creation is skipped due to the origin's quota. See [World.SetQuota].
 func (b *Builder) New(target ...Entity) Entity {
+	if b.origin == nil {
+		return b.newEntity(target...)
+	}
+	if b.world.reserveQuota(b.origin, 1) == 0 {
+		return Entity{}
+	}
+	entity := b.newEntity(target...)
+	b.world.quotas.Add(b.origin, entity)
+	return entity
+}
+
+// newEntity creates an entity, without considering the origin.
+func (b *Builder) newEntity(target ...Entity) Entity {
 	if len(target) > 0 {
 		if !b.hasRelation {
 			panic("can't set target entity: builder has no relation")
@@ -62,7 +88,15 @@ func (b *Builder) New(target ...Entity) Entity {
 //
 // The optional argument can be used to set the target [Entity] for the Builder's [Relation].
 // See [Builder.WithRelation].
+//
+// With an origin set by [Builder.WithOrigin], creates fewer entities
+// if the origin's quota requires it. See [World.SetQuota].
 func (b *Builder) NewBatch(count int, target ...Entity) {
+	if b.origin != nil {
+		query := b.NewBatchQ(count, target...)
+		query.Close()
+		return
+	}
 	if len(target) > 0 {
 		if !b.hasRelation {
 			panic("can't set target entity: builder has no relation")
@@ -85,7 +119,49 @@ func (b *Builder) NewBatch(count int, target ...Entity) {
 //
 // The optional argument can be used to set the target [Entity] for the Builder's [Relation].
 // See [Builder.WithRelation].
+//
+// With an origin set by [Builder.WithOrigin], creates fewer entities
+// if the origin's quota requires it. See [World.SetQuota].
 func (b *Builder) NewBatchQ(count int, target ...Entity) Query {
+	if b.origin == nil {
+		return b.newBatchQ(count, target...)
+	}
+	if count > 0 {
+		if count = b.world.reserveQuota(b.origin, count); count == 0 {
+			return newBatchQuery(b.world, b.world.lock(), &batchArchetypes{})
+		}
+	}
+	query := b.newBatchQ(count, target...)
+	batch := query.nodeArchetypes.(*batchArchetypes)
+	for i, arch := range batch.Archetype {
+		for j := batch.StartIndex[i]; j < batch.EndIndex[i]; j++ {
+			b.world.quotas.Add(b.origin, arch.GetEntity(j))
+		}
+	}
+	return query
+}
+
+// AppendBatch creates many entities and appends them to the given slice.
+// Returns the extended slice, like the built-in append.
+//
+// The optional argument can be used to set the target [Entity] for the Builder's [Relation].
+// See [Builder.WithRelation].
+//
+// With an origin set by [Builder.WithOrigin], creates fewer entities
+// if the origin's quota requires it. See [World.SetQuota].
+//
+// Example:
+//
+//	entities := builder.AppendBatch(nil, 100)
+func (b *Builder) AppendBatch(entities []Entity, count int, target ...Entity) []Entity {
+	query := b.NewBatchQ(count, target...)
+	entities = query.nodeArchetypes.(*batchArchetypes).AppendEntities(entities)
+	query.Close()
+	return entities
+}
+
+// newBatchQ creates many entities and returns a query over them, without considering the origin.
+func (b *Builder) newBatchQ(count int, target ...Entity) Query {
 	if len(target) > 0 {
 		if !b.hasRelation {
 			panic("can't set target entity: builder has no relation")
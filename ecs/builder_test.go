@@ -192,3 +192,38 @@ func ExampleBuilder_WithRelation() {
 	builder.New(target)
 	// Output:
 }
+
+func TestBuilderAppendBatch(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	parent := w.NewEntity()
+	entities := []ecs.Entity{parent}
+
+	entities = ecs.NewBuilder(&w, posID).AppendBatch(entities, 10)
+	assert.Equal(t, 11, len(entities))
+	assert.Equal(t, parent, entities[0])
+	query := w.Query(ecs.All(posID))
+	for i := 1; query.Next(); i++ {
+		assert.Equal(t, entities[i], query.Entity())
+	}
+
+	children := ecs.NewBuilder(&w, posID, relID).WithRelation(relID).AppendBatch(nil, 5, parent)
+	assert.Equal(t, 5, len(children))
+	for _, e := range children {
+		assert.Equal(t, parent, w.Relations().Get(e, relID))
+	}
+
+	children = ecs.NewBuilderWith(&w, ecs.Component{ID: posID, Comp: &Position{X: 3}}).AppendBatch(children[:0], 2)
+	assert.Equal(t, 2, len(children))
+	assert.Equal(t, Position{X: 3}, *(*Position)(w.Get(children[1], posID)))
+
+	w.SetQuota("limited", 3, ecs.QuotaSkip)
+	limited := ecs.NewBuilder(&w, posID).WithOrigin("limited").AppendBatch(nil, 5)
+	assert.Equal(t, 3, len(limited))
+	limited = ecs.NewBuilder(&w, posID).WithOrigin("limited").AppendBatch(limited, 5)
+	assert.Equal(t, 3, len(limited))
+
+	assert.False(t, w.IsLocked())
+}
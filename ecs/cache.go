@@ -1,11 +1,38 @@
 package ecs
 
+import (
+	"sort"
+	"time"
+
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
 // Cache entry for a [Filter].
 type cacheEntry struct {
 	ID         uint32              // Filter ID.
 	Filter     Filter              // The underlying filter.
 	Archetypes pointers[archetype] // Nodes matching the filter.
 	Indices    map[*archetype]int  // Map of archetype indices for removal.
+	Label      string              // Label of the filter, for statistics.
+	Stats      *queryStats         // Iteration statistics. Only present for labeled filters.
+}
+
+// queryStats collects iteration statistics for a labeled filter.
+type queryStats struct {
+	queries    int           // Number of queries created.
+	archetypes int           // Number of non-empty archetypes visited.
+	entities   int           // Number of entities visited.
+	time       time.Duration // Total time between query creation and closing.
+}
+
+// CacheEvent notifies about a change of the archetypes matched by a registered filter.
+//
+// See [Cache.SetCallback].
+type CacheEvent struct {
+	Filter  CachedFilter // The affected filter.
+	Mask    Mask         // Component mask of the archetype that was added or removed.
+	Target  Entity       // Relation target of the archetype. Zero if the archetype has no relation target.
+	Removed bool         // Whether the archetype was removed from the filter's matches, rather than added.
 }
 
 // Cache provides [Filter] caching to speed up queries.
@@ -20,11 +47,16 @@ type cacheEntry struct {
 // Cached filters avoid this slowdown.
 //
 // The overhead of tracking cached filters internally is very low, as updates are required only when new archetypes are created.
+//
+// To get notified when the archetypes matched by a registered filter change, see [Cache.SetCallback].
 type Cache struct {
 	indices       map[uint32]int              // Mapping from filter IDs to indices in filters
 	filters       []cacheEntry                // The cached filters, indexed by indices
 	getArchetypes func(f Filter) []*archetype // Callback for getting archetypes for a new filter from the world
 	intPool       intPool[uint32]             // Pool for filter IDs
+	callback      func(evt *CacheEvent)       // Callback for changes of matched archetypes
+	event         CacheEvent                  // Re-used event for the callback
+	stableOrder   bool                        // Whether to preserve the order of archetypes on removal. See [Config.StableOrder].
 }
 
 // newCache creates a new [Cache].
@@ -59,6 +91,31 @@ func (c *Cache) Register(f Filter) CachedFilter {
 	return CachedFilter{f, id}
 }
 
+// RegisterLabel registers a [Filter] with a label.
+//
+// For labeled filters, queries collect iteration statistics
+// (matched archetypes, entities visited, time from query creation to closing).
+// These statistics are reported in [stats.World.Queries], ranked by total time.
+// See also [Cache.ResetStats].
+func (c *Cache) RegisterLabel(f Filter, label string) CachedFilter {
+	cached := c.Register(f)
+	e := c.get(&cached)
+	e.Label = label
+	e.Stats = &queryStats{}
+	return cached
+}
+
+// ResetStats resets the iteration statistics of all labeled filters.
+//
+// Use this e.g. at the start of each tick to get per-tick statistics.
+func (c *Cache) ResetStats() {
+	for i := range c.filters {
+		if st := c.filters[i].Stats; st != nil {
+			*st = queryStats{}
+		}
+	}
+}
+
 // Unregister a filter.
 //
 // Returns the original filter.
@@ -81,6 +138,21 @@ func (c *Cache) Unregister(f *CachedFilter) Filter {
 	return filter
 }
 
+// SetCallback sets a callback that is called whenever an archetype
+// starts or stops matching a registered filter.
+// Set it to nil to remove the callback.
+//
+// Archetypes are added when they are created by an entity or component operation,
+// and removed when relation archetypes are removed due to their target entity being removed,
+// or on [World.Reset] and [World.Maintain].
+// Archetypes that already exist when a filter is registered are not reported.
+//
+// The callback is called in the middle of the world operation that caused the change.
+// It must not modify the world, and must not retain the event pointer, as the event is re-used.
+func (c *Cache) SetCallback(callback func(evt *CacheEvent)) {
+	c.callback = callback
+}
+
 // Returns the [cacheEntry] for the given filter.
 //
 // Panics if there is no entry for the filter's ID.
@@ -98,25 +170,28 @@ func (c *Cache) addArchetype(arch *archetype) {
 	if !arch.HasRelation() {
 		for i := range c.filters {
 			e := &c.filters[i]
-			if !e.Filter.Matches(&arch.Mask) {
+			if !arch.node.Matches(e.Filter) {
 				continue
 			}
 			e.Archetypes.Add(arch)
+			c.notify(e, arch, false)
 		}
 		return
 	}
 
 	for i := range c.filters {
 		e := &c.filters[i]
-		if !e.Filter.Matches(&arch.Mask) {
+		if !arch.node.Matches(e.Filter) {
 			continue
 		}
-		if rf, ok := e.Filter.(*RelationFilter); ok {
-			if rf.Target == arch.RelationTarget {
+		if tf, ok := e.Filter.(targetFilter); ok {
+			if tf.matchesTarget(arch.RelationTarget) {
 				e.Archetypes.Add(arch)
-				// Not required: can't add after removing,
-				// as the target entity is dead.
-				// if e.Indices != nil { e.Indices[arch] = int(e.Archetypes.Len() - 1) }
+				// Required, as empty archetypes of live targets can be removed by World.Maintain and re-created.
+				if e.Indices != nil {
+					e.Indices[arch] = int(e.Archetypes.Len() - 1)
+				}
+				c.notify(e, arch, false)
 			}
 			continue
 		}
@@ -124,6 +199,7 @@ func (c *Cache) addArchetype(arch *archetype) {
 		if e.Indices != nil {
 			e.Indices[arch] = int(e.Archetypes.Len() - 1)
 		}
+		c.notify(e, arch, false)
 	}
 }
 
@@ -135,20 +211,39 @@ func (c *Cache) removeArchetype(arch *archetype) {
 	for i := range c.filters {
 		e := &c.filters[i]
 
-		if e.Indices == nil && e.Filter.Matches(&arch.Mask) {
+		if e.Indices == nil && arch.node.Matches(e.Filter) {
 			c.mapArchetypes(e)
 		}
 
 		if idx, ok := e.Indices[arch]; ok {
-			swap := e.Archetypes.RemoveAt(idx)
-			if swap {
+			if c.stableOrder {
+				e.Archetypes.RemoveAtStable(idx)
+				for i := idx; i < len(e.Archetypes.pointers); i++ {
+					e.Indices[e.Archetypes.pointers[i]] = i
+				}
+			} else if e.Archetypes.RemoveAt(idx) {
 				e.Indices[e.Archetypes.Get(int32(idx))] = idx
 			}
 			delete(e.Indices, arch)
+			c.notify(e, arch, true)
 		}
 	}
 }
 
+// Notifies the callback about an archetype added to or removed from an entry, if there is a callback.
+func (c *Cache) notify(e *cacheEntry, arch *archetype, removed bool) {
+	if c.callback == nil {
+		return
+	}
+	c.event = CacheEvent{
+		Filter:  CachedFilter{filter: e.Filter, id: e.ID},
+		Mask:    arch.Mask,
+		Target:  arch.RelationTarget,
+		Removed: removed,
+	}
+	c.callback(&c.event)
+}
+
 func (c *Cache) mapArchetypes(e *cacheEntry) {
 	e.Indices = map[*archetype]int{}
 	for i, arch := range e.Archetypes.pointers {
@@ -157,3 +252,23 @@ func (c *Cache) mapArchetypes(e *cacheEntry) {
 		}
 	}
 }
+
+// Stats generates statistics for all labeled filters, sorted by total time in descending order.
+func (c *Cache) stats(st []stats.Query) []stats.Query {
+	st = st[:0]
+	for i := range c.filters {
+		e := &c.filters[i]
+		if e.Stats == nil {
+			continue
+		}
+		st = append(st, stats.Query{
+			Label:      e.Label,
+			Queries:    e.Stats.queries,
+			Archetypes: e.Stats.archetypes,
+			Entities:   e.Stats.entities,
+			Time:       e.Stats.time,
+		})
+	}
+	sort.SliceStable(st, func(i, j int) bool { return st[i].Time > st[j].Time })
+	return st
+}
@@ -119,3 +119,109 @@ func ExampleCache() {
 	}
 	// Output:
 }
+
+func TestFilterCacheLabel(t *testing.T) {
+	world := NewWorld()
+	posID := ComponentID[Position](&world)
+	velID := ComponentID[Velocity](&world)
+
+	world.NewEntity(posID)
+	world.NewEntity(posID, velID)
+	world.NewEntity(posID, velID)
+
+	cache := world.Cache()
+	unlabeled := cache.Register(All(velID))
+	fPos := cache.RegisterLabel(All(posID), "pos")
+	fVel := cache.RegisterLabel(All(velID), "vel")
+
+	query := world.Query(&unlabeled)
+	query.Close()
+
+	for i := 0; i < 2; i++ {
+		query = world.Query(&fPos)
+		for query.Next() {
+		}
+	}
+	query = world.Query(&fVel)
+	query.Close()
+
+	stats := world.Stats()
+	assert.Equal(t, 2, len(stats.Queries))
+
+	var pos, vel = stats.Queries[0], stats.Queries[1]
+	if pos.Label != "pos" {
+		pos, vel = vel, pos
+	}
+	assert.Equal(t, "pos", pos.Label)
+	assert.Equal(t, 2, pos.Queries)
+	assert.Equal(t, 4, pos.Archetypes)
+	assert.Equal(t, 6, pos.Entities)
+
+	assert.Equal(t, "vel", vel.Label)
+	assert.Equal(t, 1, vel.Queries)
+	assert.Equal(t, 0, vel.Archetypes)
+	assert.Equal(t, 0, vel.Entities)
+
+	assert.GreaterOrEqual(t, stats.Queries[0].Time, stats.Queries[1].Time)
+
+	cache.ResetStats()
+	stats = world.Stats()
+	assert.Equal(t, 2, len(stats.Queries))
+	assert.Equal(t, 0, stats.Queries[0].Queries)
+	assert.Equal(t, 0, stats.Queries[1].Queries)
+
+	cache.Unregister(&fVel)
+	stats = world.Stats()
+	assert.Equal(t, 1, len(stats.Queries))
+	assert.Equal(t, "pos", stats.Queries[0].Label)
+}
+
+func TestFilterCacheCallback(t *testing.T) {
+	world := NewWorld()
+	posID := ComponentID[Position](&world)
+	velID := ComponentID[Velocity](&world)
+	relID := ComponentID[testRelationA](&world)
+
+	world.NewEntity(posID)
+
+	events := []CacheEvent{}
+	cache := world.Cache()
+	cache.SetCallback(func(evt *CacheEvent) { events = append(events, *evt) })
+
+	f1 := cache.Register(All(posID))
+	f2 := cache.Register(All(relID))
+	assert.Equal(t, 0, len(events))
+
+	world.NewEntity(velID)
+	assert.Equal(t, 0, len(events))
+
+	world.NewEntity(posID, velID)
+	assert.Equal(t, []CacheEvent{
+		{Filter: f1, Mask: All(posID, velID)},
+	}, events)
+
+	target := world.NewEntity()
+	e := world.NewEntity(posID, relID)
+	world.Relations().Set(e, relID, target)
+	assert.Equal(t, []CacheEvent{
+		{Filter: f1, Mask: All(posID, velID)},
+		{Filter: f1, Mask: All(posID, relID)},
+		{Filter: f2, Mask: All(posID, relID)},
+		{Filter: f1, Mask: All(posID, relID), Target: target},
+		{Filter: f2, Mask: All(posID, relID), Target: target},
+	}, events)
+
+	events = events[:0]
+	world.RemoveEntity(e)
+	assert.Equal(t, 0, len(events))
+	world.RemoveEntity(target)
+	assert.Equal(t, []CacheEvent{
+		{Filter: f1, Mask: All(posID, relID), Target: target, Removed: true},
+		{Filter: f2, Mask: All(posID, relID), Target: target, Removed: true},
+	}, events)
+
+	events = events[:0]
+	cache.SetCallback(nil)
+	world.NewEntity(posID, velID, relID)
+	assert.Equal(t, 0, len(events))
+}
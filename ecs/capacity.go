@@ -0,0 +1,59 @@
+package ecs
+
+// SetCapacityIncrement sets a capacity increment hint for archetypes that contain the given component.
+//
+// Archetypes with hinted components grow by the largest of their components' hints,
+// instead of by [Config.CapacityIncrement] or [Config.RelationCapacityIncrement].
+// This allows archetypes of components with huge numbers of entities, like particles,
+// to grow in large steps, while small archetypes stay small.
+//
+// Hints also apply to existing archetypes, but only take effect on their next growth or [World.Maintain].
+// Hints are not affected by [World.Reset].
+//
+// Panics if the increment is not positive.
+func (w *World) SetCapacityIncrement(comp ID, inc int) {
+	if inc < 1 {
+		panic("invalid capacity increment, must be > 0")
+	}
+	if w.capacityHints == nil {
+		w.capacityHints = make([]uint32, MaskTotalBits)
+	}
+	w.capacityHints[comp.id] = uint32(inc)
+	w.capacityHinted.Set(comp, true)
+
+	length := w.nodes.Len()
+	var i int32
+	for i = 0; i < length; i++ {
+		nd := w.nodes.Get(i)
+		if nd.Mask.Get(comp) {
+			nd.capacityIncrement = uint32(w.capacityIncrement(&nd.Mask, nd.HasRelation))
+		}
+	}
+}
+
+// CapacityIncrement returns the capacity increment hint for a component, and whether there is one.
+// See [World.SetCapacityIncrement].
+func (w *World) CapacityIncrement(comp ID) (int, bool) {
+	if !w.capacityHinted.Get(comp) {
+		return 0, false
+	}
+	return int(w.capacityHints[comp.id]), true
+}
+
+// capacityIncrement returns the capacity increment for archetypes with the given mask.
+func (w *World) capacityIncrement(mask *Mask, hasRelation bool) int {
+	if mask.ContainsAny(&w.capacityHinted) {
+		var inc uint32
+		for i := 0; i < MaskTotalBits; i++ {
+			id := ID{id: uint8(i)}
+			if mask.Get(id) && w.capacityHinted.Get(id) && w.capacityHints[i] > inc {
+				inc = w.capacityHints[i]
+			}
+		}
+		return int(inc)
+	}
+	if hasRelation {
+		return w.config.RelationCapacityIncrement
+	}
+	return w.config.CapacityIncrement
+}
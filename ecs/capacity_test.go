@@ -0,0 +1,47 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldCapacityIncrement(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(32).WithRelationCapacityIncrement(8))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	rotID := ComponentID[rotation](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	e0 := w.NewEntity(velID)
+	assert.Equal(t, uint32(32), w.entities[e0.id].arch.Cap())
+
+	_, ok := w.CapacityIncrement(posID)
+	assert.False(t, ok)
+
+	w.SetCapacityIncrement(posID, 1024)
+	w.SetCapacityIncrement(rotID, 4)
+	w.SetCapacityIncrement(velID, 64)
+
+	inc, ok := w.CapacityIncrement(posID)
+	assert.True(t, ok)
+	assert.Equal(t, 1024, inc)
+
+	e := w.NewEntity(posID)
+	assert.Equal(t, uint32(1024), w.entities[e.id].arch.Cap())
+
+	e = w.NewEntity(rotID)
+	assert.Equal(t, uint32(4), w.entities[e.id].arch.Cap())
+
+	e = w.NewEntity(rotID, velID)
+	assert.Equal(t, uint32(64), w.entities[e.id].arch.Cap())
+
+	target := w.NewEntity()
+	e = NewBuilder(&w, relID).WithRelation(relID).New(target)
+	assert.Equal(t, uint32(8), w.entities[e.id].arch.Cap())
+
+	NewBuilder(&w, velID).NewBatch(40)
+	assert.Equal(t, uint32(64), w.entities[e0.id].arch.Cap())
+
+	assert.PanicsWithValue(t, "invalid capacity increment, must be > 0", func() { w.SetCapacityIncrement(posID, 0) })
+}
@@ -0,0 +1,153 @@
+package ecs
+
+import "fmt"
+
+// cascadeKind is the kind of a [Cascade] policy.
+type cascadeKind uint8
+
+const (
+	cascadeKeep cascadeKind = iota
+	cascadeRemoveRelation
+	cascadeRemoveEntity
+	cascadeReassign
+)
+
+// Cascade is a policy for entities with a [Relation] to a target entity that is removed.
+// See [World.SetCascade].
+type Cascade struct {
+	kind   cascadeKind
+	target Entity
+}
+
+var (
+	// CascadeKeep keeps the relation to the removed target entity. This is the default.
+	CascadeKeep = Cascade{kind: cascadeKeep}
+	// CascadeRemoveRelation removes the relation component from entities that point to the removed target.
+	CascadeRemoveRelation = Cascade{kind: cascadeRemoveRelation}
+	// CascadeRemoveEntity removes entities that point to the removed target, i.e. a cascading delete.
+	CascadeRemoveEntity = Cascade{kind: cascadeRemoveEntity}
+)
+
+// CascadeReassign sets the relation target of entities that point to the removed target to the given entity.
+//
+// If the given entity is not alive when the policy is executed, the relation target is reset to zero.
+func CascadeReassign(to Entity) Cascade {
+	return Cascade{kind: cascadeReassign, target: to}
+}
+
+// String returns a string representation of the policy.
+func (c Cascade) String() string {
+	switch c.kind {
+	case cascadeRemoveRelation:
+		return "RemoveRelation"
+	case cascadeRemoveEntity:
+		return "RemoveEntity"
+	case cascadeReassign:
+		return fmt.Sprintf("Reassign(%v)", c.target)
+	default:
+		return "Keep"
+	}
+}
+
+// SetCascade sets the policy for entities with the given [Relation] component,
+// that is executed when their relation target entity is removed.
+// Policies are best set right after registering the component.
+//
+// Policies are executed at the end of [World.RemoveEntity], [Batch.RemoveEntities] and [World.Tick],
+// with the usual events for the resulting changes.
+// Removals via [CascadeRemoveEntity] cascade further down relation hierarchies.
+// If soft-deletion is enabled via [Config.RemovalGracePeriod],
+// policies are executed when the storage of the target is reclaimed, not when it is marked as dying.
+// Entities that are already dying are not removed again.
+//
+// Policies are not affected by [World.Reset].
+//
+// Panics if the component is not a relation.
+func (w *World) SetCascade(comp ID, cascade Cascade) {
+	w.checkIsRelation(comp)
+	if w.cascades == nil {
+		w.cascades = make([]Cascade, MaskTotalBits)
+	}
+	w.cascades[comp.id] = cascade
+}
+
+// GetCascade returns the policy for removed targets of a [Relation] component.
+// See [World.SetCascade].
+func (w *World) GetCascade(comp ID) Cascade {
+	if w.cascades == nil {
+		return CascadeKeep
+	}
+	return w.cascades[comp.id]
+}
+
+// queueCascade queues a removed relation target for execution of cascade policies.
+func (w *World) queueCascade(target Entity) {
+	if w.cascades == nil {
+		return
+	}
+	for _, node := range w.relationNodes {
+		if w.cascades[node.Relation.id].kind == cascadeKeep {
+			continue
+		}
+		if arch, ok := node.archetypeMap[target]; ok && arch.Len() > 0 {
+			w.cascadeTargets = append(w.cascadeTargets, target)
+			return
+		}
+	}
+}
+
+// applyCascades executes the cascade policies for all queued targets, including targets removed by cascades.
+func (w *World) applyCascades() {
+	var entities []Entity
+	for len(w.cascadeTargets) > 0 {
+		target := w.cascadeTargets[len(w.cascadeTargets)-1]
+		w.cascadeTargets = w.cascadeTargets[:len(w.cascadeTargets)-1]
+
+		for _, node := range w.relationNodes {
+			cascade := w.cascades[node.Relation.id]
+			if cascade.kind == cascadeKeep || (node.IsDying && cascade.kind == cascadeRemoveEntity) {
+				continue
+			}
+			arch, ok := node.archetypeMap[target]
+			if !ok || arch.Len() == 0 {
+				continue
+			}
+			entities = entities[:0]
+			var j uint32
+			for j = 0; j < arch.Len(); j++ {
+				entities = append(entities, arch.GetEntity(j))
+			}
+			w.applyCascade(entities, node.Relation, cascade)
+		}
+		w.cleanupArchetypes(target)
+	}
+}
+
+// applyCascade executes a cascade policy for the given entities.
+func (w *World) applyCascade(entities []Entity, comp ID, cascade Cascade) {
+	switch cascade.kind {
+	case cascadeRemoveRelation:
+		for _, e := range entities {
+			w.Remove(e, comp)
+		}
+	case cascadeRemoveEntity:
+		for _, e := range entities {
+			if !w.entityPool.Alive(e) {
+				continue
+			}
+			if w.hasDying {
+				w.markDying(e)
+			} else {
+				w.removeEntity(e)
+			}
+		}
+	case cascadeReassign:
+		target := cascade.target
+		if !w.entityPool.Alive(target) {
+			target = Entity{}
+		}
+		for _, e := range entities {
+			w.setRelation(e, comp, target)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldCascade(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relA := ComponentID[testRelationA](&w)
+	relB := ComponentID[testRelationB](&w)
+
+	assert.Equal(t, CascadeKeep, w.GetCascade(relA))
+	w.SetCascade(relA, CascadeRemoveEntity)
+	w.SetCascade(relB, CascadeRemoveRelation)
+	assert.Equal(t, CascadeRemoveEntity, w.GetCascade(relA))
+	assert.Equal(t, "RemoveEntity", w.GetCascade(relA).String())
+
+	root := w.NewEntity(posID)
+	child := NewBuilder(&w, posID, relA).WithRelation(relA).New(root)
+	grandchild := NewBuilder(&w, posID, relA).WithRelation(relA).New(child)
+	other := NewBuilder(&w, posID, relB).WithRelation(relB).New(child)
+
+	w.RemoveEntity(root)
+	assert.False(t, w.Alive(child))
+	assert.False(t, w.Alive(grandchild))
+	assert.True(t, w.Alive(other))
+	assert.False(t, w.Has(other, relB))
+	assert.True(t, w.Has(other, posID))
+
+	assert.PanicsWithValue(t, "not a relation component: ecs.Position",
+		func() { w.SetCascade(posID, CascadeRemoveEntity) })
+}
+
+func TestWorldCascadeReassign(t *testing.T) {
+	w := NewWorld()
+	relID := ComponentID[testRelationA](&w)
+
+	fallback := w.NewEntity()
+	w.SetCascade(relID, CascadeReassign(fallback))
+	assert.Equal(t, "Reassign({1 0})", w.GetCascade(relID).String())
+
+	parent := w.NewEntity()
+	NewBuilder(&w, relID).WithRelation(relID).NewBatch(5, parent)
+
+	w.RemoveEntity(parent)
+	filter := NewRelationFilter(All(relID), fallback)
+	assert.Equal(t, 5, countEntities(&w, &filter))
+	_, ok := w.relationNodes[0].archetypeMap[parent]
+	assert.False(t, ok)
+
+	w.RemoveEntity(fallback)
+	filter = NewRelationFilter(All(relID), Entity{})
+	assert.Equal(t, 5, countEntities(&w, &filter))
+}
+
+func TestWorldCascadeBatch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+	w.SetCascade(relID, CascadeRemoveEntity)
+
+	parents := NewBuilder(&w, posID).NewBatchQ(3)
+	var targets []Entity
+	for parents.Next() {
+		targets = append(targets, parents.Entity())
+	}
+	builder := NewBuilder(&w, relID).WithRelation(relID)
+	for _, p := range targets {
+		builder.NewBatch(4, p)
+	}
+	assert.Equal(t, 15, countEntities(&w, All()))
+
+	assert.Equal(t, 3, w.Batch().RemoveEntities(All(posID)))
+	assert.Equal(t, 0, countEntities(&w, All()))
+}
+
+func TestWorldCascadeDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(1))
+	relID := ComponentID[testRelationA](&w)
+	w.SetCascade(relID, CascadeRemoveEntity)
+
+	parent := w.NewEntity()
+	child := NewBuilder(&w, relID).WithRelation(relID).New(parent)
+
+	w.RemoveEntity(parent)
+	assert.True(t, w.Alive(child))
+	assert.Equal(t, 1, countEntities(&w, All()))
+
+	w.Tick()
+	assert.False(t, w.Alive(parent))
+	assert.True(t, w.Alive(child))
+	assert.Equal(t, 0, countEntities(&w, All()))
+	assert.Equal(t, 1, countEntities(&w, w.Dying(All())))
+
+	w.Tick()
+	assert.False(t, w.Alive(child))
+}
+
+func TestWorldCascadeClone(t *testing.T) {
+	w := NewWorld()
+	relID := ComponentID[testRelationA](&w)
+	w.SetCascade(relID, CascadeRemoveRelation)
+
+	c := w.Clone()
+	assert.Equal(t, CascadeRemoveRelation, c.GetCascade(relID))
+
+	w.Reset()
+	assert.Equal(t, CascadeRemoveRelation, w.GetCascade(relID))
+}
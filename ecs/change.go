@@ -0,0 +1,262 @@
+package ecs
+
+import "fmt"
+
+// ChangeFilter is a [Filter] for change detection.
+// It matches entities whose components were added, changed or removed since the filter was last queried.
+//
+// Create it with [NewChangeFilter], and specify the changes to detect with
+// [ChangeFilter.Added], [ChangeFilter.Changed] and [ChangeFilter.Removed].
+// All given conditions must be fulfilled for an entity to match.
+// Components to detect changes for must be registered for tracking with [World.TrackChanges] first.
+//
+// Each query with a ChangeFilter detects changes since the previous query with the same filter instance.
+// Therefore, each system should use its own filter instance.
+// The first query detects all changes since tracking started.
+//
+// A ChangeFilter can be wrapped in a [RelationFilter] or [MultiRelationFilter], or registered in the [Cache].
+// Queries check each entity of matching archetypes, so they are slower than plain queries.
+//
+// Example:
+//
+//	world.TrackChanges(posID)
+//	filter := NewChangeFilter(All(velID)).Changed(posID)
+//	// ...
+//	query := world.Query(filter)
+//	for query.Next() {
+//		// Entities with a Velocity, where the Position was changed since the last query.
+//	}
+type ChangeFilter struct {
+	Filter  Filter // Components filter.
+	added   Mask
+	changed Mask
+	removed Mask
+	last    uint64
+}
+
+// NewChangeFilter creates a new [ChangeFilter] from a components filter.
+func NewChangeFilter(filter Filter) *ChangeFilter {
+	return &ChangeFilter{Filter: filter}
+}
+
+// Added requires the given components to be added since the last query.
+//
+// Entities created since the last query count as having all their components added.
+func (f *ChangeFilter) Added(comps ...ID) *ChangeFilter {
+	for _, id := range comps {
+		f.added.Set(id, true)
+	}
+	return f
+}
+
+// Changed requires the given components to be added or written since the last query.
+//
+// Writes are detected for [World.Set], [World.Assign], [World.NewEntityWith], [Apply],
+// relation target changes and explicit calls to [World.MarkChanged].
+// Writes through component pointers are not detected.
+func (f *ChangeFilter) Changed(comps ...ID) *ChangeFilter {
+	for _, id := range comps {
+		f.changed.Set(id, true)
+	}
+	return f
+}
+
+// Removed requires the given components to be removed since the last query.
+// Matches only entities that currently don't have the components.
+func (f *ChangeFilter) Removed(comps ...ID) *ChangeFilter {
+	for _, id := range comps {
+		f.removed.Set(id, true)
+	}
+	return f
+}
+
+// Matches the filter against a mask.
+func (f *ChangeFilter) Matches(bits *Mask) bool {
+	return f.Filter.Matches(bits) &&
+		bits.Contains(&f.added) && bits.Contains(&f.changed) &&
+		(f.removed.IsZero() || !bits.ContainsAny(&f.removed))
+}
+
+// changeFilterOf returns the [ChangeFilter] of a filter,
+// when it is a change filter or wraps one in a [RelationFilter], [MultiRelationFilter] or [CachedFilter].
+func changeFilterOf(filter Filter) *ChangeFilter {
+	if cached, ok := filter.(*CachedFilter); ok {
+		filter = cached.filter
+	}
+	if tf, ok := filter.(targetFilter); ok {
+		filter = tf.components()
+	}
+	if cf, ok := filter.(*ChangeFilter); ok {
+		return cf
+	}
+	return nil
+}
+
+// changeTracker stores change ticks of tracked components, by component ID and entity ID.
+type changeTracker struct {
+	tick    uint64     // Current change tick. Starts at 1.
+	tracked Mask       // Tracked components.
+	ids     []ID       // Tracked component IDs.
+	added   [][]uint64 // Tick of component addition.
+	changed [][]uint64 // Tick of component addition or change.
+	removed [][]uint64 // Tick of component removal.
+}
+
+// newChangeTracker creates a new changeTracker.
+func newChangeTracker() *changeTracker {
+	return &changeTracker{
+		tick:    1,
+		added:   make([][]uint64, MaskTotalBits),
+		changed: make([][]uint64, MaskTotalBits),
+		removed: make([][]uint64, MaskTotalBits),
+	}
+}
+
+// Reset clears all change ticks, but keeps the tracked components and the current tick.
+func (c *changeTracker) Reset() {
+	for _, id := range c.ids {
+		c.added[id.id] = c.added[id.id][:0]
+		c.changed[id.id] = c.changed[id.id][:0]
+		c.removed[id.id] = c.removed[id.id][:0]
+	}
+}
+
+// set sets the tick for a component and an entity in the given storage.
+func (c *changeTracker) set(storage [][]uint64, comp ID, entity eid, tick uint64) {
+	col := storage[comp.id]
+	if int(entity) >= len(col) {
+		if tick == 0 {
+			return
+		}
+		grown := make([]uint64, int(entity)+1, 2*int(entity)+16)
+		copy(grown, col)
+		col = grown
+		storage[comp.id] = col
+	}
+	col[entity] = tick
+}
+
+// get returns the tick for a component and an entity from the given storage.
+func (c *changeTracker) get(storage [][]uint64, comp ID, entity eid) uint64 {
+	col := storage[comp.id]
+	if int(entity) >= len(col) {
+		return 0
+	}
+	return col[entity]
+}
+
+// Create records the creation of an entity with the given components.
+func (c *changeTracker) Create(entity eid, mask *Mask) {
+	for _, id := range c.ids {
+		if mask.Get(id) {
+			c.set(c.added, id, entity, c.tick)
+			c.set(c.changed, id, entity, c.tick)
+		} else {
+			c.set(c.removed, id, entity, 0)
+		}
+	}
+}
+
+// Move records an entity moving between archetypes.
+func (c *changeTracker) Move(entity eid, from, to *archetype) {
+	for _, id := range c.ids {
+		had, has := from.Mask.Get(id), to.Mask.Get(id)
+		if has && !had {
+			c.set(c.added, id, entity, c.tick)
+			c.set(c.changed, id, entity, c.tick)
+		} else if had && !has {
+			c.set(c.removed, id, entity, c.tick)
+		} else if has && to.HasRelationComponent && to.RelationComponent == id && from.RelationTarget != to.RelationTarget {
+			c.set(c.changed, id, entity, c.tick)
+		}
+	}
+}
+
+// Change records a change of a component.
+func (c *changeTracker) Change(entity eid, comp ID) {
+	if c.tracked.Get(comp) {
+		c.set(c.changed, comp, entity, c.tick)
+	}
+}
+
+// Matches checks whether an entity fulfills the conditions of a change filter.
+func (c *changeTracker) Matches(f *ChangeFilter, entity eid, since uint64) bool {
+	for _, id := range c.ids {
+		if f.added.Get(id) && c.get(c.added, id, entity) <= since {
+			return false
+		}
+		if f.changed.Get(id) && c.get(c.changed, id, entity) <= since {
+			return false
+		}
+		if f.removed.Get(id) && c.get(c.removed, id, entity) <= since {
+			return false
+		}
+	}
+	return true
+}
+
+// TrackChanges enables change tracking for the given components.
+// Tracking is required for components used in a [ChangeFilter].
+//
+// Tracking costs some memory per entity and tracked component,
+// and some time for each entity creation and component addition or removal.
+// Tracking a component that is already tracked has no effect.
+//
+// Panics when called on a locked world.
+func (w *World) TrackChanges(comps ...ID) {
+	w.checkLocked()
+	if w.changes == nil {
+		w.changes = newChangeTracker()
+	}
+	for _, id := range comps {
+		if w.changes.tracked.Get(id) {
+			continue
+		}
+		w.changes.tracked.Set(id, true)
+		w.changes.ids = append(w.changes.ids, id)
+	}
+}
+
+// MarkChanged marks components of an entity as changed, for detection by [ChangeFilter.Changed].
+// Use this after writing to components through pointers.
+//
+// Untracked components are ignored. See [World.TrackChanges].
+//
+// Panics when called for a removed (and potentially recycled) entity,
+// or if the entity does not have one of the components.
+func (w *World) MarkChanged(entity Entity, comps ...ID) {
+	if !w.entityPool.Alive(entity) {
+		panic("can't mark components of a dead entity as changed")
+	}
+	if w.changes == nil {
+		return
+	}
+	mask := &w.entities[entity.id].arch.Mask
+	for _, id := range comps {
+		if !mask.Get(id) {
+			panic(fmt.Sprintf("entity has no component of type %v", w.registry.Types[id.id]))
+		}
+		w.changes.Change(entity.id, id)
+	}
+}
+
+// initChangeQuery prepares a query with a [ChangeFilter], and advances the change tick.
+func (w *World) initChangeQuery(query *Query, filter *ChangeFilter) {
+	if w.changes == nil {
+		panic("no components are tracked for changes, see World.TrackChanges")
+	}
+	all := filter.added.Or(&filter.changed)
+	all = all.Or(&filter.removed)
+	if !w.changes.tracked.Contains(&all) {
+		for i := 0; i < MaskTotalBits; i++ {
+			id := ID{id: uint8(i)}
+			if all.Get(id) && !w.changes.tracked.Get(id) {
+				panic(fmt.Sprintf("component %v is not tracked for changes, see World.TrackChanges", w.registry.Types[i]))
+			}
+		}
+	}
+	query.changes = filter
+	query.changeSince = filter.last
+	filter.last = w.changes.tick
+	w.changes.tick++
+}
@@ -0,0 +1,151 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeFilter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	rotID := ComponentID[rotation](&w)
+
+	w.TrackChanges(posID, velID)
+	w.TrackChanges(posID)
+
+	added := NewChangeFilter(All()).Added(posID)
+	changed := NewChangeFilter(All(velID)).Changed(posID)
+	removed := NewChangeFilter(All()).Removed(velID)
+
+	e0 := w.NewEntity(posID, velID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := w.NewEntity(velID)
+
+	assert.Equal(t, []Entity{e0, e1}, queryEntities(&w, added))
+	assert.Equal(t, []Entity{e0, e1}, queryEntities(&w, changed))
+	assert.Equal(t, []Entity{}, queryEntities(&w, removed))
+
+	assert.Equal(t, []Entity{}, queryEntities(&w, added))
+	assert.Equal(t, []Entity{}, queryEntities(&w, changed))
+
+	w.Add(e2, posID)
+	w.Set(e1, posID, &Position{1, 2})
+	w.Remove(e0, velID)
+
+	assert.Equal(t, []Entity{e2}, queryEntities(&w, added))
+	assert.ElementsMatch(t, []Entity{e1, e2}, queryEntities(&w, changed))
+	assert.Equal(t, []Entity{e0}, queryEntities(&w, removed))
+
+	w.MarkChanged(e1, posID, velID)
+	w.Add(e1, rotID)
+	assert.Equal(t, []Entity{}, queryEntities(&w, added))
+	assert.Equal(t, []Entity{e1}, queryEntities(&w, changed))
+	assert.Equal(t, []Entity{}, queryEntities(&w, removed))
+
+	w.Add(e0, velID)
+	w.Remove(e0, velID)
+	w.RemoveEntity(e0)
+	e3 := w.NewEntity(posID)
+	assert.Equal(t, e0.id, e3.id)
+	assert.Equal(t, []Entity{}, queryEntities(&w, removed))
+	assert.Equal(t, []Entity{e3}, queryEntities(&w, added))
+
+	assert.PanicsWithValue(t, "can't mark components of a dead entity as changed", func() { w.MarkChanged(e0, posID) })
+	assert.PanicsWithValue(t, "entity has no component of type ecs.Velocity", func() { w.MarkChanged(e3, velID) })
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.TrackChanges(rotID) })
+	query.Close()
+
+	untracked := NewChangeFilter(All()).Changed(rotID)
+	assert.PanicsWithValue(t, "component ecs.rotation is not tracked for changes, see World.TrackChanges",
+		func() { w.Query(untracked) })
+}
+
+func TestChangeFilterNotTracked(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e0 := w.NewEntity(posID)
+	w.MarkChanged(e0, posID)
+
+	filter := NewChangeFilter(All()).Changed(posID)
+	assert.PanicsWithValue(t, "no components are tracked for changes, see World.TrackChanges",
+		func() { w.Query(filter) })
+}
+
+func TestChangeFilterBatch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	w.TrackChanges(velID, relID)
+	added := NewChangeFilter(All(posID)).Added(velID)
+	changed := NewChangeFilter(All()).Changed(relID)
+
+	target1 := w.NewEntity()
+	target2 := w.NewEntity()
+
+	builder := NewBuilder(&w, posID)
+	builder.NewBatch(10)
+	builder = NewBuilder(&w, posID, relID).WithRelation(relID)
+	builder.NewBatch(5, target1)
+
+	q := w.Query(added)
+	assert.Equal(t, 0, q.Count())
+	q.Close()
+	q = w.Query(changed)
+	assert.Equal(t, 5, q.Count())
+	q.Close()
+
+	excl := All(posID).Exclusive()
+	w.Batch().Add(&excl, velID)
+	q = w.Query(added)
+	assert.Equal(t, 10, q.Count())
+	q.Close()
+
+	w.Batch().SetRelation(All(relID), relID, target2)
+	q = w.Query(changed)
+	assert.Equal(t, 5, q.Count())
+	assert.Equal(t, target2, w.Relations().Get(q.EntityAt(4), relID))
+	q.Close()
+
+	q = w.Query(All(relID))
+	e := q.EntityAt(0)
+	q.Close()
+	w.Relations().Set(e, relID, target1)
+	assert.Equal(t, []Entity{e}, queryEntities(&w, changed))
+}
+
+func TestChangeFilterCachedRelation(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	w.TrackChanges(posID)
+
+	target := w.NewEntity()
+	e0 := NewBuilder(&w, posID, relID).WithRelation(relID).New(target)
+	e1 := NewBuilder(&w, posID, relID).WithRelation(relID).New(target)
+	NewBuilder(&w, posID).New()
+
+	filter := NewChangeFilter(All(relID)).Changed(posID)
+	cached := w.Cache().Register(filter)
+	relFilter := NewRelationFilter(NewChangeFilter(All(relID)).Changed(posID), target)
+
+	assert.Equal(t, []Entity{e0, e1}, queryEntities(&w, &cached))
+	assert.Equal(t, []Entity{e0, e1}, queryEntities(&w, &relFilter))
+
+	w.Set(e1, posID, &Position{})
+	w.Disable(e0)
+	w.MarkChanged(e0, posID)
+
+	assert.Equal(t, []Entity{e1}, queryEntities(&w, &cached))
+	assert.Equal(t, []Entity{e1}, queryEntities(&w, &relFilter))
+
+	w.Reset()
+	assert.Equal(t, []Entity{}, queryEntities(&w, &cached))
+}
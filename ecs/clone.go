@@ -0,0 +1,300 @@
+package ecs
+
+import "reflect"
+
+// CloneEntity creates a copy of an entity, with all its components and its relation target, and returns it.
+//
+// Component data is copied byte-for-byte. Thus, pointers, slices and maps in components
+// are shared between the original and the clone.
+// The clone is not disabled (see [World.Disable]) and not dying (see [World.Dying]),
+// regardless of the original's state.
+//
+// Emits an [EntityEvent] for the creation of the clone, like [World.NewEntity].
+//
+// Panics when called for a removed (and potentially recycled) entity.
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+//
+// See also [Batch.CloneEntities].
+func (w *World) CloneEntity(entity Entity) Entity {
+	w.checkLocked()
+	if !w.entityPool.Alive(entity) {
+		panic("can't clone a dead entity")
+	}
+	arch, idx := w.cloneEntity(entity, 1)
+	return arch.GetEntity(idx)
+}
+
+// cloneEntities creates the given number of copies of all entities matching a filter.
+// Returns the number of created entities.
+func (w *World) cloneEntities(filter Filter, count int) int {
+	w.checkLocked()
+	if count < 1 {
+		panic("can only create a positive number of clones")
+	}
+
+	entities := []Entity{}
+	query := w.Query(filter)
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	for _, e := range entities {
+		w.cloneEntity(e, uint32(count))
+	}
+	return len(entities) * count
+}
+
+// cloneEntity creates copies of an entity, and emits events for them.
+// Returns the archetype of the clones, and the index of the first clone.
+func (w *World) cloneEntity(entity Entity, count uint32) (*archetype, uint32) {
+	src := w.entities[entity.id].arch
+	arch := src
+	if src.node.IsDying {
+		_, ids := w.visibleComponents(src)
+		arch = w.findOrCreateArchetype(w.archetypes.Get(0), ids, nil, src.RelationTarget)
+	}
+
+	startIdx := arch.Len()
+	w.createEntities(arch, count)
+
+	srcIdx := w.entities[entity.id].index
+	for _, id := range arch.node.Ids {
+		lay := arch.getLayout(id)
+		if lay.itemSize == 0 {
+			continue
+		}
+		from := src.Get(srcIdx, id)
+		var i uint32
+		for i = 0; i < count; i++ {
+			arch.copy(from, arch.Get(startIdx+i, id), lay.itemSize)
+		}
+	}
+
+	if w.listener != nil {
+		var newRel *ID
+		if arch.HasRelationComponent {
+			newRel = &arch.RelationComponent
+		}
+		ids := arch.node.Ids
+		bits := subscription(true, false, len(ids) > 0, false, newRel != nil, newRel != nil)
+		trigger := w.listener.Subscriptions() & bits
+		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
+			var i uint32
+			for i = 0; i < count; i++ {
+				w.notify(EntityEvent{Entity: arch.GetEntity(startIdx + i), Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
+			}
+		}
+	}
+	return arch, startIdx
+}
+
+// Clone creates a deep copy of the world, and returns it.
+//
+// The copy has the same component and resource types with the same IDs,
+// and the same entities (in terms of ID, generation, alive, disabled and dying state)
+// with the same components, sparse-set components (see [SparseSets]) and relation targets.
+// Registered filters, archetype slots, tags, capacity hints, cascade policies, quotas and tracked changes and lifetimes are copied.
+// Registered filters keep their IDs, so [CachedFilter]s can be used with both worlds.
+//
+// Component data and resources are copied byte-for-byte. Thus, pointers, slices and maps
+// in components and resources are shared between the original and the copy.
+// Component metadata (see [ComponentMetadata]) is shared as well.
+//
+// The listener, remove hooks, extensions, cache callbacks, leak detection and pending commands of [World.Commands] are not copied.
+// Archetype slot callbacks are called again for all archetypes of the copy.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Clone() *World {
+	w.checkLocked()
+
+	c := fromConfig(w.config)
+	c.registry = w.registry.clone()
+	c.resources = Resources{
+		registry:  w.resources.registry.clone(),
+		resources: make([]any, len(w.resources.resources)),
+	}
+	for i, res := range w.resources.resources {
+		if res == nil {
+			continue
+		}
+		c.resources.resources[i] = cloneResource(res)
+	}
+	c.sparse = w.sparse.clone()
+	c.tick = w.tick
+	c.eventSequence = w.eventSequence
+	if w.tags != nil {
+		c.tags = make(map[string]ID, len(w.tags))
+		for k, v := range w.tags {
+			c.tags[k] = v
+		}
+	}
+	if w.namespaces != nil {
+		c.namespaces = make(map[string]Namespace, len(w.namespaces))
+		for k, v := range w.namespaces {
+			c.namespaces[k] = v
+		}
+	}
+	c.capacityHints = append([]uint32(nil), w.capacityHints...)
+	c.capacityHinted = w.capacityHinted
+	c.cascades = append([]Cascade(nil), w.cascades...)
+	c.hashExcluded = w.hashExcluded
+	c.metadata = append([]*ComponentMeta(nil), w.metadata...)
+
+	c.archetypeSlots = append([]archetypeSlot(nil), w.archetypeSlots...)
+	c.filterCache = w.filterCache.clone()
+	root := c.archetypes.Get(0)
+	if len(c.archetypeSlots) > 0 {
+		c.initArchetypeSlots(root)
+	}
+	c.filterCache.addArchetype(root)
+
+	c.entityPool = entityPool{
+		entities:          append([]Entity(nil), w.entityPool.entities...),
+		next:              w.entityPool.next,
+		available:         w.entityPool.available,
+		capacityIncrement: w.entityPool.capacityIncrement,
+		reserved:          uint64(len(w.entityPool.entities)),
+	}
+	c.entities = make([]entityIndex, len(w.entities), cap(w.entities))
+	c.targetEntities = bitSet{data: append([]uint64(nil), w.targetEntities.data...)}
+	c.disabled = bitSet{data: append([]uint64(nil), w.disabled.data...)}
+	c.disabledCount = w.disabledCount
+
+	for _, src := range w.snapshotArchetypes() {
+		arch := c.findOrCreateArchetype(root, src.node.Ids, nil, src.RelationTarget)
+		if arch.cap < src.len {
+			arch.resize(capacityU32(src.len, arch.node.capacityIncrement))
+		}
+		arch.AllocN(src.len)
+		arch.disabled = src.disabled
+		copyItems(arch.entities, 0, src.entities, 0, src.len)
+		for _, id := range src.node.Ids {
+			lay := arch.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			copyItems(lay, 0, src.getLayout(id), 0, src.len)
+		}
+		var i uint32
+		for i = 0; i < src.len; i++ {
+			c.entities[arch.GetEntity(i).id] = entityIndex{arch: arch, index: i}
+		}
+	}
+
+	if w.lifetimes != nil {
+		c.lifetimes = w.lifetimes.clone()
+	}
+	if w.changes != nil {
+		c.changes = w.changes.clone()
+	}
+	if w.quotas != nil {
+		c.quotas = w.quotas.clone()
+	}
+	return &c
+}
+
+// cloneResource creates a shallow copy of a resource.
+func cloneResource(res any) any {
+	value := reflect.ValueOf(res)
+	if value.Kind() != reflect.Pointer {
+		return res
+	}
+	cp := reflect.New(value.Type().Elem())
+	cp.Elem().Set(value.Elem())
+	return cp.Interface()
+}
+
+// clone creates a deep copy of the registry.
+func (r *componentRegistry) clone() componentRegistry {
+	c := *r
+	c.Components = make(map[reflect.Type]uint8, len(r.Components))
+	for k, v := range r.Components {
+		c.Components[k] = v
+	}
+	c.Names = make(map[string]uint8, len(r.Names))
+	for k, v := range r.Names {
+		c.Names[k] = v
+	}
+	c.Types = append([]reflect.Type(nil), r.Types...)
+	c.IDs = append([]uint8(nil), r.IDs...)
+	return c
+}
+
+// clone creates a copy of the cache, with the same filters, but without archetypes and callback.
+func (c *Cache) clone() Cache {
+	cp := newCache()
+	cp.stableOrder = c.stableOrder
+	cp.intPool = intPool[uint32]{
+		pool:              append([]uint32(nil), c.intPool.pool...),
+		next:              c.intPool.next,
+		available:         c.intPool.available,
+		capacityIncrement: c.intPool.capacityIncrement,
+	}
+	for k, v := range c.indices {
+		cp.indices[k] = v
+	}
+	for _, e := range c.filters {
+		entry := cacheEntry{
+			ID:     e.ID,
+			Filter: e.Filter,
+			Label:  e.Label,
+		}
+		if e.Indices != nil {
+			entry.Indices = map[*archetype]int{}
+		}
+		if e.Stats != nil {
+			entry.Stats = &queryStats{}
+		}
+		cp.filters = append(cp.filters, entry)
+	}
+	return cp
+}
+
+// clone creates a deep copy of the tracker.
+func (t *lifetimeTracker) clone() *lifetimeTracker {
+	c := *t
+	c.births = append([]uint64(nil), t.births...)
+	c.buckets = append([]int(nil), t.buckets...)
+	return &c
+}
+
+// clone creates a deep copy of the tracker.
+func (t *changeTracker) clone() *changeTracker {
+	c := *t
+	c.ids = append([]ID(nil), t.ids...)
+	c.added = cloneTicks(t.added)
+	c.changed = cloneTicks(t.changed)
+	c.removed = cloneTicks(t.removed)
+	return &c
+}
+
+// cloneTicks creates a deep copy of per-component tick storage.
+func cloneTicks(ticks [][]uint64) [][]uint64 {
+	c := make([][]uint64, len(ticks))
+	for i, t := range ticks {
+		c[i] = append([]uint64(nil), t...)
+	}
+	return c
+}
+
+// clone creates a deep copy of the tracker.
+func (t *quotaTracker) clone() *quotaTracker {
+	c := &quotaTracker{
+		byName:  make(map[string]*quota, len(t.byName)),
+		origins: make([]*quota, len(t.origins)),
+	}
+	remap := make(map[*quota]*quota, len(t.byName))
+	for name, q := range t.byName {
+		cp := *q
+		cp.fifo = append([]Entity(nil), q.fifo...)
+		c.byName[name] = &cp
+		remap[q] = &cp
+	}
+	for i, q := range t.origins {
+		if q != nil {
+			c.origins[i] = remap[q]
+		}
+	}
+	return c
+}
@@ -0,0 +1,226 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldCloneEntity(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	labelID := ComponentID[label](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+	w.SetListener(&listener)
+
+	parent := w.NewEntity()
+	e := w.NewEntityWith(Component{ID: posID, Comp: &Position{X: 1, Y: 2}}, Component{ID: velID, Comp: &Velocity{X: 3, Y: 4}}, Component{ID: labelID, Comp: &label{}})
+	clone := w.CloneEntity(e)
+
+	assert.NotEqual(t, e, clone)
+	assert.Equal(t, w.Ids(e), w.Ids(clone))
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(clone, posID)))
+	assert.Equal(t, Velocity{X: 3, Y: 4}, *(*Velocity)(w.Get(clone, velID)))
+
+	(*Position)(w.Get(clone, posID)).X = 100
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e, posID)))
+
+	assert.Equal(t, 3, len(events))
+	mask := All(posID, velID, labelID)
+	assert.Equal(t, EntityEvent{
+		Entity:     clone,
+		Added:      mask,
+		AddedIDs:   []ID{posID, velID, labelID},
+		EventTypes: event.EntityCreated | event.ComponentAdded,
+		Sequence:   3,
+	}, events[2])
+
+	child := NewBuilder(&w, posID, relID).WithRelation(relID).New(parent)
+	clone = w.CloneEntity(child)
+	assert.Equal(t, parent, w.Relations().Get(clone, relID))
+	assert.Equal(t, event.EntityCreated|event.ComponentAdded|event.RelationChanged|event.TargetChanged, events[len(events)-1].EventTypes)
+
+	empty := w.NewEntity()
+	clone = w.CloneEntity(empty)
+	assert.Equal(t, 0, len(w.Ids(clone)))
+
+	w.Disable(e)
+	clone = w.CloneEntity(e)
+	assert.False(t, w.IsDisabled(clone))
+
+	w.RemoveEntity(empty)
+	assert.PanicsWithValue(t, "can't clone a dead entity", func() { w.CloneEntity(empty) })
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.CloneEntity(e) })
+	query.Close()
+}
+
+func TestWorldCloneEntityDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(2))
+	posID := ComponentID[Position](&w)
+
+	e := w.NewEntityWith(Component{ID: posID, Comp: &Position{X: 5}})
+	w.RemoveEntity(e)
+	assert.True(t, w.IsDying(e))
+
+	clone := w.CloneEntity(e)
+	assert.False(t, w.IsDying(clone))
+	assert.Equal(t, []ID{posID}, w.Ids(clone))
+	assert.Equal(t, Position{X: 5}, *(*Position)(w.Get(clone, posID)))
+}
+
+func TestBatchCloneEntities(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	query := NewBuilder(&w, posID).NewBatchQ(3)
+	i := 0
+	for query.Next() {
+		(*Position)(query.Get(posID)).X = i
+		i++
+	}
+	w.NewEntity(velID)
+
+	assert.Equal(t, 6, w.Batch().CloneEntities(All(posID), 2))
+	assert.Equal(t, 9, countEntities(&w, All(posID)))
+	assert.Equal(t, 1, countEntities(&w, All(velID)))
+
+	sums := map[int]int{}
+	query = w.Query(All(posID))
+	for query.Next() {
+		sums[(*Position)(query.Get(posID)).X]++
+	}
+	assert.Equal(t, map[int]int{0: 3, 1: 3, 2: 3}, sums)
+
+	assert.PanicsWithValue(t, "can only create a positive number of clones", func() {
+		w.Batch().CloneEntities(All(posID), 0)
+	})
+}
+
+func TestWorldClone(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(2).WithCapacityIncrement(16))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	resID := AddResource(&w, &Position{X: 7})
+	tagID := TagID(&w, "enemy")
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+	query := NewBuilder(&w, posID, velID).NewBatchQ(50)
+	i := 0
+	for query.Next() {
+		(*Position)(query.Get(posID)).X = i
+		i++
+	}
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(5, parent1)
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(7, parent2)
+	tagged := w.NewEntity(posID)
+	w.AddTag(tagged, "enemy")
+
+	toRemove := queryEntities(&w, All(velID))[:10]
+	for _, e := range toRemove[:5] {
+		w.RemoveEntity(e)
+	}
+	w.Tick()
+	w.Tick()
+	for _, e := range toRemove[5:] {
+		w.RemoveEntity(e)
+	}
+	disabled := queryEntities(&w, All(velID))[0]
+	w.Disable(disabled)
+
+	filter := All(posID)
+	cached := w.Cache().Register(&filter)
+	relFilter := NewRelationFilter(All(relID), parent2)
+	cachedRel := w.Cache().Register(&relFilter)
+
+	c := w.Clone()
+
+	assert.Equal(t, w.DumpEntities(), c.DumpEntities())
+	assert.Equal(t, posID, ComponentID[Position](c))
+	assert.Equal(t, resID, ResourceID[Position](c))
+	assert.Equal(t, tagID, TagID(c, "enemy"))
+	assert.True(t, c.HasTag(tagged, "enemy"))
+
+	q1 := w.Query(&cached)
+	q2 := c.Query(&cached)
+	assert.Equal(t, q1.Count(), q2.Count())
+	for q1.Next() {
+		assert.True(t, q2.Next())
+		assert.Equal(t, q1.Entity(), q2.Entity())
+		assert.Equal(t, *(*Position)(q1.Get(posID)), *(*Position)(q2.Get(posID)))
+	}
+	assert.False(t, q2.Next())
+
+	q2 = c.Query(&cachedRel)
+	assert.Equal(t, 7, q2.Count())
+	q2.Close()
+	assert.Equal(t, parent1, c.Relations().Get(queryEntities(c, All(relID))[0], relID))
+
+	assert.True(t, c.IsDisabled(disabled))
+	assert.True(t, c.IsDying(toRemove[5]))
+	assert.False(t, c.Alive(toRemove[0]))
+	assert.Equal(t, 5, countEntities(c, c.Dying(All())))
+
+	res := c.Resources().Get(resID).(*Position)
+	assert.Equal(t, Position{X: 7}, *res)
+	res.X = 100
+	assert.Equal(t, Position{X: 7}, *w.Resources().Get(resID).(*Position))
+
+	e := queryEntities(c, All(velID))[1]
+	(*Position)(c.Get(e, posID)).X = -1
+	assert.NotEqual(t, -1, (*Position)(w.Get(e, posID)).X)
+
+	e1 := c.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	assert.Equal(t, e2, e1)
+	c.RemoveEntity(parent2)
+	assert.True(t, w.Alive(parent2))
+
+	c.Tick()
+	c.Tick()
+	assert.Equal(t, 0, countEntities(c, c.Dying(All())))
+	assert.Equal(t, 5, countEntities(&w, w.Dying(All())))
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Clone() })
+	query.Close()
+}
+
+func TestWorldCloneTrackers(t *testing.T) {
+	w := NewWorld(NewConfig().WithEntityLifetimes(true))
+	posID := ComponentID[Position](&w)
+	w.TrackChanges(posID)
+	w.SetQuota("units", 3, QuotaPanic)
+
+	builder := NewBuilder(&w, posID).WithOrigin("units")
+	builder.NewBatch(2)
+	w.Tick()
+	e := w.NewEntity(posID)
+
+	c := w.Clone()
+	assert.Equal(t, w.EntityTick(e), c.EntityTick(e))
+	assert.Equal(t, 2, c.QuotaCount("units"))
+
+	NewBuilder(c, posID).WithOrigin("units").New()
+	assert.Equal(t, 3, c.QuotaCount("units"))
+	assert.Equal(t, 2, w.QuotaCount("units"))
+	assert.Panics(t, func() { NewBuilder(c, posID).WithOrigin("units").New() })
+
+	q1 := w.Query(NewChangeFilter(All(posID)).Added(posID))
+	q2 := c.Query(NewChangeFilter(All(posID)).Added(posID))
+	assert.Equal(t, 3, q1.Count())
+	assert.Equal(t, 4, q2.Count())
+	q1.Close()
+	q2.Close()
+}
@@ -0,0 +1,91 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Entities collects the entities of all remaining matches of the query into a new slice.
+// Iterates the query in one pass and closes it.
+//
+// The returned slice is a copy and stays valid after the query is closed
+// and the world is modified.
+func (q *Query) Entities() []Entity {
+	entities := make([]Entity, 0, q.collectCapacity())
+	for q.NextChunk() {
+		end := q.entityIndex + q.columnLen()
+		for i := q.entityIndex; i < end; i++ {
+			entities = append(entities, q.access.GetEntity(i))
+		}
+	}
+	return entities
+}
+
+// QueryToSlice copies the values of the given component for all remaining matches of the query into a new slice.
+// Iterates the query in one pass and closes it.
+// Values are copied column-wise, chunk by chunk.
+//
+// The returned slice is a copy and stays valid after the query is closed and the world is modified.
+// This is useful for handing data to non-ECS code like renderers or network encoders.
+//
+// Panics if T is not the type of the component,
+// or if a matched entity does not have the component.
+// The query is closed in both cases.
+func QueryToSlice[T any](q *Query, comp ID) []T {
+	checkCollectType[T](q, comp)
+	values := make([]T, 0, q.collectCapacity())
+	for q.NextChunk() {
+		values = append(values, collectColumn[T](q, comp)...)
+	}
+	return values
+}
+
+// Collect2 copies the values of two components for all remaining matches of the query into new slices.
+// Iterates the query in one pass and closes it.
+// Elements at the same index belong to the same entity.
+//
+// See [QueryToSlice] for details.
+func Collect2[A any, B any](q *Query, compA ID, compB ID) ([]A, []B) {
+	checkCollectType[A](q, compA)
+	checkCollectType[B](q, compB)
+	capacity := q.collectCapacity()
+	valuesA := make([]A, 0, capacity)
+	valuesB := make([]B, 0, capacity)
+	for q.NextChunk() {
+		valuesA = append(valuesA, collectColumn[A](q, compA)...)
+		valuesB = append(valuesB, collectColumn[B](q, compB)...)
+	}
+	return valuesA, valuesB
+}
+
+// collectCapacity returns the initial capacity for collecting the query's entities.
+func (q *Query) collectCapacity() int {
+	if q.isPaged || q.changes != nil {
+		return 0
+	}
+	return q.Count()
+}
+
+// checkCollectType panics if T is not the type of the given component, and closes the query in that case.
+func checkCollectType[T any](q *Query, comp ID) {
+	if tp, _ := q.world.registry.ComponentType(comp.id); tp != reflect.TypeOf((*T)(nil)).Elem() {
+		q.Close()
+		panic(fmt.Sprintf("component with ID %d is of type %v, not %v", comp.id, tp, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+}
+
+// collectColumn returns the slice of a component for the current run of the query.
+// Closes the query and panics if the archetype does not contain the component.
+func collectColumn[T any](q *Query, comp ID) []T {
+	if !q.access.HasComponent(comp) {
+		tp, _ := q.world.registry.ComponentType(comp.id)
+		q.Close()
+		panic(fmt.Sprintf("entity has no component %v", tp))
+	}
+	col := ColumnSlice[T](q, comp)
+	if col == nil {
+		// Zero-sized components have no storage.
+		return make([]T, q.columnLen())
+	}
+	return col
+}
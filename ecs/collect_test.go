@@ -0,0 +1,77 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCollect(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	labelID := ComponentID[label](&w)
+
+	all := []Entity{}
+	for i := 0; i < 5; i++ {
+		e := w.NewEntity(posID, velID)
+		(*Position)(w.Get(e, posID)).X = i
+		(*Velocity)(w.Get(e, velID)).X = 10 * i
+		all = append(all, e)
+	}
+	for i := 5; i < 8; i++ {
+		e := w.NewEntity(posID, velID, labelID)
+		(*Position)(w.Get(e, posID)).X = i
+		(*Velocity)(w.Get(e, velID)).X = 10 * i
+		all = append(all, e)
+	}
+	w.NewEntity(posID)
+
+	query := w.Query(All(posID, velID))
+	assert.Equal(t, all, query.Entities())
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(All(posID, velID))
+	pos := QueryToSlice[Position](&query, posID)
+	assert.False(t, w.IsLocked())
+	assert.Equal(t, 8, len(pos))
+	for i, p := range pos {
+		assert.Equal(t, i, p.X)
+	}
+
+	query = w.Query(All(posID, velID))
+	pos, vel := Collect2[Position, Velocity](&query, posID, velID)
+	assert.False(t, w.IsLocked())
+	assert.Equal(t, len(pos), len(vel))
+	for i := range pos {
+		assert.Equal(t, i, pos[i].X)
+		assert.Equal(t, 10*i, vel[i].X)
+	}
+
+	// Values are copies.
+	pos[0].X = 100
+	assert.Equal(t, 0, (*Position)(w.Get(all[0], posID)).X)
+
+	query = w.Query(All(labelID))
+	labels := QueryToSlice[label](&query, labelID)
+	assert.Equal(t, 3, len(labels))
+
+	query = w.Query(All(posID, velID))
+	query.Skip(3)
+	query.Limit(4)
+	assert.Equal(t, all[3:7], query.Entities())
+
+	w.Disable(all[1])
+	query = w.Query(All(posID))
+	pos = QueryToSlice[Position](&query, posID)
+	assert.Equal(t, 8, len(pos))
+	w.Enable(all[1])
+
+	query = w.Query(All(posID))
+	assert.Panics(t, func() { QueryToSlice[Velocity](&query, posID) })
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(All(posID))
+	assert.PanicsWithValue(t, "entity has no component ecs.Velocity", func() { QueryToSlice[Velocity](&query, velID) })
+	assert.False(t, w.IsLocked())
+}
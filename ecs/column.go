@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Column provides raw access to the contiguous storage of a component in a chunk of an archetype,
+// for use in cgo or SIMD kernels.
+//
+// Archetypes store their components in chunks of a fixed number of entities.
+// Each chunk is contiguous, but different chunks are not.
+// Thus, an archetype's entities are processed column by column, using [Query.NextChunk].
+//
+// Get it with [Query.Column].
+//
+// ⚠️ Warning: The memory is only valid as long as the query is not closed,
+// as only the lock of the [World] prevents it from being moved.
+// Accessing elements outside of [0, Len) results in undefined behavior!
+type Column struct {
+	Pointer  unsafe.Pointer // Pointer to the first element.
+	ItemSize uintptr        // Size of an element, in bytes. Also the stride between elements.
+	Len      int            // Number of elements.
+}
+
+// Get returns a pointer to the element at the given index.
+//
+// Does not check bounds.
+func (c Column) Get(index int) unsafe.Pointer {
+	return unsafe.Add(c.Pointer, c.ItemSize*uintptr(index))
+}
+
+// Column returns raw access to a component's storage for the archetype at the iterator's current position.
+// The column starts at the current entity and covers all remaining entities of the current chunk.
+// For all components, the columns cover the same entities.
+//
+// Returns a zero [Column] if the archetype does not contain the component.
+//
+// Use together with [Query.NextChunk] to process entities chunk by chunk:
+//
+//	query := world.Query(All(posID))
+//	for query.NextChunk() {
+//		col := query.Column(posID)
+//		process(col.Pointer, col.ItemSize, col.Len)
+//	}
+func (q *Query) Column(comp ID) Column {
+	q.checkGet()
+	if !q.access.HasComponent(comp) {
+		return Column{}
+	}
+	lay := q.access.getLayout(comp)
+	return Column{
+		Pointer:  lay.Get(q.entityIndex),
+		ItemSize: uintptr(lay.itemSize),
+		Len:      int(q.columnLen()),
+	}
+}
+
+// columnLen returns the number of entities from the current one to the end of its chunk or run.
+func (q *Query) columnLen() uint32 {
+	return q.access.entities.run(q.entityIndex, q.entityIndexMax-q.entityIndex+1)
+}
+
+// ColumnSlice returns a component's storage for the archetype at the iterator's current position, as a slice.
+// The slice starts at the current entity and covers all remaining entities of the current chunk.
+// See [Query.Column] for details.
+//
+// Loops over the slice let the compiler eliminate bounds checks and vectorize numeric code,
+// which avoids the per-entity overhead of [Query.Get].
+//
+// Returns nil if the archetype does not contain the component.
+//
+// Panics if T is not the type of the component.
+//
+// ⚠️ Warning: The slice is only valid as long as the query is not closed,
+// and until the query proceeds to the next chunk.
+//
+// Example:
+//
+//	query := world.Query(All(posID, velID))
+//	for query.NextChunk() {
+//		pos := ColumnSlice[Position](&query, posID)
+//		vel := ColumnSlice[Velocity](&query, velID)
+//		for i := range pos {
+//			pos[i].X += vel[i].X
+//		}
+//	}
+func ColumnSlice[T any](q *Query, comp ID) []T {
+	if tp, _ := q.world.registry.ComponentType(comp.id); tp != reflect.TypeOf((*T)(nil)).Elem() {
+		panic(fmt.Sprintf("component with ID %d is of type %v, not %v", comp.id, tp, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	col := q.Column(comp)
+	if col.Pointer == nil {
+		return nil
+	}
+	return unsafe.Slice((*T)(col.Pointer), col.Len)
+}
+
+// NextChunk proceeds to the first [Entity] of the next chunk of the current archetype,
+// or of the next non-empty archetype in the Query,
+// skipping all remaining entities of the current chunk.
+// Thus, it visits the same entities as the columns returned by [Query.Column].
+//
+// Archetypes usually consist of several chunks. See [Config.CapacityIncrement] for chunk sizes.
+//
+// Returns false if no next chunk could be found.
+// The query is closed in this case.
+//
+// See [Query.Column] for an example.
+func (q *Query) NextChunk() bool {
+	q.checkNext()
+	if q.archetype != nil {
+		if n := q.columnLen(); q.entityIndex+n <= q.entityIndexMax {
+			q.entityIndex += n
+			return true
+		}
+	}
+	return q.nextArchetype()
+}
@@ -0,0 +1,152 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryColumn(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	NewBuilder(&w, posID).NewBatch(10)
+	NewBuilder(&w, posID, velID).NewBatch(5)
+	w.NewEntity(velID)
+
+	query := w.Query(All(posID))
+	archetypes := 0
+	total := 0
+	for query.NextChunk() {
+		archetypes++
+		col := query.Column(posID)
+		assert.Equal(t, uintptr(16), col.ItemSize)
+		for i := 0; i < col.Len; i++ {
+			pos := (*Position)(col.Get(i))
+			pos.X = i + 1
+		}
+		total += col.Len
+
+		velCol := query.Column(velID)
+		if query.Has(velID) {
+			assert.Equal(t, col.Len, velCol.Len)
+		} else {
+			assert.Equal(t, Column{}, velCol)
+		}
+	}
+	assert.Equal(t, 2, archetypes)
+	assert.Equal(t, 15, total)
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(All(posID))
+	sum := 0
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		sum += pos.X
+	}
+	assert.Equal(t, 55+15, sum)
+
+	// Column starts at the current entity.
+	query = w.Query(All(posID, velID))
+	assert.True(t, query.Next())
+	assert.True(t, query.Next())
+	col := query.Column(posID)
+	assert.Equal(t, 4, col.Len)
+	assert.Equal(t, 2, (*Position)(col.Get(0)).X)
+	query.Close()
+
+	// Batch queries.
+	query = NewBuilder(&w, posID).NewBatchQ(3)
+	assert.True(t, query.NextChunk())
+	col = query.Column(posID)
+	assert.Equal(t, 3, col.Len)
+	assert.Equal(t, 0, (*Position)(col.Get(0)).X)
+	assert.False(t, query.NextChunk())
+}
+
+func TestQueryColumnChunks(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(4))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	query := NewBuilder(&w, posID, velID).NewBatchQ(10)
+	for i := 0; query.Next(); i++ {
+		(*Position)(query.Get(posID)).X = i
+	}
+	entities := queryEntities(&w, All(posID))
+	w.Disable(entities[5])
+
+	query = w.Query(All(posID, velID))
+	lengths := []int{}
+	xs := []int{}
+	for query.NextChunk() {
+		pos := ColumnSlice[Position](&query, posID)
+		assert.Equal(t, len(pos), query.Column(velID).Len)
+		lengths = append(lengths, len(pos))
+		for _, p := range pos {
+			xs = append(xs, p.X)
+		}
+	}
+	assert.Equal(t, []int{4, 1, 2, 2}, lengths)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 6, 7, 8, 9}, xs)
+
+	query = w.Query(All(posID))
+	assert.Equal(t, 9, len(query.Entities()))
+	query = w.Query(All(posID))
+	assert.Equal(t, 9, len(QueryToSlice[Position](&query, posID)))
+
+	query = w.Query(All(posID))
+	query.Skip(2)
+	query.Limit(5)
+	lengths = lengths[:0]
+	for query.NextChunk() {
+		lengths = append(lengths, query.Column(posID).Len)
+	}
+	assert.Equal(t, []int{2, 1, 2}, lengths)
+}
+
+func TestColumnSlice(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	labelID := ComponentID[label](&w)
+
+	query := NewBuilder(&w, posID, velID, labelID).NewBatchQ(10)
+	for i := 0; query.Next(); i++ {
+		(*Velocity)(query.Get(velID)).X = i
+	}
+	NewBuilder(&w, posID).NewBatch(5)
+
+	query = w.Query(All(posID))
+	total := 0
+	for query.NextChunk() {
+		pos := ColumnSlice[Position](&query, posID)
+		vel := ColumnSlice[Velocity](&query, velID)
+		total += len(pos)
+		if !query.Has(velID) {
+			assert.Nil(t, vel)
+			continue
+		}
+		assert.Equal(t, len(pos), len(vel))
+		assert.Equal(t, len(pos), len(ColumnSlice[label](&query, labelID)))
+		for i := range pos {
+			pos[i].X += vel[i].X
+		}
+	}
+	assert.Equal(t, 15, total)
+
+	query = w.Query(All(posID, velID))
+	for i := 0; query.Next(); i++ {
+		assert.Equal(t, i, (*Position)(query.Get(posID)).X)
+	}
+
+	query = w.Query(All(posID))
+	query.Next()
+	query.Next()
+	assert.Equal(t, 4, len(ColumnSlice[Position](&query, posID)))
+	assert.PanicsWithValue(t, "component with ID 0 is of type ecs.Position, not ecs.Velocity", func() {
+		ColumnSlice[Velocity](&query, posID)
+	})
+	query.Close()
+}
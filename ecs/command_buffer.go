@@ -0,0 +1,214 @@
+package ecs
+
+import (
+	"reflect"
+)
+
+// commandKind is the type of a recorded command.
+type commandKind uint8
+
+const (
+	cmdNewEntity commandKind = iota
+	cmdNewEntityWith
+	cmdRemoveEntity
+	cmdExchange
+	cmdAssign
+	cmdSet
+	cmdSetRelation
+)
+
+// command is a recorded structural change.
+type command struct {
+	kind   commandKind
+	entity Entity
+	id     ID
+	target Entity
+	add    []ID
+	rem    []ID
+	comps  []Component
+}
+
+// CommandBuffer records structural changes to a [World], to apply them later.
+//
+// Structural changes like entity creation and removal, or adding and removing components,
+// are not possible while the world is locked by a [Query].
+// A CommandBuffer records such operations during query iteration,
+// and applies them in recording order with [CommandBuffer.Flush] after the query is closed.
+//
+// Component values given as [Component] pointers are copied at recording time.
+//
+// Create a CommandBuffer with [NewCommandBuffer], or use the world's buffer from [World.Commands],
+// which is flushed automatically.
+//
+// Commands are validated only when they are applied.
+// Thus, [CommandBuffer.Flush] panics in the same situations as the respective [World] methods,
+// e.g. when an entity was removed before a command on it is applied.
+type CommandBuffer struct {
+	world    *World
+	commands []command
+	flushing bool
+}
+
+// NewCommandBuffer creates a new [CommandBuffer] for a world.
+//
+// The buffer needs to be flushed manually using [CommandBuffer.Flush].
+// See also [World.Commands] for a buffer that is flushed automatically.
+func NewCommandBuffer(w *World) *CommandBuffer {
+	return &CommandBuffer{world: w}
+}
+
+// Commands returns the world's [CommandBuffer].
+//
+// The buffer is flushed automatically when the world becomes unlocked by closing a [Query],
+// i.e. when the last open query finishes iteration or is closed.
+// It can also be flushed manually with [CommandBuffer.Flush].
+func (w *World) Commands() *CommandBuffer {
+	if w.commands == nil {
+		w.commands = NewCommandBuffer(w)
+	}
+	return w.commands
+}
+
+// NewEntity records the creation of an entity with the given components.
+//
+// See [World.NewEntity].
+func (b *CommandBuffer) NewEntity(comps ...ID) {
+	b.commands = append(b.commands, command{kind: cmdNewEntity, add: copyIDs(comps)})
+}
+
+// NewEntityWith records the creation of an entity with the given component values.
+// Component values are copied immediately.
+//
+// See [World.NewEntityWith].
+func (b *CommandBuffer) NewEntityWith(comps ...Component) {
+	b.commands = append(b.commands, command{kind: cmdNewEntityWith, comps: copyComponents(comps)})
+}
+
+// RemoveEntity records the removal of an entity.
+//
+// See [World.RemoveEntity].
+func (b *CommandBuffer) RemoveEntity(entity Entity) {
+	b.commands = append(b.commands, command{kind: cmdRemoveEntity, entity: entity})
+}
+
+// Add records the addition of components to an entity.
+//
+// See [World.Add].
+func (b *CommandBuffer) Add(entity Entity, comps ...ID) {
+	b.commands = append(b.commands, command{kind: cmdExchange, entity: entity, add: copyIDs(comps)})
+}
+
+// Remove records the removal of components from an entity.
+//
+// See [World.Remove].
+func (b *CommandBuffer) Remove(entity Entity, comps ...ID) {
+	b.commands = append(b.commands, command{kind: cmdExchange, entity: entity, rem: copyIDs(comps)})
+}
+
+// Exchange records the addition and removal of components in one pass.
+//
+// See [World.Exchange].
+func (b *CommandBuffer) Exchange(entity Entity, add []ID, rem []ID) {
+	b.commands = append(b.commands, command{kind: cmdExchange, entity: entity, add: copyIDs(add), rem: copyIDs(rem)})
+}
+
+// Assign records the assignment of component values to an entity.
+// Component values are copied immediately.
+//
+// See [World.Assign].
+func (b *CommandBuffer) Assign(entity Entity, comps ...Component) {
+	b.commands = append(b.commands, command{kind: cmdAssign, entity: entity, comps: copyComponents(comps)})
+}
+
+// Set records overwriting a component of an entity.
+// The component value is copied immediately.
+//
+// Note that during query iteration, components can be modified directly,
+// so Set is mainly useful for entities that are created or modified by other commands.
+//
+// See [World.Set].
+func (b *CommandBuffer) Set(entity Entity, id ID, comp interface{}) {
+	b.commands = append(b.commands, command{kind: cmdSet, entity: entity, comps: copyComponents([]Component{{ID: id, Comp: comp}})})
+}
+
+// SetRelation records setting the target of an entity relation.
+//
+// See [Relations.Set].
+func (b *CommandBuffer) SetRelation(entity Entity, comp ID, target Entity) {
+	b.commands = append(b.commands, command{kind: cmdSetRelation, entity: entity, id: comp, target: target})
+}
+
+// Len returns the number of recorded commands.
+func (b *CommandBuffer) Len() int {
+	return len(b.commands)
+}
+
+// Reset discards all recorded commands.
+func (b *CommandBuffer) Reset() {
+	for i := range b.commands {
+		b.commands[i] = command{}
+	}
+	b.commands = b.commands[:0]
+}
+
+// Flush applies all recorded commands in recording order, and resets the buffer.
+//
+// Commands recorded during flushing (e.g. by a [Listener]) are applied as well.
+//
+// Panics when called on a locked world.
+// Further, panics in the same situations as the [World] methods the commands correspond to.
+func (b *CommandBuffer) Flush() {
+	if b.flushing {
+		return
+	}
+	b.world.checkLocked()
+	if b.world.hasReserved() {
+		b.world.realizeReserved()
+	}
+
+	b.flushing = true
+	defer func() { b.flushing = false }()
+
+	w := b.world
+	for i := 0; i < len(b.commands); i++ {
+		cmd := &b.commands[i]
+		switch cmd.kind {
+		case cmdNewEntity:
+			w.NewEntity(cmd.add...)
+		case cmdNewEntityWith:
+			w.NewEntityWith(cmd.comps...)
+		case cmdRemoveEntity:
+			w.RemoveEntity(cmd.entity)
+		case cmdExchange:
+			w.Exchange(cmd.entity, cmd.add, cmd.rem)
+		case cmdAssign:
+			w.Assign(cmd.entity, cmd.comps...)
+		case cmdSet:
+			c := cmd.comps[0]
+			w.Set(cmd.entity, c.ID, c.Comp)
+		case cmdSetRelation:
+			w.setRelation(cmd.entity, cmd.id, cmd.target)
+		}
+	}
+	b.Reset()
+}
+
+// copyIDs copies a slice of component IDs.
+func copyIDs(ids []ID) []ID {
+	if len(ids) == 0 {
+		return nil
+	}
+	return append([]ID{}, ids...)
+}
+
+// copyComponents copies components and their values.
+func copyComponents(comps []Component) []Component {
+	result := make([]Component, len(comps))
+	for i, c := range comps {
+		value := reflect.ValueOf(c.Comp).Elem()
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		result[i] = Component{ID: c.ID, Comp: ptr.Interface()}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandBuffer(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID, velID)
+	e3 := w.NewEntity(posID, relID)
+
+	cmd := NewCommandBuffer(&w)
+
+	query := w.Query(All(posID))
+	for query.Next() {
+		entity := query.Entity()
+		switch entity {
+		case e1:
+			pos := Position{X: 1}
+			cmd.Add(entity, velID)
+			cmd.Set(entity, posID, &pos)
+			pos.X = 100
+		case e2:
+			cmd.RemoveEntity(entity)
+		case e3:
+			cmd.SetRelation(entity, relID, parent)
+		}
+		cmd.NewEntityWith(Component{ID: velID, Comp: &Velocity{X: 5}})
+	}
+	cmd.NewEntity(posID)
+
+	assert.Equal(t, 8, cmd.Len())
+	assert.Equal(t, 4, countEntities(&w, All()))
+
+	cmd.Flush()
+
+	assert.Equal(t, 0, cmd.Len())
+	assert.True(t, w.Has(e1, velID))
+	assert.Equal(t, 1, (*Position)(w.Get(e1, posID)).X)
+	assert.False(t, w.Alive(e2))
+	assert.Equal(t, parent, w.Relations().Get(e3, relID))
+
+	exclVel := All(velID).Exclusive()
+	query = w.Query(&exclVel)
+	cnt := 0
+	for query.Next() {
+		assert.Equal(t, 5, (*Velocity)(query.Get(velID)).X)
+		cnt++
+	}
+	assert.Equal(t, 3, cnt)
+	assert.Equal(t, 7, countEntities(&w, All()))
+
+	cmd.Remove(e1, velID)
+	cmd.Exchange(e3, []ID{velID}, []ID{relID})
+	cmd.Assign(parent, Component{ID: posID, Comp: &Position{X: 3}})
+	cmd.Flush()
+
+	assert.False(t, w.Has(e1, velID))
+	assert.True(t, w.Has(e3, velID))
+	assert.False(t, w.Has(e3, relID))
+	assert.Equal(t, 3, (*Position)(w.Get(parent, posID)).X)
+
+	cmd.NewEntity(posID)
+	cmd.Reset()
+	cmd.Flush()
+	assert.Equal(t, 7, countEntities(&w, All()))
+
+	query = w.Query(All())
+	cmd.NewEntity()
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { cmd.Flush() })
+	query.Close()
+}
+
+func TestWorldCommands(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	NewBuilder(&w, posID).NewBatch(10)
+
+	cmd := w.Commands()
+	assert.Same(t, cmd, w.Commands())
+
+	outer := w.Query(All(posID))
+	for outer.Next() {
+		inner := w.Query(All(posID))
+		for inner.Next() {
+		}
+		cmd.RemoveEntity(outer.Entity())
+		assert.Equal(t, 10, countEntities(&w, All(posID)))
+	}
+	assert.Equal(t, 0, cmd.Len())
+	assert.Equal(t, 0, countEntities(&w, All(posID)))
+
+	query := w.Query(All())
+	cmd.NewEntity(posID)
+	query.Close()
+	assert.Equal(t, 1, countEntities(&w, All(posID)))
+
+	query = w.Query(All())
+	cmd.NewEntity(posID)
+	query.Close()
+	w.Reset()
+	assert.Equal(t, 0, cmd.Len())
+}
+
+func countEntities(w *World, filter Filter) int {
+	query := w.Query(filter)
+	cnt := query.Count()
+	query.Close()
+	return cnt
+}
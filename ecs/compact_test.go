@@ -0,0 +1,60 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldCompact(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(16))
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+	effectID := SparseComponentID[statusEffect](&w)
+
+	parent := w.NewEntity()
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(10, parent)
+
+	entities := []Entity{}
+	query := NewBuilder(&w, posID).NewBatchQ(1000)
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		pos.X = int(query.Entity().id)
+		entities = append(entities, query.Entity())
+	}
+	for _, e := range entities {
+		w.SparseSets().Add(e, effectID)
+	}
+	relFilter := NewRelationFilter(All(relID), parent)
+	w.Batch().RemoveEntities(&relFilter)
+	for _, e := range entities[20:] {
+		w.RemoveEntity(e)
+	}
+
+	arch := w.entities[entities[0].id].arch
+	assert.Equal(t, uint32(1008), arch.Cap())
+	assert.Equal(t, 1, len(w.relationNodes[len(w.relationNodes)-1].archetypeMap))
+
+	w.Compact()
+
+	assert.Equal(t, uint32(32), arch.Cap())
+	assert.Equal(t, 0, len(w.relationNodes[len(w.relationNodes)-1].archetypeMap))
+	assert.LessOrEqual(t, cap(w.entityPool.entities), len(w.entityPool.entities)+16)
+	assert.LessOrEqual(t, cap(w.entities), len(w.entities)+16)
+	assert.Equal(t, 20, w.SparseSets().Len(effectID))
+	assert.Equal(t, 20, w.sparse.sets[effectID.id].buffer.Len())
+
+	for _, e := range entities[:20] {
+		assert.Equal(t, Position{X: int(e.id)}, *(*Position)(w.Get(e, posID)))
+		assert.True(t, w.SparseSets().Has(e, effectID))
+	}
+	for _, e := range entities[20:] {
+		assert.False(t, w.Alive(e))
+	}
+
+	NewBuilder(&w, posID).NewBatch(100)
+	assert.Equal(t, uint32(120), w.entities[entities[0].id].arch.Len())
+
+	w.Compact()
+	assert.Equal(t, uint32(128), arch.Cap())
+}
@@ -0,0 +1,38 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterComponent registers a component type under the given name, and returns its [ID].
+// Registers the type if it is not already registered.
+//
+// Names allow editors, mod loaders and deserializers to resolve components by string,
+// using [World.ComponentIDByName], instead of via Go generics or types.
+// A type can be registered under multiple names, e.g. for aliases.
+// Registering the same name for the same type again is a no-op.
+// Runtime-defined types, like from [DynamicType], can be registered like any other type.
+//
+// Names are not affected by [World.Reset].
+//
+// Panics if the name is empty, if the name is already registered for another type,
+// or when registering a new type in a locked world.
+func (w *World) RegisterComponent(name string, tp reflect.Type) ID {
+	if name == "" {
+		panic("can't register a component with an empty name")
+	}
+	if id, ok := w.registry.Names[name]; ok && w.registry.Types[id] != tp {
+		panic(fmt.Sprintf("component name '%s' is already registered for type %s", name, w.registry.Types[id]))
+	}
+	id := w.componentID(tp)
+	w.registry.Names[name] = id.id
+	return id
+}
+
+// ComponentIDByName returns the [ID] of the component registered under the given name, and whether it was found.
+// See [World.RegisterComponent].
+func (w *World) ComponentIDByName(name string) (ID, bool) {
+	id, ok := w.registry.Names[name]
+	return ID{id: id}, ok
+}
@@ -0,0 +1,62 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldRegisterComponent(t *testing.T) {
+	w := NewWorld()
+	posType := reflect.TypeOf(Position{})
+	velType := reflect.TypeOf(Velocity{})
+
+	_, ok := w.ComponentIDByName("Position")
+	assert.False(t, ok)
+
+	posID := w.RegisterComponent("Position", posType)
+	assert.Equal(t, ComponentID[Position](&w), posID)
+	assert.Equal(t, posID, w.RegisterComponent("Position", posType))
+	assert.Equal(t, posID, w.RegisterComponent("Pos", posType))
+
+	velID := ComponentID[Velocity](&w)
+	assert.Equal(t, velID, w.RegisterComponent("Velocity", velType))
+
+	id, ok := w.ComponentIDByName("Position")
+	assert.True(t, ok)
+	assert.Equal(t, posID, id)
+	id, _ = w.ComponentIDByName("Pos")
+	assert.Equal(t, posID, id)
+	id, _ = w.ComponentIDByName("Velocity")
+	assert.Equal(t, velID, id)
+
+	healthID := w.RegisterComponent("Health", DynamicType("Health", DynamicField{"HP", reflect.TypeOf(0.0)}))
+	e := w.NewEntity(healthID)
+	id, _ = w.ComponentIDByName("Health")
+	assert.Nil(t, w.SetField(e, id, "HP", 10))
+
+	w.Reset()
+	_, ok = w.ComponentIDByName("Health")
+	assert.True(t, ok)
+
+	w2 := w.Clone()
+	id, ok = w2.ComponentIDByName("Velocity")
+	assert.True(t, ok)
+	assert.Equal(t, velID, id)
+
+	assert.PanicsWithValue(t, "component name 'Position' is already registered for type ecs.Position", func() {
+		w.RegisterComponent("Position", velType)
+	})
+	assert.PanicsWithValue(t, "can't register a component with an empty name", func() {
+		w.RegisterComponent("", velType)
+	})
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to register a new component in a locked world", func() {
+		w.RegisterComponent("Rotation", reflect.TypeOf(rotation{}))
+	})
+	query.Close()
+	_, ok = w.ComponentIDByName("Rotation")
+	assert.False(t, ok)
+}
@@ -4,10 +4,47 @@ package ecs
 type Config struct {
 	// Capacity increment for archetypes and the entity index.
 	// The default value is 128.
+	//
+	// Archetypes store components in chunks of up to CapacityIncrement entities,
+	// rounded down to a power of two, and limited to 16KB per chunk.
+	// Growing an archetype adds chunks, so components never move in memory.
 	CapacityIncrement int
 	// Capacity increment for archetypes with a relation component.
 	// The default value is CapacityIncrement.
 	RelationCapacityIncrement int
+	// Number of ticks removed entities stay in a dying state before their storage is reclaimed.
+	// The default value 0 disables soft-deletion. See [World.Dying] and [World.Tick].
+	RemovalGracePeriod int
+	// Number of consecutive [World.Maintain] calls an archetype must be under-used
+	// before its memory is reduced. The default value 0 disables idle-archetype decay.
+	IdleMaintenanceRuns int
+	// Whether to track creation ticks and lifetimes of entities.
+	// Lifetime statistics are reported by [World.Stats]. The default value is false.
+	EntityLifetimes bool
+	// Whether queries iterate in a stable order. The default value is false.
+	//
+	// By default, removing an entity from an archetype moves the archetype's last entity into the gap,
+	// and removing a relation archetype moves the last archetype of cached filters into the gap.
+	// With stable order, the remaining entities and archetypes are shifted instead.
+	// Thus, queries visit the entities of each archetype in order of their insertion into the archetype
+	// (i.e. their creation, or their last component change), and the archetypes of cached filters in order of creation.
+	// Removal from an archetype then takes time linear in the number of subsequent entities.
+	//
+	// Note that iteration order is always reproducible for the same sequence of operations.
+	// Stable order is meant for lockstep simulations and reproducible experiments
+	// that need an order which is independent of removals.
+	StableOrder bool
+	// Allocator for the memory of archetype component columns. The default value nil uses Go-managed memory.
+	//
+	// Only component types without Go pointers are allocated with the allocator. See [Allocator] for details.
+	Allocator Allocator
+	// Whether to instrument queries with pprof labels and runtime/trace regions. The default value is false.
+	//
+	// With profiling, CPU profiles attribute the time spent in query iteration to the label [ProfileQueryLabel],
+	// with the label of registered filters (see [Cache.RegisterLabel]) or the filter's component types as value.
+	// Execution traces contain a region per query. See also [World.Profile] for instrumenting further sections, like systems.
+	// Profiling adds some overhead to query creation, so it should only be enabled while profiling.
+	Profiling bool
 }
 
 // NewConfig creates a new default [World] configuration.
@@ -31,3 +68,45 @@ func (c Config) WithRelationCapacityIncrement(inc int) Config {
 	c.RelationCapacityIncrement = inc
 	return c
 }
+
+// WithRemovalGracePeriod return a new Config with RemovalGracePeriod set.
+// Use with method chaining.
+func (c Config) WithRemovalGracePeriod(ticks int) Config {
+	c.RemovalGracePeriod = ticks
+	return c
+}
+
+// WithIdleMaintenanceRuns return a new Config with IdleMaintenanceRuns set.
+// Use with method chaining.
+func (c Config) WithIdleMaintenanceRuns(runs int) Config {
+	c.IdleMaintenanceRuns = runs
+	return c
+}
+
+// WithEntityLifetimes return a new Config with EntityLifetimes set.
+// Use with method chaining.
+func (c Config) WithEntityLifetimes(enabled bool) Config {
+	c.EntityLifetimes = enabled
+	return c
+}
+
+// WithStableOrder return a new Config with StableOrder set.
+// Use with method chaining.
+func (c Config) WithStableOrder(enabled bool) Config {
+	c.StableOrder = enabled
+	return c
+}
+
+// WithAllocator return a new Config with Allocator set.
+// Use with method chaining.
+func (c Config) WithAllocator(alloc Allocator) Config {
+	c.Allocator = alloc
+	return c
+}
+
+// WithProfiling return a new Config with Profiling set.
+// Use with method chaining.
+func (c Config) WithProfiling(enabled bool) Config {
+	c.Profiling = enabled
+	return c
+}
@@ -0,0 +1,106 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// contextChunkSize is the number of entities processed by context-aware operations
+// between checks for cancellation.
+const contextChunkSize = 4096
+
+// ProgressError is returned by context-aware operations like [Builder.NewBatchCtx]
+// when the context is cancelled or times out before the operation is complete.
+//
+// It wraps the context's error, so check for cancellation using [errors.Is]
+// with [context.Canceled] or [context.DeadlineExceeded].
+type ProgressError struct {
+	Done  int   // Number of completed work items, like entities or snapshot archetypes.
+	Total int   // Total number of work items, or -1 if not known in advance.
+	Err   error // The context's error.
+}
+
+// Error returns the error message.
+func (e *ProgressError) Error() string {
+	if e.Total < 0 {
+		return fmt.Sprintf("%s after %d items", e.Err.Error(), e.Done)
+	}
+	return fmt.Sprintf("%s after %d of %d items", e.Err.Error(), e.Done, e.Total)
+}
+
+// Unwrap returns the context's error, for use with [errors.Is].
+func (e *ProgressError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatchCtx is like [Builder.NewBatch], but checks the context for cancellation
+// between chunks of entities. Returns the number of created entities.
+//
+// If the context is cancelled, the entities created so far are kept,
+// and a [ProgressError] with their number is returned.
+// With an origin set by [Builder.WithOrigin], fewer entities may be created without an error.
+//
+// Panics when called on a locked world.
+func (b *Builder) NewBatchCtx(ctx context.Context, count int, target ...Entity) (int, error) {
+	created := 0
+	for done := 0; done < count; done += contextChunkSize {
+		if err := ctx.Err(); err != nil {
+			return created, &ProgressError{Done: created, Total: count, Err: err}
+		}
+		n := count - done
+		if n > contextChunkSize {
+			n = contextChunkSize
+		}
+		query := b.NewBatchQ(n, target...)
+		created += query.Count()
+		query.Close()
+	}
+	return created, nil
+}
+
+// RemoveEntitiesCtx is like [Batch.RemoveEntities], but checks the context for cancellation
+// between chunks of entities. Returns the number of removed entities.
+//
+// Entities are removed individually, like with [World.RemoveEntity].
+// This is slower than [Batch.RemoveEntities], but allows for cancellation at any point.
+// If the context is cancelled, the entities removed so far stay removed,
+// and a [ProgressError] with their number is returned.
+//
+// Panics when called on a locked world.
+func (b *Batch) RemoveEntitiesCtx(ctx context.Context, filter Filter) (int, error) {
+	w := b.world
+	w.checkLocked()
+
+	removed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, &ProgressError{Done: removed, Total: -1, Err: err}
+		}
+		query := w.Query(filter)
+		query.withDisabled = true
+		query.Limit(contextChunkSize)
+		entities := query.Entities()
+		if len(entities) == 0 {
+			return removed, nil
+		}
+		for _, e := range entities {
+			// Entities may already be removed by cascades.
+			if !w.Alive(e) {
+				continue
+			}
+			w.RemoveEntity(e)
+			removed++
+		}
+	}
+}
+
+// SnapshotCtx is like [World.Snapshot], but checks the context for cancellation between archetypes.
+//
+// If the context is cancelled, the snapshot written so far is incomplete and can't be loaded.
+// A [ProgressError] with the number of archetypes written is returned in this case.
+//
+// Panics when called on a locked world.
+func (w *World) SnapshotCtx(ctx context.Context, out io.Writer) error {
+	return w.snapshot(ctx, out)
+}
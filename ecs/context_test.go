@@ -0,0 +1,96 @@
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderNewBatchCtx(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	builder := NewBuilder(&w, posID)
+	n, err := builder.NewBatchCtx(context.Background(), 2*contextChunkSize+10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*contextChunkSize+10, n)
+	assert.Equal(t, 2*contextChunkSize+10, countEntities(&w, All(posID)))
+	assert.False(t, w.IsLocked())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, err = builder.NewBatchCtx(ctx, 100)
+	assert.Equal(t, 0, n)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	var progress *ProgressError
+	assert.True(t, errors.As(err, &progress))
+	assert.Equal(t, ProgressError{Done: 0, Total: 100, Err: context.Canceled}, *progress)
+	assert.Equal(t, "context canceled after 0 of 100 items", err.Error())
+}
+
+func TestBatchRemoveEntitiesCtx(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	NewBuilder(&w, posID).NewBatch(contextChunkSize + 10)
+	NewBuilder(&w, posID, velID).NewBatch(20)
+	e := w.NewEntity(posID)
+	w.Disable(e)
+
+	n, err := w.Batch().RemoveEntitiesCtx(context.Background(), All(posID, velID))
+	assert.Nil(t, err)
+	assert.Equal(t, 20, n)
+	assert.Equal(t, 0, countEntities(&w, All(velID)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, err = w.Batch().RemoveEntitiesCtx(ctx, All(posID))
+	assert.Equal(t, 0, n)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "context canceled after 0 items", err.Error())
+
+	n, err = w.Batch().RemoveEntitiesCtx(context.Background(), All(posID))
+	assert.Nil(t, err)
+	assert.Equal(t, contextChunkSize+11, n)
+	assert.False(t, w.Alive(e))
+	assert.False(t, w.IsLocked())
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() {
+		_, _ = w.Batch().RemoveEntitiesCtx(context.Background(), All(posID))
+	})
+	query.Close()
+}
+
+func TestWorldSnapshotCtx(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	NewBuilder(&w, posID).NewBatch(10)
+	NewBuilder(&w, posID, velID).NewBatch(10)
+
+	var buf bytes.Buffer
+	assert.Nil(t, w.SnapshotCtx(context.Background(), &buf))
+
+	w2 := NewWorld()
+	ComponentID[Position](&w2)
+	ComponentID[Velocity](&w2)
+	assert.Nil(t, w2.LoadSnapshot(&buf))
+	assert.Equal(t, 20, countEntities(&w2, All(posID)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	err := w.SnapshotCtx(ctx, &buf)
+	assert.True(t, errors.Is(err, context.Canceled))
+	var progress *ProgressError
+	assert.True(t, errors.As(err, &progress))
+	assert.Equal(t, 0, progress.Done)
+	assert.Greater(t, progress.Total, 0)
+}
@@ -0,0 +1,244 @@
+package ecs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// csvColumn describes a CSV column, mapped to a component field.
+type csvColumn struct {
+	Name  string // Column header, like "Position.X".
+	Comp  int    // Index of the component in the list of exported/imported components.
+	Field []int  // Field index path, for use with [reflect.Value.FieldByIndex].
+}
+
+// ExportCSV writes the given components of all entities matching the filter to CSV.
+//
+// Each exported field of the components results in one column, with a header like "Position.X".
+// Nested structs are flattened, using dots to separate field names.
+// Fields of embedded structs are promoted like in Go, i.e. without the name of the embedded type.
+// Components without exported fields, like tags and relation markers, do not result in any columns.
+// Relation targets are not exported.
+//
+// Supported field kinds are booleans, integers, unsigned integers, floats and strings.
+// Returns an error if any component has exported fields of other kinds.
+//
+// Entities that don't have all the given components are skipped.
+func (w *World) ExportCSV(out io.Writer, filter Filter, comps ...ID) error {
+	columns, _, err := w.csvColumns(comps)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(out)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	values := make([]reflect.Value, len(comps))
+	required := All(comps...)
+	query := w.Query(filter)
+	for query.Next() {
+		mask := query.Mask()
+		if !mask.Contains(&required) {
+			continue
+		}
+		for i, id := range comps {
+			tp, _ := w.registry.ComponentType(id.id)
+			values[i] = reflect.NewAt(tp, query.Get(id)).Elem()
+		}
+		for i, col := range columns {
+			record[i] = formatCSVValue(values[col.Comp].FieldByIndex(col.Field))
+		}
+		if err := writer.Write(record); err != nil {
+			query.Close()
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportCSV creates entities with the given components from CSV data, and returns them.
+//
+// The CSV data must have a header row with columns as produced by [World.ExportCSV].
+// Columns may be in any order, and missing columns leave the respective fields at their zero value.
+// Returns an error for unknown columns or values that cannot be parsed.
+// In case of an error, no entities are created.
+//
+// Panics when called on a locked world.
+func (w *World) ImportCSV(in io.Reader, comps ...ID) ([]Entity, error) {
+	columns, types, err := w.csvColumns(comps)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*csvColumn, len(columns))
+	for i := range columns {
+		byName[columns[i].Name] = &columns[i]
+	}
+
+	reader := csv.NewReader(in)
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("missing CSV header")
+		}
+		return nil, err
+	}
+	mapping := make([]*csvColumn, len(header))
+	for i, name := range header {
+		col, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column '%s'", name)
+		}
+		mapping[i] = col
+	}
+
+	rows := [][]Component{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make([]Component, len(comps))
+		for i, id := range comps {
+			row[i] = Component{ID: id, Comp: reflect.New(types[i]).Interface()}
+		}
+		for i, value := range record {
+			col := mapping[i]
+			field := reflect.ValueOf(row[col.Comp].Comp).Elem().FieldByIndex(col.Field)
+			if err := parseCSVValue(value, field); err != nil {
+				line, _ := reader.FieldPos(i)
+				return nil, fmt.Errorf("invalid value for CSV column '%s' in line %d: %w", col.Name, line, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	w.checkLocked()
+	entities := make([]Entity, len(rows))
+	for i, row := range rows {
+		entities[i] = w.NewEntityWith(row...)
+	}
+	return entities, nil
+}
+
+// csvColumns creates the CSV column descriptions for the given components.
+func (w *World) csvColumns(comps []ID) ([]csvColumn, []reflect.Type, error) {
+	columns := []csvColumn{}
+	types := make([]reflect.Type, len(comps))
+	for i, id := range comps {
+		tp, ok := w.registry.ComponentType(id.id)
+		if !ok {
+			return nil, nil, fmt.Errorf("component with ID %d is not registered", id.id)
+		}
+		types[i] = tp
+		var err error
+		columns, err = appendCSVColumns(columns, i, tp.Name(), tp, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return columns, types, nil
+}
+
+// appendCSVColumns appends the CSV columns for all exported fields of a struct type.
+func appendCSVColumns(columns []csvColumn, comp int, prefix string, tp reflect.Type, index []int) ([]csvColumn, error) {
+	if tp.Kind() != reflect.Struct {
+		if !isCSVKind(tp.Kind()) {
+			return nil, fmt.Errorf("unsupported type %s for CSV column '%s'", tp, prefix)
+		}
+		return append(columns, csvColumn{Name: prefix, Comp: comp, Field: index}), nil
+	}
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		embedded := field.Anonymous && field.Type.Kind() == reflect.Struct
+		if !field.IsExported() && !embedded {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		name := prefix + "." + field.Name
+		if embedded {
+			name = prefix
+		}
+		var err error
+		columns, err = appendCSVColumns(columns, comp, name, field.Type, fieldIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// isCSVKind checks whether values of the given kind can be converted to and from CSV.
+func isCSVKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatCSVValue formats a field value for CSV.
+func formatCSVValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		return v.String()
+	}
+}
+
+// parseCSVValue parses a CSV value into a field.
+func parseCSVValue(s string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		v.SetString(s)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package ecs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type csvInner struct {
+	Name   string
+	Active bool
+}
+
+type csvComp struct {
+	csvInner
+	Inner  csvInner
+	Weight float32
+	Count  uint16
+	hidden int
+}
+
+type csvInvalid struct {
+	Values []int
+}
+
+func TestWorldExportCSV(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	csvID := ComponentID[csvComp](&w)
+
+	e1 := w.NewEntity(posID, csvID)
+	e2 := w.NewEntity(posID, csvID, velID)
+	w.NewEntity(posID)
+
+	*(*Position)(w.Get(e1, posID)) = Position{X: 1, Y: 2}
+	*(*csvComp)(w.Get(e1, csvID)) = csvComp{
+		csvInner: csvInner{Name: "a, b", Active: true},
+		Inner:    csvInner{Name: "inner"},
+		Weight:   0.5,
+		Count:    7,
+		hidden:   3,
+	}
+	*(*Position)(w.Get(e2, posID)) = Position{X: 3, Y: 4}
+
+	buf := bytes.Buffer{}
+	err := w.ExportCSV(&buf, All(posID), posID, csvID)
+	assert.Nil(t, err)
+	assert.False(t, w.IsLocked())
+	assert.Equal(t,
+		"Position.X,Position.Y,csvComp.Name,csvComp.Active,csvComp.Inner.Name,csvComp.Inner.Active,csvComp.Weight,csvComp.Count\n"+
+			"1,2,\"a, b\",true,inner,false,0.5,7\n"+
+			"3,4,,false,,false,0,0\n",
+		buf.String())
+
+	w2 := NewWorld()
+	posID2 := ComponentID[Position](&w2)
+	csvID2 := ComponentID[csvComp](&w2)
+	entities, err := w2.ImportCSV(&buf, posID2, csvID2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entities))
+
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w2.Get(entities[0], posID2)))
+	assert.Equal(t, csvComp{
+		csvInner: csvInner{Name: "a, b", Active: true},
+		Inner:    csvInner{Name: "inner"},
+		Weight:   0.5,
+		Count:    7,
+	}, *(*csvComp)(w2.Get(entities[0], csvID2)))
+	assert.Equal(t, Position{X: 3, Y: 4}, *(*Position)(w2.Get(entities[1], posID2)))
+
+	invID := ComponentID[csvInvalid](&w)
+	err = w.ExportCSV(&buf, All(), invID)
+	assert.EqualError(t, err, "unsupported type []int for CSV column 'csvInvalid.Values'")
+}
+
+func TestWorldImportCSV(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	labelID := ComponentID[label](&w)
+
+	entities, err := w.ImportCSV(strings.NewReader("Position.Y\n5\n6\n"), posID, labelID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entities))
+	assert.Equal(t, Position{X: 0, Y: 6}, *(*Position)(w.Get(entities[1], posID)))
+	assert.True(t, w.Has(entities[1], labelID))
+
+	_, err = w.ImportCSV(strings.NewReader("Position.Z\n5\n"), posID)
+	assert.EqualError(t, err, "unknown CSV column 'Position.Z'")
+
+	_, err = w.ImportCSV(strings.NewReader("Position.X\n5\nabc\n"), posID)
+	assert.EqualError(t, err, "invalid value for CSV column 'Position.X' in line 3: strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	_, err = w.ImportCSV(strings.NewReader(""), posID)
+	assert.EqualError(t, err, "missing CSV header")
+
+	query := w.Query(All(posID))
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() {
+		_, _ = w.ImportCSV(strings.NewReader("Position.X\n5\n"), posID)
+	})
+	query.Close()
+}
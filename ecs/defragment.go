@@ -0,0 +1,104 @@
+package ecs
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// Defragment re-packs the archetypes of entities matching the filter, to restore cache locality after heavy churn.
+//
+// Entities in each matching archetype are re-ordered, and the archetype's capacity is reduced
+// to the minimum required for its entities, according to the capacity increment.
+// Entities are sorted by the given less function, or by entity ID if it is nil.
+// The order of entities is stable for entities that compare equal.
+// Empty relation archetypes with a non-zero target are removed (de-activated for re-use).
+//
+// Entities keep their components, relations and disabled state, and no events are emitted.
+// Returns the number of entities that changed their position.
+//
+// The operation invalidates pointers to components obtained before.
+// The less function must not modify the world.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+//
+// Example:
+//
+//	world.Defragment(All(posID), func(a, b Entity) bool {
+//		return (*Position)(world.Get(a, posID)).X < (*Position)(world.Get(b, posID)).X
+//	})
+func (w *World) Defragment(filter Filter, less func(a, b Entity) bool) int {
+	w.checkLocked()
+
+	arches := append([]*archetype{}, w.getArchetypes(filter)...)
+	moved := 0
+	for _, arch := range arches {
+		moved += w.defragmentArchetype(arch, less)
+	}
+	return moved
+}
+
+// defragmentArchetype sorts the entities of an archetype and shrinks its capacity.
+func (w *World) defragmentArchetype(arch *archetype, less func(a, b Entity) bool) int {
+	if arch.len == 0 && arch.node.HasRelation && !arch.RelationTarget.IsZero() {
+		w.removeArchetype(arch)
+		return 0
+	}
+
+	order := make([]uint32, arch.len)
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	l := w.lock()
+	if less == nil {
+		sort.SliceStable(order, func(i, j int) bool {
+			return arch.GetEntity(order[i]).id < arch.GetEntity(order[j]).id
+		})
+	} else {
+		sort.SliceStable(order, func(i, j int) bool {
+			return less(arch.GetEntity(order[i]), arch.GetEntity(order[j]))
+		})
+	}
+	w.unlock(l)
+
+	moved := 0
+	for i, idx := range order {
+		if uint32(i) != idx {
+			moved++
+		}
+	}
+
+	inc := arch.node.capacityIncrement
+	required := capacityU32(arch.len, inc)
+	if required < inc {
+		required = inc
+	}
+
+	if moved > 0 {
+		entities := make([]Entity, arch.len)
+		for i, idx := range order {
+			entities[i] = arch.GetEntity(idx)
+		}
+		for _, id := range arch.node.Ids {
+			lay := arch.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			size := lay.itemSize
+			temp := make([]byte, arch.len*size)
+			for i, idx := range order {
+				copy(temp[uint32(i)*size:], unsafe.Slice((*byte)(lay.Get(idx)), size))
+			}
+			lay.setBytes(0, temp)
+		}
+		for i, entity := range entities {
+			arch.SetEntity(uint32(i), entity)
+			w.entities[entity.id].index = uint32(i)
+		}
+	}
+
+	if required < arch.cap {
+		arch.resize(required)
+	}
+	return moved
+}
@@ -0,0 +1,71 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldDefragment(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(8))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	entities := []Entity{}
+	for i := 0; i < 40; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = 100 - i
+		entities = append(entities, e)
+	}
+	for i := 0; i < 40; i += 2 {
+		w.RemoveEntity(entities[i])
+	}
+	for i := 0; i < 4; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = i
+	}
+	w.Disable(entities[1])
+	arch := w.entities[entities[1].id].arch
+	assert.Equal(t, uint32(40), arch.Cap())
+	assert.Equal(t, uint32(24), arch.Len())
+
+	moved := w.Defragment(All(posID), func(a, b Entity) bool {
+		return (*Position)(w.Get(a, posID)).X < (*Position)(w.Get(b, posID)).X
+	})
+	assert.Greater(t, moved, 0)
+	assert.Equal(t, uint32(24), arch.Cap())
+	assert.True(t, w.IsDisabled(entities[1]))
+
+	prev := -1
+	var i uint32
+	for i = 0; i < arch.Len(); i++ {
+		e := arch.GetEntity(i)
+		assert.Equal(t, i, w.entities[e.id].index)
+		x := (*Position)(w.Get(e, posID)).X
+		assert.Greater(t, x, prev)
+		prev = x
+	}
+
+	moved = w.Defragment(All(posID), nil)
+	assert.Greater(t, moved, 0)
+	for i = 1; i < arch.Len(); i++ {
+		assert.Less(t, arch.GetEntity(i-1).id, arch.GetEntity(i).id)
+	}
+	assert.Equal(t, 0, w.Defragment(All(posID), nil))
+	assert.Equal(t, 0, w.Defragment(All(velID), nil))
+
+	target := w.NewEntity()
+	e := NewBuilder(&w, relID).WithRelation(relID).New(target)
+	w.RemoveEntity(e)
+	assert.Equal(t, 1, len(w.getArchetypes(All(relID))))
+	w.Defragment(All(relID), nil)
+	assert.Equal(t, 0, len(w.getArchetypes(All(relID))))
+
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() {
+		w.Defragment(All(posID), func(a, b Entity) bool {
+			w.NewEntity()
+			return false
+		})
+	})
+}
@@ -0,0 +1,126 @@
+package ecs
+
+// Disable disables an [Entity], so that it is skipped by queries.
+//
+// Disabled entities stay in their archetype and keep their components, relations and storage location.
+// Thus, disabling and enabling entities does not cause any archetype changes,
+// and component pointers obtained with [World.Get] are not invalidated by it.
+// Disabled entities can still be accessed and modified through the [World], and can be removed.
+//
+// Disabled entities are skipped by all queries created with [World.Query],
+// including cached filters, relation filters and generic queries.
+// In archetypes with disabled entities, [Query.NextChunk] and [Query.Column]
+// operate on contiguous runs of enabled entities.
+// Batch operations and the queries they return still include disabled entities.
+//
+// Disabling does not emit [EntityEvent]s, and the disabled state is not stored by
+// [World.DumpEntities] or [World.Snapshot].
+//
+// Disabling an already disabled entity has no effect.
+//
+// Panics when called on a locked world or for a removed (and potentially recycled) entity.
+// Do not use during [Query] iteration!
+func (w *World) Disable(entity Entity) {
+	w.checkLocked()
+	if !w.entityPool.Alive(entity) {
+		panic("can't disable a dead entity")
+	}
+	if w.isDisabled(entity.id) {
+		return
+	}
+	w.setDisabled(entity, true)
+}
+
+// Enable re-enables an [Entity] that was disabled with [World.Disable].
+//
+// Enabling an entity that is not disabled has no effect.
+//
+// Panics when called on a locked world or for a removed (and potentially recycled) entity.
+// Do not use during [Query] iteration!
+func (w *World) Enable(entity Entity) {
+	w.checkLocked()
+	if !w.entityPool.Alive(entity) {
+		panic("can't enable a dead entity")
+	}
+	if !w.isDisabled(entity.id) {
+		return
+	}
+	w.setDisabled(entity, false)
+}
+
+// IsDisabled reports whether an [Entity] is disabled. See [World.Disable].
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) IsDisabled(entity Entity) bool {
+	if !w.entityPool.Alive(entity) {
+		panic("can't check disabled state of a dead entity")
+	}
+	return w.isDisabled(entity.id)
+}
+
+// isDisabled reports whether the entity with the given ID is disabled.
+func (w *World) isDisabled(id eid) bool {
+	return int(id) < len(w.disabled.data)*wordSize && w.disabled.Get(id)
+}
+
+// setDisabled changes the disabled state of an entity, and records the change.
+// The state must be different from the current one.
+func (w *World) setDisabled(entity Entity, disabled bool) {
+	arch := w.entities[entity.id].arch
+	if disabled {
+		w.disabled.ExtendTo(len(w.entities))
+		w.disabledCount++
+		arch.disabled++
+	} else {
+		w.disabledCount--
+		arch.disabled--
+	}
+	w.disabled.Set(entity.id, disabled)
+	if len(w.journals) > 0 {
+		kind := changeEnable
+		if disabled {
+			kind = changeDisable
+		}
+		w.record(change{kind: kind, entity: entity})
+	}
+}
+
+// moveDisabled updates the disabled counts of archetypes when an entity is moved between them.
+func (w *World) moveDisabled(id eid, from, to *archetype) {
+	if w.disabledCount > 0 && w.isDisabled(id) {
+		from.disabled--
+		to.disabled++
+	}
+}
+
+// removeDisabled clears the disabled state of a removed entity.
+// Updates the archetype's count if it is not nil.
+func (w *World) removeDisabled(id eid, arch *archetype) {
+	if w.disabledCount == 0 || !w.isDisabled(id) {
+		return
+	}
+	w.disabled.Set(id, false)
+	w.disabledCount--
+	if arch != nil {
+		arch.disabled--
+	}
+}
+
+// enabledEntity returns the entity at the given index among the enabled entities of an archetype.
+func (w *World) enabledEntity(arch *archetype, index uint32) Entity {
+	if arch.disabled == 0 {
+		return arch.GetEntity(index)
+	}
+	var i uint32
+	for i = 0; i < arch.len; i++ {
+		entity := arch.GetEntity(i)
+		if w.isDisabled(entity.id) {
+			continue
+		}
+		if index == 0 {
+			return entity
+		}
+		index--
+	}
+	panic("enabled entity index out of range")
+}
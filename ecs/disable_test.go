@@ -0,0 +1,155 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func queryEntities(w *World, filter Filter) []Entity {
+	entities := []Entity{}
+	query := w.Query(filter)
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	return entities
+}
+
+func TestWorldDisable(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	entities := make([]Entity, 6)
+	for i := range entities {
+		entities[i] = w.NewEntity(posID)
+	}
+	e0, e1, e2, e3, e4, e5 := entities[0], entities[1], entities[2], entities[3], entities[4], entities[5]
+
+	pos := (*Position)(w.Get(e1, posID))
+	pos.X = 10
+
+	w.Disable(e1)
+	w.Disable(e1)
+	w.Disable(e2)
+	w.Disable(e5)
+	assert.True(t, w.IsDisabled(e1))
+	assert.False(t, w.IsDisabled(e0))
+
+	assert.Equal(t, []Entity{e0, e3, e4}, queryEntities(&w, All(posID)))
+	assert.Equal(t, pos, (*Position)(w.Get(e1, posID)))
+	assert.Equal(t, 10, pos.X)
+
+	query := w.Query(All(posID))
+	assert.Equal(t, 3, query.Count())
+	assert.Equal(t, e0, query.EntityAt(0))
+	assert.Equal(t, e3, query.EntityAt(1))
+	assert.Equal(t, e4, query.EntityAt(2))
+	assert.True(t, query.Step(2))
+	assert.Equal(t, e3, query.Entity())
+	query.Close()
+
+	query = w.Query(All(posID))
+	runs := [][]Entity{}
+	for query.NextChunk() {
+		col := query.Column(posID)
+		run := []Entity{}
+		for i := 0; i < col.Len; i++ {
+			run = append(run, query.Entity())
+			if i < col.Len-1 {
+				query.Next()
+			}
+		}
+		runs = append(runs, run)
+	}
+	assert.Equal(t, [][]Entity{{e0}, {e3, e4}}, runs)
+
+	cached := w.Cache().Register(All(posID))
+	assert.Equal(t, []Entity{e0, e3, e4}, queryEntities(&w, &cached))
+
+	w.Add(e1, velID)
+	w.Add(e3, velID)
+	assert.True(t, w.IsDisabled(e1))
+	assert.Equal(t, []Entity{e3}, queryEntities(&w, All(posID, velID)))
+	assert.Equal(t, []Entity{e0, e4, e3}, queryEntities(&w, All(posID)))
+
+	w.RemoveEntity(e2)
+	assert.Equal(t, []Entity{e0, e4, e3}, queryEntities(&w, All(posID)))
+
+	w.Enable(e1)
+	w.Enable(e1)
+	assert.False(t, w.IsDisabled(e1))
+	assert.Equal(t, []Entity{e1, e3}, queryEntities(&w, All(posID, velID)))
+
+	w.Batch().Remove(All(posID, velID), velID)
+	assert.ElementsMatch(t, []Entity{e0, e4, e3, e1}, queryEntities(&w, All(posID)))
+
+	e6 := w.NewEntity(posID)
+	assert.False(t, w.IsDisabled(e6))
+	w.Batch().RemoveEntities(All(posID))
+	assert.Equal(t, 0, w.disabledCount)
+	assert.Equal(t, 0, len(queryEntities(&w, All())))
+
+	e7 := w.NewEntity(posID)
+	w.Disable(e7)
+	dump := w.DumpEntities()
+	assert.Equal(t, []uint32{uint32(e7.id)}, dump.Alive)
+
+	w.Reset()
+	assert.Equal(t, 0, w.disabledCount)
+	e7 = w.NewEntity(posID)
+	assert.False(t, w.IsDisabled(e7))
+
+	w.RemoveEntity(e7)
+	assert.PanicsWithValue(t, "can't disable a dead entity", func() { w.Disable(e7) })
+	assert.PanicsWithValue(t, "can't enable a dead entity", func() { w.Enable(e7) })
+	assert.PanicsWithValue(t, "can't check disabled state of a dead entity", func() { w.IsDisabled(e7) })
+
+	e8 := w.NewEntity(posID)
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Disable(e8) })
+	query.Close()
+}
+
+func TestWorldDisableRelation(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	e0 := w.NewEntity(posID, relID)
+	e1 := w.NewEntity(posID, relID)
+	w.Relations().Set(e0, relID, parent1)
+	w.Relations().Set(e1, relID, parent1)
+
+	w.Disable(e0)
+	filter := NewRelationFilter(All(relID), parent1)
+	assert.Equal(t, []Entity{e1}, queryEntities(&w, &filter))
+
+	w.Relations().Set(e0, relID, parent2)
+	filter = NewRelationFilter(All(relID), parent2)
+	assert.Equal(t, 0, len(queryEntities(&w, &filter)))
+
+	w.Relations().SetBatch(All(relID), relID, parent1)
+	filter = NewRelationFilter(All(relID), parent1)
+	assert.Equal(t, []Entity{e1}, queryEntities(&w, &filter))
+
+	w.Enable(e0)
+	assert.ElementsMatch(t, []Entity{e0, e1}, queryEntities(&w, &filter))
+}
+
+func TestWorldDisableDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(1))
+	posID := ComponentID[Position](&w)
+
+	e0 := w.NewEntity(posID)
+	w.Disable(e0)
+	w.RemoveEntity(e0)
+	assert.True(t, w.Alive(e0))
+
+	w.Tick()
+	assert.False(t, w.Alive(e0))
+	assert.Equal(t, 0, w.disabledCount)
+}
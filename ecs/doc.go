@@ -18,7 +18,13 @@
 //     with [Cache.Register] and [Cache.Unregister].
 //   - [Resources] provide a storage for global resources, with functionality like
 //     [Resources.Get], [Resources.Add] and [Resources.Remove].
+//   - [SparseSets] provide sparse-set storage for frequently added and removed components,
+//     with [SparseSets.Add], [SparseSets.Get] and [SparseSets.Remove].
+//   - [Tx] provides transactions with rollback, started with [World.Begin].
+//   - [History] provides undo and redo with checkpoints, for editors.
+//   - [View] provides read-only access for multiple goroutines, created with [World.View].
 //   - [Listener] provides [EntityEvent] notifications for ECS operations.
+//   - [Extension] allows for drop-in world extensions, installed with [World.Use].
 //   - Useful functions: [All], [ComponentID], [ResourceID], [GetResource], [AddResource].
 //
 // # Sub-packages
@@ -59,15 +65,22 @@
 //
 // # Build tags
 //
-// Arche provides two build tags:
+// Arche provides four build tags:
 //   - tiny -- Reduces the maximum number of components to 64, giving a performance boost for mask-related operations.
 //   - debug -- Improves error messages on [Query] misuse, at the cost of performance. Use this if you get panics from queries.
+//   - entity64 -- Uses 64 bit entity IDs and generations, for long-running worlds that create and remove
+//     billions of entities. Doubles the memory per [Entity]. Snapshots are not compatible between builds with and without the tag.
+//   - checked -- Verifies the world's internal invariants after each structural operation, and panics with a detailed report
+//     on any inconsistency. Checks the entity index, archetype lengths against the entity pool, and archetype masks against component IDs.
+//     Very slow, as each check is linear in the number of entities. Use this for testing and for debugging suspected corruption.
 //
 // When building your application, use them like this:
 //
 //	go build -tags tiny .
 //	go build -tags debug .
 //	go build -tags tiny,debug .
+//	go build -tags entity64 .
+//	go test -tags checked ./...
 //
 // [User Guide]: https://mlange-42.github.io/arche/
 package ecs
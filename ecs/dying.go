@@ -0,0 +1,181 @@
+package ecs
+
+// dying is the internal component marking soft-deleted entities.
+type dying struct {
+	Tick uint64 // World tick of the entity's removal.
+}
+
+// DyingFilter is a [Filter] for soft-deleted (dying) entities, in addition to components.
+//
+// Create it with [World.Dying].
+type DyingFilter struct {
+	Filter Filter // Components filter.
+	id     ID     // Component ID of the internal dying marker.
+}
+
+// Matches the filter against a mask.
+func (f *DyingFilter) Matches(bits *Mask) bool {
+	return bits.Get(f.id) && f.Filter.Matches(bits)
+}
+
+// Dying creates a [DyingFilter] that matches soft-deleted entities that also match the given filter.
+//
+// Soft-deletion is enabled by a [Config.RemovalGracePeriod] greater than zero.
+// In this mode, [World.RemoveEntity] and [Batch.RemoveEntities] only mark entities as dying.
+// Dying entities are excluded from all queries, except for queries with a [DyingFilter]
+// (optionally wrapped in a [RelationFilter] or registered in the [Cache]).
+// Their storage is reclaimed by [World.Tick] after the grace period,
+// and [EntityEvent]s for entity removal are emitted only then.
+// Until then, dying entities are alive and keep their components and relations.
+//
+// Removing a dying entity reclaims it immediately.
+// Note that a [DyingFilter] wrapped in logic filters of package [github.com/mlange-42/arche/filter]
+// does not match any dying entities.
+//
+// Panics if soft-deletion is not enabled.
+func (w *World) Dying(filter Filter) *DyingFilter {
+	if !w.hasDying {
+		panic("soft-deletion is not enabled, see Config.RemovalGracePeriod")
+	}
+	return &DyingFilter{Filter: filter, id: w.dyingID}
+}
+
+// IsDying reports whether an entity is soft-deleted and waits for its storage to be reclaimed.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+//
+// See [World.Dying] for details.
+func (w *World) IsDying(entity Entity) bool {
+	if !w.entityPool.Alive(entity) {
+		panic("can't check dying state of a dead entity")
+	}
+	return w.entities[entity.id].arch.node.IsDying
+}
+
+// Tick advances the world's tick counter.
+//
+// With soft-deletion enabled, reclaims the storage of entities that have been dying
+// for [Config.RemovalGracePeriod] ticks. See [World.Dying] for details.
+//
+// With leak detection enabled, detects leaked entities. See [World.SetLeakDetection].
+//
+// Finally, notifies the world's listener if it is a [TickListener].
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Tick() {
+	w.checkLocked()
+	if len(w.journals) > 0 {
+		w.record(change{kind: changeTick, tick: w.tick})
+	}
+	w.tick++
+
+	if w.hasDying {
+		w.reclaimDying()
+	}
+	if w.leaks != nil {
+		w.detectLeaks()
+	}
+	w.checkInvariants("Tick")
+	if l, ok := w.listener.(TickListener); ok {
+		l.Tick(w)
+	}
+}
+
+// reclaimDying removes entities that have been dying for the grace period.
+func (w *World) reclaimDying() {
+	grace := uint64(w.config.RemovalGracePeriod)
+	var expired []Entity
+	query := w.Query(w.Dying(All()))
+	query.withDisabled = true
+	for query.Next() {
+		d := (*dying)(query.Get(w.dyingID))
+		if w.tick-d.Tick >= grace {
+			expired = append(expired, query.Entity())
+		}
+	}
+	for _, e := range expired {
+		w.removeEntity(e)
+	}
+	if len(w.cascadeTargets) > 0 {
+		w.applyCascades()
+	}
+}
+
+// CurrentTick returns the world's current tick, as advanced by [World.Tick].
+func (w *World) CurrentTick() uint64 {
+	return w.tick
+}
+
+// markDying marks an entity as dying, or removes it if it is already dying.
+func (w *World) markDying(entity Entity) {
+	if w.entities[entity.id].arch.node.IsDying {
+		w.removeEntity(entity)
+		return
+	}
+	// The marker is internal, so no events are emitted for it.
+	listener := w.listener
+	w.listener = nil
+	w.exchange(entity, []ID{w.dyingID}, nil, ID{}, false, Entity{})
+	w.listener = listener
+
+	(*dying)(w.GetUnchecked(entity, w.dyingID)).Tick = w.tick
+	if w.quotas != nil {
+		w.quotas.Remove(entity.id)
+	}
+}
+
+// markDyingBatch marks all entities matching a filter as dying.
+func (w *World) markDyingBatch(filter Filter) int {
+	// The marker is internal, so no events are emitted for it.
+	listener := w.listener
+	w.listener = nil
+	query := w.exchangeBatchQuery(filter, []ID{w.dyingID}, nil, ID{}, false, Entity{})
+	count := query.Count()
+	for query.Next() {
+		(*dying)(query.Get(w.dyingID)).Tick = w.tick
+		if w.quotas != nil {
+			w.quotas.Remove(query.Entity().id)
+		}
+	}
+	w.listener = listener
+	return count
+}
+
+// visibleComponents returns the mask and component IDs of an archetype, without the internal dying marker.
+func (w *World) visibleComponents(arch *archetype) (Mask, []ID) {
+	ids := arch.node.Ids
+	if !arch.node.IsDying {
+		if len(ids) == 0 {
+			return arch.Mask, nil
+		}
+		return arch.Mask, ids
+	}
+	mask := arch.Mask
+	mask.Set(w.dyingID, false)
+	visible := make([]ID, 0, len(ids)-1)
+	for _, id := range ids {
+		if id != w.dyingID {
+			visible = append(visible, id)
+		}
+	}
+	if len(visible) == 0 {
+		return mask, nil
+	}
+	return mask, visible
+}
+
+// isDyingFilter checks whether a filter is a [DyingFilter], potentially wrapped.
+func isDyingFilter(f Filter) bool {
+	switch ft := f.(type) {
+	case *DyingFilter:
+		return true
+	case *RelationFilter:
+		return isDyingFilter(ft.Filter)
+	case *MultiRelationFilter:
+		return isDyingFilter(ft.Filter)
+	case *CachedFilter:
+		return isDyingFilter(ft.filter)
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(2))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) { events = append(events, e) })
+	w.SetListener(&listener)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID, velID)
+	e3 := w.NewEntity(posID)
+	assert.Equal(t, 3, len(events))
+
+	w.RemoveEntity(e1)
+	assert.Equal(t, 3, len(events))
+	assert.True(t, w.Alive(e1))
+	assert.True(t, w.IsDying(e1))
+	assert.False(t, w.IsDying(e2))
+	assert.True(t, w.Has(e1, posID))
+
+	query := w.Query(All(posID))
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	query = w.Query(w.Dying(All(posID)))
+	assert.Equal(t, 1, query.Count())
+	for query.Next() {
+		assert.Equal(t, e1, query.Entity())
+	}
+
+	cached := w.Cache().Register(w.Dying(All()))
+	query = w.Query(&cached)
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	w.Tick()
+	assert.Equal(t, uint64(1), w.CurrentTick())
+	assert.True(t, w.Alive(e1))
+
+	assert.Equal(t, 1, w.Batch().RemoveEntities(All(velID)))
+	assert.True(t, w.IsDying(e2))
+	query = w.Query(&cached)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	w.Tick()
+	assert.False(t, w.Alive(e1))
+	assert.True(t, w.Alive(e2))
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, e1, events[3].Entity)
+	assert.Equal(t, event.EntityRemoved|event.ComponentRemoved, events[3].EventTypes)
+	assert.Equal(t, All(posID), events[3].Removed)
+	assert.Equal(t, []ID{posID}, events[3].RemovedIDs)
+
+	w.Tick()
+	assert.False(t, w.Alive(e2))
+	assert.Equal(t, 5, len(events))
+	assert.Equal(t, All(posID, velID), events[4].Removed)
+
+	// Removing a dying entity reclaims it immediately.
+	w.RemoveEntity(e3)
+	assert.True(t, w.Alive(e3))
+	w.RemoveEntity(e3)
+	assert.False(t, w.Alive(e3))
+
+	e4 := w.NewEntity(posID)
+	w.RemoveEntity(e4)
+	dump := w.DumpEntities()
+	assert.Equal(t, []uint32{uint32(e4.id)}, dump.Alive)
+
+	assert.Equal(t, 1, w.Batch().RemoveEntities(w.Dying(All())))
+	assert.False(t, w.Alive(e4))
+
+	assert.PanicsWithValue(t, "can't check dying state of a dead entity", func() { w.IsDying(e4) })
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Tick() })
+	query.Close()
+
+	w.NewEntity(posID)
+	w.Tick()
+	w.Reset()
+	assert.Equal(t, uint64(0), w.CurrentTick())
+}
+
+func TestWorldDyingRemoveTwice(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(2))
+	posID := ComponentID[Position](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) { events = append(events, e) })
+	w.SetListener(&listener)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+
+	w.RemoveEntity(e1)
+	assert.True(t, w.Alive(e1))
+	assert.True(t, w.IsDying(e1))
+	assert.Equal(t, 2, len(events))
+
+	w.RemoveEntity(e1)
+	assert.False(t, w.Alive(e1))
+	assert.True(t, w.Alive(e2))
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, e1, events[2].Entity)
+	assert.Equal(t, event.EntityRemoved|event.ComponentRemoved, events[2].EventTypes)
+	assert.Equal(t, []ID{posID}, events[2].RemovedIDs)
+
+	query := w.Query(w.Dying(All()))
+	assert.Equal(t, 0, query.Count())
+	query.Close()
+
+	w.Tick()
+	w.Tick()
+	assert.Equal(t, 3, len(events))
+
+	assert.PanicsWithValue(t, "can't remove a dead entity", func() { w.RemoveEntity(e1) })
+
+	e3 := w.NewEntity(posID)
+	assert.Equal(t, e1.id, e3.id)
+	assert.False(t, w.IsDying(e3))
+}
+
+func TestWorldDyingRelation(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(1))
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	child := NewBuilder(&w, relID).WithRelation(relID).New(parent)
+
+	w.RemoveEntity(child)
+	filter := NewRelationFilter(w.Dying(All(relID)), parent)
+	query := w.Query(&filter)
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	filter = NewRelationFilter(All(relID), parent)
+	query = w.Query(&filter)
+	assert.Equal(t, 0, query.Count())
+	query.Close()
+
+	assert.Equal(t, parent, w.Relations().Get(child, relID))
+	w.Tick()
+	assert.False(t, w.Alive(child))
+}
+
+func TestWorldDyingDisabled(t *testing.T) {
+	w := NewWorld()
+	assert.PanicsWithValue(t, "soft-deletion is not enabled, see Config.RemovalGracePeriod",
+		func() { w.Dying(All()) })
+
+	e := w.NewEntity()
+	assert.False(t, w.IsDying(e))
+	w.Tick()
+	assert.Equal(t, uint64(1), w.CurrentTick())
+
+	assert.PanicsWithValue(t, "invalid RemovalGracePeriod in config, must be >= 0",
+		func() { NewWorld(NewConfig().WithRemovalGracePeriod(-1)) })
+}
+
+type testTickListener struct {
+	testListener
+	Ticks []uint64
+}
+
+func (l *testTickListener) Tick(world *World) {
+	l.Ticks = append(l.Ticks, world.CurrentTick())
+}
+
+func TestTickListener(t *testing.T) {
+	w := NewWorld()
+	l := testTickListener{testListener: newTestListener(func(world *World, e EntityEvent) {})}
+	w.SetListener(&l)
+
+	w.Tick()
+	w.Tick()
+	assert.Equal(t, []uint64{1, 2}, l.Ticks)
+}
@@ -0,0 +1,210 @@
+package ecs
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// dynamicPkgPath is the package path of unexported fields of dynamic component types.
+const dynamicPkgPath = "github.com/mlange-42/arche/ecs/dynamic"
+
+// DynamicField describes a field of a dynamic component type. See [DynamicType].
+type DynamicField struct {
+	Name string       // Name of the field. Must be a valid Go identifier.
+	Type reflect.Type // Type of the field.
+}
+
+// BlobField describes a named field in a raw byte blob component type. See [BlobType].
+type BlobField struct {
+	Name   string       // Name of the field. Must be a valid Go identifier.
+	Offset uintptr      // Offset of the field in the blob, in bytes. Must be a multiple of the type's alignment.
+	Type   reflect.Type // Type of the field.
+}
+
+// DynamicType creates a struct component type at runtime, with the given name and fields.
+//
+// The type can be registered with [TypeID] like any other component type,
+// and its fields can be accessed by name with [World.GetField] and [World.SetField].
+// This allows scripting layers to add data to entities without recompiling the Go program.
+//
+// The name distinguishes dynamic types with the same fields.
+// It is available in the component's annotations under key "dynamic", see [ComponentMetadata].
+// Calls with the same name and fields return the same type.
+//
+// Panics if the name is empty or contains a comma, if a field name is not a valid identifier,
+// or if field names are not unique.
+func DynamicType(name string, fields ...DynamicField) reflect.Type {
+	structFields := make([]reflect.StructField, 0, len(fields)+1)
+	structFields = append(structFields, dynamicNameField(name))
+	for _, f := range fields {
+		structFields = append(structFields, dynamicField(f.Name, f.Type))
+	}
+	return structOf(structFields)
+}
+
+// BlobType creates a component type at runtime, as a raw byte blob of the given size, with named fields at given offsets.
+//
+// This allows scripting layers to define components by a memory layout, e.g. taken from a C struct or a schema file.
+// Bytes of the blob not covered by fields are inaccessible padding.
+// Otherwise, blob types behave like types created by [DynamicType].
+//
+// Panics in the same cases as [DynamicType], if fields overlap, exceed the size or are not aligned,
+// or if the size is not a multiple of the largest field alignment.
+func BlobType(name string, size uintptr, fields ...BlobField) reflect.Type {
+	sorted := append([]BlobField{}, fields...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	byteType := reflect.TypeOf(byte(0))
+	structFields := make([]reflect.StructField, 0, 2*len(fields)+2)
+	structFields = append(structFields, dynamicNameField(name))
+	var offset uintptr
+	pad := func(to uintptr) {
+		if to > offset {
+			structFields = append(structFields, reflect.StructField{
+				Name:    fmt.Sprintf("pad%d", offset),
+				PkgPath: dynamicPkgPath,
+				Type:    reflect.ArrayOf(int(to-offset), byteType),
+			})
+			offset = to
+		}
+	}
+	for _, f := range sorted {
+		if f.Offset < offset {
+			panic(fmt.Sprintf("blob field %s overlaps with the previous field", f.Name))
+		}
+		if f.Offset%uintptr(f.Type.Align()) != 0 {
+			panic(fmt.Sprintf("blob field %s is not aligned", f.Name))
+		}
+		pad(f.Offset)
+		structFields = append(structFields, dynamicField(f.Name, f.Type))
+		offset += f.Type.Size()
+	}
+	if offset > size {
+		panic("blob fields exceed the blob size")
+	}
+	pad(size)
+
+	tp := structOf(structFields)
+	if tp.Size() != size {
+		panic("blob size is not a multiple of the field alignment")
+	}
+	return tp
+}
+
+// GetField returns a copy of the value of a component field of an entity, by field name.
+// Fields of embedded structs are promoted, like in Go. See also [ComponentMetadata].
+//
+// Returns an error if the entity does not have the component, or if the component has no such field.
+// This is intended for scripting layers and tools, not for performance-critical code.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) GetField(entity Entity, comp ID, field string) (any, error) {
+	if !w.entityPool.Alive(entity) {
+		panic("can't get field of a dead entity")
+	}
+	ptr, f, err := w.fieldPointer(entity, comp, field)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.NewAt(f.Type, ptr).Elem().Interface(), nil
+}
+
+// SetField sets the value of a component field of an entity, by field name.
+// Fields of embedded structs are promoted, like in Go. See also [ComponentMetadata].
+//
+// The value must be assignable to the field's type.
+// Numeric values are converted to numeric field types, e.g. a float64 from a scripting language to an int32 field.
+// Marks the component as changed, and notifies the listener like [World.Set].
+//
+// Returns an error if the entity does not have the component, if the component has no such field,
+// or if the value can't be assigned to the field.
+// This is intended for scripting layers and tools, not for performance-critical code.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) SetField(entity Entity, comp ID, field string, value any) error {
+	if !w.entityPool.Alive(entity) {
+		panic("can't set field of a dead entity")
+	}
+	ptr, f, err := w.fieldPointer(entity, comp, field)
+	if err != nil {
+		return err
+	}
+	val := reflect.ValueOf(value)
+	switch {
+	case val.IsValid() && val.Type().AssignableTo(f.Type):
+	case val.IsValid() && isNumeric(val.Kind()) && isNumeric(f.Type.Kind()):
+		val = val.Convert(f.Type)
+	default:
+		return fmt.Errorf("can't assign %T to field %s of type %s", value, f.Name, f.Type)
+	}
+	if len(w.journals) > 0 {
+		w.recordSet(entity, comp)
+	}
+	reflect.NewAt(f.Type, ptr).Elem().Set(val)
+
+	if w.changes != nil {
+		w.changes.Change(entity.id, comp)
+	}
+	w.notifySet(entity, comp)
+	return nil
+}
+
+// fieldPointer returns a pointer to a component field of an alive entity.
+func (w *World) fieldPointer(entity Entity, comp ID, field string) (unsafe.Pointer, *FieldMeta, error) {
+	meta, ok := ComponentMetadata(w, comp)
+	if !ok || !w.Has(entity, comp) {
+		return nil, nil, fmt.Errorf("entity has no component with ID %d", comp.id)
+	}
+	f, ok := meta.Field(field)
+	if !ok {
+		return nil, nil, fmt.Errorf("component %s has no field %s", meta.Type, field)
+	}
+	return unsafe.Add(w.Get(entity, comp), f.Offset), f, nil
+}
+
+// dynamicNameField creates the zero-size leading field that carries the name of a dynamic type.
+func dynamicNameField(name string) reflect.StructField {
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("invalid dynamic type name '%s'", name))
+	}
+	return reflect.StructField{
+		Name:    "_",
+		PkgPath: dynamicPkgPath,
+		Type:    reflect.TypeOf(struct{}{}),
+		Tag:     reflect.StructTag("arche:" + strconv.Quote("dynamic="+name)),
+	}
+}
+
+// dynamicField creates a field of a dynamic type.
+func dynamicField(name string, tp reflect.Type) reflect.StructField {
+	if !token.IsIdentifier(name) || name == "_" {
+		panic(fmt.Sprintf("invalid field name '%s'", name))
+	}
+	f := reflect.StructField{Name: name, Type: tp}
+	if !token.IsExported(name) {
+		f.PkgPath = dynamicPkgPath
+	}
+	return f
+}
+
+// structOf creates a struct type, and panics with a readable message for duplicate field names.
+func structOf(fields []reflect.StructField) reflect.Type {
+	names := map[string]bool{}
+	for _, f := range fields {
+		if names[f.Name] {
+			panic(fmt.Sprintf("duplicate field name '%s'", f.Name))
+		}
+		names[f.Name] = true
+	}
+	return reflect.StructOf(fields)
+}
+
+// isNumeric reports whether a kind is an integer or floating point number.
+func isNumeric(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
@@ -0,0 +1,106 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicType(t *testing.T) {
+	w := NewWorld()
+	floatType := reflect.TypeOf(float64(0))
+
+	healthType := DynamicType("Health", DynamicField{"Current", floatType}, DynamicField{"max", reflect.TypeOf(int32(0))})
+	assert.Equal(t, healthType, DynamicType("Health", DynamicField{"Current", floatType}, DynamicField{"max", reflect.TypeOf(int32(0))}))
+	assert.NotEqual(t, healthType, DynamicType("Mana", DynamicField{"Current", floatType}, DynamicField{"max", reflect.TypeOf(int32(0))}))
+
+	healthID := TypeID(&w, healthType)
+	meta, _ := ComponentMetadata(&w, healthID)
+	assert.Equal(t, "Health", meta.Annotations["dynamic"])
+	assert.Equal(t, 2, len(meta.Fields))
+	assert.Equal(t, "Current", meta.Fields[0].Name)
+
+	e := w.NewEntity(healthID)
+	assert.Nil(t, w.SetField(e, healthID, "Current", 12.5))
+	assert.Nil(t, w.SetField(e, healthID, "max", 100.0))
+
+	value, err := w.GetField(e, healthID, "Current")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.5, value)
+	value, err = w.GetField(e, healthID, "max")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(100), value)
+
+	assert.NotNil(t, w.SetField(e, healthID, "Current", "abc"))
+	assert.NotNil(t, w.SetField(e, healthID, "Current", nil))
+	assert.NotNil(t, w.SetField(e, healthID, "Unknown", 1.0))
+	_, err = w.GetField(e, healthID, "Unknown")
+	assert.NotNil(t, err)
+
+	posID := ComponentID[Position](&w)
+	_, err = w.GetField(e, posID, "X")
+	assert.NotNil(t, err)
+
+	w.RemoveEntity(e)
+	assert.PanicsWithValue(t, "can't get field of a dead entity", func() { _, _ = w.GetField(e, healthID, "Current") })
+	assert.PanicsWithValue(t, "can't set field of a dead entity", func() { _ = w.SetField(e, healthID, "Current", 1.0) })
+
+	assert.PanicsWithValue(t, "invalid field name '1x'", func() { DynamicType("A", DynamicField{"1x", floatType}) })
+	assert.PanicsWithValue(t, "duplicate field name 'X'", func() { DynamicType("A", DynamicField{"X", floatType}, DynamicField{"X", floatType}) })
+	assert.PanicsWithValue(t, "invalid dynamic type name 'A,B'", func() { DynamicType("A,B") })
+}
+
+func TestBlobType(t *testing.T) {
+	w := NewWorld()
+	blobType := BlobType("Body", 16,
+		BlobField{"Mass", 8, reflect.TypeOf(float32(0))},
+		BlobField{"Handle", 0, reflect.TypeOf(uint32(0))},
+	)
+	assert.Equal(t, uintptr(16), blobType.Size())
+
+	blobID := TypeID(&w, blobType)
+	meta, _ := ComponentMetadata(&w, blobID)
+	mass, _ := meta.Field("Mass")
+	assert.Equal(t, uintptr(8), mass.Offset)
+	handle, _ := meta.Field("Handle")
+	assert.Equal(t, uintptr(0), handle.Offset)
+
+	e := w.NewEntity(blobID)
+	assert.Nil(t, w.SetField(e, blobID, "Mass", 2.5))
+	assert.Equal(t, float32(2.5), *(*float32)(unsafe.Add(w.Get(e, blobID), 8)))
+
+	u32 := reflect.TypeOf(uint32(0))
+	assert.PanicsWithValue(t, "blob field B overlaps with the previous field", func() {
+		BlobType("A", 8, BlobField{"A", 0, reflect.TypeOf(uint64(0))}, BlobField{"B", 4, u32})
+	})
+	assert.PanicsWithValue(t, "blob field A is not aligned", func() { BlobType("A", 8, BlobField{"A", 2, u32}) })
+	assert.PanicsWithValue(t, "blob fields exceed the blob size", func() { BlobType("A", 4, BlobField{"A", 4, u32}) })
+	assert.PanicsWithValue(t, "blob size is not a multiple of the field alignment", func() { BlobType("A", 6, BlobField{"A", 0, u32}) })
+}
+
+func TestWorldSetFieldEvents(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.TrackChanges(posID)
+
+	events := []EntityEvent{}
+	listener := testListener{
+		Callback:  func(world *World, e EntityEvent) { events = append(events, e) },
+		Subscribe: event.ComponentSet,
+	}
+	w.SetListener(&listener)
+
+	changed := NewChangeFilter(All()).Changed(posID)
+	e := w.NewEntity(posID)
+	assert.Equal(t, []Entity{e}, queryEntities(&w, changed))
+	assert.Equal(t, []Entity{}, queryEntities(&w, changed))
+
+	assert.Nil(t, w.SetField(e, posID, "X", 5))
+	assert.Equal(t, Position{X: 5}, *(*Position)(w.Get(e, posID)))
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, posID, *events[0].SetID)
+	assert.Equal(t, []Entity{e}, queryEntities(&w, changed))
+}
@@ -2,7 +2,9 @@ package ecs
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"unsafe"
 )
 
 // Reflection type of an [Entity].
@@ -16,6 +18,7 @@ var entityIndexSize uint32 = uint32(reflect.TypeOf(entityIndex{}).Size())
 
 // Entity identifier.
 // Holds an entity ID and its generation for recycling.
+// Both are 32 bit by default, and 64 bit with build tag `entity64`.
 //
 // Entities are only created via the [World], using [World.NewEntity] or [World.NewEntityWith].
 // Batch creation of entities is possible via [Builder].
@@ -24,8 +27,8 @@ var entityIndexSize uint32 = uint32(reflect.TypeOf(entityIndex{}).Size())
 // Entities are intended to be stored and passed around via copy, not via pointers!
 // The zero value should be used to indicate "nil", and can be checked with [Entity.IsZero].
 type Entity struct {
-	id  eid    // Entity ID
-	gen uint32 // Entity generation
+	id  eid  // Entity ID
+	gen egen // Entity generation
 }
 
 // newEntity creates a new Entity.
@@ -34,7 +37,7 @@ func newEntity(id eid) Entity {
 }
 
 // newEntityGen creates a new Entity with a given generation.
-func newEntityGen(id eid, gen uint32) Entity {
+func newEntityGen(id eid, gen egen) Entity {
 	return Entity{id, gen}
 }
 
@@ -47,7 +50,7 @@ func (e Entity) IsZero() bool {
 //
 // The JSON representation of an entity is a two-element array of entity ID and generation.
 func (e Entity) MarshalJSON() ([]byte, error) {
-	arr := [2]uint32{uint32(e.id), e.gen}
+	arr := [2]uint64{uint64(e.id), uint64(e.gen)}
 	jsonValue, _ := json.Marshal(arr) // Ignore the error, as we can be sure this works.
 	return jsonValue, nil
 }
@@ -56,12 +59,15 @@ func (e Entity) MarshalJSON() ([]byte, error) {
 //
 // For serialization purposes only. Do not use this to create entities!
 func (e *Entity) UnmarshalJSON(data []byte) error {
-	arr := [2]uint32{}
+	arr := [2]uint64{}
 	if err := json.Unmarshal(data, &arr); err != nil {
 		return err
 	}
+	if uint64(eid(arr[0])) != arr[0] || uint64(egen(arr[1])) != arr[1] {
+		return fmt.Errorf("entity [%d, %d] is out of range for %d bit entities", arr[0], arr[1], 8*unsafe.Sizeof(eid(0)))
+	}
 	e.id = eid(arr[0])
-	e.gen = arr[1]
+	e.gen = egen(arr[1])
 
 	return nil
 }
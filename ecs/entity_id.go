@@ -0,0 +1,25 @@
+//go:build !entity64
+
+package ecs
+
+import "encoding/binary"
+
+// eid is the entity identifier/index type.
+//
+// Use build tag `entity64` for 64 bit entity IDs and generations.
+type eid uint32
+
+// egen is the entity generation type.
+//
+// Use build tag `entity64` for 64 bit entity IDs and generations.
+type egen uint32
+
+// entityHeader is appended to the platform part of snapshot headers.
+// Empty for 32 bit entities, for compatibility with snapshots written before build tag `entity64` existed.
+var entityHeader = []byte{}
+
+// appendEntity appends the binary representation of an entity to a buffer, in little-endian byte order.
+func appendEntity(buf []byte, e Entity) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(e.id))
+	return binary.LittleEndian.AppendUint32(buf, uint32(e.gen))
+}
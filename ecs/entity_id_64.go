@@ -0,0 +1,25 @@
+//go:build entity64
+
+package ecs
+
+import "encoding/binary"
+
+// eid is the entity identifier/index type.
+//
+// ⚠️ This build uses the build tag `entity64`. Remove the tag for 32 bit entity IDs and generations.
+type eid uint64
+
+// egen is the entity generation type.
+//
+// ⚠️ This build uses the build tag `entity64`. Remove the tag for 32 bit entity IDs and generations.
+type egen uint64
+
+// entityHeader is appended to the platform part of snapshot headers,
+// to reject snapshots written with 32 bit entities.
+var entityHeader = []byte{64}
+
+// appendEntity appends the binary representation of an entity to a buffer, in little-endian byte order.
+func appendEntity(buf []byte, e Entity) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(e.id))
+	return binary.LittleEndian.AppendUint64(buf, uint64(e.gen))
+}
@@ -0,0 +1,22 @@
+//go:build entity64
+
+package ecs
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityID64(t *testing.T) {
+	assert.Equal(t, uint32(16), entitySize)
+
+	e := Entity{}
+	err := json.Unmarshal([]byte("[4294967296, 4294967297]"), &e)
+	assert.Nil(t, err)
+	assert.Equal(t, newEntityGen(math.MaxUint32+1, math.MaxUint32+2), e)
+
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}, appendEntity(nil, newEntityGen(1, 2)))
+}
@@ -0,0 +1,20 @@
+//go:build !entity64
+
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityID32(t *testing.T) {
+	assert.Equal(t, uint32(8), entitySize)
+
+	e := Entity{}
+	err := json.Unmarshal([]byte("[4294967296, 0]"), &e)
+	assert.EqualError(t, err, "entity [4294967296, 0] is out of range for 32 bit entities")
+
+	assert.Equal(t, []byte{1, 0, 0, 0, 2, 0, 0, 0}, appendEntity(nil, newEntityGen(1, 2)))
+}
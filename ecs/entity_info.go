@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// EntityInfo is a debug dump of an entity and its components. See [World.DumpEntity].
+//
+// Values are copies, so they are not affected by later changes to the entity.
+type EntityInfo struct {
+	Entity     Entity           // The entity.
+	Disabled   bool             // Whether the entity is disabled. See [World.Disable].
+	Dying      bool             // Whether the entity is dying. See [World.Dying].
+	Components []ComponentValue // The entity's components, in the order of [World.Ids].
+}
+
+// ComponentValue is a debug dump of a component of an entity. See [World.DumpEntity].
+type ComponentValue struct {
+	ID     ID           // ID of the component.
+	Type   reflect.Type // Type of the component.
+	Value  any          // Copy of the component value.
+	Fields []FieldValue // Field values, with fields of embedded structs promoted. Nil for non-struct components.
+	Target *Entity      // Relation target, for [Relation] components. Nil otherwise.
+}
+
+// FieldValue is the value of a component field in a [ComponentValue].
+type FieldValue struct {
+	Name  string // Name of the field.
+	Value any    // Copy of the field value.
+}
+
+// DumpEntity returns a debug dump of an entity, with the IDs, types and field values of its components.
+//
+// Field values are read via reflection, and include unexported fields.
+// Use [EntityInfo.String] for a human-readable representation.
+// This is intended for debugging and logging, not for performance-critical code.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (w *World) DumpEntity(entity Entity) EntityInfo {
+	if !w.entityPool.Alive(entity) {
+		panic("can't dump a dead entity")
+	}
+	index := &w.entities[entity.id]
+	arch := index.arch
+	_, ids := w.visibleComponents(arch)
+
+	info := EntityInfo{
+		Entity:     entity,
+		Disabled:   w.isDisabled(entity.id),
+		Dying:      arch.node.IsDying,
+		Components: make([]ComponentValue, 0, len(ids)),
+	}
+	for _, id := range ids {
+		ptr := arch.Get(index.index, id)
+		meta, _ := ComponentMetadata(w, id)
+		comp := ComponentValue{
+			ID:    id,
+			Type:  meta.Type,
+			Value: reflect.NewAt(meta.Type, ptr).Elem().Interface(),
+		}
+		if meta.Type.Kind() == reflect.Struct {
+			comp.Fields = make([]FieldValue, len(meta.Fields))
+			for i := range meta.Fields {
+				f := &meta.Fields[i]
+				comp.Fields[i] = FieldValue{
+					Name:  f.Name,
+					Value: reflect.NewAt(f.Type, unsafe.Add(ptr, f.Offset)).Elem().Interface(),
+				}
+			}
+		}
+		if meta.IsRelation {
+			target := arch.RelationTarget
+			comp.Target = &target
+		}
+		info.Components = append(info.Components, comp)
+	}
+	return info
+}
+
+// String returns a human-readable, multi-line representation of the entity dump.
+func (e EntityInfo) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "Entity %v", e.Entity)
+	if e.Disabled {
+		b.WriteString(" (disabled)")
+	}
+	if e.Dying {
+		b.WriteString(" (dying)")
+	}
+	for i := range e.Components {
+		b.WriteString("\n  ")
+		b.WriteString(e.Components[i].String())
+	}
+	return b.String()
+}
+
+// String returns a human-readable representation of the component dump.
+func (c ComponentValue) String() string {
+	b := strings.Builder{}
+	b.WriteString(c.Type.String())
+	if c.Fields == nil {
+		fmt.Fprintf(&b, "(%v)", c.Value)
+	} else {
+		b.WriteString("{")
+		for i, f := range c.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s: %v", f.Name, f.Value)
+		}
+		b.WriteString("}")
+	}
+	if c.Target != nil {
+		fmt.Fprintf(&b, " -> %v", *c.Target)
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldDumpEntity(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(1))
+	posID := ComponentID[Position](&w)
+	labelID := ComponentID[label](&w)
+	relID := ComponentID[ChildOf](&w)
+	valID := ComponentID[testStruct1](&w)
+	defID := ComponentID[compTypeDef](&w)
+
+	parent := w.NewEntity()
+	e := NewBuilderWith(&w,
+		Component{ID: posID, Comp: &Position{X: 1, Y: 2}},
+		Component{ID: labelID, Comp: &label{}},
+		Component{ID: relID, Comp: &ChildOf{}},
+		Component{ID: valID, Comp: &testStruct1{val: 7}},
+		Component{ID: defID, Comp: new(compTypeDef)},
+	).WithRelation(relID).New(parent)
+	*(*compTypeDef)(w.Get(e, defID)) = 3
+
+	info := w.DumpEntity(e)
+	assert.Equal(t, e, info.Entity)
+	assert.False(t, info.Disabled)
+	assert.False(t, info.Dying)
+	assert.Equal(t, 5, len(info.Components))
+
+	pos := info.Components[0]
+	assert.Equal(t, posID, pos.ID)
+	assert.Equal(t, reflect.TypeOf(Position{}), pos.Type)
+	assert.Equal(t, Position{X: 1, Y: 2}, pos.Value)
+	assert.Equal(t, []FieldValue{{"X", 1}, {"Y", 2}}, pos.Fields)
+	assert.Nil(t, pos.Target)
+
+	rel := info.Components[2]
+	assert.Equal(t, parent, *rel.Target)
+	assert.Equal(t, []FieldValue{}, rel.Fields)
+
+	assert.Equal(t, []FieldValue{{"val", int32(7)}}, info.Components[3].Fields)
+	assert.Nil(t, info.Components[4].Fields)
+	assert.Equal(t, compTypeDef(3), info.Components[4].Value)
+
+	// Values are copies.
+	(*Position)(w.Get(e, posID)).X = 100
+	assert.Equal(t, 1, info.Components[0].Fields[0].Value)
+
+	assert.Equal(t, `Entity {2 0}
+  ecs.Position{X: 1, Y: 2}
+  ecs.label{}
+  ecs.ChildOf{} -> {1 0}
+  ecs.testStruct1{val: 7}
+  ecs.compTypeDef(3)`, info.String())
+
+	w.Disable(e)
+	w.RemoveEntity(e)
+	info = w.DumpEntity(e)
+	assert.True(t, info.Disabled)
+	assert.True(t, info.Dying)
+	assert.Equal(t, 5, len(info.Components))
+	assert.Equal(t, "Entity {2 0} (disabled) (dying)", info.String()[:31])
+
+	w.Tick()
+	assert.PanicsWithValue(t, "can't dump a dead entity", func() { w.DumpEntity(e) })
+}
@@ -10,6 +10,7 @@ import "github.com/mlange-42/arche/ecs/event"
 //
 // Events notified are entity creation and removal, component addition and removal,
 // and change of relations and their targets.
+// Optionally, value changes of components via [World.Set] and [Apply] are notified, see [event.ComponentSet].
 //
 // Event types that are subscribed are determined by [Listener].Subscriptions.
 // Events that cover multiple types (e.g. entity creation and component addition) are only notified once.
@@ -29,13 +30,29 @@ import "github.com/mlange-42/arche/ecs/event"
 // Events for batch-creation of entities using a [Builder] are fired after all entities are created.
 // For batch methods that return a [Query], events are fired after the [Query] is closed (or fully iterated).
 // This allows the [World] to be in an unlocked state, and notifies after potential entity initialization.
+//
+// # Sequence numbers & delivery order
+//
+// Each event notified to the world's [Listener] carries a sequence number in field Sequence.
+// Sequence numbers start at 1 and increase by exactly one per notified event, over the entire lifetime of a [World]
+// (they are not reset by [World.Reset]).
+// Events are delivered synchronously, in the order of their sequence numbers.
+// For batch operations, events for all entities of an operation are delivered
+// before any event of a subsequent operation.
+//
+// Events that no subscription matches are not notified and don't consume a sequence number.
+// Thus, a gap in the sequence numbers received by a listener indicates a lost event.
+// Note that listeners that filter events further, like the sub-listeners of [github.com/mlange-42/arche/listener.Dispatch],
+// may observe gaps for events they did not subscribe to.
 type EntityEvent struct {
 	Entity                   Entity             // The entity that was changed.
 	Added, Removed           Mask               // Masks indicating changed components (additions and removals).
 	AddedIDs, RemovedIDs     []ID               // Components added and removed. DO NOT MODIFY! Get the current components with [World.Ids].
 	OldRelation, NewRelation *ID                // Old and new relation component ID. No relation is indicated by nil.
 	OldTarget                Entity             // Old relation target entity. Get the new target with [World.Relations] and [Relations.Get].
+	SetID                    *ID                // Component that was overwritten, for event type [event.ComponentSet]. Nil otherwise.
 	EventTypes               event.Subscription // Bit mask of event types. See [event.Subscription].
+	Sequence                 uint64             // Sequence number of the event. Zero for events not emitted by a [World].
 }
 
 // Contains returns whether the event's types contain the given type/subscription bit.
@@ -68,6 +85,16 @@ type Listener interface {
 	Components() *Mask
 }
 
+// TickListener is a [Listener] that is notified at the end of each tick, by [World.Tick].
+//
+// This allows e.g. for listeners that buffer events and deliver them in a batch,
+// see [github.com/mlange-42/arche/listener.Buffered].
+type TickListener interface {
+	Listener
+	// Tick is called at the end of [World.Tick], after the world's tick was advanced.
+	Tick(world *World)
+}
+
 // testListener for [EntityEvent]s.
 type testListener struct {
 	Callback  func(world *World, e EntityEvent)
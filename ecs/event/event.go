@@ -75,6 +75,17 @@ const (
 	//   - Whenever RelationChanged is triggered
 	//   - Change of the target entity of any of the given (relation) components
 	TargetChanged Subscription = 1 << 5
+
+	// ComponentSet subscription bit.
+	//
+	// Without component subscription:
+	//   - Overwriting the value of any component of an entity
+	// With component subscription:
+	//   - Overwriting the value of any of the given components
+	//
+	// Value changes are not structural changes, and can be very frequent.
+	// Thus, ComponentSet is opt-in and not contained in [All].
+	ComponentSet Subscription = 1 << 6
 )
 
 // Subscription bits for groups of events
@@ -0,0 +1,61 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
+// Extension is the interface for drop-in world extensions,
+// like spatial indices, replication or metrics.
+//
+// Extensions are installed into a [World] with [World.Use].
+// In Install, they can register components, add resources,
+// set up a [Listener] or register cached filters.
+//
+// Extensions are identified by equality, so implementations should be pointer types.
+type Extension interface {
+	// Install the extension into the given world.
+	Install(w *World) error
+}
+
+// StatsExtension is an [Extension] that contributes to the statistics returned by [World.Stats].
+type StatsExtension interface {
+	Extension
+	// Stats adds the extension's statistics to the world statistics.
+	// Called by [World.Stats], after the world's own statistics are updated.
+	Stats(s *stats.World)
+}
+
+// Use installs an [Extension] into the world.
+//
+// Returns the error returned by [Extension.Install], wrapped with the extension's type.
+// An extension that failed to install is not recorded as installed.
+//
+// Panics when called on a locked world, or when the extension is already installed.
+// Extensions of non-comparable types are never detected as already installed.
+// Do not use during [Query] iteration!
+func (w *World) Use(ext Extension) error {
+	w.checkLocked()
+
+	if reflect.TypeOf(ext).Comparable() {
+		for _, e := range w.extensions {
+			if e == ext {
+				panic(fmt.Sprintf("extension %T is already installed", ext))
+			}
+		}
+	}
+	if err := ext.Install(w); err != nil {
+		return fmt.Errorf("failed to install extension %T: %w", ext, err)
+	}
+	w.extensions = append(w.extensions, ext)
+	return nil
+}
+
+// Extensions returns the extensions installed with [World.Use], in installation order.
+//
+// Returns a copy of the world's extensions slice, for safety.
+func (w *World) Extensions() []Extension {
+	return append([]Extension{}, w.extensions...)
+}
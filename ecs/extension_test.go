@@ -0,0 +1,83 @@
+package ecs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+type testExtension struct {
+	err       error
+	installed int
+}
+
+func (e *testExtension) Install(w *World) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.installed++
+	AddResource(w, &Position{1, 2})
+	return nil
+}
+
+func TestWorldUse(t *testing.T) {
+	w := NewWorld()
+
+	ext := testExtension{}
+	assert.Nil(t, w.Use(&ext))
+	assert.Equal(t, 1, ext.installed)
+	assert.Equal(t, []Extension{&ext}, w.Extensions())
+	assert.Equal(t, Position{1, 2}, *GetResource[Position](&w))
+
+	assert.PanicsWithValue(t, "extension *ecs.testExtension is already installed",
+		func() { _ = w.Use(&ext) })
+
+	failing := testExtension{err: errors.New("test error")}
+	err := w.Use(&failing)
+	assert.EqualError(t, err, "failed to install extension *ecs.testExtension: test error")
+	assert.True(t, errors.Is(err, failing.err))
+	assert.Equal(t, 1, len(w.Extensions()))
+
+	q := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world",
+		func() { _ = w.Use(&testExtension{}) })
+	q.Close()
+}
+
+type sliceExtension []int
+
+func (e sliceExtension) Install(w *World) error {
+	return nil
+}
+
+func TestWorldUseNonComparable(t *testing.T) {
+	w := NewWorld()
+
+	ext := testExtension{}
+	assert.Nil(t, w.Use(&ext))
+	assert.Nil(t, w.Use(sliceExtension{1, 2}))
+	assert.Nil(t, w.Use(sliceExtension{1, 2}))
+	assert.Equal(t, 3, len(w.Extensions()))
+
+	assert.PanicsWithValue(t, "extension *ecs.testExtension is already installed",
+		func() { _ = w.Use(&ext) })
+}
+
+type testStatsExtension struct {
+	testExtension
+}
+
+func (e *testStatsExtension) Stats(s *stats.World) {
+	s.Systems = append(s.Systems, stats.System{Name: "test"})
+}
+
+func TestWorldStatsExtension(t *testing.T) {
+	w := NewWorld()
+	assert.Equal(t, 0, len(w.Stats().Systems))
+
+	assert.Nil(t, w.Use(&testStatsExtension{}))
+	assert.Equal(t, []stats.System{{Name: "test"}}, w.Stats().Systems)
+	assert.Equal(t, []stats.System{{Name: "test"}}, w.Stats().Systems)
+}
@@ -4,6 +4,7 @@ package ecs
 // Filters are required to query entities using [World.Query].
 //
 // See [Mask], [MaskFilter] anf [RelationFilter] for basic filters.
+// See [NewFilter] for composing filters from [With], [Without] and [Optional] terms.
 // For type-safe generics queries, see package [github.com/mlange-42/arche/generic].
 // For advanced filtering, see package [github.com/mlange-42/arche/filter].
 type Filter interface {
@@ -46,6 +47,87 @@ func (f *RelationFilter) Matches(bits *Mask) bool {
 	return f.Filter.Matches(bits)
 }
 
+// matchesTarget checks whether the filter accepts the given relation target.
+func (f *RelationFilter) matchesTarget(target Entity) bool {
+	return f.Target == target
+}
+
+// targets returns the relation targets accepted by the filter.
+func (f *RelationFilter) targets() []Entity {
+	return []Entity{f.Target}
+}
+
+// components returns the components filter.
+func (f *RelationFilter) components() Filter {
+	return f.Filter
+}
+
+// MultiRelationFilter is a [Filter] for a set of [Relation] targets, in addition to components.
+// It matches entities with any of the targets, in a single query.
+//
+// Entities are iterated target by target, in the order of the targets.
+//
+// See [Relation] for details and examples.
+type MultiRelationFilter struct {
+	Filter  Filter   // Components filter.
+	Targets []Entity // Relation target entities. Must not contain duplicates.
+}
+
+// NewMultiRelationFilter creates a new [MultiRelationFilter].
+// It is a [Filter] for a set of [Relation] targets, in addition to components.
+// Duplicate targets are ignored.
+func NewMultiRelationFilter(filter Filter, targets ...Entity) MultiRelationFilter {
+	unique := make([]Entity, 0, len(targets))
+	for _, t := range targets {
+		if !containsEntity(unique, t) {
+			unique = append(unique, t)
+		}
+	}
+	return MultiRelationFilter{
+		Filter:  filter,
+		Targets: unique,
+	}
+}
+
+// Matches the filter against a mask.
+func (f *MultiRelationFilter) Matches(bits *Mask) bool {
+	return f.Filter.Matches(bits)
+}
+
+// matchesTarget checks whether the filter accepts the given relation target.
+func (f *MultiRelationFilter) matchesTarget(target Entity) bool {
+	return containsEntity(f.Targets, target)
+}
+
+// targets returns the relation targets accepted by the filter.
+func (f *MultiRelationFilter) targets() []Entity {
+	return f.Targets
+}
+
+// components returns the components filter.
+func (f *MultiRelationFilter) components() Filter {
+	return f.Filter
+}
+
+// targetFilter is implemented by filters for relation targets,
+// i.e. [RelationFilter] and [MultiRelationFilter].
+type targetFilter interface {
+	Filter
+	matchesTarget(target Entity) bool
+	targets() []Entity
+	components() Filter
+}
+
+// containsEntity checks whether a slice contains the given entity.
+func containsEntity(entities []Entity, entity Entity) bool {
+	for _, e := range entities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
+
 // CachedFilter is a filter that is cached by the world.
 //
 // Create a cached filter from any other filter using [Cache.Register].
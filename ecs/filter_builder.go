@@ -0,0 +1,68 @@
+package ecs
+
+// FilterTerm is a term for composing filters with [NewFilter].
+//
+// See [With], [Without] and [Optional].
+type FilterTerm struct {
+	include  Mask
+	exclude  Mask
+	optional Mask
+}
+
+// With creates a [FilterTerm] for components that are required.
+func With(comps ...ID) FilterTerm {
+	return FilterTerm{include: All(comps...)}
+}
+
+// Without creates a [FilterTerm] for components that are excluded.
+func Without(comps ...ID) FilterTerm {
+	return FilterTerm{exclude: All(comps...)}
+}
+
+// Optional creates a [FilterTerm] for components that are optional.
+//
+// Optional components are not considered for matching.
+// During query iteration, [Query.Has] reports whether an optional component is present,
+// and [Query.Get] returns nil if it is absent.
+//
+// Like in package [github.com/mlange-42/arche/generic], optional components
+// are removed from components required by [With] terms.
+func Optional(comps ...ID) FilterTerm {
+	return FilterTerm{optional: All(comps...)}
+}
+
+// NewFilter composes a [MaskFilter] from terms created with [With], [Without] and [Optional].
+//
+// The result can be used with [World.Query] and [Cache.Register].
+//
+// Example:
+//
+//	filter := ecs.NewFilter(ecs.With(posID, velID), ecs.Without(deadID), ecs.Optional(targetID))
+//	query := world.Query(filter)
+//	for query.Next() {
+//		if query.Has(targetID) {
+//			// ...
+//		}
+//	}
+//
+// Panics if a component is both required and excluded, or both optional and excluded.
+func NewFilter(terms ...FilterTerm) *MaskFilter {
+	var include, exclude, optional Mask
+	for i := range terms {
+		t := &terms[i]
+		include = include.Or(&t.include)
+		exclude = exclude.Or(&t.exclude)
+		optional = optional.Or(&t.optional)
+	}
+	if include.ContainsAny(&exclude) {
+		panic("component is both required and excluded in filter")
+	}
+	if optional.ContainsAny(&exclude) {
+		panic("component is both optional and excluded in filter")
+	}
+	notOptional := optional.Not()
+	return &MaskFilter{
+		Include: include.And(&notOptional),
+		Exclude: exclude,
+	}
+}
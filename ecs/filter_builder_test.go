@@ -0,0 +1,52 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFilter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	rotID := ComponentID[rotation](&w)
+	labelID := ComponentID[label](&w)
+
+	w.NewEntity(posID)
+	w.NewEntity(posID, velID)
+	w.NewEntity(posID, rotID)
+	w.NewEntity(posID, velID, labelID)
+	w.NewEntity(velID)
+
+	filter := NewFilter(With(posID), Without(labelID), Optional(velID))
+	assert.Equal(t, &MaskFilter{Include: All(posID), Exclude: All(labelID)}, filter)
+
+	query := w.Query(filter)
+	assert.Equal(t, 3, query.Count())
+	withVel := 0
+	for query.Next() {
+		if query.Has(velID) {
+			assert.NotNil(t, query.Get(velID))
+			withVel++
+		} else {
+			assert.Nil(t, query.Get(velID))
+		}
+	}
+	assert.Equal(t, 1, withVel)
+
+	filter = NewFilter(With(posID, velID), Optional(velID))
+	assert.Equal(t, &MaskFilter{Include: All(posID)}, filter)
+
+	cached := w.Cache().Register(NewFilter(With(velID), Without(rotID, labelID)))
+	query = w.Query(&cached)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	assert.PanicsWithValue(t, "component is both required and excluded in filter", func() {
+		NewFilter(With(posID, velID), Without(velID))
+	})
+	assert.PanicsWithValue(t, "component is both optional and excluded in filter", func() {
+		NewFilter(With(posID), Without(velID), Optional(velID))
+	})
+}
@@ -74,3 +74,58 @@ func ExampleRelationFilter() {
 	}
 	// Output:
 }
+
+func TestMultiRelationFilter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	t1, t2, t3 := w.NewEntity(), w.NewEntity(), w.NewEntity()
+	builder := NewBuilder(&w, posID, relID).WithRelation(relID)
+	builder.NewBatch(3, t1)
+	builder.NewBatch(4, t2)
+	builder.NewBatch(5, t3)
+	w.NewEntity(posID)
+
+	filter := NewMultiRelationFilter(All(relID), t3, t1, t3)
+	assert.Equal(t, []Entity{t3, t1}, filter.Targets)
+
+	query := w.Query(&filter)
+	assert.Equal(t, 8, query.Count())
+	assert.Equal(t, t1, w.Relations().Get(query.EntityAt(7), relID))
+	targets := []Entity{}
+	for query.Next() {
+		targets = append(targets, query.Relation(relID))
+	}
+	assert.Equal(t, 8, len(targets))
+	assert.Equal(t, t3, targets[0])
+	assert.Equal(t, t1, targets[7])
+
+	withoutRel := NewMultiRelationFilter(All(posID), t1)
+	assert.Equal(t, 4, countEntities(&w, &withoutRel))
+
+	cached := w.Cache().Register(&filter)
+	assert.Equal(t, 8, countEntities(&w, &cached))
+
+	t4 := w.NewEntity()
+	filter2 := NewMultiRelationFilter(All(relID), t2, t4)
+	cached2 := w.Cache().Register(&filter2)
+	assert.Equal(t, 4, countEntities(&w, &cached2))
+	builder.NewBatch(2, t4)
+	assert.Equal(t, 6, countEntities(&w, &cached2))
+	assert.Equal(t, 8, countEntities(&w, &cached))
+
+	rf := NewRelationFilter(All(relID), t4)
+	w.Batch().RemoveEntities(&rf)
+	w.RemoveEntity(t4)
+	assert.Equal(t, 4, countEntities(&w, &cached2))
+
+	w.TrackChanges(posID)
+	all := All(relID)
+	for _, e := range queryEntities(&w, &all) {
+		w.MarkChanged(e, posID)
+	}
+	changed := NewMultiRelationFilter(NewChangeFilter(All(relID)).Changed(posID), t1, t2)
+	assert.Equal(t, 7, countEntities(&w, &changed))
+	assert.Equal(t, 0, countEntities(&w, &changed))
+}
@@ -75,6 +75,21 @@ func ResourceType(w *World, id ResID) (reflect.Type, bool) {
 	return w.resources.registry.ComponentType(id.id)
 }
 
+// SparseComponentID returns the [SparseID] for a sparse-set component type via generics.
+// Registers the type if it is not already registered.
+//
+// Sparse-set component IDs are independent of archetype component IDs. See [SparseSets].
+// The number of sparse-set components per [World] is limited to [MaskTotalBits].
+func SparseComponentID[T any](w *World) SparseID {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	return w.sparseID(tp)
+}
+
+// SparseComponentType returns the reflect.Type for a [SparseID], and whether the ID is assigned.
+func SparseComponentType(w *World, id SparseID) (reflect.Type, bool) {
+	return w.sparse.registry.ComponentType(id.id)
+}
+
 // GetResource returns a pointer to the given resource type in the world.
 // Returns nil if there is no such resource.
 //
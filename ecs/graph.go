@@ -0,0 +1,84 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DumpArchetypeGraph returns the archetype graph of the world in the Graphviz DOT language.
+//
+// Each node of the graph represents a component composition.
+// Nodes are labeled with their component types, and the number of archetypes and entities they contain.
+// Nodes with relation components can contain multiple archetypes, one per relation target.
+// Inactive nodes, which currently have no archetypes, are dashed.
+// Edges are transitions between nodes by adding a component, labeled with that component's type.
+// Removal transitions are the same edges in reverse direction, and are not shown.
+//
+// This helps to diagnose archetype explosion and unexpected component combinations.
+// Render the result with e.g.:
+//
+//	dot -Tsvg graph.dot -o graph.svg
+func (w *World) DumpArchetypeGraph() string {
+	b := strings.Builder{}
+	b.WriteString("digraph archetypes {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	numNodes := w.nodes.Len()
+	indices := make(map[*archNode]int32, numNodes)
+	var i int32
+	for i = 0; i < numNodes; i++ {
+		node := w.nodes.Get(i)
+		indices[node] = i
+
+		names := make([]string, 0, len(node.Ids)+1)
+		for _, id := range node.Ids {
+			names = append(names, w.registry.Types[id.id].String())
+		}
+		if len(names) == 0 {
+			names = append(names, "(no components)")
+		}
+		if node.IsDying {
+			names = append(names, "(dying)")
+		}
+
+		style := ""
+		if node.IsActive {
+			arches := node.Archetypes()
+			numArches := arches.Len()
+			active, entities := 0, 0
+			var j int32
+			for j = 0; j < numArches; j++ {
+				arch := arches.Get(j)
+				if arch.IsActive() {
+					active++
+					entities += int(arch.Len())
+				}
+			}
+			names = append(names, fmt.Sprintf("archetypes: %d, entities: %d", active, entities))
+		} else {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  n%d [label=\"%s\"%s];\n", i, dotEscape(strings.Join(names, "\n")), style)
+	}
+
+	for i = 0; i < numNodes; i++ {
+		node := w.nodes.Get(i)
+		for j := 0; j < MaskTotalBits; j++ {
+			next, ok := node.TransitionAdd.Get(uint8(j))
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&b, "  n%d -> n%d [label=\"+%s\"];\n", i, indices[next], dotEscape(w.registry.Types[j].String()))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotEscape escapes a string for use in a quoted DOT string.
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
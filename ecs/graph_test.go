@@ -0,0 +1,33 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldDumpArchetypeGraph(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[ChildOf](&w)
+
+	parent := w.NewEntity(posID, velID)
+	NewBuilder(&w, relID).WithRelation(relID).NewBatch(3, parent)
+	NewBuilder(&w, relID).WithRelation(relID).NewBatch(2)
+
+	expected := `digraph archetypes {
+  node [shape=box];
+  n0 [label="(no components)\narchetypes: 1, entities: 0"];
+  n1 [label="ecs.Position", style=dashed];
+  n2 [label="ecs.Position\necs.Velocity\narchetypes: 1, entities: 1"];
+  n3 [label="ecs.ChildOf\narchetypes: 2, entities: 5"];
+  n0 -> n1 [label="+ecs.Position"];
+  n0 -> n3 [label="+ecs.ChildOf"];
+  n1 -> n2 [label="+ecs.Velocity"];
+}
+`
+	assert.Equal(t, expected, w.DumpArchetypeGraph())
+
+	assert.Equal(t, `a\\b\"c\"\nd`, dotEscape("a\\b\"c\"\nd"))
+}
@@ -0,0 +1,69 @@
+package ecs
+
+import (
+	"hash/fnv"
+	"unsafe"
+)
+
+// Hash returns a deterministic hash of the world's entities and their components.
+//
+// The hash covers the IDs and generations of all alive entities, their component IDs,
+// relation targets and the raw bytes of their component data.
+// Entities are hashed in the order of their IDs, and components in the order of their component IDs.
+// Thus, the hash is independent of archetype layout and iteration order,
+// and worlds with the same state have the same hash, even if they were built by different sequences of operations.
+// Resources are not hashed.
+//
+// This is intended for lockstep simulations, which compare hashes between peers every few ticks to detect desyncs.
+//
+// Components that contain pointers, slices, maps, strings or interfaces hash their memory addresses,
+// which differ between processes. Such components, as well as components with non-deterministic data,
+// should be excluded with [World.ExcludeFromHash].
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Hash() uint64 {
+	w.checkLocked()
+
+	h := fnv.New64a()
+	buf := make([]byte, 0, 64)
+	for i := 1; i < len(w.entityPool.entities); i++ {
+		index := &w.entities[i]
+		arch := index.arch
+		if arch == nil {
+			continue
+		}
+		entity := w.entityPool.entities[i]
+		mask, _ := w.visibleComponents(arch)
+
+		buf = appendEntity(buf[:0], entity)
+		buf = append(buf, unsafe.Slice((*byte)(unsafe.Pointer(&mask)), unsafe.Sizeof(mask))...)
+		if arch.HasRelationComponent {
+			buf = appendEntity(buf, arch.RelationTarget)
+		}
+		_, _ = h.Write(buf)
+
+		for j := 0; j < MaskTotalBits; j++ {
+			id := ID{id: uint8(j)}
+			if !mask.Get(id) || w.hashExcluded.Get(id) {
+				continue
+			}
+			lay := arch.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			_, _ = h.Write(unsafe.Slice((*byte)(lay.Get(index.index)), lay.itemSize))
+		}
+	}
+	return h.Sum64()
+}
+
+// ExcludeFromHash excludes components from [World.Hash].
+// Excluded components are still reflected in the hash by their presence, but not by their data.
+//
+// Exclusions are not affected by [World.Reset].
+func (w *World) ExcludeFromHash(comps ...ID) {
+	for _, id := range comps {
+		w.hashExcluded.Set(id, true)
+	}
+}
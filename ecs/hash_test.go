@@ -0,0 +1,64 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldHash(t *testing.T) {
+	build := func(reverse bool) *World {
+		w := NewWorld()
+		posID := ComponentID[Position](&w)
+		velID := ComponentID[Velocity](&w)
+		relID := ComponentID[testRelationA](&w)
+
+		parent := w.NewEntity()
+		e1 := w.NewEntity()
+		e2 := w.NewEntity()
+		if reverse {
+			w.Add(e2, velID, posID)
+			w.Add(e1, posID)
+		} else {
+			w.Add(e1, posID)
+			w.Add(e2, posID, velID)
+		}
+		*(*Position)(w.Get(e1, posID)) = Position{X: 1, Y: 2}
+		*(*Velocity)(w.Get(e2, velID)) = Velocity{X: 3, Y: 4}
+		w.Add(e2, relID)
+		w.Relations().Set(e2, relID, parent)
+		return &w
+	}
+
+	w1 := build(false)
+	w2 := build(true)
+	assert.Equal(t, w1.Hash(), w2.Hash())
+	assert.Equal(t, w1.Hash(), w1.Clone().Hash())
+
+	posID := ComponentID[Position](w1)
+	relID := ComponentID[testRelationA](w1)
+	e1 := w1.entityPool.entities[2]
+	e2 := w1.entityPool.entities[3]
+
+	hash := w1.Hash()
+	(*Position)(w1.Get(e1, posID)).X = 5
+	assert.NotEqual(t, hash, w1.Hash())
+
+	w1.ExcludeFromHash(posID)
+	w2.ExcludeFromHash(posID)
+	assert.Equal(t, w1.Hash(), w2.Hash())
+
+	w1.Relations().Set(e2, relID, Entity{})
+	assert.NotEqual(t, w1.Hash(), w2.Hash())
+	w1.Relations().Set(e2, relID, w1.entityPool.entities[1])
+	assert.Equal(t, w1.Hash(), w2.Hash())
+
+	w1.RemoveEntity(e1)
+	assert.NotEqual(t, w1.Hash(), w2.Hash())
+	w2.RemoveEntity(w2.entityPool.entities[2])
+	assert.Equal(t, w1.Hash(), w2.Hash())
+
+	w1.Reset()
+	w3 := NewWorld()
+	assert.Equal(t, w3.Hash(), w1.Hash())
+}
@@ -0,0 +1,46 @@
+package ecs
+
+import "reflect"
+
+// Reflection type of the [Hidden] marker.
+var hiddenType = reflect.TypeOf(Hidden{})
+
+// Hidden is a marker component for internal entities,
+// like the value entities of [github.com/mlange-42/arche/generic.Shared].
+//
+// Entities with a Hidden component are excluded from all queries and batch operations,
+// unless the filter explicitly requires the Hidden component, e.g. with [All] or [With].
+// They are counted in [stats.Entities.Used] like all alive entities, and separately in [stats.Entities.Hidden].
+//
+// Apart from that, hidden entities are ordinary entities. They are contained in snapshots,
+// copied by [World.Clone] and can be accessed through the [World] directly.
+type Hidden struct{}
+
+// requiresHidden checks whether a filter only matches a mask of hidden entities
+// because it requires the [Hidden] component.
+func requiresHidden(f Filter, mask *Mask, id ID) bool {
+	visible := *mask
+	visible.Set(id, false)
+	return !f.Matches(&visible)
+}
+
+// hiddenID returns the component ID of the [Hidden] marker, and whether it is registered.
+func (w *World) hiddenID() (ID, bool) {
+	id, ok := w.registry.Components[hiddenType]
+	return ID{id: id}, ok
+}
+
+// hiddenCount returns the number of alive entities with the [Hidden] marker.
+func (w *World) hiddenCount(id ID) int {
+	count := 0
+	for _, nd := range w.nodePointers {
+		if !nd.IsActive || !nd.Mask.Get(id) {
+			continue
+		}
+		arches := nd.Archetypes()
+		for i := int32(0); i < arches.Len(); i++ {
+			count += int(arches.Get(i).Len())
+		}
+	}
+	return count
+}
@@ -0,0 +1,45 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHidden(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	hiddenID := ComponentID[Hidden](&w)
+
+	w.NewEntity(posID)
+	w.NewEntity(posID)
+	hidden := w.NewEntity(posID, hiddenID)
+
+	query := w.Query(All())
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	query = w.Query(All(posID))
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	query = w.Query(All(posID, hiddenID))
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	query = w.Query(NewFilter(With(hiddenID), Optional(posID)))
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	assert.Equal(t, 3, w.Stats().Entities.Used)
+	assert.Equal(t, 1, w.Stats().Entities.Hidden)
+
+	dump := w.DumpEntities()
+	assert.Equal(t, []uint32{1, 2, 3}, dump.Alive)
+
+	assert.Equal(t, 2, w.Batch().RemoveEntities(All(posID)))
+	assert.True(t, w.Alive(hidden))
+	assert.Equal(t, 1, w.Batch().RemoveEntities(All(hiddenID)))
+	assert.False(t, w.Alive(hidden))
+	assert.Equal(t, 0, w.Stats().Entities.Hidden)
+}
@@ -0,0 +1,174 @@
+package ecs
+
+// History provides undo and redo for a [World], e.g. for level editors.
+//
+// History works with checkpoints. Call [History.Checkpoint] before each user action,
+// like creating or removing entities, adding or removing components, or setting component values.
+// [History.Undo] restores the world to the state of the last checkpoint, and [History.Redo] reverts that.
+// A new checkpoint clears all redo steps.
+//
+// From the first checkpoint until [History.Clear], the world records its changes in a journal, like for transactions (see [Tx]),
+// and the same restrictions apply. In particular, component values changed through pointers are not recorded,
+// so use [World.Set] for changes that should be undone.
+// Undo and redo steps store the recorded changes between their states, as well as copies of the resource values.
+// The number of steps is limited to bound memory use. When the limit is exceeded, the oldest step is dropped.
+// Restoring a step does not emit events, and entities created after a step must not be used after restoring it.
+// Resource values are restored, while added and removed resources are not affected.
+//
+// Create a history with [NewHistory].
+//
+// Example:
+//
+//	history := NewHistory(&world, 32)
+//
+//	history.Checkpoint()
+//	world.RemoveEntity(selected)
+//
+//	history.Undo()
+//	history.Redo()
+type History struct {
+	world   *World
+	journal *journal // Changes since the last checkpoint, undo or redo. Nil if not recording.
+	undo    []historyStep
+	redo    []historyStep
+	limit   int
+}
+
+// historyStep is an undo or redo step of a [History].
+type historyStep struct {
+	changes   []change // Changes since the step's state, reverted to restore it.
+	resources []any    // Copies of the resource values in the step's state.
+}
+
+// NewHistory creates a new [History] for a world, with the given maximum number of undo steps.
+//
+// Panics if the limit is smaller than 1.
+func NewHistory(world *World, limit int) *History {
+	if limit < 1 {
+		panic("history limit must be at least 1")
+	}
+	return &History{
+		world: world,
+		limit: limit,
+	}
+}
+
+// Checkpoint records the current state of the world as an undo step, and clears all redo steps.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (h *History) Checkpoint() {
+	h.world.checkLocked()
+	if h.journal == nil {
+		h.journal = h.world.beginJournal()
+	}
+	h.flush()
+	h.undo = pushStep(h.undo, historyStep{resources: h.world.resourceValues()}, h.limit)
+	clear(h.redo)
+	h.redo = h.redo[:0]
+}
+
+// Undo restores the world to the state of the last checkpoint.
+// The current state is recorded as a redo step.
+// Returns false if there is nothing to undo.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (h *History) Undo() bool {
+	h.world.checkLocked()
+	if len(h.undo) == 0 {
+		return false
+	}
+	// Changes since the last undo or redo must be reverted before the next redo step.
+	if last := len(h.redo) - 1; last >= 0 {
+		h.redo[last].changes = append(h.redo[last].changes, h.journal.changes...)
+	}
+	h.flush()
+	h.redo = pushStep(h.redo, h.restore(popStep(&h.undo), nil), h.limit)
+	return true
+}
+
+// Redo reverts the last [History.Undo].
+// The current state is recorded as an undo step.
+// Returns false if there is nothing to redo.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (h *History) Redo() bool {
+	h.world.checkLocked()
+	if len(h.redo) == 0 {
+		return false
+	}
+	pending := h.journal.changes
+	h.flush()
+	h.undo = pushStep(h.undo, h.restore(popStep(&h.redo), pending), h.limit)
+	return true
+}
+
+// CanUndo returns whether there are undo steps.
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo returns whether there are redo steps.
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+// Clear removes all undo and redo steps, and stops recording changes until the next checkpoint.
+func (h *History) Clear() {
+	clear(h.undo)
+	clear(h.redo)
+	h.undo = h.undo[:0]
+	h.redo = h.redo[:0]
+	if h.journal != nil {
+		h.world.endJournal(h.journal)
+		h.journal = nil
+	}
+}
+
+// flush moves the changes since the last checkpoint, undo or redo into the last undo step.
+// Without undo steps, the changes are dropped.
+func (h *History) flush() {
+	if last := len(h.undo) - 1; last >= 0 {
+		h.undo[last].changes = append(h.undo[last].changes, h.journal.changes...)
+	}
+	h.journal.changes = nil
+}
+
+// restore reverts the given pending changes and the changes of a step, to restore the step's state.
+// Returns a step for restoring the current state.
+func (h *History) restore(step historyStep, pending []change) historyStep {
+	w := h.world
+	current := historyStep{resources: w.resourceValues()}
+
+	w.endJournal(h.journal)
+	capture := w.beginJournal()
+	w.revert(&journal{changes: pending}, 0)
+	w.revert(&journal{changes: step.changes}, 0)
+	w.endJournal(capture)
+	w.journals = append(w.journals, h.journal)
+
+	w.restoreResources(append([]any(nil), w.resources.resources...), step.resources)
+	current.changes = capture.changes
+	return current
+}
+
+// pushStep appends a step to a stack, and drops the oldest step if the limit is exceeded.
+func pushStep(stack []historyStep, step historyStep, limit int) []historyStep {
+	if len(stack) >= limit {
+		copy(stack, stack[1:])
+		stack[len(stack)-1] = historyStep{}
+		stack = stack[:len(stack)-1]
+	}
+	return append(stack, step)
+}
+
+// popStep removes the last step from a stack, and returns it.
+func popStep(stack *[]historyStep) historyStep {
+	last := len(*stack) - 1
+	step := (*stack)[last]
+	(*stack)[last] = historyStep{}
+	*stack = (*stack)[:last]
+	return step
+}
@@ -0,0 +1,153 @@
+package ecs
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	resID := AddResource(&w, &Position{X: 1})
+	res := w.Resources().Get(resID).(*Position)
+
+	h := NewHistory(&w, 8)
+	assert.False(t, h.CanUndo())
+	assert.False(t, h.CanRedo())
+	assert.False(t, h.Undo())
+	assert.False(t, h.Redo())
+
+	h.Checkpoint()
+	e := w.NewEntity(posID)
+
+	h.Checkpoint()
+	w.Add(e, velID)
+
+	h.Checkpoint()
+	w.Set(e, posID, &Position{X: 5})
+	res.X = 2
+
+	assert.True(t, h.CanUndo())
+
+	assert.True(t, h.Undo())
+	assert.Equal(t, 0, (*Position)(w.Get(e, posID)).X)
+	assert.Equal(t, 1, res.X)
+	assert.True(t, w.Has(e, velID))
+	assert.True(t, h.CanRedo())
+
+	assert.True(t, h.Undo())
+	assert.False(t, w.Has(e, velID))
+
+	assert.True(t, h.Undo())
+	assert.Equal(t, 0, w.entityPool.Len())
+	assert.False(t, h.CanUndo())
+	assert.False(t, h.Undo())
+
+	assert.True(t, h.Redo())
+	assert.True(t, w.Alive(e))
+	assert.True(t, h.Redo())
+	assert.True(t, w.Has(e, velID))
+	assert.True(t, h.Redo())
+	assert.Equal(t, 5, (*Position)(w.Get(e, posID)).X)
+	assert.Equal(t, 2, res.X)
+	assert.False(t, h.Redo())
+
+	assert.True(t, h.Undo())
+	h.Checkpoint()
+	assert.False(t, h.CanRedo())
+	w.RemoveEntity(e)
+	assert.True(t, h.Undo())
+	assert.True(t, w.Alive(e))
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { h.Undo() })
+	query.Close()
+
+	h.Clear()
+	assert.False(t, h.CanUndo())
+	assert.False(t, h.CanRedo())
+
+	assert.PanicsWithValue(t, "history limit must be at least 1", func() { NewHistory(&w, 0) })
+}
+
+func TestHistoryLimit(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	h := NewHistory(&w, 2)
+	for i := 0; i < 4; i++ {
+		h.Checkpoint()
+		w.NewEntity(posID)
+	}
+	assert.Equal(t, 2, len(h.undo))
+
+	assert.True(t, h.Undo())
+	assert.True(t, h.Undo())
+	assert.False(t, h.Undo())
+	assert.Equal(t, 2, w.entityPool.Len())
+
+	assert.True(t, h.Redo())
+	assert.True(t, h.Redo())
+	assert.Equal(t, 4, w.entityPool.Len())
+}
+
+func TestHistoryRandom(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		w := NewWorld()
+		posID := ComponentID[Position](&w)
+		velID := ComponentID[Velocity](&w)
+		relID := ComponentID[testRelationA](&w)
+		statusID := SparseComponentID[statusEffect](&w)
+		rng := rand.New(rand.NewSource(seed))
+
+		limit := 4
+		h := NewHistory(&w, limit)
+		var undo, redo []txState
+		push := func(stack []txState, st txState) []txState {
+			if len(stack) >= limit {
+				stack = stack[1:]
+			}
+			return append(stack, st)
+		}
+
+		for i := 0; i < 100; i++ {
+			switch rng.Intn(5) {
+			case 0:
+				h.Checkpoint()
+				undo = push(undo, captureTxState(&w, statusID))
+				redo = redo[:0]
+			case 1:
+				current := captureTxState(&w, statusID)
+				assert.Equal(t, len(undo) > 0, h.Undo())
+				if len(undo) > 0 {
+					redo = push(redo, current)
+					undo, current = undo[:len(undo)-1], undo[len(undo)-1]
+				}
+				assert.Equal(t, current, captureTxState(&w, statusID), "seed %d, step %d", seed, i)
+			case 2:
+				current := captureTxState(&w, statusID)
+				assert.Equal(t, len(redo) > 0, h.Redo())
+				if len(redo) > 0 {
+					undo = push(undo, current)
+					redo, current = redo[:len(redo)-1], redo[len(redo)-1]
+				}
+				assert.Equal(t, current, captureTxState(&w, statusID), "seed %d, step %d", seed, i)
+			case 3:
+				current := captureTxState(&w, statusID)
+				tx := w.Begin()
+				txRandomOps(&w, rng, rng.Intn(10), posID, velID, relID, statusID)
+				tx.Rollback()
+				assert.Equal(t, current, captureTxState(&w, statusID), "seed %d, step %d", seed, i)
+			default:
+				txRandomOps(&w, rng, rng.Intn(10), posID, velID, relID, statusID)
+			}
+			assert.Empty(t, w.verifyInvariants(), "seed %d, step %d", seed, i)
+		}
+
+		h.Clear()
+		assert.Empty(t, w.journals)
+	}
+}
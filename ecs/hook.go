@@ -0,0 +1,74 @@
+package ecs
+
+import "unsafe"
+
+// RemoveHook is called before a component is removed from an entity. See [World.SetRemoveHook].
+// Argument comp is a pointer to the component, which is valid only during the call.
+type RemoveHook func(entity Entity, comp unsafe.Pointer)
+
+// SetRemoveHook sets a hook that is called before a component is removed from an entity and destroyed.
+// Setting a nil hook removes the component's hook.
+//
+// Hooks allow for deterministic cleanup of components that own external resources,
+// like GPU buffers, file handles or physics bodies.
+// They are called for components removed by [World.Remove], [World.Exchange], [World.RemoveEntity]
+// and their batch and generic variants, as well as for dying entities when they are finally removed.
+// Hooks are not called by [World.Reset], nor for components of entities replaced
+// by [World.LoadSnapshot] or [World.ApplyDiff].
+//
+// The world is locked during hook calls, so hooks can read and modify component data,
+// but must not make structural changes. Use [World.Commands] to defer structural changes.
+//
+// For a generic variant, see [OnRemove].
+func (w *World) SetRemoveHook(comp ID, hook RemoveHook) {
+	if w.removeHooks == nil {
+		w.removeHooks = make([]RemoveHook, MaskTotalBits)
+	}
+	w.removeHooks[comp.id] = hook
+	w.hookedRemove.Set(comp, hook != nil)
+}
+
+// OnRemove sets a hook that is called before a component of type T is removed from an entity and destroyed.
+// Setting a nil hook removes the component's hook.
+// Registers the component type if it is not already registered.
+//
+// See [World.SetRemoveHook] for details.
+func OnRemove[T any](w *World, hook func(entity Entity, comp *T)) {
+	id := ComponentID[T](w)
+	if hook == nil {
+		w.SetRemoveHook(id, nil)
+		return
+	}
+	w.SetRemoveHook(id, func(entity Entity, comp unsafe.Pointer) {
+		hook(entity, (*T)(comp))
+	})
+}
+
+// hasRemoveHooks checks whether any of the given components has a remove hook.
+func (w *World) hasRemoveHooks(mask *Mask) bool {
+	return !w.hookedRemove.IsZero() && w.hookedRemove.ContainsAny(mask)
+}
+
+// callRemoveHooks calls the remove hooks for the components of an entity that are in the given mask.
+// The world must be locked.
+func (w *World) callRemoveHooks(arch *archetype, index uint32, entity Entity, mask *Mask) {
+	for _, id := range arch.node.Ids {
+		if mask.Get(id) && w.hookedRemove.Get(id) {
+			w.removeHooks[id.id](entity, arch.Get(index, id))
+		}
+	}
+}
+
+// callRemoveHooksArch calls the remove hooks for the components of all entities of an archetype
+// that are in the given mask.
+func (w *World) callRemoveHooksArch(arch *archetype, mask *Mask) {
+	if !w.hasRemoveHooks(mask) {
+		return
+	}
+	lock := w.lock()
+	var i uint32
+	for i = 0; i < arch.Len(); i++ {
+		w.callRemoveHooks(arch, i, arch.GetEntity(i), mask)
+	}
+	w.unlock(lock)
+}
@@ -0,0 +1,109 @@
+package ecs
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldRemoveHook(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	removed := []Position{}
+	entities := []Entity{}
+	OnRemove(&w, func(entity Entity, pos *Position) {
+		assert.True(t, w.IsLocked())
+		removed = append(removed, *pos)
+		entities = append(entities, entity)
+	})
+
+	e0 := w.NewEntity(posID, velID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := w.NewEntity(velID)
+	w.Set(e0, posID, &Position{X: 1})
+	w.Set(e1, posID, &Position{X: 2})
+
+	w.Remove(e0, velID)
+	w.Add(e2, posID)
+	assert.Equal(t, 0, len(removed))
+
+	w.Remove(e0, posID)
+	assert.Equal(t, []Position{{X: 1}}, removed)
+	assert.Equal(t, []Entity{e0}, entities)
+
+	w.Exchange(e1, []ID{ComponentID[rotation](&w)}, []ID{posID})
+	assert.Equal(t, []Position{{X: 1}, {X: 2}}, removed)
+
+	w.Set(e2, posID, &Position{X: 3})
+	w.RemoveEntity(e2)
+	assert.Equal(t, []Position{{X: 1}, {X: 2}, {X: 3}}, removed)
+	assert.Equal(t, []Entity{e0, e1, e2}, entities)
+
+	w.RemoveEntity(e0)
+	assert.Equal(t, 3, len(removed))
+
+	w.SetRemoveHook(posID, nil)
+	e3 := w.NewEntity(posID)
+	w.RemoveEntity(e3)
+	assert.Equal(t, 3, len(removed))
+}
+
+func TestWorldRemoveHookBatch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	count := 0
+	w.SetRemoveHook(velID, func(entity Entity, comp unsafe.Pointer) {
+		assert.Equal(t, Velocity{X: int(entity.id)}, *(*Velocity)(comp))
+		count++
+	})
+
+	query := NewBuilder(&w, posID, velID).NewBatchQ(10)
+	for query.Next() {
+		(*Velocity)(query.Get(velID)).X = int(query.Entity().id)
+	}
+	query = NewBuilder(&w, velID).NewBatchQ(5)
+	for query.Next() {
+		(*Velocity)(query.Get(velID)).X = int(query.Entity().id)
+	}
+
+	w.Batch().Remove(All(posID), velID)
+	assert.Equal(t, 10, count)
+	w.Batch().RemoveEntities(All(posID))
+	assert.Equal(t, 10, count)
+	w.Batch().RemoveEntities(All(velID))
+	assert.Equal(t, 15, count)
+}
+
+func TestWorldRemoveHookDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(1))
+	posID := ComponentID[Position](&w)
+
+	count := 0
+	OnRemove(&w, func(entity Entity, pos *Position) { count++ })
+
+	e := w.NewEntity(posID)
+	w.RemoveEntity(e)
+	assert.Equal(t, 0, count)
+	w.Tick()
+	assert.Equal(t, 1, count)
+
+	OnRemove[Position](&w, nil)
+	w.NewEntity(posID)
+	w.Batch().RemoveEntities(All(posID))
+	w.Tick()
+	assert.Equal(t, 1, count)
+}
+
+func TestWorldRemoveHookLocked(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	OnRemove(&w, func(entity Entity, pos *Position) { w.NewEntity() })
+
+	e := w.NewEntity(posID)
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.RemoveEntity(e) })
+}
@@ -0,0 +1,149 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxInvariantViolations is the maximum number of violations listed in an invariant report.
+const maxInvariantViolations = 16
+
+// verifyInvariants checks the consistency of the world's internal data structures.
+// Returns a description for each violation found, or nil if the world is consistent.
+//
+// Checked invariants are:
+//   - The entity index points each entity in an archetype back to that archetype and index.
+//   - Only alive entities are stored in archetypes, and dead entities have no archetype in the entity index.
+//   - The sum of archetype lengths equals the number of alive entities in the entity pool.
+//   - Node masks agree with the component IDs of the node, and with the masks of its archetypes.
+func (w *World) verifyInvariants() []string {
+	var errs []string
+	total := 0
+
+	numNodes := w.nodes.Len()
+	var i int32
+	for i = 0; i < numNodes; i++ {
+		node := w.nodes.Get(i)
+		errs = w.verifyNode(node, errs)
+
+		arches := node.Archetypes()
+		numArches := arches.Len()
+		var j int32
+		for j = 0; j < numArches; j++ {
+			arch := arches.Get(j)
+			if arch == nil {
+				continue
+			}
+			total += int(arch.Len())
+			errs = w.verifyArchetype(node, arch, errs)
+		}
+	}
+
+	if alive := w.entityPool.Len(); total != alive {
+		errs = append(errs, fmt.Sprintf("archetypes contain %d entities, but the entity pool has %d alive entities", total, alive))
+	}
+
+	for id := 1; id < len(w.entityPool.entities); id++ {
+		isAlive := w.entityPool.entities[id].id == eid(id)
+		if id >= len(w.entities) {
+			if isAlive {
+				errs = append(errs, fmt.Sprintf("alive entity %v is not in the entity index", w.entityPool.entities[id]))
+			}
+			continue
+		}
+		if hasArch := w.entities[id].arch != nil; hasArch != isAlive {
+			if isAlive {
+				errs = append(errs, fmt.Sprintf("alive entity %v has no archetype in the entity index", w.entityPool.entities[id]))
+			} else {
+				errs = append(errs, fmt.Sprintf("dead entity ID %d still has an archetype in the entity index", id))
+			}
+		}
+	}
+
+	return errs
+}
+
+// verifyNode checks the agreement of an archetype node's mask and component IDs.
+func (w *World) verifyNode(node *archNode, errs []string) []string {
+	if node.nodeData == nil {
+		return append(errs, fmt.Sprintf("node %v has no data", componentIDs(&node.Mask)))
+	}
+	if cnt := node.Mask.TotalBitsSet(); cnt != len(node.Ids) {
+		errs = append(errs, fmt.Sprintf("node %v has %d mask bits, but %d component IDs", componentIDs(&node.Mask), cnt, len(node.Ids)))
+	}
+	for _, id := range node.Ids {
+		if !node.Mask.Get(id) {
+			errs = append(errs, fmt.Sprintf("node %v has component ID %d that is not in its mask", componentIDs(&node.Mask), id.id))
+		}
+	}
+	return errs
+}
+
+// verifyArchetype checks an archetype against its node and the entity index.
+func (w *World) verifyArchetype(node *archNode, arch *archetype, errs []string) []string {
+	if arch.node != node {
+		errs = append(errs, fmt.Sprintf("archetype %v is stored in node %v, but points to another node", componentIDs(&arch.Mask), componentIDs(&node.Mask)))
+	}
+	if arch.Mask != node.Mask {
+		errs = append(errs, fmt.Sprintf("archetype mask %v does not match its node mask %v", componentIDs(&arch.Mask), componentIDs(&node.Mask)))
+	}
+	if arch.HasRelationComponent != node.HasRelation {
+		errs = append(errs, fmt.Sprintf("archetype %v has relation %t, but its node has relation %t", componentIDs(&arch.Mask), arch.HasRelationComponent, node.HasRelation))
+	}
+	if arch.len > arch.cap {
+		errs = append(errs, fmt.Sprintf("archetype %v has length %d, exceeding its capacity %d", componentIDs(&arch.Mask), arch.len, arch.cap))
+		return errs
+	}
+
+	ln := arch.Len()
+	var i uint32
+	for i = 0; i < ln; i++ {
+		entity := arch.GetEntity(i)
+		if entity.id == 0 || int(entity.id) >= len(w.entityPool.entities) {
+			errs = append(errs, fmt.Sprintf("archetype %v contains invalid entity %v at index %d", componentIDs(&arch.Mask), entity, i))
+			continue
+		}
+		if !w.entityPool.Alive(entity) {
+			errs = append(errs, fmt.Sprintf("archetype %v contains dead entity %v at index %d", componentIDs(&arch.Mask), entity, i))
+		}
+		if int(entity.id) >= len(w.entities) {
+			errs = append(errs, fmt.Sprintf("entity %v in archetype %v is not in the entity index", entity, componentIDs(&arch.Mask)))
+			continue
+		}
+		index := w.entities[entity.id]
+		if index.arch != arch || index.index != i {
+			var mask Mask
+			if index.arch != nil {
+				mask = index.arch.Mask
+			}
+			errs = append(errs, fmt.Sprintf("entity %v is at index %d of archetype %v, but the entity index points to index %d of archetype %v",
+				entity, i, componentIDs(&arch.Mask), index.index, componentIDs(&mask)))
+		}
+	}
+	return errs
+}
+
+// componentIDs returns the IDs of the components in a mask, for reporting.
+func componentIDs(mask *Mask) []uint8 {
+	ids := make([]uint8, 0, mask.TotalBitsSet())
+	for i := 0; i < MaskTotalBits; i++ {
+		if mask.Get(ID{id: uint8(i)}) {
+			ids = append(ids, uint8(i))
+		}
+	}
+	return ids
+}
+
+// invariantReport formats violations found by [World.verifyInvariants] after an operation.
+func invariantReport(op string, errs []string) string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "world invariants violated after %s (%d violations):", op, len(errs))
+	for i, err := range errs {
+		if i == maxInvariantViolations {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(errs)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n  - %s", err)
+	}
+	return b.String()
+}
@@ -0,0 +1,16 @@
+//go:build checked
+
+package ecs
+
+const isChecked = true
+
+// checkInvariants verifies the world's internal consistency after an operation.
+// Panics with a detailed report on any violation.
+//
+// Only active with build tag checked. Verification is linear in the number of entities,
+// so the checked mode is intended for testing and debugging only.
+func (w *World) checkInvariants(op string) {
+	if errs := w.verifyInvariants(); len(errs) > 0 {
+		panic(invariantReport(op, errs))
+	}
+}
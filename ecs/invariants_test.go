@@ -0,0 +1,102 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldVerifyInvariants(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[relationComp](&w)
+
+	assert.Empty(t, w.verifyInvariants())
+
+	parent := w.NewEntity(posID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := w.NewEntity(posID, relID)
+	NewBuilder(&w, posID, velID).NewBatch(10)
+	assert.Empty(t, w.verifyInvariants())
+
+	w.Relations().Set(e2, relID, parent)
+	w.Remove(e1, velID)
+	w.RemoveEntity(parent)
+	w.Batch().RemoveEntities(All(velID))
+	assert.Empty(t, w.verifyInvariants())
+
+	w.Reset()
+	assert.Empty(t, w.verifyInvariants())
+}
+
+func TestWorldVerifyInvariantsViolations(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+
+	w.entities[e1.id].index, w.entities[e2.id].index = w.entities[e2.id].index, w.entities[e1.id].index
+	errs := w.verifyInvariants()
+	assert.Equal(t, []string{
+		"entity {1 0} is at index 0 of archetype [0], but the entity index points to index 1 of archetype [0]",
+		"entity {2 0} is at index 1 of archetype [0], but the entity index points to index 0 of archetype [0]",
+	}, errs)
+	w.entities[e1.id].index, w.entities[e2.id].index = w.entities[e2.id].index, w.entities[e1.id].index
+	assert.Empty(t, w.verifyInvariants())
+
+	w.entityPool.Recycle(e2)
+	errs = w.verifyInvariants()
+	assert.Equal(t, []string{
+		"archetype [0] contains dead entity {2 0} at index 1",
+		"archetypes contain 2 entities, but the entity pool has 1 alive entities",
+		"dead entity ID 2 still has an archetype in the entity index",
+	}, errs)
+
+	w = NewWorld()
+	posID = ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	w.NewEntity(posID)
+
+	node := w.entities[1].arch.node
+	node.Ids = append(node.Ids, velID)
+	errs = w.verifyInvariants()
+	assert.Equal(t, []string{
+		"node [0] has 1 mask bits, but 2 component IDs",
+		"node [0] has component ID 1 that is not in its mask",
+	}, errs)
+}
+
+func TestWorldCheckInvariants(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e1 := w.NewEntity(posID)
+	w.entities[e1.id].index = 5
+
+	if isChecked {
+		assert.PanicsWithValue(t,
+			"world invariants violated after NewEntity (1 violations):\n"+
+				"  - entity {1 0} is at index 0 of archetype [0], but the entity index points to index 5 of archetype [0]",
+			func() { w.NewEntity(posID) })
+	} else {
+		assert.NotPanics(t, func() { w.NewEntity(posID) })
+	}
+}
+
+func TestInvariantReport(t *testing.T) {
+	errs := make([]string, maxInvariantViolations+3)
+	for i := range errs {
+		errs[i] = fmt.Sprintf("error %d", i)
+	}
+	report := invariantReport("Tick", errs)
+	lines := strings.Split(report, "\n")
+
+	assert.Equal(t, maxInvariantViolations+2, len(lines))
+	assert.Equal(t, "world invariants violated after Tick (19 violations):", lines[0])
+	assert.Equal(t, "  - error 0", lines[1])
+	assert.Equal(t, "  ... and 3 more", lines[len(lines)-1])
+}
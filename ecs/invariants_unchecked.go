@@ -0,0 +1,7 @@
+//go:build !checked
+
+package ecs
+
+const isChecked = false
+
+func (w *World) checkInvariants(op string) {}
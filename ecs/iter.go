@@ -0,0 +1,76 @@
+//go:build go1.23
+
+package ecs
+
+import "iter"
+
+// Iter returns an iterator over the query's entities, for use with range-over-func loops.
+// The second value is the query itself, for accessing the components of the current entity.
+//
+// The query is closed when the loop finishes or is left early with break or return.
+//
+// Example:
+//
+//	query := world.Query(All(posID, velID))
+//	for e, q := range query.Iter() {
+//		pos := (*Position)(q.Get(posID))
+//		vel := (*Velocity)(q.Get(velID))
+//		pos.X += vel.X
+//		_ = e
+//	}
+//
+// Requires Go 1.23 or later.
+func (q *Query) Iter() iter.Seq2[Entity, *Query] {
+	return func(yield func(Entity, *Query) bool) {
+		for q.Next() {
+			if !yield(q.Entity(), q) {
+				q.Close()
+				return
+			}
+		}
+	}
+}
+
+// IterChunks returns an iterator over the chunks of the query's archetypes, for use with range-over-func loops.
+// Yields the query positioned at the first entity of each chunk,
+// for column access with [Query.Column] or [ColumnSlice].
+// See [Query.NextChunk] for details.
+//
+// The query is closed when the loop finishes or is left early with break or return.
+//
+// Example:
+//
+//	query := world.Query(All(posID))
+//	for q := range query.IterChunks() {
+//		for _, pos := range ColumnSlice[Position](q, posID) {
+//			// ...
+//		}
+//	}
+//
+// Requires Go 1.23 or later.
+func (q *Query) IterChunks() iter.Seq[*Query] {
+	return func(yield func(*Query) bool) {
+		for q.NextChunk() {
+			if !yield(q) {
+				q.Close()
+				return
+			}
+		}
+	}
+}
+
+// Iter returns an iterator over all present resources and their IDs, for use with range-over-func loops.
+//
+// Requires Go 1.23 or later.
+func (r *Resources) Iter() iter.Seq2[ResID, any] {
+	return func(yield func(ResID, any) bool) {
+		for i, res := range r.resources {
+			if res == nil {
+				continue
+			}
+			if !yield(ResID{id: uint8(i)}, res) {
+				return
+			}
+		}
+	}
+}
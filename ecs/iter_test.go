@@ -0,0 +1,80 @@
+//go:build go1.23
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryIter(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	all := []Entity{}
+	for i := 0; i < 5; i++ {
+		all = append(all, w.NewEntity(posID))
+	}
+	for i := 0; i < 5; i++ {
+		all = append(all, w.NewEntity(posID, velID))
+	}
+
+	query := w.Query(All(posID))
+	entities := []Entity{}
+	for e, q := range query.Iter() {
+		entities = append(entities, e)
+		(*Position)(q.Get(posID)).X = len(entities)
+	}
+	assert.Equal(t, all, entities)
+	assert.False(t, w.IsLocked())
+	assert.Equal(t, 10, (*Position)(w.Get(all[9], posID)).X)
+
+	query = w.Query(All(posID))
+	cnt := 0
+	for range query.Iter() {
+		cnt++
+		if cnt == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, cnt)
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(All(posID))
+	lengths := []int{}
+	for q := range query.IterChunks() {
+		lengths = append(lengths, len(ColumnSlice[Position](q, posID)))
+	}
+	assert.Equal(t, []int{5, 5}, lengths)
+	assert.False(t, w.IsLocked())
+
+	query = w.Query(All(posID))
+	for range query.IterChunks() {
+		break
+	}
+	assert.False(t, w.IsLocked())
+}
+
+func TestResourcesIter(t *testing.T) {
+	w := NewWorld()
+	posID := AddResource(&w, &Position{1, 2})
+	velID := AddResource(&w, &Velocity{3, 4})
+
+	ids := []ResID{}
+	values := []any{}
+	for id, res := range w.Resources().Iter() {
+		ids = append(ids, id)
+		values = append(values, res)
+	}
+	assert.Equal(t, []ResID{posID, velID}, ids)
+	assert.Equal(t, []any{&Position{1, 2}, &Velocity{3, 4}}, values)
+
+	cnt := 0
+	for range w.Resources().Iter() {
+		cnt++
+		break
+	}
+	assert.Equal(t, 1, cnt)
+}
@@ -0,0 +1,341 @@
+package ecs
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// changeKind is the kind of a recorded world change.
+type changeKind uint8
+
+const (
+	changeGet          changeKind = iota // Entity taken from the pool and placed in the root archetype.
+	changeUnget                          // Entity removed from the root archetype and handed back to the pool. Inverse of changeGet.
+	changeRecycle                        // Entity removed from the root archetype and recycled.
+	changeUnrecycle                      // Recycled entity placed in the root archetype again. Inverse of changeRecycle.
+	changeMove                           // Entity moved to another archetype.
+	changeSet                            // Component value overwritten.
+	changeDisable                        // Entity disabled.
+	changeEnable                         // Entity enabled.
+	changeSparseAdd                      // Sparse-set component added.
+	changeSparseRemove                   // Sparse-set component removed.
+	changeSparseSet                      // Sparse-set component value overwritten.
+	changeTick                           // World tick advanced.
+	changeState                          // Entire world state replaced, e.g. by [World.Reset].
+)
+
+// change is a reversible change of a world, recorded in a [journal].
+//
+// A change holds the state that it overwrote, so that it can be reverted.
+// Entity creation and removal are recorded as a move between the entity's archetype
+// and the root archetype, and a change that takes the entity from or hands it back to the pool.
+type change struct {
+	kind      changeKind
+	entity    Entity
+	fresh     bool             // Get/Unget: the entity ID was not recycled.
+	target    bool             // Entity changes: the entity was a relation target.
+	id        uint8            // Component or sparse-set component ID.
+	ids       []ID             // Move: components before the move.
+	relTarget Entity           // Move: relation target before the move.
+	values    []unsafe.Pointer // Copies of the overwritten component values.
+	tick      uint64           // Tick: tick before the change. Entity changes: the entity's creation tick.
+	quota     *quota           // Entity changes: the entity's quota.
+	state     *World           // State: clone of the world before the change.
+	resources []any            // State: resources before the change.
+}
+
+// journal records reversible changes of a world, for [Tx] and [History].
+type journal struct {
+	changes []change
+}
+
+// beginJournal starts recording changes into a new journal.
+func (w *World) beginJournal() *journal {
+	j := &journal{}
+	w.journals = append(w.journals, j)
+	return j
+}
+
+// endJournal stops recording changes into a journal.
+func (w *World) endJournal(j *journal) {
+	for i, jr := range w.journals {
+		if jr == j {
+			last := len(w.journals) - 1
+			copy(w.journals[i:], w.journals[i+1:])
+			w.journals[last] = nil
+			w.journals = w.journals[:last]
+			return
+		}
+	}
+}
+
+// record appends a change to all active journals.
+func (w *World) record(c change) {
+	for _, j := range w.journals {
+		j.changes = append(j.changes, c)
+	}
+}
+
+// revert reverts the changes of a journal after the given position, in reverse order, and drops them.
+// The journal must not be active. The reverting changes are recorded into all active journals.
+//
+// Does not emit events and does not call remove hooks. Entities reserved with [World.Reserve] are dropped.
+func (w *World) revert(j *journal, from int) {
+	if len(j.changes) > from {
+		w.epoch++
+	}
+	w.entityPool.DropReserved()
+	for i := len(j.changes) - 1; i >= from; i-- {
+		w.revertChange(&j.changes[i])
+		j.changes[i] = change{}
+	}
+	j.changes = j.changes[:from]
+	w.checkInvariants("Revert")
+}
+
+// revertChange reverts a single change, and records the inverse change.
+func (w *World) revertChange(c *change) {
+	e := c.entity
+	switch c.kind {
+	case changeGet:
+		inv := w.takeEntity(e)
+		w.entityPool.Unget(e, c.fresh)
+		if len(w.entities) > len(w.entityPool.entities) {
+			w.entities = w.entities[:len(w.entityPool.entities)]
+		}
+		inv.kind, inv.fresh = changeUnget, c.fresh
+		w.record(inv)
+	case changeUnget:
+		if got := w.entityPool.Get(); got != e {
+			panic("journal is out of sync with the world")
+		}
+		w.putEntity(c)
+		w.record(change{kind: changeGet, entity: e, fresh: c.fresh})
+	case changeRecycle:
+		w.entityPool.Unrecycle(e)
+		w.putEntity(c)
+		w.record(change{kind: changeUnrecycle, entity: e})
+	case changeUnrecycle:
+		inv := w.takeEntity(e)
+		w.entityPool.Recycle(e)
+		inv.kind = changeRecycle
+		w.record(inv)
+	case changeMove:
+		w.revertMove(c)
+	case changeSet:
+		index := &w.entities[e.id]
+		id := ID{id: c.id}
+		ptr := index.arch.Get(index.index, id)
+		tp := w.registry.Types[c.id]
+		w.record(change{kind: changeSet, entity: e, id: c.id, values: []unsafe.Pointer{saveValue(tp, ptr)}})
+		copyValue(tp, c.values[0], ptr)
+		if w.changes != nil {
+			w.changes.Change(e.id, id)
+		}
+	case changeDisable:
+		w.setDisabled(e, false)
+	case changeEnable:
+		w.setDisabled(e, true)
+	case changeSparseAdd:
+		set := w.sparse.sets[c.id]
+		w.record(change{kind: changeSparseRemove, entity: e, id: c.id, values: []unsafe.Pointer{saveValue(set.Type, set.Get(e.id))}})
+		set.Remove(e.id)
+	case changeSparseRemove:
+		set := w.sparse.Set(SparseID{id: c.id})
+		copyValue(set.Type, c.values[0], set.Add(e))
+		w.record(change{kind: changeSparseAdd, entity: e, id: c.id})
+	case changeSparseSet:
+		set := w.sparse.sets[c.id]
+		ptr := set.Get(e.id)
+		w.record(change{kind: changeSparseSet, entity: e, id: c.id, values: []unsafe.Pointer{saveValue(set.Type, ptr)}})
+		copyValue(set.Type, c.values[0], ptr)
+	case changeTick:
+		w.record(change{kind: changeTick, tick: w.tick})
+		w.tick = c.tick
+	case changeState:
+		w.record(change{kind: changeState, state: w.Clone(), resources: append([]any(nil), w.resources.resources...)})
+		// The state may be shared with other journals, so it is copied before moving it into the world.
+		w.restore(c.state.Clone(), c.resources)
+	}
+}
+
+// revertMove moves an entity back to the archetype it had before a recorded move.
+func (w *World) revertMove(c *change) {
+	index := &w.entities[c.entity.id]
+	oldArch := index.arch
+
+	var mask Mask
+	add := []ID{}
+	for _, id := range c.ids {
+		mask.Set(id, true)
+		if !oldArch.Mask.Get(id) {
+			add = append(add, id)
+		}
+	}
+	rem := []ID{}
+	for _, id := range oldArch.node.Ids {
+		if !mask.Get(id) {
+			rem = append(rem, id)
+		}
+	}
+	arch := w.findOrCreateArchetype(oldArch, add, rem, c.relTarget)
+	if arch == oldArch {
+		return
+	}
+	w.record(change{kind: changeMove, entity: c.entity, ids: oldArch.node.Ids, relTarget: oldArch.RelationTarget,
+		values: w.saveComponents(oldArch, index.index, &arch.Mask)})
+
+	newIndex := arch.Alloc(c.entity)
+	v := 0
+	for _, id := range arch.node.dataIds {
+		if oldArch.Mask.Get(id) {
+			arch.SetPointer(newIndex, id, oldArch.Get(index.index, id))
+		} else {
+			arch.SetPointer(newIndex, id, c.values[v])
+			v++
+		}
+	}
+
+	w.removeFromArchetype(oldArch, index.index)
+	w.entities[c.entity.id] = entityIndex{arch: arch, index: newIndex}
+	w.moveDisabled(c.entity.id, oldArch, arch)
+	if w.changes != nil {
+		w.changes.Move(c.entity.id, oldArch, arch)
+	}
+	if !arch.RelationTarget.IsZero() {
+		w.targetEntities.Set(arch.RelationTarget.id, true)
+	}
+	w.cleanupArchetype(oldArch)
+}
+
+// takeEntity removes an entity from the root archetype, before handing it back to the pool.
+// Returns a change holding the entity's state, to put it back later.
+func (w *World) takeEntity(entity Entity) change {
+	index := &w.entities[entity.id]
+	root := w.archetypes.Get(0)
+	if index.arch != root {
+		panic("journal is out of sync with the world")
+	}
+	c := w.entityState(entity)
+	w.removeFromArchetype(root, index.index)
+	index.arch = nil
+	w.targetEntities.Set(entity.id, false)
+	if w.quotas != nil {
+		w.quotas.Remove(entity.id)
+	}
+	return c
+}
+
+// putEntity places an entity taken from the pool in the root archetype, and restores its state from a change.
+func (w *World) putEntity(c *change) {
+	root := w.archetypes.Get(0)
+	entity := c.entity
+	idx := root.Alloc(entity)
+	if int(entity.id) >= len(w.entities) {
+		w.entities = append(w.entities, make([]entityIndex, int(entity.id)+1-len(w.entities))...)
+		w.targetEntities.ExtendTo(len(w.entities) + w.config.CapacityIncrement)
+	}
+	w.entities[entity.id] = entityIndex{arch: root, index: idx}
+	w.targetEntities.Set(entity.id, c.target)
+	if w.lifetimes != nil {
+		w.lifetimes.Load(entity.id, c.tick)
+	}
+	if w.changes != nil {
+		w.changes.Create(entity.id, &root.Mask)
+	}
+	if w.quotas != nil && c.quota != nil {
+		w.quotas.Add(c.quota, entity)
+	}
+}
+
+// entityState returns a change holding the state of an entity that is not stored in archetypes.
+func (w *World) entityState(entity Entity) change {
+	c := change{entity: entity, target: w.targetEntities.Get(entity.id)}
+	if w.lifetimes != nil {
+		c.tick = w.lifetimes.births[entity.id]
+	}
+	if w.quotas != nil && int(entity.id) < len(w.quotas.origins) {
+		c.quota = w.quotas.origins[entity.id]
+	}
+	return c
+}
+
+// recordCreate records the creation of an entity in an archetype.
+func (w *World) recordCreate(entity Entity, fresh bool, arch *archetype) {
+	w.record(change{kind: changeGet, entity: entity, fresh: fresh})
+	if arch != w.archetypes.Get(0) {
+		w.record(change{kind: changeMove, entity: entity})
+	}
+}
+
+// recordRemove records the removal of an entity at the given index of an archetype,
+// including its components, sparse-set components and disabled state.
+func (w *World) recordRemove(entity Entity, arch *archetype, index uint32) {
+	if root := w.archetypes.Get(0); arch != root {
+		w.recordMove(entity, arch, index, root)
+	}
+	for _, id := range w.sparse.registry.IDs {
+		if set := w.sparse.sets[id]; set != nil && set.Has(entity.id) {
+			w.record(change{kind: changeSparseRemove, entity: entity, id: id, values: []unsafe.Pointer{saveValue(set.Type, set.Get(entity.id))}})
+		}
+	}
+	if w.isDisabled(entity.id) {
+		w.record(change{kind: changeEnable, entity: entity})
+	}
+	c := w.entityState(entity)
+	c.kind = changeRecycle
+	w.record(c)
+}
+
+// recordMove records a move of the entity at the given index of an archetype to another archetype.
+func (w *World) recordMove(entity Entity, from *archetype, index uint32, to *archetype) {
+	w.record(change{kind: changeMove, entity: entity, ids: from.node.Ids, relTarget: from.RelationTarget,
+		values: w.saveComponents(from, index, &to.Mask)})
+}
+
+// recordSet records the value of a component before it is overwritten.
+func (w *World) recordSet(entity Entity, id ID) {
+	index := &w.entities[entity.id]
+	tp := w.registry.Types[id.id]
+	w.record(change{kind: changeSet, entity: entity, id: id.id, values: []unsafe.Pointer{saveValue(tp, index.arch.Get(index.index, id))}})
+}
+
+// recordSparse records a change of a sparse-set component, with the value before the change if there is one.
+func (w *World) recordSparse(kind changeKind, entity Entity, id SparseID, set *sparseSet) {
+	c := change{kind: kind, entity: entity, id: id.id}
+	if kind != changeSparseAdd {
+		c.values = []unsafe.Pointer{saveValue(set.Type, set.Get(entity.id))}
+	}
+	w.record(c)
+}
+
+// recordState records the entire world state, before it is replaced.
+func (w *World) recordState() {
+	w.record(change{kind: changeState, state: w.Clone(), resources: append([]any(nil), w.resources.resources...)})
+}
+
+// saveComponents returns copies of the components of the entity at the given index of an archetype
+// that are not in the given mask, in the order of the archetype's components with data.
+func (w *World) saveComponents(arch *archetype, index uint32, keep *Mask) []unsafe.Pointer {
+	var values []unsafe.Pointer
+	for _, id := range arch.node.dataIds {
+		if !keep.Get(id) {
+			values = append(values, saveValue(w.registry.Types[id.id], arch.Get(index, id)))
+		}
+	}
+	return values
+}
+
+// saveValue returns a pointer to a copy of a value.
+// The copy is allocated with its type, so that pointers in it are tracked by the garbage collector.
+func saveValue(tp reflect.Type, src unsafe.Pointer) unsafe.Pointer {
+	dst := reflect.New(tp).UnsafePointer()
+	copyValue(tp, src, dst)
+	return dst
+}
+
+// copyValue copies a value from one pointer to another.
+func copyValue(tp reflect.Type, src, dst unsafe.Pointer) {
+	size := tp.Size()
+	copy(unsafe.Slice((*byte)(dst), size), unsafe.Slice((*byte)(src), size))
+}
@@ -0,0 +1,126 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/stats"
+
+// LeakDetection configures the detection of leaked entities, for debugging. See [World.SetLeakDetection].
+//
+// An entity is an orphan if it has no components, or only components listed as markers.
+// Orphans that stay orphans for the given number of ticks are considered leaked.
+type LeakDetection struct {
+	Ticks    int                             // Number of ticks until an orphan is considered leaked. Zero to report orphans immediately.
+	Markers  []ID                            // Marker components. Entities with only markers are orphans.
+	Callback func(w *World, leaked []Entity) // Called by [World.Tick] with newly leaked entities. Optional.
+}
+
+// leakTracker tracks orphaned entities.
+type leakTracker struct {
+	config   LeakDetection
+	markers  Mask
+	orphans  map[Entity]orphan // Currently orphaned entities.
+	next     map[Entity]orphan // Buffer for the next scan.
+	leaked   []Entity          // Currently leaked entities.
+	reported int               // Total number of reported leaks.
+}
+
+// orphan is the state of an orphaned entity.
+type orphan struct {
+	Since    uint64 // Tick since the entity is an orphan.
+	Reported bool   // Whether the entity was reported as leaked.
+}
+
+// orphanFilter matches masks that contain only marker components.
+type orphanFilter struct {
+	markers Mask
+}
+
+// Matches the filter against a mask.
+func (f *orphanFilter) Matches(bits *Mask) bool {
+	notMarkers := f.markers.Not()
+	rest := bits.And(&notMarkers)
+	return rest.IsZero()
+}
+
+// SetLeakDetection enables detection of leaked entities, for debugging. Use nil to disable it.
+//
+// Entities that have no components, or only marker components, for a certain number of ticks are considered leaked.
+// Such entities are a common bug, e.g. from forgetting to remove entities after removing their last component.
+// Detection runs in [World.Tick], which scans all orphaned entities.
+// It is intended for debugging, not for production use.
+//
+// Newly leaked entities are reported to the callback of the [LeakDetection], if given.
+// Currently leaked entities can be retrieved with [World.Leaked],
+// and their number is reported by [World.Stats].
+// Entities are reported once, until they stop being orphans.
+//
+// Panics when called on a locked world.
+func (w *World) SetLeakDetection(detection *LeakDetection) {
+	w.checkLocked()
+	if detection == nil {
+		w.leaks = nil
+		return
+	}
+	w.leaks = &leakTracker{
+		config:  *detection,
+		markers: All(detection.Markers...),
+		orphans: map[Entity]orphan{},
+		next:    map[Entity]orphan{},
+	}
+}
+
+// Leaked returns the entities that are currently considered leaked by leak detection.
+// Returns nil if leak detection is not enabled. See [World.SetLeakDetection].
+//
+// The returned slice must not be modified, and is only valid until the next call to [World.Tick].
+func (w *World) Leaked() []Entity {
+	if w.leaks == nil {
+		return nil
+	}
+	return w.leaks.leaked
+}
+
+// detectLeaks scans orphaned entities and reports newly leaked entities.
+func (w *World) detectLeaks() {
+	t := w.leaks
+	t.leaked = t.leaked[:0]
+	newLeaks := []Entity{}
+
+	query := w.Query(&orphanFilter{markers: t.markers})
+	query.withDisabled = true
+	for query.Next() {
+		e := query.Entity()
+		o, ok := t.orphans[e]
+		if !ok || o.Since > w.tick {
+			o = orphan{Since: w.tick}
+		}
+		if w.tick-o.Since >= uint64(t.config.Ticks) {
+			t.leaked = append(t.leaked, e)
+			if !o.Reported {
+				o.Reported = true
+				newLeaks = append(newLeaks, e)
+			}
+		}
+		t.next[e] = o
+	}
+
+	clear(t.orphans)
+	t.orphans, t.next = t.next, t.orphans
+	t.reported += len(newLeaks)
+
+	if len(newLeaks) > 0 && t.config.Callback != nil {
+		t.config.Callback(w, newLeaks)
+	}
+}
+
+// Stats writes leak statistics to the given stats struct.
+func (t *leakTracker) Stats(st *stats.Leaks) {
+	st.Orphans = len(t.orphans)
+	st.Leaked = len(t.leaked)
+	st.Reported = t.reported
+}
+
+// Reset clears all tracked entities, but keeps the configuration.
+func (t *leakTracker) Reset() {
+	clear(t.orphans)
+	t.leaked = t.leaked[:0]
+	t.reported = 0
+}
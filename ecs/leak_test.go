@@ -0,0 +1,72 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldLeakDetection(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	markerID := ComponentID[label](&w)
+
+	reported := [][]Entity{}
+	w.SetLeakDetection(&LeakDetection{
+		Ticks:   2,
+		Markers: []ID{markerID},
+		Callback: func(w *World, leaked []Entity) {
+			reported = append(reported, append([]Entity{}, leaked...))
+		},
+	})
+	assert.Empty(t, w.Leaked())
+
+	e1 := w.NewEntity()
+	e2 := w.NewEntity(markerID)
+	e3 := w.NewEntity(posID, markerID)
+	w.NewEntity(posID)
+
+	w.Tick()
+	w.Tick()
+	assert.Empty(t, reported)
+	assert.Empty(t, w.Leaked())
+	assert.Equal(t, 2, w.Stats().Leaks.Orphans)
+
+	w.Tick()
+	assert.Equal(t, [][]Entity{{e1, e2}}, reported)
+	assert.Equal(t, []Entity{e1, e2}, w.Leaked())
+
+	w.Remove(e3, posID)
+	w.Add(e2, posID)
+	w.Tick()
+	assert.Equal(t, 1, len(reported))
+	assert.Equal(t, []Entity{e1}, w.Leaked())
+
+	w.Tick()
+	w.Tick()
+	assert.Equal(t, [][]Entity{{e1, e2}, {e3}}, reported)
+
+	st := w.Stats().Leaks
+	assert.Equal(t, 2, st.Orphans)
+	assert.Equal(t, 2, st.Leaked)
+	assert.Equal(t, 3, st.Reported)
+
+	w.Reset()
+	w.Tick()
+	assert.Empty(t, w.Leaked())
+	assert.Equal(t, 0, w.Stats().Leaks.Reported)
+
+	w.SetLeakDetection(nil)
+	assert.Nil(t, w.Leaked())
+	assert.Nil(t, w.Stats().Leaks)
+}
+
+func TestWorldLeakDetectionImmediate(t *testing.T) {
+	w := NewWorld()
+	w.SetLeakDetection(&LeakDetection{})
+
+	e := w.NewEntity()
+	w.Disable(e)
+	w.Tick()
+	assert.Equal(t, []Entity{e}, w.Leaked())
+}
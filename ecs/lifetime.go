@@ -0,0 +1,78 @@
+package ecs
+
+import (
+	"math/bits"
+
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
+// lifetimeTracker records creation ticks and lifetimes of entities.
+// See [Config.EntityLifetimes].
+type lifetimeTracker struct {
+	births  []uint64 // Creation tick by entity ID.
+	created int      // Number of created entities.
+	removed int      // Number of removed entities.
+	total   uint64   // Sum of the lifetimes of all removed entities.
+	buckets []int    // Lifetime histogram. See [stats.Lifetimes.Histogram].
+}
+
+// Create records the creation of an entity.
+func (t *lifetimeTracker) Create(id eid, tick uint64) {
+	t.Load(id, tick)
+	t.created++
+}
+
+// Load records the creation tick of a loaded entity, without counting it as created.
+func (t *lifetimeTracker) Load(id eid, tick uint64) {
+	for int(id) >= len(t.births) {
+		t.births = append(t.births, 0)
+	}
+	t.births[id] = tick
+}
+
+// Remove records the removal of an entity.
+func (t *lifetimeTracker) Remove(id eid, tick uint64) {
+	lifetime := tick - t.births[id]
+	bucket := bits.Len64(lifetime)
+	for bucket >= len(t.buckets) {
+		t.buckets = append(t.buckets, 0)
+	}
+	t.buckets[bucket]++
+	t.total += lifetime
+	t.removed++
+}
+
+// Reset clears all recorded data.
+func (t *lifetimeTracker) Reset() {
+	t.births = t.births[:0]
+	t.created = 0
+	t.removed = 0
+	t.total = 0
+	t.buckets = t.buckets[:0]
+}
+
+// Stats writes lifetime statistics to the given stats struct.
+func (t *lifetimeTracker) Stats(st *stats.Lifetimes) {
+	st.Created = t.created
+	st.Removed = t.removed
+	st.Mean = 0
+	if t.removed > 0 {
+		st.Mean = float64(t.total) / float64(t.removed)
+	}
+	st.Histogram = append(st.Histogram[:0], t.buckets...)
+}
+
+// EntityTick returns the tick at which an entity was created.
+//
+// Requires [Config.EntityLifetimes] to be enabled, panics otherwise.
+// Panics when called for a removed (and potentially recycled) entity.
+// See also [World.Tick].
+func (w *World) EntityTick(entity Entity) uint64 {
+	if w.lifetimes == nil {
+		panic("entity lifetime tracking is not enabled in the world config")
+	}
+	if !w.entityPool.Alive(entity) {
+		panic("can't get the creation tick of a dead entity")
+	}
+	return w.lifetimes.births[entity.id]
+}
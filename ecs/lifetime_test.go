@@ -0,0 +1,56 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityLifetimes(t *testing.T) {
+	w := NewWorld(NewConfig().WithEntityLifetimes(true))
+	posID := ComponentID[Position](&w)
+
+	e0 := w.NewEntity(posID)
+	w.RemoveEntity(e0)
+
+	e1 := w.NewEntity(posID)
+	NewBuilder(&w, posID).NewBatch(4)
+	for i := 0; i < 5; i++ {
+		w.Tick()
+	}
+	e2 := w.NewEntity()
+	assert.Equal(t, uint64(0), w.EntityTick(e1))
+	assert.Equal(t, uint64(5), w.EntityTick(e2))
+
+	w.RemoveEntity(e1)
+	w.Batch().RemoveEntities(All(posID))
+
+	st := w.Stats()
+	assert.Equal(t, &stats.Lifetimes{
+		Created:   7,
+		Removed:   6,
+		Mean:      25.0 / 6.0,
+		Histogram: []int{1, 0, 0, 5},
+	}, st.Lifetimes)
+	assert.Contains(t, st.String(), "Lifetimes -- Created: 7, Removed: 6, Mean: 4.2, Histogram: [1 0 0 5]")
+
+	assert.PanicsWithValue(t, "can't get the creation tick of a dead entity", func() { w.EntityTick(e1) })
+
+	dump := w.DumpEntities()
+	w.Reset()
+	assert.Equal(t, 0, w.Stats().Lifetimes.Created)
+	w.LoadEntities(&dump)
+	assert.Equal(t, uint64(0), w.EntityTick(e2))
+	w.RemoveEntity(e2)
+	assert.Equal(t, 1, w.Stats().Lifetimes.Removed)
+}
+
+func TestEntityLifetimesDisabled(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	w.RemoveEntity(e)
+
+	assert.Nil(t, w.Stats().Lifetimes)
+	assert.PanicsWithValue(t, "entity lifetime tracking is not enabled in the world config", func() { w.EntityTick(e) })
+}
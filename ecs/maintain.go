@@ -0,0 +1,195 @@
+package ecs
+
+// idleFraction is the inverse of the fill ratio below which an archetype counts as under-used.
+const idleFraction = 4
+
+// Maintain runs memory maintenance on the world's archetypes.
+//
+// Memory maintenance is controlled by [Config.IdleMaintenanceRuns], and does nothing if it is zero.
+// An archetype is considered under-used if it is filled to less than a quarter of its capacity.
+// Archetypes that are under-used for at least [Config.IdleMaintenanceRuns] consecutive calls to Maintain
+// are processed as follows:
+//
+//   - Relation archetypes with a non-zero target that are empty are removed (de-activated for re-use).
+//   - Other archetypes gradually shrink their capacity, by half per call to Maintain,
+//     but not below what is required by their entities.
+//
+// Further, memory of de-activated relation archetypes is reduced to the capacity increment.
+//
+// Maintain is intended to be called at a low frequency, e.g. once per tick or less often.
+// Memory is released in whole chunks of unused storage, so components of remaining entities do not move.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Maintain() {
+	w.checkLocked()
+
+	runs := uint32(w.config.IdleMaintenanceRuns)
+	if runs == 0 {
+		return
+	}
+
+	len := w.nodes.Len()
+	var i int32
+	for i = 0; i < len; i++ {
+		node := w.nodes.Get(i)
+		if !node.IsActive {
+			continue
+		}
+		if !node.HasRelation {
+			w.maintainArchetype(node.archetype, runs)
+			continue
+		}
+		lenArches := node.archetypes.Len()
+		var j int32
+		for j = 0; j < lenArches; j++ {
+			w.maintainArchetype(node.archetypes.Get(j), runs)
+		}
+	}
+}
+
+// maintainArchetype applies idle-archetype decay to a single archetype.
+func (w *World) maintainArchetype(arch *archetype, runs uint32) {
+	inc := arch.node.capacityIncrement
+	if !arch.IsActive() {
+		if arch.cap > inc {
+			arch.resize(inc)
+		}
+		return
+	}
+
+	if arch.len*idleFraction >= arch.cap {
+		arch.idle = 0
+		return
+	}
+	arch.idle++
+	if arch.idle < runs {
+		return
+	}
+
+	if arch.len == 0 && arch.node.HasRelation && !arch.RelationTarget.IsZero() {
+		w.removeArchetype(arch)
+		return
+	}
+
+	required := capacityU32(arch.len, inc)
+	if required < inc {
+		required = inc
+	}
+	target := capacityU32(arch.cap/2, inc)
+	if target < required {
+		target = required
+	}
+	if target < arch.cap {
+		arch.resize(target)
+	}
+}
+
+// RemoveEmptyArchetypes removes all empty relation archetypes with a non-zero target,
+// regardless of [Config.IdleMaintenanceRuns]. Returns the number of removed archetypes.
+//
+// Removed archetypes are de-activated for re-use, and are removed from the [Cache] and from [World.Stats].
+// Further, memory of all empty and de-activated archetypes is reduced to the capacity increment.
+//
+// For gradual, automatic removal of archetypes that stay empty, see [World.Maintain].
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) RemoveEmptyArchetypes() int {
+	w.checkLocked()
+
+	removed := 0
+	len := w.nodes.Len()
+	var i int32
+	for i = 0; i < len; i++ {
+		node := w.nodes.Get(i)
+		if !node.IsActive {
+			continue
+		}
+		if !node.HasRelation {
+			w.shrinkEmptyArchetype(node.archetype)
+			continue
+		}
+		lenArches := node.archetypes.Len()
+		var j int32
+		for j = 0; j < lenArches; j++ {
+			arch := node.archetypes.Get(j)
+			if arch.IsActive() && arch.len == 0 && !arch.RelationTarget.IsZero() {
+				w.removeArchetype(arch)
+				removed++
+			}
+			w.shrinkEmptyArchetype(arch)
+		}
+	}
+	return removed
+}
+
+// shrinkEmptyArchetype reduces the memory of an empty archetype to the capacity increment.
+func (w *World) shrinkEmptyArchetype(arch *archetype) {
+	inc := arch.node.capacityIncrement
+	if arch.len == 0 && arch.cap > inc {
+		arch.resize(inc)
+	}
+}
+
+// Compact shrinks the memory of the world back toward its current requirements,
+// e.g. after large waves of entity removal.
+//
+// In contrast to [World.Maintain], Compact acts immediately, regardless of [Config.IdleMaintenanceRuns]:
+//
+//   - Empty relation archetypes with a non-zero target are removed, like with [World.RemoveEmptyArchetypes].
+//   - All other archetypes shrink their capacity to what is required by their entities,
+//     rounded up to the capacity increment.
+//   - Spare capacity of the entity pool and the entity index is released.
+//   - Sparse-set components (see [SparseSets]) shrink their storage to the current number of entities.
+//
+// The entity pool keeps a slot per entity ID that was ever used, to keep track of entity generations.
+// Only spare capacity beyond these slots is released.
+//
+// Archetypes release memory in whole chunks of unused storage, so their components do not move.
+// The operation invalidates pointers to sparse-set components obtained before.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Compact() {
+	w.RemoveEmptyArchetypes()
+
+	lenNodes := w.nodes.Len()
+	var i int32
+	for i = 0; i < lenNodes; i++ {
+		node := w.nodes.Get(i)
+		if !node.IsActive {
+			continue
+		}
+		if !node.HasRelation {
+			w.compactArchetype(node.archetype)
+			continue
+		}
+		lenArches := node.archetypes.Len()
+		var j int32
+		for j = 0; j < lenArches; j++ {
+			w.compactArchetype(node.archetypes.Get(j))
+		}
+	}
+
+	inc := w.config.CapacityIncrement
+	if required := capacityNonZero(len(w.entityPool.entities), inc); cap(w.entityPool.entities) > required {
+		w.entityPool.entities = append(make([]Entity, 0, required), w.entityPool.entities...)
+	}
+	if required := capacityNonZero(len(w.entities), inc); cap(w.entities) > required {
+		w.entities = append(make([]entityIndex, 0, required), w.entities...)
+	}
+	w.sparse.Compact()
+}
+
+// compactArchetype shrinks the capacity of an archetype to what is required by its entities.
+func (w *World) compactArchetype(arch *archetype) {
+	inc := arch.node.capacityIncrement
+	required := capacityU32(arch.len, inc)
+	if required < inc {
+		required = inc
+	}
+	if required < arch.cap {
+		arch.resize(required)
+	}
+}
@@ -0,0 +1,124 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldMaintain(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(8).WithIdleMaintenanceRuns(2))
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	NewBuilder(&w, posID).NewBatch(64)
+	relBuilder := NewBuilder(&w, posID, relID).WithRelation(relID)
+	relBuilder.NewBatch(9, parent)
+	relNode := w.entities[relBuilder.New(parent).id].arch.node
+
+	posArch := w.entities[w.NewEntity(posID).id].arch
+	assert.Equal(t, uint32(72), posArch.Cap())
+
+	exclPos := All(posID).Exclusive()
+	w.Batch().RemoveEntities(&exclPos)
+	w.NewEntity(posID)
+	assert.Equal(t, uint32(1), posArch.Len())
+
+	w.Maintain()
+	assert.Equal(t, uint32(72), posArch.Cap())
+	w.Maintain()
+	assert.Equal(t, uint32(40), posArch.Cap())
+	w.Maintain()
+	assert.Equal(t, uint32(24), posArch.Cap())
+	w.Maintain()
+	assert.Equal(t, uint32(16), posArch.Cap())
+	w.Maintain()
+	assert.Equal(t, uint32(8), posArch.Cap())
+	w.Maintain()
+	assert.Equal(t, uint32(8), posArch.Cap())
+
+	NewBuilder(&w, posID).NewBatch(7)
+	w.Maintain()
+	assert.Equal(t, uint32(0), posArch.idle)
+
+	filter := All(relID)
+	w.Batch().RemoveEntities(&filter)
+
+	assert.Equal(t, 1, len(relNode.archetypeMap))
+	w.Maintain()
+	assert.Equal(t, 1, len(relNode.archetypeMap))
+	w.Maintain()
+	assert.Equal(t, 0, len(relNode.archetypeMap))
+	assert.Equal(t, 1, len(relNode.freeIndices))
+
+	e := NewBuilder(&w, posID, relID).WithRelation(relID).New(parent)
+	assert.Equal(t, parent, w.Relations().Get(e, relID))
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Maintain() })
+	query.Close()
+}
+
+func TestWorldMaintainDisabled(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(8))
+	posID := ComponentID[Position](&w)
+
+	e := w.NewEntity(posID)
+	arch := w.entities[e.id].arch
+	NewBuilder(&w, posID).NewBatch(63)
+	w.Batch().RemoveEntities(All(posID))
+
+	for i := 0; i < 10; i++ {
+		w.Maintain()
+	}
+	assert.Equal(t, uint32(64), arch.Cap())
+
+	assert.PanicsWithValue(t, "invalid IdleMaintenanceRuns in config, must be >= 0", func() {
+		NewWorld(NewConfig().WithIdleMaintenanceRuns(-1))
+	})
+}
+
+func TestWorldRemoveEmptyArchetypes(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(8))
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent1, parent2 := w.NewEntity(), w.NewEntity()
+	relBuilder := NewBuilder(&w, posID, relID).WithRelation(relID)
+	relBuilder.NewBatch(20, parent1)
+	relBuilder.NewBatch(3, parent2)
+	relBuilder.NewBatch(2)
+	relNode := w.entities[relBuilder.New(parent1).id].arch.node
+	posArch := w.entities[w.NewEntity(posID).id].arch
+	NewBuilder(&w, posID).NewBatch(30)
+
+	cached := w.Cache().Register(All(relID))
+	assert.Equal(t, int32(3), w.Cache().get(&cached).Archetypes.Len())
+
+	assert.Equal(t, 0, w.RemoveEmptyArchetypes())
+
+	rf := NewRelationFilter(All(relID), parent1)
+	w.Batch().RemoveEntities(&rf)
+	zero := NewRelationFilter(All(relID), Entity{})
+	w.Batch().RemoveEntities(&zero)
+	exclPos := All(posID).Exclusive()
+	w.Batch().RemoveEntities(&exclPos)
+	assert.Equal(t, uint32(32), posArch.Cap())
+
+	assert.Equal(t, 3, len(relNode.archetypeMap))
+	assert.Equal(t, 1, w.RemoveEmptyArchetypes())
+	assert.Equal(t, 2, len(relNode.archetypeMap))
+	assert.Equal(t, int32(2), w.Cache().get(&cached).Archetypes.Len())
+	assert.Equal(t, uint32(8), posArch.Cap())
+	assert.Equal(t, uint32(8), relNode.archetypes.Get(0).Cap())
+	assert.Equal(t, 3, countEntities(&w, &cached))
+	assert.Equal(t, 0, w.RemoveEmptyArchetypes())
+
+	relBuilder.NewBatch(2, parent1)
+	assert.Equal(t, 5, countEntities(&w, &cached))
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.RemoveEmptyArchetypes() })
+	query.Close()
+}
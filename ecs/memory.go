@@ -0,0 +1,80 @@
+package ecs
+
+import (
+	"slices"
+)
+
+// MemoryCallback is called when the component memory of a world crosses a threshold. See [World.SetMemoryThresholds].
+//
+// Argument memory is the current component memory in bytes, threshold is the crossed threshold,
+// and exceeded reports whether the memory went above the threshold (true) or back down to or below it (false).
+type MemoryCallback func(memory int, threshold int, exceeded bool)
+
+// ComponentMemory returns the memory currently allocated for archetype storage,
+// i.e. for entities and components, in bytes.
+//
+// The value is tracked continuously as archetypes grow and shrink, and is cheap to query.
+// It equals [stats.World.Memory] from [World.Stats], minus the memory of the entity index.
+// Sparse-set components (see [SparseSets]) are not included.
+func (w *World) ComponentMemory() int {
+	return w.memory.total
+}
+
+// SetMemoryThresholds sets a callback that is called whenever the component memory
+// (see [World.ComponentMemory]) crosses one of the given thresholds, in bytes.
+// This allows long-running applications to trigger compaction or entity culling before running out of memory.
+// Calling it without thresholds, or with a nil callback, removes the callback.
+//
+// The callback is called once for each crossed threshold, in the order of crossing.
+// Thresholds that are already exceeded when they are set are not reported.
+//
+// The callback is called from within the structural change that caused the crossing.
+// It must not modify the world. Instead, schedule the response,
+// e.g. by setting a flag that is checked by the main loop to call [World.Compact].
+//
+// The callback is not copied by [World.Clone].
+func (w *World) SetMemoryThresholds(callback MemoryCallback, thresholds ...int) {
+	if callback == nil || len(thresholds) == 0 {
+		w.memory.callback = nil
+		w.memory.thresholds = nil
+		w.memory.level = 0
+		return
+	}
+	w.memory.callback = callback
+	w.memory.thresholds = slices.Clone(thresholds)
+	slices.Sort(w.memory.thresholds)
+	w.memory.level = 0
+	for w.memory.level < len(w.memory.thresholds) && w.memory.total > w.memory.thresholds[w.memory.level] {
+		w.memory.level++
+	}
+}
+
+// memoryTracker tracks the memory of archetype storage, and notifies about crossed thresholds.
+//
+// It is shared by pointer between the world and its archetype graph nodes.
+type memoryTracker struct {
+	total      int            // Current memory, in bytes.
+	thresholds []int          // Sorted thresholds.
+	level      int            // Number of exceeded thresholds.
+	callback   MemoryCallback // Callback for crossed thresholds.
+}
+
+// Add adds the given number of bytes, which may be negative, and notifies about crossed thresholds.
+// Does nothing for a nil tracker, e.g. for archetypes created outside of a world.
+func (m *memoryTracker) Add(bytes int) {
+	if m == nil {
+		return
+	}
+	m.total += bytes
+	if m.callback == nil {
+		return
+	}
+	for m.level < len(m.thresholds) && m.total > m.thresholds[m.level] {
+		m.level++
+		m.callback(m.total, m.thresholds[m.level-1], true)
+	}
+	for m.level > 0 && m.total <= m.thresholds[m.level-1] {
+		m.level--
+		m.callback(m.total, m.thresholds[m.level], false)
+	}
+}
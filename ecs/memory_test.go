@@ -0,0 +1,68 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCrossing struct {
+	Threshold int
+	Exceeded  bool
+}
+
+func TestWorldComponentMemory(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(32))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	indexMemory := func() int {
+		return cap(w.entities)*int(entityIndexSize) + w.entityPool.TotalCap()*int(entitySize)
+	}
+	assert.Equal(t, w.Stats().Memory-indexMemory(), w.ComponentMemory())
+
+	parent := w.NewEntity()
+	NewBuilder(&w, posID, velID).NewBatch(1000)
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(100, parent)
+	assert.Equal(t, w.Stats().Memory-indexMemory(), w.ComponentMemory())
+
+	w.Batch().RemoveEntities(All(posID, velID))
+	w.Compact()
+	assert.Equal(t, w.Stats().Memory-indexMemory(), w.ComponentMemory())
+}
+
+func TestWorldMemoryThresholds(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(32))
+	posID := ComponentID[Position](&w)
+
+	crossings := []memoryCrossing{}
+	w.SetMemoryThresholds(func(memory int, threshold int, exceeded bool) {
+		assert.Equal(t, w.ComponentMemory(), memory)
+		crossings = append(crossings, memoryCrossing{threshold, exceeded})
+	}, 40_000, 20_000)
+
+	NewBuilder(&w, posID).NewBatch(500)
+	assert.Equal(t, []memoryCrossing{}, crossings)
+
+	NewBuilder(&w, posID).NewBatch(1500)
+	assert.Equal(t, []memoryCrossing{{20_000, true}, {40_000, true}}, crossings)
+
+	w.Batch().RemoveEntities(All(posID))
+	w.Compact()
+	assert.Equal(t, []memoryCrossing{{20_000, true}, {40_000, true}, {40_000, false}, {20_000, false}}, crossings)
+
+	crossings = crossings[:0]
+	NewBuilder(&w, posID).NewBatch(2000)
+	w.SetMemoryThresholds(nil)
+	w.Batch().RemoveEntities(All(posID))
+	w.Compact()
+	assert.Equal(t, []memoryCrossing{{20_000, true}, {40_000, true}}, crossings)
+
+	w.SetMemoryThresholds(func(memory int, threshold int, exceeded bool) {
+		crossings = append(crossings, memoryCrossing{threshold, exceeded})
+	}, 0)
+	assert.Equal(t, 2, len(crossings))
+	w.Compact()
+	assert.Equal(t, 2, len(crossings))
+}
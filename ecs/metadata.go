@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldMeta describes a field of a component type. See [ComponentMetadata].
+type FieldMeta struct {
+	Name        string            // Name of the field. Fields of embedded structs are promoted, like in Go.
+	Index       []int             // Index sequence for [reflect.Value.FieldByIndex].
+	Offset      uintptr           // Offset of the field in the component, in bytes.
+	Type        reflect.Type      // Type of the field.
+	Exported    bool              // Whether the field is exported.
+	Annotations map[string]string // User annotations, initialized from the `arche` struct tag.
+}
+
+// ComponentMeta describes a component type and its fields. See [ComponentMetadata].
+type ComponentMeta struct {
+	ID          ID                // ID of the component.
+	Type        reflect.Type      // Type of the component.
+	IsRelation  bool              // Whether the component is a [Relation].
+	Fields      []FieldMeta       // Fields of the component. Fields of embedded structs are promoted.
+	Annotations map[string]string // User annotations.
+}
+
+// Field returns the metadata of the field with the given name, and whether the field exists.
+func (m *ComponentMeta) Field(name string) (*FieldMeta, bool) {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// ComponentMetadata returns the field-level [ComponentMeta] for a component [ID], and whether the ID is assigned.
+//
+// Metadata is created on first access and shared by all callers, so that serializers,
+// inspectors and other tools can use the same field descriptions and annotations.
+// Annotations of fields are initialized from the `arche` struct tag,
+// as a comma-separated list of keys or key=value pairs:
+//
+//	type Health struct {
+//		Current float64 `arche:"min=0,label=HP"`
+//		Regen   float64 `arche:"hidden"`
+//	}
+//
+// Annotations of the component itself are initialized from the `arche` tag of blank fields (`_`),
+// which are not listed as fields.
+// Annotations can be modified via the returned pointer. They are not affected by [World.Reset].
+func ComponentMetadata(w *World, id ID) (*ComponentMeta, bool) {
+	tp, ok := w.registry.ComponentType(id.id)
+	if !ok {
+		return nil, false
+	}
+	for int(id.id) >= len(w.metadata) {
+		w.metadata = append(w.metadata, nil)
+	}
+	if meta := w.metadata[id.id]; meta != nil {
+		return meta, true
+	}
+
+	meta := &ComponentMeta{
+		ID:          id,
+		Type:        tp,
+		IsRelation:  w.registry.IsRelation.Get(id),
+		Annotations: map[string]string{},
+	}
+	if tp.Kind() == reflect.Struct {
+		meta.Fields = appendFieldMeta(nil, tp, nil, 0)
+		for i := 0; i < tp.NumField(); i++ {
+			if f := tp.Field(i); f.Name == "_" {
+				for k, v := range parseAnnotations(f.Tag.Get("arche")) {
+					meta.Annotations[k] = v
+				}
+			}
+		}
+	}
+	w.metadata[id.id] = meta
+	return meta, true
+}
+
+// appendFieldMeta appends the metadata of all fields of a struct type, and promotes fields of embedded structs.
+func appendFieldMeta(fields []FieldMeta, tp reflect.Type, index []int, offset uintptr) []FieldMeta {
+	for i := 0; i < tp.NumField(); i++ {
+		f := tp.Field(i)
+		if f.Name == "_" {
+			continue
+		}
+		idx := append(append([]int{}, index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = appendFieldMeta(fields, f.Type, idx, offset+f.Offset)
+			continue
+		}
+		fields = append(fields, FieldMeta{
+			Name:        f.Name,
+			Index:       idx,
+			Offset:      offset + f.Offset,
+			Type:        f.Type,
+			Exported:    f.IsExported(),
+			Annotations: parseAnnotations(f.Tag.Get("arche")),
+		})
+	}
+	return fields
+}
+
+// parseAnnotations parses a comma-separated list of keys or key=value pairs.
+func parseAnnotations(tag string) map[string]string {
+	annotations := map[string]string{}
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		annotations[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return annotations
+}
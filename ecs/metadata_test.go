@@ -0,0 +1,85 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metaBase struct {
+	X int `arche:"min=0, label = Pos X"`
+	Y int
+}
+
+type metaComp struct {
+	metaBase
+	Health float64 `arche:"hidden,max=100"`
+	secret uint8
+}
+
+func TestComponentMetadata(t *testing.T) {
+	w := NewWorld()
+	compID := ComponentID[metaComp](&w)
+	relID := ComponentID[testRelationA](&w)
+	intID := ComponentID[int](&w)
+
+	meta, ok := ComponentMetadata(&w, compID)
+	assert.True(t, ok)
+	assert.Equal(t, compID, meta.ID)
+	assert.Equal(t, reflect.TypeOf(metaComp{}), meta.Type)
+	assert.False(t, meta.IsRelation)
+	assert.Equal(t, 4, len(meta.Fields))
+
+	names := []string{}
+	for _, f := range meta.Fields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"X", "Y", "Health", "secret"}, names)
+
+	f, ok := meta.Field("Y")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1}, f.Index)
+	assert.Equal(t, reflect.TypeOf(metaComp{}.Y), f.Type)
+	assert.Equal(t, reflect.TypeOf(metaComp{}).Field(0).Offset+reflect.TypeOf(metaBase{}).Field(1).Offset, f.Offset)
+	assert.True(t, f.Exported)
+	assert.Equal(t, map[string]string{}, f.Annotations)
+
+	f, _ = meta.Field("X")
+	assert.Equal(t, map[string]string{"min": "0", "label": "Pos X"}, f.Annotations)
+	f, _ = meta.Field("Health")
+	assert.Equal(t, map[string]string{"hidden": "", "max": "100"}, f.Annotations)
+	f, _ = meta.Field("secret")
+	assert.False(t, f.Exported)
+
+	comp := metaComp{metaBase: metaBase{Y: 7}, Health: 2.5}
+	field, _ := meta.Field("Y")
+	assert.Equal(t, 7, reflect.ValueOf(comp).FieldByIndex(field.Index).Interface())
+
+	_, ok = meta.Field("Z")
+	assert.False(t, ok)
+
+	meta.Annotations["category"] = "stats"
+	meta.Fields[2].Annotations["unit"] = "hp"
+	meta2, _ := ComponentMetadata(&w, compID)
+	assert.Same(t, meta, meta2)
+	assert.Equal(t, "stats", meta2.Annotations["category"])
+	f, _ = meta2.Field("Health")
+	assert.Equal(t, "hp", f.Annotations["unit"])
+
+	w.Reset()
+	meta2, _ = ComponentMetadata(&w, compID)
+	assert.Same(t, meta, meta2)
+
+	meta, ok = ComponentMetadata(&w, relID)
+	assert.True(t, ok)
+	assert.True(t, meta.IsRelation)
+	assert.Equal(t, 0, len(meta.Fields))
+
+	meta, ok = ComponentMetadata(&w, intID)
+	assert.True(t, ok)
+	assert.Nil(t, meta.Fields)
+
+	_, ok = ComponentMetadata(&w, ID{id: 20})
+	assert.False(t, ok)
+}
@@ -0,0 +1,125 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Namespace is a contiguous block of component IDs, reserved for a named module.
+//
+// Create namespaces with [World.ReserveIDs],
+// and register components in them with [ComponentIDAt] or [TypeIDAt].
+type Namespace struct {
+	name  string
+	start int
+	count int
+}
+
+// Name of the namespace.
+func (n Namespace) Name() string {
+	return n.name
+}
+
+// Len returns the number of IDs reserved for the namespace.
+func (n Namespace) Len() int {
+	return n.count
+}
+
+// ID returns the component [ID] at the given index in the namespace.
+// The ID is not necessarily registered yet.
+//
+// Panics if the index is out of range.
+func (n Namespace) ID(index int) ID {
+	n.checkIndex(index)
+	return id(uint8(n.start + index))
+}
+
+// Contains returns whether the given component [ID] is in the namespace.
+func (n Namespace) Contains(compID ID) bool {
+	i := int(compID.id)
+	return i >= n.start && i < n.start+n.count
+}
+
+// checkIndex panics if the index is out of range.
+func (n Namespace) checkIndex(index int) {
+	if index < 0 || index >= n.count {
+		panic(fmt.Sprintf("index %d is out of range for namespace '%s' with %d IDs", index, n.name, n.count))
+	}
+}
+
+// ReserveIDs reserves a contiguous block of component IDs for a named module.
+//
+// Components registered in the namespace with [ComponentIDAt] get the ID
+// at their explicit index in the block, independent of the registration order of other components.
+// This way, independently developed modules don't collide,
+// and get the same IDs in all worlds where the namespaces are reserved in the same order.
+//
+// Reserved IDs count towards the limit of [MaskTotalBits] component types, even when unused.
+// Namespaces are not affected by [World.Reset].
+//
+// Panics if the name is already reserved, if the count is not positive,
+// if not enough IDs are left, or when called on a locked world.
+func (w *World) ReserveIDs(name string, count int) Namespace {
+	w.checkLocked()
+	if _, ok := w.namespaces[name]; ok {
+		panic(fmt.Sprintf("namespace '%s' is already reserved", name))
+	}
+	if count <= 0 {
+		panic("invalid number of IDs to reserve, must be > 0")
+	}
+	start := w.registry.reserve(count, MaskTotalBits)
+
+	ns := Namespace{name: name, start: int(start), count: count}
+	if w.namespaces == nil {
+		w.namespaces = map[string]Namespace{}
+	}
+	w.namespaces[name] = ns
+	return ns
+}
+
+// Namespace returns the [Namespace] reserved under the given name, and whether it exists.
+//
+// See [World.ReserveIDs].
+func (w *World) Namespace(name string) (Namespace, bool) {
+	ns, ok := w.namespaces[name]
+	return ns, ok
+}
+
+// ComponentIDAt returns the [ID] for a component type, registered at an explicit index of a [Namespace].
+// Registers the type if it is not already registered.
+//
+// Panics if the index is out of range, if the type is already registered with another ID,
+// if the ID is already used by another type, or if called on a locked world and the type is not registered yet.
+//
+// See [World.ReserveIDs] for reserving namespaces.
+func ComponentIDAt[T any](w *World, ns Namespace, index int) ID {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	return w.componentIDAt(tp, ns, index)
+}
+
+// TypeIDAt returns the [ID] for a component type, registered at an explicit index of a [Namespace].
+// Registers the type if it is not already registered.
+//
+// Panics in the same cases as [ComponentIDAt].
+func TypeIDAt(w *World, tp reflect.Type, ns Namespace, index int) ID {
+	return w.componentIDAt(tp, ns, index)
+}
+
+// componentIDAt registers a component type at an explicit index of a namespace.
+func (w *World) componentIDAt(tp reflect.Type, ns Namespace, index int) ID {
+	compID := ns.ID(index)
+	if oldID, ok := w.registry.Components[tp]; ok {
+		if oldID != compID.id {
+			panic(fmt.Sprintf("component type %s is already registered with ID %d", tp, oldID))
+		}
+		return compID
+	}
+	if other, ok := w.registry.ComponentType(compID.id); ok {
+		panic(fmt.Sprintf("ID %d in namespace '%s' is already used by component type %s", compID.id, ns.name, other))
+	}
+	if w.IsLocked() {
+		panic("attempt to register a new component in a locked world")
+	}
+	w.registry.registerComponentAt(tp, compID.id)
+	return compID
+}
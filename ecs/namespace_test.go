@@ -0,0 +1,93 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespace(t *testing.T) {
+	w := NewWorld()
+
+	posID := ComponentID[Position](&w)
+	physics := w.ReserveIDs("physics", 8)
+	render := w.ReserveIDs("render", 4)
+	velID := ComponentID[Velocity](&w)
+
+	assert.Equal(t, "physics", physics.Name())
+	assert.Equal(t, 8, physics.Len())
+	assert.Equal(t, id(0), posID)
+	assert.Equal(t, id(13), velID)
+
+	rotID := ComponentIDAt[rotation](&w, physics, 3)
+	assert.Equal(t, id(4), rotID)
+	assert.Equal(t, rotID, ComponentIDAt[rotation](&w, physics, 3))
+	assert.Equal(t, rotID, ComponentID[rotation](&w))
+	assert.True(t, physics.Contains(rotID))
+	assert.False(t, render.Contains(rotID))
+	assert.False(t, physics.Contains(velID))
+
+	labelID := TypeIDAt(&w, reflect.TypeOf(label{}), render, 0)
+	assert.Equal(t, id(9), labelID)
+	assert.Equal(t, render.ID(0), labelID)
+
+	assert.Equal(t, []ID{posID, rotID, labelID, velID}, ComponentIDs(&w))
+	assert.Equal(t, 4, w.Stats().ComponentCount)
+
+	ns, ok := w.Namespace("physics")
+	assert.True(t, ok)
+	assert.Equal(t, physics, ns)
+	_, ok = w.Namespace("audio")
+	assert.False(t, ok)
+
+	e := w.NewEntity(posID, rotID, labelID)
+	assert.True(t, w.Has(e, rotID))
+	assert.True(t, w.Has(e, labelID))
+	assert.Equal(t, &rotation{Angle: 0}, (*rotation)(w.Get(e, rotID)))
+
+	assert.PanicsWithValue(t, "namespace 'physics' is already reserved", func() { w.ReserveIDs("physics", 2) })
+	assert.PanicsWithValue(t, "invalid number of IDs to reserve, must be > 0", func() { w.ReserveIDs("audio", 0) })
+	assert.PanicsWithValue(t, "index 8 is out of range for namespace 'physics' with 8 IDs", func() { ComponentIDAt[testStruct0](&w, physics, 8) })
+	assert.PanicsWithValue(t, "component type ecs.Position is already registered with ID 0", func() { ComponentIDAt[Position](&w, physics, 0) })
+	assert.PanicsWithValue(t, "ID 4 in namespace 'physics' is already used by component type ecs.rotation", func() { ComponentIDAt[testStruct0](&w, physics, 3) })
+
+	q := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to register a new component in a locked world", func() { ComponentIDAt[testStruct0](&w, physics, 0) })
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.ReserveIDs("audio", 2) })
+	q.Close()
+
+	w.Reset()
+	ns, ok = w.Namespace("render")
+	assert.True(t, ok)
+	assert.Equal(t, render, ns)
+}
+
+func TestNamespaceLayouts(t *testing.T) {
+	w := NewWorld()
+
+	posID := ComponentID[Position](&w)
+	e := w.NewEntity(posID)
+
+	ns := w.ReserveIDs("big", 40)
+	velID := ComponentIDAt[Velocity](&w, ns, 39)
+	assert.Equal(t, id(40), velID)
+
+	w.Add(e, velID)
+	vel := (*Velocity)(w.Get(e, velID))
+	vel.X = 5
+	assert.Equal(t, 5, (*Velocity)(w.Get(e, velID)).X)
+
+	e2 := w.NewEntity(velID)
+	assert.True(t, w.Has(e2, velID))
+}
+
+func TestNamespaceOverflow(t *testing.T) {
+	w := NewWorld()
+	w.ReserveIDs("a", MaskTotalBits-2)
+	ComponentID[Position](&w)
+	ComponentID[Velocity](&w)
+
+	assert.Panics(t, func() { ComponentID[rotation](&w) })
+	assert.Panics(t, func() { w.ReserveIDs("b", 1) })
+}
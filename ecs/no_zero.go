@@ -0,0 +1,33 @@
+package ecs
+
+import "reflect"
+
+var noZeroType = reflect.TypeOf((*NoZero)(nil)).Elem()
+
+// NoZero can be implemented by component types that don't need zero-initialization.
+//
+// By default, component storage is reset to zero when an entity is removed from an archetype,
+// so that entities allocated later start with zero-valued components.
+// For components that are always fully overwritten right after allocation,
+// e.g. using [World.NewEntityWith], [World.Assign] or [Query.Get] after batch creation,
+// this can be skipped by implementing NoZero on the component type (or its pointer).
+//
+// ⚠️ Warning: Newly allocated components of such types may contain stale data from previously removed entities!
+// Further, pointers in stale data prevent the garbage collection of the referenced objects.
+//
+// Example:
+//
+//	type Position struct {
+//		X, Y float64
+//	}
+//
+//	func (p *Position) NoZero() {}
+type NoZero interface {
+	// NoZero is a marker method without any functionality.
+	NoZero()
+}
+
+// isNoZero checks whether a component type implements [NoZero].
+func isNoZero(tp reflect.Type) bool {
+	return tp.Implements(noZeroType) || reflect.PointerTo(tp).Implements(noZeroType)
+}
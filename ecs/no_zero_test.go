@@ -0,0 +1,38 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type noZeroComp struct {
+	Value int
+}
+
+func (c *noZeroComp) NoZero() {}
+
+func TestNoZero(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	nzID := ComponentID[noZeroComp](&w)
+
+	assert.True(t, isNoZero(reflect.TypeOf(noZeroComp{})))
+	assert.False(t, isNoZero(reflect.TypeOf(Position{})))
+
+	e := w.NewEntity(posID, nzID)
+	*(*Position)(w.Get(e, posID)) = Position{X: 1, Y: 2}
+	(*noZeroComp)(w.Get(e, nzID)).Value = 3
+	w.RemoveEntity(e)
+
+	e = w.NewEntity(posID, nzID)
+	assert.Equal(t, Position{}, *(*Position)(w.Get(e, posID)))
+	assert.Equal(t, 3, (*noZeroComp)(w.Get(e, nzID)).Value)
+
+	*(*Position)(w.Get(e, posID)) = Position{X: 1, Y: 2}
+	w.Batch().RemoveEntities(All(posID))
+	e = w.NewEntity(posID, nzID)
+	assert.Equal(t, Position{}, *(*Position)(w.Get(e, posID)))
+	assert.Equal(t, 3, (*noZeroComp)(w.Get(e, nzID)).Value)
+}
@@ -3,6 +3,7 @@ package ecs
 import (
 	"fmt"
 	"math"
+	"sync/atomic"
 )
 
 type number interface {
@@ -16,6 +17,7 @@ type entityPool struct {
 	next              eid
 	available         uint32
 	capacityIncrement uint32
+	reserved          uint64 // Next fresh ID for entities reserved with [World.Reserve]. Accessed atomically.
 }
 
 // newEntityPool creates a new, initialized Entity pool.
@@ -27,6 +29,7 @@ func newEntityPool(capacityIncrement uint32) entityPool {
 		next:              0,
 		available:         0,
 		capacityIncrement: capacityIncrement,
+		reserved:          1,
 	}
 }
 
@@ -50,9 +53,35 @@ func (p *entityPool) getNew() Entity {
 		copy(p.entities, old)
 	}
 	p.entities = append(p.entities, e)
+	// Keep reservations ahead of the pool. When realizing reserved entities, they already are.
+	if ln := uint64(len(p.entities)); ln > atomic.LoadUint64(&p.reserved) {
+		atomic.StoreUint64(&p.reserved, ln)
+	}
 	return e
 }
 
+// Reserve reserves fresh IDs for the given number of entities, and returns the first one.
+// Safe for concurrent use, as it only accesses the atomic reservation counter.
+func (p *entityPool) Reserve(count int) eid {
+	end := atomic.AddUint64(&p.reserved, uint64(count))
+	return eid(end - uint64(count))
+}
+
+// ReservedEnd returns the ID after the last reserved entity.
+func (p *entityPool) ReservedEnd() eid {
+	return eid(atomic.LoadUint64(&p.reserved))
+}
+
+// HasReserved reports whether there are reserved entities beyond the pool.
+func (p *entityPool) HasReserved() bool {
+	return atomic.LoadUint64(&p.reserved) > uint64(len(p.entities))
+}
+
+// DropReserved drops all reservations, after the pool's entities were replaced.
+func (p *entityPool) DropReserved() {
+	atomic.StoreUint64(&p.reserved, uint64(len(p.entities)))
+}
+
 // Recycle hands an entity back for recycling.
 func (p *entityPool) Recycle(e Entity) {
 	if e.id == 0 {
@@ -63,11 +92,38 @@ func (p *entityPool) Recycle(e Entity) {
 	p.available++
 }
 
+// Unget reverts taking an entity with [entityPool.Get], for rolling back changes.
+// Fresh entities must be the last in the pool.
+func (p *entityPool) Unget(e Entity, fresh bool) {
+	if p.entities[e.id] != e || (fresh && int(e.id) != len(p.entities)-1) {
+		panic("journal is out of sync with the world")
+	}
+	if fresh {
+		p.entities = p.entities[:e.id]
+		p.DropReserved()
+		return
+	}
+	p.next, p.entities[e.id].id = e.id, p.next
+	p.available++
+}
+
+// Unrecycle reverts recycling an entity with [entityPool.Recycle], for rolling back changes.
+// The entity must be the last recycled one.
+func (p *entityPool) Unrecycle(e Entity) {
+	if p.available == 0 || p.next != e.id || p.entities[e.id].gen != e.gen+1 {
+		panic("journal is out of sync with the world")
+	}
+	p.next, p.entities[e.id].id = p.entities[e.id].id, e.id
+	p.entities[e.id].gen--
+	p.available--
+}
+
 // Reset recycles all entities. Does NOT free the reserved memory.
 func (p *entityPool) Reset() {
 	p.entities = p.entities[:1]
 	p.next = 0
 	p.available = 0
+	p.DropReserved()
 }
 
 // Alive returns whether an entity is still alive, based on the entity's generations.
@@ -0,0 +1,172 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Prefab is a named template for entities, with a set of components and their default values.
+//
+// In contrast to a [Builder], a prefab is independent of component IDs and of any [World].
+// Components are identified by their types, which are registered on spawning if necessary.
+// Thus, prefabs can be stored, shared between worlds, serialized
+// (see package [github.com/mlange-42/arche/serde]) and composed with [Prefab.Extend].
+//
+// Optionally, prefabs have children, which are spawned along with each entity,
+// with a [Relation] to it. See [Prefab.WithChild].
+//
+// Create prefabs with [NewPrefab].
+type Prefab struct {
+	name     string
+	comps    []any
+	children []PrefabChild
+}
+
+// PrefabChild describes child entities of a [Prefab]. See [Prefab.WithChild].
+type PrefabChild struct {
+	Prefab   *Prefab      // Prefab of the child entities.
+	Relation reflect.Type // Relation component type pointing to the parent.
+	Count    int          // Number of child entities per parent.
+}
+
+// NewPrefab creates a new [Prefab] with the given name and component values.
+//
+// Component values must be given as pointers. They are copied, so the prefab is not affected by later changes.
+// For multiple values of the same type, the last one is used.
+//
+// Panics if any of the components is not a pointer.
+func NewPrefab(name string, comps ...any) *Prefab {
+	p := &Prefab{name: name}
+	p.set(comps)
+	return p
+}
+
+// Name of the prefab.
+func (p *Prefab) Name() string {
+	return p.name
+}
+
+// Components returns pointers to the component default values of the prefab, in order of their addition.
+//
+// Modifying the pointed-to values changes the defaults for subsequently spawned entities.
+func (p *Prefab) Components() []any {
+	return p.comps
+}
+
+// Children returns the child descriptions of the prefab. See [Prefab.WithChild].
+func (p *Prefab) Children() []PrefabChild {
+	return p.children
+}
+
+// Extend creates a new [Prefab] with the components and children of this prefab,
+// and the given additional component values.
+// Values of component types that are already in this prefab replace their defaults.
+//
+// Panics if any of the components is not a pointer.
+func (p *Prefab) Extend(name string, comps ...any) *Prefab {
+	ext := &Prefab{
+		name:     name,
+		children: append([]PrefabChild{}, p.children...),
+	}
+	ext.set(p.comps)
+	ext.set(comps)
+	return ext
+}
+
+// WithChild adds child entities to the prefab, and returns the prefab for method chaining.
+//
+// For each spawned entity, the given number of entities is spawned from the child prefab,
+// with the relation component of the given type targeting the spawned entity.
+// The relation component is added to the children if the child prefab does not contain it.
+//
+// Panics if the relation type is not a struct, or if the count is not positive.
+// Spawning panics if the type is not a [Relation].
+func (p *Prefab) WithChild(child *Prefab, relation reflect.Type, count int) *Prefab {
+	if relation.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("relation type %v is not a struct", relation))
+	}
+	if count < 1 {
+		panic("prefab child count must be positive")
+	}
+	p.children = append(p.children, PrefabChild{Prefab: child, Relation: relation, Count: count})
+	return p
+}
+
+// Spawn creates an entity from the prefab, including its children, and returns it.
+// Registers the prefab's component types if necessary.
+//
+// Panics when called on a locked world.
+func (p *Prefab) Spawn(w *World) Entity {
+	return p.spawn(w, 1, nil, Entity{})[0]
+}
+
+// SpawnBatch creates the given number of entities from the prefab, including their children, and returns them.
+// Registers the prefab's component types if necessary.
+//
+// Panics when called on a locked world.
+func (p *Prefab) SpawnBatch(w *World, count int) []Entity {
+	return p.spawn(w, count, nil, Entity{})
+}
+
+// set copies component values into the prefab, replacing those of the same type.
+func (p *Prefab) set(comps []any) {
+	for _, c := range comps {
+		value := reflect.ValueOf(c)
+		if value.Kind() != reflect.Pointer {
+			panic(fmt.Sprintf("prefab components must be pointers, got %T", c))
+		}
+		cp := reflect.New(value.Type().Elem())
+		cp.Elem().Set(value.Elem())
+		if idx := p.index(value.Type().Elem()); idx >= 0 {
+			p.comps[idx] = cp.Interface()
+			continue
+		}
+		p.comps = append(p.comps, cp.Interface())
+	}
+}
+
+// index returns the index of the component of the given type, or -1.
+func (p *Prefab) index(tp reflect.Type) int {
+	for i, c := range p.comps {
+		if reflect.TypeOf(c).Elem() == tp {
+			return i
+		}
+	}
+	return -1
+}
+
+// spawn creates entities and their children, optionally with a relation to the given target.
+func (p *Prefab) spawn(w *World, count int, relation reflect.Type, target Entity) []Entity {
+	comps := make([]Component, 0, len(p.comps)+1)
+	for _, c := range p.comps {
+		comps = append(comps, Component{ID: TypeID(w, reflect.TypeOf(c).Elem()), Comp: c})
+	}
+
+	targets := []Entity{}
+	var relID ID
+	if relation != nil {
+		relID = TypeID(w, relation)
+		if p.index(relation) < 0 {
+			comps = append(comps, Component{ID: relID, Comp: reflect.New(relation).Interface()})
+		}
+		targets = append(targets, target)
+	}
+	builder := NewBuilderWith(w, comps...)
+	if relation != nil {
+		builder.WithRelation(relID)
+	}
+
+	var entities []Entity
+	if count == 1 {
+		entities = []Entity{builder.New(targets...)}
+	} else {
+		entities = builder.AppendBatch(make([]Entity, 0, count), count, targets...)
+	}
+
+	for _, child := range p.children {
+		for _, e := range entities {
+			child.Prefab.spawn(w, child.Count, child.Relation, e)
+		}
+	}
+	return entities
+}
@@ -0,0 +1,94 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefab(t *testing.T) {
+	pos := Position{X: 1, Y: 2}
+	unit := NewPrefab("unit", &pos, &Velocity{X: 3, Y: 4})
+	pos.X = 100
+
+	assert.Equal(t, "unit", unit.Name())
+	assert.Equal(t, []any{&Position{X: 1, Y: 2}, &Velocity{X: 3, Y: 4}}, unit.Components())
+
+	w := NewWorld()
+	e := unit.Spawn(&w)
+
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	assert.Equal(t, []ID{posID, velID}, w.Ids(e))
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e, posID)))
+	assert.Equal(t, Velocity{X: 3, Y: 4}, *(*Velocity)(w.Get(e, velID)))
+
+	(*Position)(w.Get(e, posID)).X = 50
+	e2 := unit.Spawn(&w)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e2, posID)))
+
+	fast := unit.Extend("fast", &Velocity{X: 10, Y: 20}, &rotation{Angle: 5})
+	assert.Equal(t, "fast", fast.Name())
+	assert.Equal(t, []any{&Position{X: 1, Y: 2}, &Velocity{X: 10, Y: 20}, &rotation{Angle: 5}}, fast.Components())
+	assert.Equal(t, []any{&Position{X: 1, Y: 2}, &Velocity{X: 3, Y: 4}}, unit.Components())
+
+	entities := fast.SpawnBatch(&w, 10)
+	assert.Equal(t, 10, len(entities))
+	rotID := ComponentID[rotation](&w)
+	for _, e := range entities {
+		assert.Equal(t, Velocity{X: 10, Y: 20}, *(*Velocity)(w.Get(e, velID)))
+		assert.Equal(t, rotation{Angle: 5}, *(*rotation)(w.Get(e, rotID)))
+	}
+	assert.Equal(t, 12, countEntities(&w, All(posID)))
+
+	assert.PanicsWithValue(t, "prefab components must be pointers, got ecs.Position", func() {
+		NewPrefab("invalid", Position{})
+	})
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { unit.Spawn(&w) })
+	query.Close()
+}
+
+func TestPrefabChildren(t *testing.T) {
+	relType := reflect.TypeOf(testRelationA{})
+
+	leaf := NewPrefab("leaf", &rotation{Angle: 1})
+	branch := NewPrefab("branch", &Velocity{}).WithChild(leaf, relType, 2)
+	tree := NewPrefab("tree", &Position{}).WithChild(branch, reflect.TypeOf(testRelationB{}), 3)
+
+	assert.Equal(t, []PrefabChild{{Prefab: branch, Relation: reflect.TypeOf(testRelationB{}), Count: 3}}, tree.Children())
+
+	w := NewWorld()
+	trees := tree.SpawnBatch(&w, 2)
+	assert.Equal(t, 2, len(trees))
+
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	rotID := ComponentID[rotation](&w)
+	relAID := ComponentID[testRelationA](&w)
+	relBID := ComponentID[testRelationB](&w)
+
+	assert.Equal(t, 2, countEntities(&w, All(posID)))
+	assert.Equal(t, 6, countEntities(&w, All(velID, relBID)))
+	assert.Equal(t, 12, countEntities(&w, All(rotID, relAID)))
+
+	filter := NewRelationFilter(All(relBID), trees[0])
+	branches := queryEntities(&w, &filter)
+	assert.Equal(t, 3, len(branches))
+	filter = NewRelationFilter(All(relAID), branches[0])
+	leaves := queryEntities(&w, &filter)
+	assert.Equal(t, 2, len(leaves))
+	assert.Equal(t, rotation{Angle: 1}, *(*rotation)(w.Get(leaves[0], rotID)))
+
+	ext := tree.Extend("big tree", &Velocity{})
+	assert.Equal(t, tree.Children(), ext.Children())
+
+	assert.PanicsWithValue(t, "prefab child count must be positive", func() {
+		tree.WithChild(leaf, relType, 0)
+	})
+	assert.PanicsWithValue(t, "relation type int is not a struct", func() {
+		tree.WithChild(leaf, reflect.TypeOf(0), 1)
+	})
+}
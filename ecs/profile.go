@@ -0,0 +1,122 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+)
+
+// ProfileQueryLabel is the pprof label key for queries, set when profiling is enabled.
+// See [Config.Profiling].
+const ProfileQueryLabel = "arche.query"
+
+// profiler holds the profiling context of a world, with the labels of the currently running sections.
+type profiler struct {
+	ctx context.Context
+}
+
+// queryProfile holds the profiling state of a query. See [Config.Profiling].
+type queryProfile struct {
+	region *trace.Region   // Trace region of the query.
+	parent context.Context // Profiling context before the query was created.
+}
+
+// Profile runs a function with the pprof label key=value and a runtime/trace region named after the label.
+//
+// Labels are nested: the function inherits the labels of enclosing sections, and queries created in the function
+// are attributed to both. This way, CPU profiles and execution traces can attribute time to individual systems.
+// The scheduler in [github.com/mlange-42/arche/systems] uses this for each system it updates.
+//
+// If profiling is not enabled via [Config.Profiling], the function is called without instrumentation.
+//
+// Not safe for concurrent use. For concurrent goroutines, use [World.ProfileContext] with [pprof.Do] instead.
+func (w *World) Profile(key, value string, fn func()) {
+	if w.profile == nil {
+		fn()
+		return
+	}
+	parent := w.profile.ctx
+	pprof.Do(parent, pprof.Labels(key, value), func(ctx context.Context) {
+		w.profile.ctx = ctx
+		defer func() { w.profile.ctx = parent }()
+		trace.WithRegion(ctx, key+" "+value, fn)
+	})
+}
+
+// ProfileContext returns the context with the pprof labels of the currently running sections,
+// and whether profiling is enabled via [Config.Profiling].
+//
+// Use the context for instrumenting goroutines started from inside a section, e.g. with [pprof.Do].
+func (w *World) ProfileContext() (context.Context, bool) {
+	if w.profile == nil {
+		return context.Background(), false
+	}
+	return w.profile.ctx, true
+}
+
+// startQueryProfile labels the current goroutine with the query's filter and starts a trace region.
+func (w *World) startQueryProfile(query *Query, filter Filter) {
+	label := w.filterLabel(filter)
+	parent := w.profile.ctx
+	ctx := pprof.WithLabels(parent, pprof.Labels(ProfileQueryLabel, label))
+	pprof.SetGoroutineLabels(ctx)
+	w.profile.ctx = ctx
+	query.profile = &queryProfile{
+		region: trace.StartRegion(ctx, ProfileQueryLabel+" "+label),
+		parent: parent,
+	}
+}
+
+// endQueryProfile ends a query's trace region and restores the previous goroutine labels.
+func (w *World) endQueryProfile(query *Query) {
+	query.profile.region.End()
+	pprof.SetGoroutineLabels(query.profile.parent)
+	w.profile.ctx = query.profile.parent
+	query.profile = nil
+}
+
+// filterLabel returns a label for a filter, for profiling.
+// Uses the label of registered filters, and the component types of masks.
+func (w *World) filterLabel(filter Filter) string {
+	switch f := filter.(type) {
+	case *CachedFilter:
+		if label := w.filterCache.get(f).Label; label != "" {
+			return label
+		}
+		return w.filterLabel(f.filter)
+	case Mask:
+		return w.maskLabel(&f)
+	case *Mask:
+		return w.maskLabel(f)
+	case *MaskFilter:
+		return w.maskFilterLabel(f)
+	default:
+		return fmt.Sprintf("%T", filter)
+	}
+}
+
+// maskFilterLabel returns a label for a [MaskFilter], with excluded components prefixed by "!".
+func (w *World) maskFilterLabel(f *MaskFilter) string {
+	if f.Exclude.IsZero() {
+		return w.maskLabel(&f.Include)
+	}
+	exclude := w.maskLabel(&f.Exclude)
+	return w.maskLabel(&f.Include) + " !" + strings.ReplaceAll(exclude, ",", ",!")
+}
+
+// maskLabel returns the comma-separated component types of a mask.
+func (w *World) maskLabel(mask *Mask) string {
+	b := strings.Builder{}
+	for i := 0; i < MaskTotalBits; i++ {
+		if !mask.Get(ID{id: uint8(i)}) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(w.registry.Types[i].String())
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldProfile(t *testing.T) {
+	w := NewWorld(NewConfig().WithProfiling(true))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	w.NewEntity(posID, velID)
+
+	label := func(key string) string {
+		ctx, ok := w.ProfileContext()
+		assert.True(t, ok)
+		value, _ := pprof.Label(ctx, key)
+		return value
+	}
+
+	// Exercise trace regions, unless tracing is already enabled by the test runner.
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err == nil {
+		defer trace.Stop()
+	}
+
+	runs := 0
+	w.Profile("system", "Move", func() {
+		assert.Equal(t, "Move", label("system"))
+
+		query := w.Query(All(posID))
+		assert.Equal(t, "ecs.Position", label(ProfileQueryLabel))
+		assert.Equal(t, "Move", label("system"))
+		for query.Next() {
+			inner := w.Query(All(velID))
+			assert.Equal(t, "ecs.Velocity", label(ProfileQueryLabel))
+			inner.Close()
+			assert.Equal(t, "ecs.Position", label(ProfileQueryLabel))
+			runs++
+		}
+		assert.Equal(t, "", label(ProfileQueryLabel))
+		assert.Equal(t, "Move", label("system"))
+	})
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, "", label("system"))
+}
+
+func TestWorldProfileDisabled(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.NewEntity(posID)
+
+	ctx, ok := w.ProfileContext()
+	assert.False(t, ok)
+	assert.Equal(t, context.Background(), ctx)
+
+	runs := 0
+	w.Profile("system", "Move", func() {
+		query := w.Query(All(posID))
+		assert.Nil(t, query.profile)
+		for query.Next() {
+			runs++
+		}
+	})
+	assert.Equal(t, 1, runs)
+}
+
+func TestWorldFilterLabel(t *testing.T) {
+	w := NewWorld(NewConfig().WithProfiling(true))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[relationComp](&w)
+
+	all := All(posID, velID)
+	assert.Equal(t, "ecs.Position,ecs.Velocity", w.filterLabel(all))
+	assert.Equal(t, "ecs.Position,ecs.Velocity", w.filterLabel(&all))
+	assert.Equal(t, "", w.filterLabel(All()))
+
+	without := All(posID).Without(velID, relID)
+	assert.Equal(t, "ecs.Position !ecs.Velocity,!ecs.relationComp", w.filterLabel(&without))
+
+	cached := w.Cache().Register(all)
+	assert.Equal(t, "ecs.Position,ecs.Velocity", w.filterLabel(&cached))
+	labeled := w.Cache().RegisterLabel(All(posID), "Positions")
+	assert.Equal(t, "Positions", w.filterLabel(&labeled))
+
+	rel := NewRelationFilter(All(relID), Entity{})
+	assert.Equal(t, "*ecs.RelationFilter", w.filterLabel(&rel))
+
+	query := w.Query(&labeled)
+	ctx, _ := w.ProfileContext()
+	value, _ := pprof.Label(ctx, ProfileQueryLabel)
+	assert.Equal(t, "Positions", value)
+	query.Close()
+}
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"fmt"
+	"time"
 	"unsafe"
 )
 
@@ -21,6 +22,8 @@ type Query struct {
 	nodeArchetypes archetypes       // The query's archetypes of the current node.
 	nodes          []*archNode      // The query's nodes.
 	world          *World           // The [World].
+	stats          *queryStats      // Iteration statistics for labeled filters. Nil otherwise.
+	start          time.Time        // Creation time of the query. Only set for labeled filters.
 	access         *archetypeAccess // Access helper for the archetype currently being iterated.
 	archetype      *archetype       // The archetype currently being iterated.
 	entityIndex    uint32           // Iteration index of the current [Entity] current archetype.
@@ -31,6 +34,15 @@ type Query struct {
 	lockBit        uint8            // The bit that was used to lock the [World] when the query was created.
 	isFiltered     bool             // Whether the list of archetype nodes is already filtered.
 	isBatch        bool             // Marks the query as a query over a batch iteration.
+	withDisabled   bool             // Whether to include disabled entities during iteration. For internal use.
+	isView         bool             // Whether the query was created by a [View], and does not hold its own lock.
+	changes        *ChangeFilter    // Change filter of the query. Nil otherwise.
+	profile        *queryProfile    // Profiling state of the query. Nil if profiling is not enabled.
+	changeSince    uint64           // Change tick of the previous query with the change filter.
+	skip           uint32           // Number of entities still to skip. See [Query.Skip].
+	limit          uint32           // Number of entities still to iterate, if limited. See [Query.Limit].
+	isLimited      bool             // Whether the number of iterated entities is limited.
+	isPaged        bool             // Whether the query skips entities or is limited.
 }
 
 // newQuery creates a new Filter
@@ -97,6 +109,7 @@ func (q *Query) Has(comp ID) bool {
 }
 
 // Get returns the pointer to the given component at the iterator's position.
+// Returns nil if the current entity does not have the component.
 func (q *Query) Get(comp ID) unsafe.Pointer {
 	q.checkGet()
 	return q.access.Get(q.entityIndex, comp)
@@ -179,6 +192,62 @@ func (q *Query) Step(step int) bool {
 	}
 }
 
+// Skip skips the given number of entities at the start of the iteration.
+// Must be called before the first call to [Query.Next].
+//
+// Together with [Query.Limit], this allows to process a limited number of entities per frame,
+// and to resume the next frame, e.g. for amortized work like pathfinding.
+// Whole archetypes are skipped at once, so skipping is cheap compared to iterating.
+// Does not affect [Query.Count] or [Query.EntityAt].
+//
+// Example:
+//
+//	query := world.Query(filter)
+//	query.Skip(cursor)
+//	query.Limit(100)
+//	for query.Next() {
+//		cursor++
+//		// ...
+//	}
+//	if cursor >= total {
+//		cursor = 0
+//	}
+//
+// Panics if n is negative, or if the iteration has already started.
+func (q *Query) Skip(n int) {
+	if n < 0 {
+		panic("can't skip a negative number of entities")
+	}
+	q.checkPaging()
+	q.skip = uint32(n)
+	q.isPaged = q.skip > 0 || q.isLimited
+}
+
+// Limit limits the iteration to at most the given number of entities.
+// Must be called before the first call to [Query.Next].
+// The query is closed when the limit is reached.
+//
+// See [Query.Skip] for an example.
+// Does not affect [Query.Count] or [Query.EntityAt].
+//
+// Panics if n is negative, or if the iteration has already started.
+func (q *Query) Limit(n int) {
+	if n < 0 {
+		panic("can't limit a query to a negative number of entities")
+	}
+	q.checkPaging()
+	q.limit = uint32(n)
+	q.isLimited = true
+	q.isPaged = true
+}
+
+// checkPaging panics if the iteration of the query has already started.
+func (q *Query) checkPaging() {
+	if q.archetype != nil || q.nodeIndex < -1 {
+		panic("can't skip or limit a query after iteration started")
+	}
+}
+
 // Count counts the entities matching this query.
 //
 // Involves a small overhead of iterating through archetypes when called the first time.
@@ -216,7 +285,133 @@ func (q *Query) Close() {
 }
 
 // nextArchetype proceeds to the next archetype, and returns whether this was successful/possible.
+// With disabled entities in the world, or with a change filter,
+// proceeds to the next run of matching entities instead.
 func (q *Query) nextArchetype() bool {
+	if q.isPaged {
+		return q.nextPage()
+	}
+	return q.nextArchetypeUnpaged()
+}
+
+// nextPage proceeds to the next run of matching entities, considering skipped entities and the limit.
+func (q *Query) nextPage() bool {
+	if q.isLimited && q.limit == 0 {
+		q.world.closeQuery(q)
+		return false
+	}
+	for q.nextArchetypeUnpaged() {
+		if q.skip > 0 {
+			n := q.entityIndexMax - q.entityIndex + 1
+			if n <= q.skip {
+				q.skip -= n
+				continue
+			}
+			q.entityIndex += q.skip
+			q.skip = 0
+		}
+		if q.isLimited {
+			n := q.entityIndexMax - q.entityIndex + 1
+			if n > q.limit {
+				n = q.limit
+				q.entityIndexMax = q.entityIndex + n - 1
+			}
+			q.limit -= n
+		}
+		return true
+	}
+	return false
+}
+
+// nextArchetypeUnpaged proceeds to the next archetype or run of matching entities, ignoring skip and limit.
+func (q *Query) nextArchetypeUnpaged() bool {
+	if q.changes != nil || (q.world.disabledCount > 0 && !q.isBatch && !q.withDisabled) {
+		return q.nextEnabled()
+	}
+	return q.nextArchetypeAny()
+}
+
+// nextEnabled proceeds to the next contiguous run of matching entities,
+// in the current or in subsequent archetypes.
+func (q *Query) nextEnabled() bool {
+	if q.archetype != nil && q.checkEntities(q.archetype) && q.nextRun(q.entityIndexMax+1) {
+		return true
+	}
+	for q.nextArchetypeAny() {
+		if !q.checkEntities(q.archetype) || q.nextRun(q.entityIndex) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextRun proceeds to the next run of matching entities in the current archetype, starting at the given index.
+func (q *Query) nextRun(from uint32) bool {
+	a := q.archetype
+	i := from
+	for i < a.len && !q.entityMatches(a.GetEntity(i).id) {
+		i++
+	}
+	if i >= a.len {
+		return false
+	}
+	j := i
+	for j+1 < a.len && q.entityMatches(a.GetEntity(j+1).id) {
+		j++
+	}
+	q.entityIndex = i
+	q.entityIndexMax = j
+	return true
+}
+
+// checkEntities reports whether entities of an archetype need to be checked individually.
+func (q *Query) checkEntities(arch *archetype) bool {
+	return q.changes != nil || (arch.disabled > 0 && !q.withDisabled)
+}
+
+// entityMatches reports whether an entity is enabled, and matches the query's change filter.
+func (q *Query) entityMatches(id eid) bool {
+	if !q.withDisabled && q.world.isDisabled(id) {
+		return false
+	}
+	return q.changes == nil || q.world.changes.Matches(q.changes, id, q.changeSince)
+}
+
+// archetypeLen returns the number of entities in an archetype that match the query.
+func (q *Query) archetypeLen(arch *archetype) uint32 {
+	if q.changes == nil {
+		return arch.activeLen()
+	}
+	var count, i uint32
+	for i = 0; i < arch.len; i++ {
+		if q.entityMatches(arch.GetEntity(i).id) {
+			count++
+		}
+	}
+	return count
+}
+
+// archetypeEntity returns the entity at the given index among the entities of an archetype that match the query.
+func (q *Query) archetypeEntity(arch *archetype, index uint32) Entity {
+	if q.changes == nil {
+		return q.world.enabledEntity(arch, index)
+	}
+	var i uint32
+	for i = 0; i < arch.len; i++ {
+		entity := arch.GetEntity(i)
+		if !q.entityMatches(entity.id) {
+			continue
+		}
+		if index == 0 {
+			return entity
+		}
+		index--
+	}
+	panic("matching entity index out of range")
+}
+
+// nextArchetypeAny proceeds to the next archetype, including disabled entities.
+func (q *Query) nextArchetypeAny() bool {
 	if q.isFiltered {
 		return q.nextArchetypeFiltered()
 	}
@@ -283,6 +478,10 @@ func (q *Query) nextArchetypeFiltered() bool {
 		q.archetype = a
 		q.entityIndex = 0
 		q.entityIndexMax = aLen - 1
+		if q.stats != nil {
+			q.stats.archetypes++
+			q.stats.entities += int(aLen)
+		}
 		return true
 	}
 	q.world.closeQuery(q)
@@ -332,6 +531,14 @@ func (q *Query) nextNode() bool {
 			continue
 		}
 
+		if mf, ok := q.filter.(*MultiRelationFilter); ok {
+			q.setArchetype(targetArchetypes(n, mf.Targets), nil, nil, -1, 0)
+			if q.nextArchetypeSimple() {
+				return true
+			}
+			continue
+		}
+
 		q.setArchetype(arches, nil, nil, -1, 0)
 		if q.nextArchetypeSimple() {
 			return true
@@ -366,7 +573,7 @@ func (q *Query) countEntities() int {
 		ln := int32(len(q.archetypes))
 		var i int32
 		for i = 0; i < ln; i++ {
-			count += q.archetypes[i].Len()
+			count += q.archetypeLen(q.archetypes[i])
 		}
 		return int(count)
 	}
@@ -390,14 +597,23 @@ func (q *Query) countEntities() int {
 			// There should be at least one archetype.
 			// Otherwise, the node would be inactive.
 			arch := nd.Archetypes().Get(0)
-			count += arch.Len()
+			count += q.archetypeLen(arch)
 			continue
 		}
 
 		if rf, ok := q.filter.(*RelationFilter); ok {
 			target := rf.Target
 			if arch, ok := nd.archetypeMap[target]; ok {
-				count += arch.Len()
+				count += q.archetypeLen(arch)
+			}
+			continue
+		}
+
+		if mf, ok := q.filter.(*MultiRelationFilter); ok {
+			for _, target := range mf.Targets {
+				if arch, ok := nd.archetypeMap[target]; ok {
+					count += q.archetypeLen(arch)
+				}
 			}
 			continue
 		}
@@ -407,7 +623,7 @@ func (q *Query) countEntities() int {
 		var j int32
 		for j = 0; j < nArch; j++ {
 			a := arches.Get(j)
-			count += a.Len()
+			count += q.archetypeLen(a)
 		}
 	}
 	return int(count)
@@ -424,9 +640,9 @@ func (q *Query) entityAt(index int) Entity {
 		ln := int32(len(q.archetypes))
 		var i int32
 		for i = 0; i < ln; i++ {
-			ln := q.archetypes[i].Len()
+			ln := q.archetypeLen(q.archetypes[i])
 			if idx < count+ln {
-				return q.archetypes[i].GetEntity(idx - count)
+				return q.archetypeEntity(q.archetypes[i], idx-count)
 			}
 			count += ln
 		}
@@ -457,9 +673,9 @@ func (q *Query) entityAt(index int) Entity {
 			// Otherwise, the node would be inactive.
 			arch := nd.Archetypes().Get(0)
 
-			ln := arch.Len()
+			ln := q.archetypeLen(arch)
 			if idx < count+ln {
-				return arch.GetEntity(idx - count)
+				return q.archetypeEntity(arch, idx-count)
 			}
 			count += ln
 			continue
@@ -469,24 +685,37 @@ func (q *Query) entityAt(index int) Entity {
 			target := rf.Target
 			if arch, ok := nd.archetypeMap[target]; ok {
 
-				ln := arch.Len()
+				ln := q.archetypeLen(arch)
 				if idx < count+ln {
-					return arch.GetEntity(idx - count)
+					return q.archetypeEntity(arch, idx-count)
 				}
 				count += ln
 			}
 			continue
 		}
 
+		if mf, ok := q.filter.(*MultiRelationFilter); ok {
+			for _, target := range mf.Targets {
+				if arch, ok := nd.archetypeMap[target]; ok {
+					ln := q.archetypeLen(arch)
+					if idx < count+ln {
+						return q.archetypeEntity(arch, idx-count)
+					}
+					count += ln
+				}
+			}
+			continue
+		}
+
 		arches := nd.Archetypes()
 		nArch := arches.Len()
 		var j int32
 		for j = 0; j < nArch; j++ {
 			arch := arches.Get(j)
 
-			ln := arch.Len()
+			ln := q.archetypeLen(arch)
 			if idx < count+ln {
-				return arch.GetEntity(idx - count)
+				return q.archetypeEntity(arch, idx-count)
 			}
 			count += ln
 		}
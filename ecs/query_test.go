@@ -115,6 +115,30 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, 0, cnt)
 }
 
+func TestQueryGetAbsent(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	w.NewEntity(posID)
+	w.NewEntity(velID)
+
+	query := w.Query(All())
+	cnt := 0
+	for query.Next() {
+		assert.Nil(t, query.Get(relID))
+		if query.Has(posID) {
+			assert.NotNil(t, query.Get(posID))
+			assert.Nil(t, query.Get(velID))
+		} else {
+			assert.Nil(t, query.Get(posID))
+		}
+		cnt++
+	}
+	assert.Equal(t, 2, cnt)
+}
+
 func TestQueryCached(t *testing.T) {
 	w := NewWorld()
 
@@ -597,6 +621,76 @@ func TestQueryEntityAt(t *testing.T) {
 	query.Close()
 }
 
+func TestQuerySkipLimit(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	all := []Entity{}
+	for i := 0; i < 10; i++ {
+		all = append(all, w.NewEntity(posID))
+	}
+	for i := 0; i < 10; i++ {
+		all = append(all, w.NewEntity(posID, velID))
+	}
+
+	collect := func(filter Filter, skip, limit int) []Entity {
+		query := w.Query(filter)
+		query.Skip(skip)
+		if limit >= 0 {
+			query.Limit(limit)
+		}
+		entities := []Entity{}
+		for query.Next() {
+			entities = append(entities, query.Entity())
+		}
+		return entities
+	}
+
+	filter := All(posID)
+	cached := w.Cache().Register(All(posID))
+	for _, f := range []Filter{filter, &cached} {
+		assert.Equal(t, all, collect(f, 0, -1))
+		assert.Equal(t, all[3:], collect(f, 3, -1))
+		assert.Equal(t, all[10:], collect(f, 10, -1))
+		assert.Equal(t, all[12:17], collect(f, 12, 5))
+		assert.Equal(t, all[8:12], collect(f, 8, 4))
+		assert.Equal(t, all[:10], collect(f, 0, 10))
+		assert.Equal(t, all[15:], collect(f, 15, 100))
+		assert.Equal(t, []Entity{}, collect(f, 20, -1))
+		assert.Equal(t, []Entity{}, collect(f, 25, 5))
+		assert.Equal(t, []Entity{}, collect(f, 0, 0))
+		assert.False(t, w.IsLocked())
+	}
+
+	w.Disable(all[1])
+	w.Disable(all[2])
+	assert.Equal(t, []Entity{all[0], all[3], all[4]}, collect(filter, 0, 3))
+	assert.Equal(t, []Entity{all[4], all[5]}, collect(filter, 2, 2))
+	w.Enable(all[1])
+	w.Enable(all[2])
+
+	query := w.Query(filter)
+	query.Skip(5)
+	query.Limit(7)
+	assert.Equal(t, 20, query.Count())
+	assert.True(t, query.Next())
+	assert.PanicsWithValue(t, "can't skip or limit a query after iteration started", func() { query.Skip(1) })
+	assert.PanicsWithValue(t, "can't skip or limit a query after iteration started", func() { query.Limit(1) })
+	query.Close()
+
+	query = w.Query(filter)
+	assert.PanicsWithValue(t, "can't skip a negative number of entities", func() { query.Skip(-1) })
+	assert.PanicsWithValue(t, "can't limit a query to a negative number of entities", func() { query.Limit(-1) })
+	query.Limit(12)
+	cnt := 0
+	for query.NextChunk() {
+		cnt += query.Column(posID).Len
+	}
+	assert.Equal(t, 12, cnt)
+	assert.False(t, w.IsLocked())
+}
+
 func BenchmarkQueryCreate(b *testing.B) {
 	b.StopTimer()
 
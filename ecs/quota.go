@@ -0,0 +1,152 @@
+package ecs
+
+import "fmt"
+
+// QuotaOverflow specifies the behavior when creating entities would exceed an entity quota.
+// See [World.SetQuota].
+type QuotaOverflow uint8
+
+const (
+	// QuotaPanic panics when a quota would be exceeded.
+	QuotaPanic QuotaOverflow = iota
+	// QuotaSkip skips the creation of entities that would exceed a quota.
+	QuotaSkip
+	// QuotaRemoveOldest removes the oldest entities of the origin to make room for new ones.
+	QuotaRemoveOldest
+)
+
+// quota holds the state of an entity quota for an origin label.
+type quota struct {
+	name     string
+	max      int // Maximum number of entities. Negative for unlimited.
+	overflow QuotaOverflow
+	count    int      // Number of live entities.
+	fifo     []Entity // Entities in creation order, may contain removed entities.
+	head     int      // Index of the oldest entry in fifo.
+}
+
+// quotaTracker tracks the origins of entities and the entity quotas per origin.
+type quotaTracker struct {
+	byName  map[string]*quota
+	origins []*quota // Quota by entity ID. Nil for entities without origin.
+}
+
+// Get returns the quota for an origin, and creates an unlimited quota if there is none.
+func (t *quotaTracker) Get(origin string) *quota {
+	q, ok := t.byName[origin]
+	if !ok {
+		q = &quota{name: origin, max: -1}
+		t.byName[origin] = q
+	}
+	return q
+}
+
+// Add registers a new entity with the given origin.
+func (t *quotaTracker) Add(q *quota, entity Entity) {
+	for int(entity.id) >= len(t.origins) {
+		t.origins = append(t.origins, nil)
+	}
+	t.origins[entity.id] = q
+	q.count++
+	q.fifo = append(q.fifo, entity)
+}
+
+// Remove releases the quota of a removed entity. Has no effect if the entity has no origin.
+func (t *quotaTracker) Remove(entity eid) {
+	if int(entity) >= len(t.origins) {
+		return
+	}
+	if q := t.origins[entity]; q != nil {
+		q.count--
+		t.origins[entity] = nil
+	}
+}
+
+// Reset releases all entities, but keeps the quotas.
+func (t *quotaTracker) Reset() {
+	for _, q := range t.byName {
+		q.count = 0
+		q.fifo = q.fifo[:0]
+		q.head = 0
+	}
+	t.origins = t.origins[:0]
+}
+
+// oldest returns the oldest live entity of a quota, and drops it from the queue.
+func (t *quotaTracker) oldest(q *quota, pool *entityPool) Entity {
+	for q.head < len(q.fifo) {
+		e := q.fifo[q.head]
+		q.head++
+		if pool.Alive(e) && t.origins[e.id] == q {
+			t.compact(q)
+			return e
+		}
+	}
+	panic("no entity to remove for quota")
+}
+
+// compact removes dropped entries from the queue when they make up more than half of it.
+func (t *quotaTracker) compact(q *quota) {
+	if q.head < 64 || q.head*2 < len(q.fifo) {
+		return
+	}
+	n := copy(q.fifo, q.fifo[q.head:])
+	q.fifo = q.fifo[:n]
+	q.head = 0
+}
+
+// SetQuota sets the maximum number of live entities for an origin label.
+// Entities are assigned to an origin when created by a [Builder] with [Builder.WithOrigin].
+//
+// The overflow behavior determines what happens when creating entities would exceed the quota:
+// panic, skip creation of the excess entities, or remove the oldest entities of the origin.
+// Lowering the maximum below the current number of entities does not remove any entities.
+// A negative maximum removes the limit, but the origin's entities are still counted.
+//
+// Quotas are not affected by [World.Reset], but entity counts are.
+// Entities count towards the quota until they are removed. With [Config.RemovalGracePeriod],
+// this is when they are marked as dying.
+func (w *World) SetQuota(origin string, max int, overflow QuotaOverflow) {
+	q := w.quotaTracker().Get(origin)
+	q.max = max
+	q.overflow = overflow
+}
+
+// QuotaCount returns the number of live entities of an origin label. See [World.SetQuota].
+func (w *World) QuotaCount(origin string) int {
+	if w.quotas == nil {
+		return 0
+	}
+	if q, ok := w.quotas.byName[origin]; ok {
+		return q.count
+	}
+	return 0
+}
+
+// quotaTracker returns the world's quota tracker, and creates it if it does not exist.
+func (w *World) quotaTracker() *quotaTracker {
+	if w.quotas == nil {
+		w.quotas = &quotaTracker{byName: map[string]*quota{}}
+	}
+	return w.quotas
+}
+
+// reserveQuota checks a quota before creating the given number of entities,
+// and applies the overflow behavior. Returns the number of entities to create.
+func (w *World) reserveQuota(q *quota, count int) int {
+	if q.max < 0 || q.count+count <= q.max {
+		return count
+	}
+	switch q.overflow {
+	case QuotaSkip:
+		return max(q.max-q.count, 0)
+	case QuotaRemoveOldest:
+		count = min(count, q.max)
+		for q.count+count > q.max {
+			w.RemoveEntity(w.quotas.oldest(q, &w.entityPool))
+		}
+		return count
+	default:
+		panic(fmt.Sprintf("entity quota of origin '%s' exceeded: %d of %d entities exist, can't create %d", q.name, q.count, q.max, count))
+	}
+}
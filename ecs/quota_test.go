@@ -0,0 +1,150 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldQuotaPanic(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	w.SetQuota("particles", 3, QuotaPanic)
+	builder := NewBuilder(&w, posID).WithOrigin("particles")
+
+	e1 := builder.New()
+	builder.NewBatch(2)
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+
+	assert.PanicsWithValue(t, "entity quota of origin 'particles' exceeded: 3 of 3 entities exist, can't create 1",
+		func() { builder.New() })
+
+	w.RemoveEntity(e1)
+	assert.Equal(t, 2, w.QuotaCount("particles"))
+	builder.New()
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+
+	NewBuilder(&w, posID).NewBatch(10)
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+	assert.Equal(t, 0, w.QuotaCount("projectiles"))
+}
+
+func TestWorldQuotaSkip(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	w.SetQuota("particles", 5, QuotaSkip)
+	builder := NewBuilder(&w, posID).WithOrigin("particles")
+
+	query := builder.NewBatchQ(3)
+	assert.Equal(t, 3, query.Count())
+	query.Close()
+
+	query = builder.NewBatchQ(4)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+	assert.Equal(t, 5, w.QuotaCount("particles"))
+
+	e := builder.New()
+	assert.True(t, e.IsZero())
+	builder.NewBatch(4)
+	assert.Equal(t, 5, w.QuotaCount("particles"))
+	assert.Equal(t, 5, countEntities(&w, All(posID)))
+}
+
+func TestWorldQuotaRemoveOldest(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	w.SetQuota("particles", 3, QuotaRemoveOldest)
+	builder := NewBuilder(&w, posID).WithOrigin("particles")
+
+	e1 := builder.New()
+	e2 := builder.New()
+	e3 := builder.New()
+	e4 := builder.New()
+
+	assert.False(t, w.Alive(e1))
+	assert.True(t, w.Alive(e2))
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+
+	w.RemoveEntity(e2)
+	e5 := builder.New()
+	assert.True(t, w.Alive(e3))
+	assert.True(t, w.Alive(e5))
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+
+	query := builder.NewBatchQ(5)
+	assert.Equal(t, 3, query.Count())
+	query.Close()
+	assert.False(t, w.Alive(e3))
+	assert.False(t, w.Alive(e4))
+	assert.False(t, w.Alive(e5))
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+	assert.Equal(t, 3, countEntities(&w, All(posID)))
+
+	for i := 0; i < 200; i++ {
+		builder.New()
+	}
+	assert.Equal(t, 3, w.QuotaCount("particles"))
+	assert.Equal(t, 3, countEntities(&w, All(posID)))
+}
+
+func TestWorldQuotaRelation(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	w.SetQuota("children", 2, QuotaSkip)
+	builder := NewBuilder(&w, posID, relID).WithRelation(relID).WithOrigin("children")
+
+	builder.NewBatch(3, parent)
+	assert.Equal(t, 2, w.QuotaCount("children"))
+	filter := NewRelationFilter(All(relID), parent)
+	assert.Equal(t, 2, countEntities(&w, &filter))
+}
+
+func TestWorldQuotaDying(t *testing.T) {
+	w := NewWorld(NewConfig().WithRemovalGracePeriod(2))
+	posID := ComponentID[Position](&w)
+
+	w.SetQuota("particles", 2, QuotaPanic)
+	builder := NewBuilder(&w, posID).WithOrigin("particles")
+
+	e1 := builder.New()
+	builder.New()
+	w.RemoveEntity(e1)
+	assert.True(t, w.Alive(e1))
+	assert.Equal(t, 1, w.QuotaCount("particles"))
+
+	builder.New()
+	w.Batch().RemoveEntities(All(posID))
+	assert.Equal(t, 0, w.QuotaCount("particles"))
+
+	w.Tick()
+	w.Tick()
+	assert.Equal(t, 0, w.QuotaCount("particles"))
+}
+
+func TestWorldQuotaReset(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	w.SetQuota("particles", 2, QuotaPanic)
+	builder := NewBuilder(&w, posID).WithOrigin("particles")
+	builder.NewBatch(2)
+
+	w.Reset()
+	assert.Equal(t, 0, w.QuotaCount("particles"))
+
+	builder = NewBuilder(&w, posID).WithOrigin("particles")
+	builder.NewBatch(2)
+	assert.Equal(t, 2, w.QuotaCount("particles"))
+	assert.Panics(t, func() { builder.New() })
+
+	w.SetQuota("particles", -1, QuotaPanic)
+	builder.NewBatch(5)
+	assert.Equal(t, 7, w.QuotaCount("particles"))
+}
@@ -8,16 +8,19 @@ import (
 // componentRegistry keeps track of component IDs.
 type componentRegistry struct {
 	Components map[reflect.Type]uint8
+	Names      map[string]uint8 // Explicit names of components.
 	Types      []reflect.Type
 	Used       Mask
 	IsRelation Mask
 	IDs        []uint8
+	Next       int // Next ID for automatic assignment. All used and reserved IDs are below.
 }
 
 // newComponentRegistry creates a new ComponentRegistry.
 func newComponentRegistry() componentRegistry {
 	return componentRegistry{
 		Components: map[reflect.Type]uint8{},
+		Names:      map[string]uint8{},
 		Types:      make([]reflect.Type, MaskTotalBits),
 		Used:       Mask{},
 		IsRelation: Mask{},
@@ -39,37 +42,76 @@ func (r *componentRegistry) ComponentType(id uint8) (reflect.Type, bool) {
 	return r.Types[id], r.Used.Get(ID{id: id})
 }
 
-// ComponentType returns the type of a component by ID.
+// Count returns the number of used and reserved IDs, i.e. the upper bound of all IDs.
 func (r *componentRegistry) Count() int {
-	return len(r.Components)
+	return r.Next
 }
 
 // registerComponent registers a components and assigns an ID for it.
 func (r *componentRegistry) registerComponent(tp reflect.Type, totalBits int) uint8 {
-	val := len(r.Components)
+	val := r.Next
 	if val >= totalBits {
 		panic(fmt.Sprintf("exceeded the maximum of %d component types or resource types", totalBits))
 	}
+	r.Next++
 	newID := uint8(val)
+	r.registerComponentAt(tp, newID)
+	return newID
+}
+
+// reserve reserves a contiguous block of IDs, and returns the first ID of the block.
+func (r *componentRegistry) reserve(count int, totalBits int) uint8 {
+	start := r.Next
+	if start+count > totalBits {
+		panic(fmt.Sprintf("can't reserve %d IDs, exceeds the maximum of %d component types", count, totalBits))
+	}
+	r.Next += count
+	return uint8(start)
+}
+
+// registerComponentAt registers a components with the given ID.
+// The ID must be reserved and unused.
+func (r *componentRegistry) registerComponentAt(tp reflect.Type, newID uint8) {
 	id := id(newID)
 	r.Components[tp], r.Types[newID] = newID, tp
 	r.Used.Set(id, true)
 	if r.isRelation(tp) {
 		r.IsRelation.Set(id, true)
 	}
-	r.IDs = append(r.IDs, newID)
-	return newID
+	idx := len(r.IDs)
+	for idx > 0 && r.IDs[idx-1] > newID {
+		idx--
+	}
+	r.IDs = append(r.IDs, 0)
+	copy(r.IDs[idx+1:], r.IDs[idx:])
+	r.IDs[idx] = newID
 }
 
+// unregisterLastComponent unregisters the last automatically assigned ID.
 func (r *componentRegistry) unregisterLastComponent() {
-	newID := uint8(len(r.Components) - 1)
+	r.Next--
+	r.unregisterComponent(uint8(r.Next))
+}
+
+// unregisterComponent unregisters the component with the given ID, without releasing the ID.
+func (r *componentRegistry) unregisterComponent(newID uint8) {
 	id := id(newID)
 	tp, _ := r.ComponentType(newID)
 	delete(r.Components, tp)
+	for name, nameID := range r.Names {
+		if nameID == newID {
+			delete(r.Names, name)
+		}
+	}
 	r.Types[newID] = nil
 	r.Used.Set(id, false)
 	r.IsRelation.Set(id, false)
-	r.IDs = r.IDs[:len(r.IDs)-1]
+	for i, iid := range r.IDs {
+		if iid == newID {
+			r.IDs = append(r.IDs[:i], r.IDs[i+1:]...)
+			break
+		}
+	}
 }
 
 func (r *componentRegistry) isRelation(tp reflect.Type) bool {
@@ -12,6 +12,6 @@ var relationType = reflect.TypeOf((*Relation)(nil)).Elem()
 // E.g. to iterate over all entities that are the child of a certain parent entity.
 // Currently, each entity can only have a single relation component.
 //
-// See also [RelationFilter], [World.Relations], [Relations.Get], [Relations.Set] and
+// See also [RelationFilter], [MultiRelationFilter], [World.Relations], [Relations.Get], [Relations.Set] and
 // [Builder.WithRelation].
 type Relation struct{}
@@ -126,3 +126,54 @@ func (r *Relations) ExchangeBatch(filter Filter, add []ID, rem []ID, relation ID
 func (r *Relations) ExchangeBatchQ(filter Filter, add []ID, rem []ID, relation ID, target Entity) Query {
 	return r.world.exchangeBatchQuery(filter, add, rem, relation, true, target)
 }
+
+// Children returns a query over the direct children of an entity,
+// i.e. over all entities with the given [Relation] component targeting it.
+//
+// Panics:
+//   - when called for a component that is not a relation.
+//
+// See also [Relations.Descendants] and [Relations.RemoveEntityRecursive].
+func (r *Relations) Children(parent Entity, comp ID) Query {
+	r.world.checkIsRelation(comp)
+	filter := NewRelationFilter(All(comp), parent)
+	return r.world.Query(&filter)
+}
+
+// Descendants returns all descendants of an entity in breadth-first order,
+// following the given [Relation] component from targets to the entities pointing to them.
+// The entity itself is not included.
+//
+// Panics:
+//   - when called for a component that is not a relation.
+//
+// See also [Relations.Children].
+func (r *Relations) Descendants(parent Entity, comp ID) []Entity {
+	r.world.checkIsRelation(comp)
+	return r.world.appendDescendants(nil, parent, comp)
+}
+
+// RemoveEntityRecursive removes an entity and all its descendants,
+// following the given [Relation] component. See [Relations.Descendants].
+// Returns the number of removed entities.
+//
+// Descendants are removed before their ancestors, deepest first.
+// Removal happens as with [World.RemoveEntity], including events and soft-deletion.
+//
+// Panics:
+//   - when called for a removed (and potentially recycled) entity.
+//   - when called for a component that is not a relation.
+//   - when called on a locked world. Do not use during [Query] iteration!
+func (r *Relations) RemoveEntityRecursive(parent Entity, comp ID) int {
+	w := r.world
+	w.checkLocked()
+	w.checkIsRelation(comp)
+	if !w.entityPool.Alive(parent) {
+		panic("can't remove a dead entity")
+	}
+	entities := w.appendDescendants([]Entity{parent}, parent, comp)
+	for i := len(entities) - 1; i >= 0; i-- {
+		w.RemoveEntity(entities[i])
+	}
+	return len(entities)
+}
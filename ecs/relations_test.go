@@ -57,6 +57,65 @@ func TestRelationsExchange(t *testing.T) {
 
 }
 
+func TestRelationsHierarchy(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	childID := ecs.ComponentID[ChildOf](&w)
+
+	builder := ecs.NewBuilder(&w, posID, childID).WithRelation(childID)
+
+	root := w.NewEntity(posID)
+	children := builder.AppendBatch(nil, 3, root)
+	grandChildren := builder.AppendBatch(nil, 2, children[0])
+	grandChildren = builder.AppendBatch(grandChildren, 2, children[2])
+	greatGrandChild := builder.New(grandChildren[3])
+	other := builder.New(w.NewEntity())
+
+	query := w.Relations().Children(root, childID)
+	assert.Equal(t, 3, query.Count())
+	for i := 0; query.Next(); i++ {
+		assert.Equal(t, children[i], query.Entity())
+	}
+
+	query = w.Relations().Children(children[1], childID)
+	assert.Equal(t, 0, query.Count())
+	query.Close()
+
+	descendants := w.Relations().Descendants(root, childID)
+	expected := append(append(append([]ecs.Entity{}, children...), grandChildren...), greatGrandChild)
+	assert.Equal(t, expected, descendants)
+	assert.Equal(t, []ecs.Entity{greatGrandChild}, w.Relations().Descendants(grandChildren[3], childID))
+	assert.Empty(t, w.Relations().Descendants(greatGrandChild, childID))
+
+	assert.PanicsWithValue(t, "not a relation component: ecs_test.Position", func() {
+		w.Relations().Descendants(root, posID)
+	})
+	assert.PanicsWithValue(t, "not a relation component: ecs_test.Position", func() {
+		w.Relations().Children(root, posID)
+	})
+
+	assert.Equal(t, 4, w.Relations().RemoveEntityRecursive(children[2], childID))
+	assert.False(t, w.Alive(children[2]))
+	assert.False(t, w.Alive(greatGrandChild))
+	assert.True(t, w.Alive(children[0]))
+	assert.True(t, w.Alive(grandChildren[0]))
+
+	assert.Equal(t, 5, w.Relations().RemoveEntityRecursive(root, childID))
+	assert.False(t, w.Alive(grandChildren[1]))
+	assert.True(t, w.Alive(other))
+	assert.PanicsWithValue(t, "can't remove a dead entity", func() {
+		w.Relations().RemoveEntityRecursive(root, childID)
+	})
+
+	// Cycles are visited only once.
+	a := w.NewEntity()
+	b := builder.New(a)
+	w.Add(a, posID, childID)
+	w.Relations().Set(a, childID, b)
+	assert.Equal(t, []ecs.Entity{b}, w.Relations().Descendants(a, childID))
+	assert.Equal(t, 2, w.Relations().RemoveEntityRecursive(a, childID))
+}
+
 func ExampleRelations() {
 	world := ecs.NewWorld()
 
@@ -0,0 +1,60 @@
+package ecs
+
+// Reserve reserves the given number of entities, and returns them.
+//
+// Reserved entities are not alive yet. They are created without any components ("realized")
+// when the world is unlocked by closing the last open [Query], when a [CommandBuffer] is flushed,
+// or before the next entity is created, whichever comes first.
+// Realized entities are in the same state as entities created with [World.NewEntity] without components,
+// and emit the same events.
+// Components are typically added by recording commands for the reserved entities,
+// e.g. in the world's buffer from [World.Commands], which is flushed after realization.
+//
+// Reserve is safe for concurrent use by multiple goroutines, also while the world is locked.
+// This allows parallel spawning pipelines to use entity IDs before the entities are created on the main thread.
+// It must not be called concurrently with structural changes of the world.
+// Reserved entities must not be used with other world methods before they are realized.
+//
+// Reserved entities always get fresh IDs, and never recycle IDs of removed entities.
+// Reservations that are not yet realized are dropped by [World.Reset].
+func (w *World) Reserve(count int) []Entity {
+	if count < 0 {
+		panic("can't reserve a negative number of entities")
+	}
+	first := w.entityPool.Reserve(count)
+
+	entities := make([]Entity, count)
+	for i := range entities {
+		entities[i] = newEntity(first + eid(i))
+	}
+	return entities
+}
+
+// realizeReserved creates all entities reserved with [World.Reserve], without components.
+//
+// Only realizes reservations made before the call.
+// Entities reserved concurrently get IDs after these, and are realized on the next call.
+func (w *World) realizeReserved() {
+	end := w.entityPool.ReservedEnd()
+	arch := w.archetypes.Get(0)
+	for eid(len(w.entityPool.entities)) < end {
+		entity := w.entityPool.getNew()
+		w.placeEntity(arch, entity)
+		if len(w.journals) > 0 {
+			w.recordCreate(entity, true, arch)
+		}
+
+		if w.listener != nil {
+			bits := subscription(true, false, false, false, false, false)
+			trigger := w.listener.Subscriptions() & bits
+			if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, nil) {
+				w.notify(EntityEvent{Entity: entity, Added: arch.Mask, EventTypes: bits})
+			}
+		}
+	}
+}
+
+// hasReserved reports whether there are reserved entities that are not yet realized.
+func (w *World) hasReserved() bool {
+	return w.entityPool.HasReserved()
+}
@@ -0,0 +1,163 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldReserve(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e1 := w.NewEntity(posID)
+	w.RemoveEntity(w.NewEntity(posID))
+
+	reserved := w.Reserve(3)
+	assert.Equal(t, []Entity{newEntity(3), newEntity(4), newEntity(5)}, reserved)
+	assert.Equal(t, []Entity{newEntity(6)}, w.Reserve(1))
+	assert.Equal(t, []Entity{}, w.Reserve(0))
+
+	e2 := w.NewEntity(posID)
+	assert.Equal(t, newEntityGen(2, 1), e2)
+	for _, e := range append(reserved, newEntity(6)) {
+		assert.True(t, w.Alive(e))
+		assert.Equal(t, 0, len(w.Ids(e)))
+	}
+	assert.True(t, w.Alive(e1))
+	assert.Equal(t, newEntity(7), w.NewEntity())
+
+	assert.PanicsWithValue(t, "can't reserve a negative number of entities", func() { w.Reserve(-1) })
+}
+
+func TestWorldReserveCommands(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) { events = append(events, e) })
+	w.SetListener(&listener)
+
+	w.NewEntity(posID)
+	NewBuilder(&w, posID).NewBatch(10)
+
+	reserved := []Entity{}
+	query := w.Query(All(posID))
+	for query.Next() {
+		entity := w.Reserve(1)[0]
+		reserved = append(reserved, entity)
+		w.Commands().Add(entity, velID)
+	}
+
+	assert.Equal(t, 11, len(reserved))
+	for _, e := range reserved {
+		assert.True(t, w.Alive(e))
+		assert.True(t, w.Has(e, velID))
+	}
+	assert.Equal(t, 11+2*11, len(events))
+	assert.Equal(t, event.EntityCreated, events[11].EventTypes)
+	assert.Equal(t, reserved[0], events[11].Entity)
+
+	buffer := NewCommandBuffer(&w)
+	entities := w.Reserve(2)
+	buffer.Add(entities[1], posID)
+	buffer.Flush()
+	assert.True(t, w.Has(entities[1], posID))
+	assert.False(t, w.Has(entities[0], posID))
+
+	w.Reserve(5)
+	w.Reset()
+	assert.Equal(t, newEntity(1), w.NewEntity())
+
+	w2 := NewWorld()
+	w2.Reserve(1)
+	assert.PanicsWithValue(t, "can set entity data only on a fresh or reset world", func() {
+		w2.LoadEntities(&EntityDump{})
+	})
+}
+
+func TestWorldReserveConcurrent(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	NewBuilder(&w, posID).NewBatch(100)
+
+	workers := 8
+	results := make([][]Entity, workers)
+
+	query := w.Query(All(posID))
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				results[i] = append(results[i], w.Reserve(1)...)
+			}
+		}(i)
+	}
+	wg.Wait()
+	query.Close()
+
+	seen := map[Entity]bool{}
+	for _, res := range results {
+		for _, e := range res {
+			assert.False(t, seen[e])
+			seen[e] = true
+			assert.True(t, w.Alive(e))
+		}
+	}
+	assert.Equal(t, workers*100, len(seen))
+	assert.Equal(t, 100+workers*100, w.entityPool.Len())
+}
+
+func TestWorldReserveConcurrentRealize(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	NewBuilder(&w, posID).NewBatch(100)
+
+	workers := 8
+	results := make([][]Entity, workers)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				results[i] = append(results[i], w.Reserve(2)...)
+			}
+		}(i)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Reservations are realized on every close, concurrently to the workers.
+	running := true
+	for running {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		query := w.Query(All(posID))
+		query.Close()
+	}
+
+	seen := map[Entity]bool{}
+	for _, res := range results {
+		for _, e := range res {
+			assert.False(t, seen[e])
+			seen[e] = true
+			assert.True(t, w.Alive(e))
+		}
+	}
+	assert.Equal(t, workers*400, len(seen))
+	assert.Equal(t, 100+workers*400, w.entityPool.Len())
+	assert.Equal(t, newEntity(eid(100+workers*400+1)), w.NewEntity())
+}
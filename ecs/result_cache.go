@@ -0,0 +1,104 @@
+package ecs
+
+import "github.com/mlange-42/arche/ecs/event"
+
+// ResultCache caches the entities matching a [Filter], at entity granularity.
+//
+// The cache is a [Listener], and is invalidated by events that change whether an entity matches the filter.
+// As long as the matching population is stable, [ResultCache.Entities] returns the cached entities
+// without re-matching archetypes or iterating a query.
+// This is intended for expensive filters, like those with exclusions, relations or custom logic.
+//
+// The cache needs to receive the world's events, either directly via [World.SetListener],
+// or as a sub-listener of [github.com/mlange-42/arche/listener.Dispatch].
+//
+// A relation target change invalidates caches for [RelationFilter] filters if the entity matches the components filter.
+// Operations that don't emit events, like [World.Reset], [World.LoadEntities] and [World.LoadSnapshot],
+// require a manual [ResultCache.Invalidate].
+//
+// Example:
+//
+//	filter := All(posID).Without(velID)
+//	cache := NewResultCache(&filter)
+//	world.SetListener(cache)
+//
+//	for _, e := range cache.Entities(&world) {
+//		pos := (*Position)(world.Get(e, posID))
+//		// ...
+//	}
+type ResultCache struct {
+	filter   Filter
+	relation bool
+	entities []Entity
+	valid    bool
+}
+
+// NewResultCache creates a new [ResultCache] for the given filter.
+// The cache is initially invalid and is filled on the first call to [ResultCache.Entities].
+func NewResultCache(filter Filter) *ResultCache {
+	_, relation := filter.(targetFilter)
+	return &ResultCache{
+		filter:   filter,
+		relation: relation,
+	}
+}
+
+// Entities returns the entities matching the filter, in query iteration order.
+// Re-runs the query only if the cache was invalidated.
+//
+// The returned slice is owned by the cache. It must not be modified,
+// and is only valid until the cache is invalidated.
+func (c *ResultCache) Entities(w *World) []Entity {
+	if c.valid {
+		return c.entities
+	}
+	c.entities = c.entities[:0]
+	query := w.Query(c.filter)
+	for query.Next() {
+		c.entities = append(c.entities, query.Entity())
+	}
+	c.valid = true
+	return c.entities
+}
+
+// Valid returns whether the cached entities are up to date.
+func (c *ResultCache) Valid() bool {
+	return c.valid
+}
+
+// Invalidate the cache, so that the query is re-run on the next call to [ResultCache.Entities].
+func (c *ResultCache) Invalidate() {
+	c.valid = false
+}
+
+// Notify the cache about an event. Invalidates it if the event changes the filter's results.
+func (c *ResultCache) Notify(world *World, evt EntityEvent) {
+	if !c.valid {
+		return
+	}
+	var oldMask, newMask Mask
+	if evt.Contains(event.EntityRemoved) {
+		oldMask = evt.Removed
+	} else if evt.Contains(event.EntityCreated) {
+		newMask = evt.Added
+	} else {
+		newMask = world.Mask(evt.Entity)
+		oldMask = newMask.Xor(&evt.Added)
+		oldMask = oldMask.Or(&evt.Removed)
+	}
+	oldMatch := c.filter.Matches(&oldMask)
+	newMatch := c.filter.Matches(&newMask)
+	if oldMatch != newMatch || (c.relation && (oldMatch || newMatch) && evt.Contains(event.TargetChanged)) {
+		c.valid = false
+	}
+}
+
+// Subscriptions of the cache.
+func (c *ResultCache) Subscriptions() event.Subscription {
+	return event.Entities | event.Components | event.TargetChanged
+}
+
+// Components the cache subscribes to. Always nil, i.e. all components.
+func (c *ResultCache) Components() *Mask {
+	return nil
+}
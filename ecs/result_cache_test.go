@@ -0,0 +1,91 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCache(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	rotID := ComponentID[rotation](&w)
+
+	filter := All(posID).Without(velID)
+	cache := NewResultCache(&filter)
+	w.SetListener(cache)
+
+	e0 := w.NewEntity(posID)
+	e1 := w.NewEntity(posID, velID)
+	assert.False(t, cache.Valid())
+
+	assert.Equal(t, []Entity{e0}, cache.Entities(&w))
+	assert.True(t, cache.Valid())
+
+	w.Add(e0, rotID)
+	assert.True(t, cache.Valid())
+	w.NewEntity(rotID)
+	assert.True(t, cache.Valid())
+	w.Remove(e1, posID)
+	assert.True(t, cache.Valid())
+
+	w.Remove(e0, rotID)
+	assert.True(t, cache.Valid())
+
+	w.Add(e0, velID)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, 0, len(cache.Entities(&w)))
+
+	w.Remove(e0, velID)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, []Entity{e0}, cache.Entities(&w))
+
+	e2 := w.NewEntity(posID)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, []Entity{e0, e2}, cache.Entities(&w))
+
+	w.RemoveEntity(e0)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, []Entity{e2}, cache.Entities(&w))
+
+	NewBuilder(&w, posID).NewBatch(5)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, 6, len(cache.Entities(&w)))
+
+	w.Batch().Add(All(posID), velID)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, 0, len(cache.Entities(&w)))
+
+	w.NewEntity(posID)
+	cache.Entities(&w)
+	w.Reset()
+	assert.True(t, cache.Valid())
+	cache.Invalidate()
+	assert.Equal(t, 0, len(cache.Entities(&w)))
+}
+
+func TestResultCacheRelation(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	filter := NewRelationFilter(All(posID, relID), parent1)
+	cache := NewResultCache(&filter)
+	w.SetListener(cache)
+
+	e0 := w.NewEntity(posID, relID)
+	w.Relations().Set(e0, relID, parent1)
+	e1 := w.NewEntity(relID)
+	assert.Equal(t, []Entity{e0}, cache.Entities(&w))
+
+	w.Relations().Set(e1, relID, parent1)
+	assert.True(t, cache.Valid())
+
+	w.Relations().Set(e0, relID, parent2)
+	assert.False(t, cache.Valid())
+	assert.Equal(t, 0, len(cache.Entities(&w)))
+}
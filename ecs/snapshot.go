@@ -0,0 +1,576 @@
+package ecs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// snapshotMagic identifies binary snapshots.
+const snapshotMagic = "ARCHE-SNAPSHOT"
+
+// snapshotVersion is the version of the binary snapshot format.
+// Version 2 adds resources, version 3 adds the archetype schema,
+// version 4 writes a separate section per archetype column.
+const snapshotVersion uint32 = 4
+
+// ErrSnapshotLayout is the base error for snapshots that are incompatible with a world's component layouts.
+// Check for it using [errors.Is].
+var ErrSnapshotLayout = errors.New("incompatible snapshot layout")
+
+// snapshotComponent describes a component type in a binary snapshot.
+type snapshotComponent struct {
+	Name      string // Type name, as given by [reflect.Type.String].
+	Size      uint32 // Size of the type in bytes.
+	Signature uint64 // Hash of the type's memory layout.
+	id        ID     // ID of the component in the world.
+}
+
+// Snapshot writes a binary snapshot of all entities and their components.
+//
+// Component data is written as raw memory blocks per archetype column.
+// Therefore, snapshots are fast to write and load, but can only contain components without pointers,
+// i.e. without pointers, slices, strings, maps, interfaces, channels and functions.
+// Returns an error wrapping [ErrSnapshotLayout] if any component type in use contains pointers.
+//
+// Snapshots can only be loaded on machines with the same byte order and word size.
+// Resources are only part of snapshots if they implement [SerializableResource].
+// Returns errors from serializing resources.
+//
+// See [World.LoadSnapshot] for loading snapshots.
+// Panics when called on a locked world.
+func (w *World) Snapshot(out io.Writer) error {
+	return w.snapshot(context.Background(), out)
+}
+
+// snapshot writes a binary snapshot, checking the context for cancellation between archetypes.
+func (w *World) snapshot(ctx context.Context, out io.Writer) error {
+	w.checkLocked()
+
+	arches := w.snapshotArchetypes()
+	compIndex, comps, err := w.snapshotComponents(arches)
+	if err != nil {
+		return err
+	}
+
+	sw := newSnapshotWriter(out)
+
+	if err := sw.WriteSection("header", snapshotHeader()); err != nil {
+		return err
+	}
+
+	buf := w.appendSnapshotEntityPool(nil)
+	if err := sw.WriteSection("entities", buf); err != nil {
+		return err
+	}
+
+	buf = appendSnapshotComponents(buf[:0], comps)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(arches)))
+	if err := sw.WriteSection("components", buf); err != nil {
+		return err
+	}
+
+	buf = buf[:0]
+	for _, arch := range arches {
+		buf = appendSnapshotArchetype(buf, arch, compIndex)
+	}
+	if err := sw.WriteSection("schema", buf); err != nil {
+		return err
+	}
+
+	for i, arch := range arches {
+		if err := ctx.Err(); err != nil {
+			return &ProgressError{Done: i, Total: len(arches), Err: err}
+		}
+		buf = arch.entities.appendBytes(buf[:0], 0, arch.len)
+		if err := sw.WriteSection(snapshotArchetypeSection(i), buf); err != nil {
+			return err
+		}
+		for _, id := range arch.node.Ids {
+			lay := arch.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			buf = lay.appendBytes(buf[:0], 0, arch.len)
+			tp, _ := w.registry.ComponentType(id.id)
+			if err := sw.WriteSection(snapshotColumnSection(i, tp), buf); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf, err = w.snapshotResources()
+	if err != nil {
+		return err
+	}
+	if err := sw.WriteSection("resources", buf); err != nil {
+		return err
+	}
+
+	return sw.Finish()
+}
+
+// LoadSnapshot restores entities and their components from a binary snapshot written by [World.Snapshot].
+//
+// Use this only on an empty world! Can be used after [World.Reset].
+// All component types contained in the snapshot must be registered,
+// e.g. using [ComponentID], and their memory layout must match the layout at snapshot time.
+//
+// The resulting world has the same entities (in terms of ID, generation and alive state),
+// with the same components and relation targets as the original world.
+// When loaded into a fresh world, entities are restored in their original iteration order.
+// Does not emit any events to the world's [Listener].
+// All archetypes are created with the capacity required for their entities before entity data is copied.
+// See also [World.PrewarmSnapshot].
+//
+// Resources in the snapshot are restored into the world's resources of the same type,
+// which must be added before loading. See [SerializableResource].
+//
+// Returns an error wrapping [ErrSnapshotCorrupt] for corrupt or truncated data,
+// and an error wrapping [ErrSnapshotLayout] for unregistered or incompatible component types,
+// or for resources that are missing or have a newer version than supported.
+// All data is validated before the world is modified.
+// Errors from deserializing resources are returned before entities are restored,
+// but resources restored before the failing one remain modified.
+//
+// Panics when called on a locked world or on a world that is not fresh or reset.
+func (w *World) LoadSnapshot(in io.Reader) error {
+	w.checkLocked()
+	if len(w.entityPool.entities) > 1 || w.entityPool.available > 0 || w.hasReserved() {
+		panic("can set entity data only on a fresh or reset world")
+	}
+	w.epoch++
+	if len(w.journals) > 0 {
+		w.recordState()
+	}
+
+	sr := newSnapshotReader(in)
+	schema, err := w.readSnapshotSchema(sr)
+	if err != nil {
+		return err
+	}
+	numEntities := schema.numEntities
+
+	arches, err := w.readSnapshotArchetypes(sr, schema)
+	if err != nil {
+		return err
+	}
+
+	var resources []snapshotResource
+	var resTargets []SerializableResource
+	if schema.version >= 2 {
+		data, err := sr.ReadSection("resources")
+		if err != nil {
+			return err
+		}
+		resources, resTargets, err = w.decodeSnapshotResources(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := sr.Finish(); err != nil {
+		return err
+	}
+	if err := loadSnapshotResources(resources, resTargets); err != nil {
+		return err
+	}
+
+	capacity := capacity(int(numEntities), w.config.CapacityIncrement)
+	entities := make([]Entity, numEntities, capacity)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&entities[0])), len(schema.entityBytes)), schema.entityBytes)
+	w.entityPool.entities = entities
+	w.entityPool.next = eid(schema.next)
+	w.entityPool.available = schema.available
+	w.entityPool.DropReserved()
+
+	w.entities = make([]entityIndex, numEntities, capacity)
+	w.targetEntities = bitSet{}
+	w.targetEntities.ExtendTo(capacity)
+
+	schemaArches := make([]snapshotArchetype, len(arches))
+	for i := range arches {
+		schemaArches[i] = arches[i].snapshotArchetype
+	}
+	w.prewarmArchetypes(schemaArches)
+
+	root := w.archetypes.Get(0)
+	for i := range arches {
+		a := &arches[i]
+		arch := w.findOrCreateArchetype(root, a.ids, nil, a.target)
+		if !a.target.IsZero() {
+			w.targetEntities.Set(a.target.id, true)
+		}
+		start := arch.len
+		arch.AllocN(a.count)
+		arch.entities.setBytes(start, a.entities)
+		for j, id := range a.ids {
+			lay := arch.getLayout(id)
+			if lay.itemSize == 0 {
+				continue
+			}
+			lay.setBytes(start, a.columns[j])
+		}
+		var j uint32
+		for j = 0; j < a.count; j++ {
+			entity := arch.GetEntity(start + j)
+			w.entities[entity.id] = entityIndex{arch: arch, index: start + j}
+			if w.lifetimes != nil {
+				w.lifetimes.Load(entity.id, w.tick)
+			}
+			if w.changes != nil {
+				w.changes.Create(entity.id, &arch.Mask)
+			}
+		}
+	}
+
+	return nil
+}
+
+// snapshotArchetypes returns all non-empty archetypes, in query iteration order.
+func (w *World) snapshotArchetypes() []*archetype {
+	arches := []*archetype{}
+	for _, node := range w.nodePointers {
+		if !node.IsActive {
+			continue
+		}
+		if !node.HasRelation {
+			if node.archetype.Len() > 0 {
+				arches = append(arches, node.archetype)
+			}
+			continue
+		}
+		lenArches := node.archetypes.Len()
+		var j int32
+		for j = 0; j < lenArches; j++ {
+			arch := node.archetypes.Get(j)
+			if arch.IsActive() && arch.Len() > 0 {
+				arches = append(arches, arch)
+			}
+		}
+	}
+	return arches
+}
+
+// snapshotArchetypeData holds the description and raw data of an archetype in a binary snapshot.
+type snapshotArchetypeData struct {
+	snapshotArchetype
+	entities []byte   // Raw entity data.
+	columns  [][]byte // Raw component data, in the order of the archetype's IDs.
+}
+
+// readSnapshotArchetypes reads and validates the archetype sections of a binary snapshot.
+func (w *World) readSnapshotArchetypes(sr *snapshotReader, schema *snapshotSchema) ([]snapshotArchetypeData, error) {
+	if schema.version < 4 {
+		return w.readSnapshotArchetypesLegacy(sr, schema)
+	}
+	arches := make([]snapshotArchetypeData, schema.numArches)
+	for i := range arches {
+		a := &arches[i]
+		a.snapshotArchetype = schema.arches[i]
+		section := snapshotArchetypeSection(i)
+		data, err := sr.ReadSection(section)
+		if err != nil {
+			return nil, err
+		}
+		if a.entities, err = readSnapshotColumn(data, section, int(a.count)*int(entitySize)); err != nil {
+			return nil, err
+		}
+		if err := checkSnapshotEntities(a.entities, section, schema.numEntities); err != nil {
+			return nil, err
+		}
+		a.columns = make([][]byte, len(a.ids))
+		for j, id := range a.ids {
+			tp, _ := w.registry.ComponentType(id.id)
+			if tp.Size() == 0 {
+				continue
+			}
+			section := snapshotColumnSection(i, tp)
+			data, err := sr.ReadSection(section)
+			if err != nil {
+				return nil, err
+			}
+			if a.columns[j], err = readSnapshotColumn(data, section, int(a.count)*int(tp.Size())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return arches, nil
+}
+
+// readSnapshotArchetypesLegacy reads and validates the archetype sections of snapshots before format version 4,
+// which store all columns of an archetype in a single section.
+func (w *World) readSnapshotArchetypesLegacy(sr *snapshotReader, schema *snapshotSchema) ([]snapshotArchetypeData, error) {
+	numEntities := schema.numEntities
+	arches := make([]snapshotArchetypeData, schema.numArches)
+	for i := range arches {
+		data, err := sr.ReadSection("archetype")
+		if err != nil {
+			return nil, err
+		}
+		dec := snapshotDecoder{data: data, section: "archetype"}
+		a := &arches[i]
+		if schema.arches == nil {
+			if a.snapshotArchetype, err = decodeSnapshotArchetype(&dec, schema.comps, numEntities); err != nil {
+				return nil, err
+			}
+		} else {
+			a.snapshotArchetype = schema.arches[i]
+		}
+		a.entities = dec.Bytes(int(a.count) * int(entitySize))
+		a.columns = make([][]byte, len(a.ids))
+		for j, id := range a.ids {
+			tp, _ := w.registry.ComponentType(id.id)
+			a.columns[j] = dec.Bytes(int(a.count) * int(tp.Size()))
+		}
+		if dec.err != nil {
+			return nil, dec.err
+		}
+		if !dec.Done() {
+			return nil, &SnapshotError{Section: "archetype", Reason: "unexpected trailing data"}
+		}
+		if err := checkSnapshotEntities(a.entities, "archetype", numEntities); err != nil {
+			return nil, err
+		}
+	}
+	return arches, nil
+}
+
+// readSnapshotColumn checks that the data of a column section has the expected size.
+func readSnapshotColumn(data []byte, section string, size int) ([]byte, error) {
+	dec := snapshotDecoder{data: data, section: section}
+	column := dec.Bytes(size)
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	if !dec.Done() {
+		return nil, &SnapshotError{Section: section, Reason: "unexpected trailing data"}
+	}
+	return column, nil
+}
+
+// checkSnapshotEntities checks that the raw entities of an archetype are in the range of the entity pool.
+func checkSnapshotEntities(entities []byte, section string, numEntities uint32) error {
+	var entity Entity
+	entityView := unsafe.Slice((*byte)(unsafe.Pointer(&entity)), entitySize)
+	for j := 0; j < len(entities); j += int(entitySize) {
+		copy(entityView, entities[j:])
+		if entity.id == 0 || entity.id >= eid(numEntities) {
+			return &SnapshotError{Section: section, Reason: "entity out of range"}
+		}
+	}
+	return nil
+}
+
+// snapshotArchetypeSection returns the name of the section holding the entities of the archetype with the given index.
+func snapshotArchetypeSection(index int) string {
+	return fmt.Sprintf("archetype %d", index)
+}
+
+// snapshotColumnSection returns the name of the section holding a component column of the archetype with the given index.
+func snapshotColumnSection(index int, tp reflect.Type) string {
+	return fmt.Sprintf("archetype %d/%s", index, tp.String())
+}
+
+// snapshotComponents collects the components of the given archetypes,
+// and checks that they can be written as raw memory.
+// Returns the index of each component in the collected components, by component ID.
+func (w *World) snapshotComponents(arches []*archetype) (map[uint8]uint32, []snapshotComponent, error) {
+	compIndex := map[uint8]uint32{}
+	comps := []snapshotComponent{}
+	for _, arch := range arches {
+		for _, id := range arch.node.Ids {
+			if _, ok := compIndex[id.id]; ok {
+				continue
+			}
+			tp, _ := w.registry.ComponentType(id.id)
+			if err := checkSnapshotType(tp); err != nil {
+				return nil, nil, err
+			}
+			compIndex[id.id] = uint32(len(comps))
+			comps = append(comps, snapshotComponent{
+				Name:      tp.String(),
+				Size:      uint32(tp.Size()),
+				Signature: layoutSignature(tp),
+			})
+		}
+	}
+	return compIndex, comps, nil
+}
+
+// appendSnapshotEntityPool appends the state of the entity pool to a buffer.
+func (w *World) appendSnapshotEntityPool(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(w.entityPool.next))
+	buf = binary.LittleEndian.AppendUint32(buf, w.entityPool.available)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(w.entityPool.entities)))
+	return append(buf, unsafe.Slice((*byte)(unsafe.Pointer(&w.entityPool.entities[0])), len(w.entityPool.entities)*int(entitySize))...)
+}
+
+// appendSnapshotComponents appends the descriptions of components to a buffer.
+func appendSnapshotComponents(buf []byte, comps []snapshotComponent) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(comps)))
+	for _, c := range comps {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(c.Name)))
+		buf = append(buf, c.Name...)
+		buf = binary.LittleEndian.AppendUint32(buf, c.Size)
+		buf = binary.LittleEndian.AppendUint64(buf, c.Signature)
+	}
+	return buf
+}
+
+// matchSnapshotComponents assigns world component IDs to snapshot components,
+// and checks their layout compatibility.
+func (w *World) matchSnapshotComponents(comps []snapshotComponent) error {
+	byName := map[string]ID{}
+	for _, iid := range w.registry.IDs {
+		tp, _ := w.registry.ComponentType(iid)
+		byName[tp.String()] = id(iid)
+	}
+	for i := range comps {
+		c := &comps[i]
+		compID, ok := byName[c.Name]
+		if !ok {
+			return fmt.Errorf("%w: component type %s is not registered", ErrSnapshotLayout, c.Name)
+		}
+		tp, _ := w.registry.ComponentType(compID.id)
+		if uint32(tp.Size()) != c.Size || layoutSignature(tp) != c.Signature {
+			return fmt.Errorf("%w: memory layout of component type %s has changed", ErrSnapshotLayout, c.Name)
+		}
+		c.id = compID
+	}
+	return nil
+}
+
+// snapshotHeader creates the header section of a snapshot.
+func snapshotHeader() []byte {
+	buf := []byte(snapshotMagic)
+	buf = binary.LittleEndian.AppendUint32(buf, snapshotVersion)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(unsafe.Sizeof(uintptr(0))))
+	probe := uint32(1)
+	buf = append(buf, *(*byte)(unsafe.Pointer(&probe)))
+	return append(buf, entityHeader...)
+}
+
+// checkSnapshotHeader checks the header section of a snapshot, and returns the snapshot format version.
+// Accepts all versions up to the current one.
+func checkSnapshotHeader(data []byte) (uint32, error) {
+	if len(data) < len(snapshotMagic) || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, &SnapshotError{Section: "header", Reason: "not an arche snapshot"}
+	}
+	dec := snapshotDecoder{data: data[len(snapshotMagic):], section: "header"}
+	version := dec.U32()
+	if dec.err != nil {
+		return 0, dec.err
+	}
+	if version == 0 || version > snapshotVersion {
+		return 0, fmt.Errorf("%w: unsupported snapshot version %d", ErrSnapshotLayout, version)
+	}
+	expected := snapshotHeader()[len(snapshotMagic)+4:]
+	if string(data[len(snapshotMagic)+4:]) != string(expected) {
+		return 0, fmt.Errorf("%w: snapshot was written on a platform with different word size, byte order or entity size", ErrSnapshotLayout)
+	}
+	return version, nil
+}
+
+// checkSnapshotType checks that a component type can be written as raw memory.
+func checkSnapshotType(tp reflect.Type) error {
+	if hasPointers(tp) {
+		return fmt.Errorf("%w: component type %s contains pointers", ErrSnapshotLayout, tp.String())
+	}
+	return nil
+}
+
+// hasPointers checks whether a type contains pointers.
+func hasPointers(tp reflect.Type) bool {
+	switch tp.Kind() {
+	case reflect.Struct:
+		for i := 0; i < tp.NumField(); i++ {
+			if hasPointers(tp.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Array:
+		return hasPointers(tp.Elem())
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Slice, reflect.String,
+		reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
+
+// layoutSignature creates a hash of the memory layout of a type.
+func layoutSignature(tp reflect.Type) uint64 {
+	h := fnv.New64a()
+	writeLayout(h, tp)
+	return h.Sum64()
+}
+
+// writeLayout writes a description of a type's memory layout to a writer.
+func writeLayout(w io.Writer, tp reflect.Type) {
+	fmt.Fprintf(w, "%d:%d:%d", tp.Kind(), tp.Size(), tp.Align())
+	switch tp.Kind() {
+	case reflect.Struct:
+		fmt.Fprint(w, "{")
+		for i := 0; i < tp.NumField(); i++ {
+			field := tp.Field(i)
+			fmt.Fprintf(w, "%s@%d=", field.Name, field.Offset)
+			writeLayout(w, field.Type)
+			fmt.Fprint(w, ";")
+		}
+		fmt.Fprint(w, "}")
+	case reflect.Array:
+		fmt.Fprintf(w, "[%d]", tp.Len())
+		writeLayout(w, tp.Elem())
+	}
+}
+
+// snapshotDecoder reads values from the data of a snapshot section.
+// After the first error, all reads return zero values.
+type snapshotDecoder struct {
+	data    []byte
+	section string
+	err     error
+}
+
+// U32 reads an uint32.
+func (d *snapshotDecoder) U32() uint32 {
+	b := d.Bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// U64 reads an uint64.
+func (d *snapshotDecoder) U64() uint64 {
+	b := d.Bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// Bytes reads the given number of bytes.
+func (d *snapshotDecoder) Bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.data) {
+		d.err = &SnapshotError{Section: d.section, Reason: "section data too short"}
+		return nil
+	}
+	b := d.data[:n:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// Done reports whether all data was read.
+func (d *snapshotDecoder) Done() bool {
+	return len(d.data) == 0
+}
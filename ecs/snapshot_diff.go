@@ -0,0 +1,444 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// diffMagic identifies binary snapshot diffs.
+const diffMagic = "ARCHE-DIFF"
+
+// diffVersion is the version of the binary diff format.
+const diffVersion uint32 = 1
+
+// ErrDiffBase is the base error for diffs that are applied to a world that is not in the diff's base state.
+// Check for it using [errors.Is].
+var ErrDiffBase = errors.New("world does not match diff base")
+
+// diffBaseEntity locates an entity in the archetype data of a base snapshot.
+type diffBaseEntity struct {
+	entity Entity
+	arch   *snapshotArchetypeData // Nil if the entity is not alive in the base snapshot.
+	row    uint32
+}
+
+// diffEntity is an added or changed entity in a binary diff.
+type diffEntity struct {
+	entity Entity
+	ids    []ID
+	target Entity
+	data   [][]byte // Component data, in the order of ids. Nil for unchanged components.
+}
+
+// Diff writes a binary diff between a base snapshot written by [World.Snapshot] and the current state of the world.
+//
+// The diff contains only entities that were added, removed or changed since the base snapshot.
+// For changed entities, only the data of changed components is written.
+// Additionally, the diff contains the state of the entity pool, which grows with the number of entity IDs ever used.
+// Resources are not part of diffs.
+//
+// Apply the diff with [World.ApplyDiff] to a world in the state of the base snapshot,
+// e.g. a world loaded from the base snapshot, or a world in the base state of a previous diff.
+// This allows for efficient autosaves and network state sync, as a sequence of diffs against
+// one full snapshot, or as a chain of diffs.
+//
+// The same restrictions as for [World.Snapshot] apply.
+// Returns an error wrapping [ErrSnapshotLayout] if any component type in use contains pointers,
+// or if the base snapshot contains unregistered or incompatible component types,
+// and an error wrapping [ErrSnapshotCorrupt] for corrupt or truncated base snapshots.
+//
+// Panics when called on a locked world.
+func (w *World) Diff(base io.Reader, out io.Writer) error {
+	w.checkLocked()
+
+	sr := newSnapshotReader(base)
+	schema, err := w.readSnapshotSchema(sr)
+	if err != nil {
+		return err
+	}
+	baseArches, err := w.readSnapshotArchetypes(sr, schema)
+	if err != nil {
+		return err
+	}
+	if schema.version >= 2 {
+		if _, err := sr.ReadSection("resources"); err != nil {
+			return err
+		}
+	}
+	if err := sr.Finish(); err != nil {
+		return err
+	}
+
+	baseEntities := make([]diffBaseEntity, schema.numEntities)
+	for i := range baseArches {
+		a := &baseArches[i]
+		var j uint32
+		for j = 0; j < a.count; j++ {
+			var entity Entity
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(&entity)), entitySize), a.entities[j*entitySize:])
+			baseEntities[entity.id] = diffBaseEntity{entity: entity, arch: a, row: j}
+		}
+	}
+
+	arches := w.snapshotArchetypes()
+	compIndex, comps, err := w.snapshotComponents(arches)
+	if err != nil {
+		return err
+	}
+
+	removed := []byte{}
+	numRemoved := 0
+	changed := []byte{}
+	numChanged := 0
+	for i := 1; i < len(baseEntities) || i < len(w.entityPool.entities); i++ {
+		var b *diffBaseEntity
+		if i < len(baseEntities) && baseEntities[i].arch != nil {
+			b = &baseEntities[i]
+		}
+		var index *entityIndex
+		var entity Entity
+		if i < len(w.entityPool.entities) && w.entities[i].arch != nil {
+			index = &w.entities[i]
+			entity = w.entityPool.entities[i]
+		}
+
+		if b != nil && (index == nil || b.entity != entity) {
+			removed = append(removed, unsafe.Slice((*byte)(unsafe.Pointer(&b.entity)), entitySize)...)
+			numRemoved++
+			b = nil
+		}
+		if index == nil {
+			continue
+		}
+
+		arch := index.arch
+		same := b != nil && b.arch.target == arch.RelationTarget && len(b.arch.ids) == len(arch.node.Ids)
+		start := len(changed)
+		changed = append(changed, unsafe.Slice((*byte)(unsafe.Pointer(&entity)), entitySize)...)
+		changed = binary.LittleEndian.AppendUint32(changed, uint32(len(arch.node.Ids)))
+		for _, id := range arch.node.Ids {
+			changed = binary.LittleEndian.AppendUint32(changed, compIndex[id.id])
+			lay := arch.getLayout(id)
+			data := unsafe.Slice((*byte)(lay.Get(index.index)), lay.itemSize)
+
+			var baseData []byte
+			if b != nil {
+				if col, ok := b.arch.column(id); ok {
+					baseData = col[b.row*lay.itemSize : (b.row+1)*lay.itemSize]
+				} else {
+					same = false
+				}
+			}
+			if lay.itemSize == 0 || (baseData != nil && bytes.Equal(data, baseData)) {
+				changed = append(changed, 0)
+				continue
+			}
+			same = false
+			changed = append(changed, 1)
+			changed = append(changed, data...)
+		}
+		changed = append(changed, unsafe.Slice((*byte)(unsafe.Pointer(&arch.RelationTarget)), entitySize)...)
+		if same {
+			changed = changed[:start]
+			continue
+		}
+		numChanged++
+	}
+
+	sw := newSnapshotWriter(out)
+	if err := sw.WriteSection("header", diffHeader()); err != nil {
+		return err
+	}
+	if err := sw.WriteSection("entities", w.appendSnapshotEntityPool(nil)); err != nil {
+		return err
+	}
+	if err := sw.WriteSection("components", appendSnapshotComponents(nil, comps)); err != nil {
+		return err
+	}
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(numRemoved))
+	if err := sw.WriteSection("removed", append(buf, removed...)); err != nil {
+		return err
+	}
+	buf = binary.LittleEndian.AppendUint32(nil, uint32(numChanged))
+	if err := sw.WriteSection("changed", append(buf, changed...)); err != nil {
+		return err
+	}
+	return sw.Finish()
+}
+
+// ApplyDiff applies a binary diff written by [World.Diff].
+//
+// The world must be in the state of the diff's base snapshot, regarding entities and their components.
+// After applying, the world has the same entities (in terms of ID, generation and alive state),
+// with the same components and relation targets as the world the diff was created from.
+// Entities that are moved to another archetype are appended to it,
+// so iteration order may differ from the original world.
+// Resources are not affected.
+//
+// Does not emit any events to the world's [Listener], and does not apply relation cascades
+// for removed entities, as their effects are already contained in the diff.
+// Applied components are marked as changed for change tracking.
+//
+// Returns an error wrapping [ErrSnapshotCorrupt] for corrupt or truncated data,
+// an error wrapping [ErrSnapshotLayout] for unregistered or incompatible component types,
+// and an error wrapping [ErrDiffBase] if the world's entities do not match the diff's base state.
+// All data is validated before the world is modified.
+//
+// Panics when called on a locked world.
+func (w *World) ApplyDiff(in io.Reader) error {
+	w.checkLocked()
+	w.epoch++
+	if len(w.journals) > 0 {
+		w.recordState()
+	}
+
+	sr := newSnapshotReader(in)
+	data, err := sr.ReadSection("header")
+	if err != nil {
+		return err
+	}
+	if err := checkDiffHeader(data); err != nil {
+		return err
+	}
+
+	data, err = sr.ReadSection("entities")
+	if err != nil {
+		return err
+	}
+	dec := snapshotDecoder{data: data, section: "entities"}
+	next, available, numEntities := dec.U32(), dec.U32(), dec.U32()
+	entityBytes := dec.Bytes(int(numEntities) * int(entitySize))
+	if dec.err != nil {
+		return dec.err
+	}
+	if numEntities == 0 {
+		return &SnapshotError{Section: "entities", Reason: "missing entity pool"}
+	}
+	if int(numEntities) < len(w.entityPool.entities) {
+		return fmt.Errorf("%w: diff has fewer entity IDs than the world", ErrDiffBase)
+	}
+	pool := make([]Entity, numEntities, capacity(int(numEntities), w.config.CapacityIncrement))
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&pool[0])), len(entityBytes)), entityBytes)
+
+	data, err = sr.ReadSection("components")
+	if err != nil {
+		return err
+	}
+	dec = snapshotDecoder{data: data, section: "components"}
+	comps := decodeSnapshotComponents(&dec)
+	if dec.err != nil {
+		return dec.err
+	}
+	if err := w.matchSnapshotComponents(comps); err != nil {
+		return err
+	}
+
+	data, err = sr.ReadSection("removed")
+	if err != nil {
+		return err
+	}
+	dec = snapshotDecoder{data: data, section: "removed"}
+	removed := make([]Entity, dec.U32())
+	isRemoved := map[eid]bool{}
+	for i := range removed {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&removed[i])), entitySize), dec.Bytes(int(entitySize)))
+		if dec.err != nil {
+			return dec.err
+		}
+		if int(removed[i].id) >= len(w.entityPool.entities) || !w.entityPool.Alive(removed[i]) || isRemoved[removed[i].id] {
+			return fmt.Errorf("%w: removed entity %v is not alive", ErrDiffBase, removed[i])
+		}
+		isRemoved[removed[i].id] = true
+	}
+	if !dec.Done() {
+		return &SnapshotError{Section: "removed", Reason: "unexpected trailing data"}
+	}
+
+	data, err = sr.ReadSection("changed")
+	if err != nil {
+		return err
+	}
+	dec = snapshotDecoder{data: data, section: "changed"}
+	changed := make([]diffEntity, dec.U32())
+	for i := range changed {
+		if err := w.decodeDiffEntity(&dec, &changed[i], comps, pool, isRemoved); err != nil {
+			return err
+		}
+	}
+	if !dec.Done() {
+		return &SnapshotError{Section: "changed", Reason: "unexpected trailing data"}
+	}
+
+	if err := sr.Finish(); err != nil {
+		return err
+	}
+
+	listener := w.listener
+	w.listener = nil
+	for _, entity := range removed {
+		w.removeEntity(entity)
+	}
+	w.listener = listener
+	w.cascadeTargets = w.cascadeTargets[:0]
+
+	w.entityPool.entities = pool
+	w.entityPool.next = eid(next)
+	w.entityPool.available = available
+	w.entityPool.DropReserved()
+	if int(numEntities) > cap(w.entities) {
+		old := w.entities
+		w.entities = make([]entityIndex, numEntities, cap(pool))
+		copy(w.entities, old)
+	} else {
+		oldLen := len(w.entities)
+		w.entities = w.entities[:numEntities]
+		clear(w.entities[oldLen:])
+	}
+	w.targetEntities.ExtendTo(cap(w.entities))
+
+	root := w.archetypes.Get(0)
+	for i := range changed {
+		w.applyDiffEntity(root, &changed[i])
+	}
+	for i := range changed {
+		if target := changed[i].target; !target.IsZero() {
+			w.targetEntities.Set(target.id, true)
+		}
+	}
+
+	return nil
+}
+
+// decodeDiffEntity decodes and validates an added or changed entity of a binary diff.
+func (w *World) decodeDiffEntity(dec *snapshotDecoder, e *diffEntity, comps []snapshotComponent, pool []Entity, isRemoved map[eid]bool) error {
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&e.entity)), entitySize), dec.Bytes(int(entitySize)))
+	e.ids = make([]ID, dec.U32())
+	e.data = make([][]byte, len(e.ids))
+	for j := range e.ids {
+		idx := dec.U32()
+		if dec.err == nil && idx >= uint32(len(comps)) {
+			return &SnapshotError{Section: dec.section, Reason: fmt.Sprintf("component index %d out of range", idx)}
+		}
+		hasData := dec.Bytes(1)
+		if dec.err != nil {
+			return dec.err
+		}
+		e.ids[j] = comps[idx].id
+		if hasData[0] != 0 {
+			e.data[j] = dec.Bytes(int(comps[idx].Size))
+		}
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&e.target)), entitySize), dec.Bytes(int(entitySize)))
+	if dec.err != nil {
+		return dec.err
+	}
+
+	if e.entity.id == 0 || int(e.entity.id) >= len(pool) || pool[e.entity.id] != e.entity {
+		return &SnapshotError{Section: dec.section, Reason: "entity out of range"}
+	}
+	if int(e.target.id) >= len(pool) {
+		return &SnapshotError{Section: dec.section, Reason: "relation target out of range"}
+	}
+	alive := false
+	if int(e.entity.id) < len(w.entityPool.entities) && w.entities[e.entity.id].arch != nil && !isRemoved[e.entity.id] {
+		if w.entityPool.entities[e.entity.id] != e.entity {
+			return fmt.Errorf("%w: entity %v is not alive", ErrDiffBase, e.entity)
+		}
+		alive = true
+	}
+	for j, id := range e.ids {
+		if e.data[j] != nil {
+			continue
+		}
+		if tp, _ := w.registry.ComponentType(id.id); tp.Size() == 0 {
+			continue
+		}
+		if !alive || !w.entities[e.entity.id].arch.HasComponent(id) {
+			return fmt.Errorf("%w: entity %v has no component to keep", ErrDiffBase, e.entity)
+		}
+	}
+	return nil
+}
+
+// applyDiffEntity adds or changes an entity from a binary diff.
+func (w *World) applyDiffEntity(root *archetype, e *diffEntity) {
+	arch := w.findOrCreateArchetype(root, e.ids, nil, e.target)
+	index := &w.entities[e.entity.id]
+
+	if index.arch == nil {
+		newIndex := arch.Alloc(e.entity)
+		*index = entityIndex{arch: arch, index: newIndex}
+		w.targetEntities.Set(e.entity.id, false)
+		if w.lifetimes != nil {
+			w.lifetimes.Create(e.entity.id, w.tick)
+		}
+		if w.changes != nil {
+			w.changes.Create(e.entity.id, &arch.Mask)
+		}
+	} else if oldArch := index.arch; oldArch != arch {
+		newIndex := arch.Alloc(e.entity)
+		for _, id := range oldArch.node.dataIds {
+			if arch.HasComponent(id) {
+				arch.SetPointer(newIndex, id, oldArch.Get(index.index, id))
+			}
+		}
+		w.removeFromArchetype(oldArch, index.index)
+		*index = entityIndex{arch: arch, index: newIndex}
+		w.moveDisabled(e.entity.id, oldArch, arch)
+		if w.changes != nil {
+			w.changes.Move(e.entity.id, oldArch, arch)
+		}
+		w.cleanupArchetype(oldArch)
+	}
+
+	for j, id := range e.ids {
+		if e.data[j] == nil {
+			continue
+		}
+		lay := arch.getLayout(id)
+		copy(unsafe.Slice((*byte)(lay.Get(index.index)), lay.itemSize), e.data[j])
+		if w.changes != nil {
+			w.changes.Change(e.entity.id, id)
+		}
+	}
+}
+
+// column returns the raw data of a component in the archetype data of a binary snapshot.
+func (a *snapshotArchetypeData) column(id ID) ([]byte, bool) {
+	for j, cid := range a.ids {
+		if cid == id {
+			return a.columns[j], true
+		}
+	}
+	return nil, false
+}
+
+// diffHeader creates the header section of a binary diff.
+func diffHeader() []byte {
+	buf := []byte(diffMagic)
+	buf = binary.LittleEndian.AppendUint32(buf, diffVersion)
+	return append(buf, snapshotHeader()[len(snapshotMagic)+4:]...)
+}
+
+// checkDiffHeader checks the header section of a binary diff.
+func checkDiffHeader(data []byte) error {
+	if len(data) < len(diffMagic) || string(data[:len(diffMagic)]) != diffMagic {
+		return &SnapshotError{Section: "header", Reason: "not an arche diff"}
+	}
+	dec := snapshotDecoder{data: data[len(diffMagic):], section: "header"}
+	version := dec.U32()
+	if dec.err != nil {
+		return dec.err
+	}
+	if version != diffVersion {
+		return fmt.Errorf("%w: unsupported diff version %d", ErrSnapshotLayout, version)
+	}
+	if string(dec.data) != string(diffHeader()[len(diffMagic)+4:]) {
+		return fmt.Errorf("%w: diff was written on a platform with different word size, byte order or entity size", ErrSnapshotLayout)
+	}
+	return nil
+}
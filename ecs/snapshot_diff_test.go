@@ -0,0 +1,155 @@
+package ecs
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldDiff(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	labelID := ComponentID[label](&w)
+
+	parent1 := w.NewEntity(labelID)
+	parent2 := w.NewEntity(labelID)
+	entities := []Entity{}
+	query := NewBuilder(&w, posID, velID).NewBatchQ(20)
+	for query.Next() {
+		entities = append(entities, query.Entity())
+		pos := (*Position)(query.Get(posID))
+		pos.X = int(query.Entity().id)
+	}
+	children := []Entity{}
+	query = NewBuilder(&w, posID, relID).WithRelation(relID).NewBatchQ(5, parent1)
+	for query.Next() {
+		children = append(children, query.Entity())
+	}
+
+	base := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&base))
+
+	w2 := NewWorld()
+	_ = ComponentID[Position](&w2)
+	_ = ComponentID[Velocity](&w2)
+	_ = ComponentID[testRelationA](&w2)
+	_ = ComponentID[label](&w2)
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(base.Bytes())))
+
+	diff := bytes.Buffer{}
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+	assert.Nil(t, w2.ApplyDiff(bytes.NewReader(diff.Bytes())))
+	assertSameEntities(t, &w, &w2)
+
+	w.RemoveEntity(entities[3])
+	w.RemoveEntity(entities[7])
+	recycled := w.NewEntity(velID)
+	(*Position)(w.Get(entities[5], posID)).Y = 42
+	w.Remove(entities[6], velID)
+	w.Add(entities[8], labelID)
+	w.Relations().Set(children[2], relID, parent2)
+	w.RemoveEntity(parent1)
+	w.NewEntity(posID)
+
+	diff.Reset()
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+	assert.Less(t, diff.Len(), base.Len())
+	assert.Nil(t, w2.ApplyDiff(bytes.NewReader(diff.Bytes())))
+
+	assertSameEntities(t, &w, &w2)
+	assert.Equal(t, Position{X: int(entities[5].id), Y: 42}, *(*Position)(w2.Get(entities[5], posID)))
+	assert.False(t, w2.Has(entities[6], velID))
+	assert.True(t, w2.Has(entities[8], labelID))
+	assert.True(t, w2.Alive(recycled))
+	assert.False(t, w2.Alive(parent1))
+	assert.Equal(t, parent2, w2.Relations().Get(children[2], relID))
+	assert.Equal(t, parent1, w2.Relations().Get(children[0], relID))
+
+	newEntity := w2.NewEntity(posID)
+	assert.Equal(t, w.NewEntity(posID), newEntity)
+
+	// Chained diffs.
+	base.Reset()
+	assert.Nil(t, w.Snapshot(&base))
+	w.Remove(entities[9], posID)
+	diff.Reset()
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+	assert.Nil(t, w2.ApplyDiff(bytes.NewReader(diff.Bytes())))
+	assertSameEntities(t, &w, &w2)
+}
+
+func TestWorldDiffUnchanged(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	NewBuilder(&w, posID).NewBatch(100)
+
+	base := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&base))
+
+	diff := bytes.Buffer{}
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+	empty := diff.Len()
+
+	w.Set(w.entityPool.entities[50], posID, &Position{X: 1})
+	diff.Reset()
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+	assert.Greater(t, diff.Len(), empty)
+	assert.Less(t, diff.Len(), empty+64)
+}
+
+func TestWorldApplyDiffFail(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+
+	base := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&base))
+
+	w.RemoveEntity(e1)
+	(*Position)(w.Get(e2, posID)).X = 5
+	diff := bytes.Buffer{}
+	assert.Nil(t, w.Diff(bytes.NewReader(base.Bytes()), &diff))
+
+	w2 := NewWorld()
+	_ = ComponentID[Position](&w2)
+	err := w2.ApplyDiff(bytes.NewReader(diff.Bytes()))
+	assert.True(t, errors.Is(err, ErrDiffBase))
+	assert.Equal(t, 1, len(w2.entityPool.entities))
+
+	w3 := NewWorld()
+	err = w3.ApplyDiff(bytes.NewReader(diff.Bytes()))
+	assert.True(t, errors.Is(err, ErrSnapshotLayout))
+
+	err = w2.ApplyDiff(bytes.NewReader(base.Bytes()))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+
+	data := bytes.Clone(diff.Bytes())
+	data[len(data)/2]++
+	err = w2.ApplyDiff(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+
+	err = w.Diff(bytes.NewReader(diff.Bytes()), &bytes.Buffer{})
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+
+	w4 := NewWorld()
+	_ = ComponentID[Position](&w4)
+	ptrID := ComponentID[snapshotPointer](&w4)
+	w4.NewEntity(ptrID)
+	err = w4.Diff(bytes.NewReader(base.Bytes()), &bytes.Buffer{})
+	assert.True(t, errors.Is(err, ErrSnapshotLayout))
+}
+
+// assertSameEntities checks that two worlds have the same entities, regardless of iteration order.
+func assertSameEntities(t *testing.T, w1, w2 *World) {
+	d1, d2 := w1.DumpEntities(), w2.DumpEntities()
+	slices.Sort(d1.Alive)
+	slices.Sort(d2.Alive)
+	assert.Equal(t, d1, d2)
+	assert.Equal(t, w1.Hash(), w2.Hash())
+}
@@ -0,0 +1,200 @@
+package ecs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+)
+
+// snapshotEndMarker marks the end of the sections in a binary snapshot stream.
+const snapshotEndMarker uint32 = 0xFFFFFFFF
+
+// ErrSnapshotCorrupt is the base error for corrupt or truncated snapshot data.
+// Check for it using [errors.Is].
+var ErrSnapshotCorrupt = errors.New("corrupt snapshot")
+
+// SnapshotError describes a corrupt or truncated section of a binary snapshot.
+type SnapshotError struct {
+	Section string // Name of the affected section, e.g. "archetype 3/pkg.Position" for a component column of the fourth archetype.
+	Reason  string // Description of the problem.
+}
+
+// Error returns the error message.
+func (e *SnapshotError) Error() string {
+	if e.Section == "" {
+		return fmt.Sprintf("%s: %s", ErrSnapshotCorrupt.Error(), e.Reason)
+	}
+	return fmt.Sprintf("%s: section '%s': %s", ErrSnapshotCorrupt.Error(), e.Section, e.Reason)
+}
+
+// Is reports whether the error matches the target, for use with [errors.Is].
+func (e *SnapshotError) Is(target error) bool {
+	return target == ErrSnapshotCorrupt
+}
+
+// snapshotWriter writes named sections with a CRC32 checksum each,
+// followed by a hash over the entire stream.
+//
+// Section layout: name length (uint32), name, data length (uint64), data, CRC32 of data (uint32).
+// Stream end: end marker (uint32), FNV-1a 64 hash of all preceding bytes (uint64).
+type snapshotWriter struct {
+	w    io.Writer
+	hash hash.Hash64
+	buf  [8]byte
+}
+
+// newSnapshotWriter creates a new snapshotWriter.
+func newSnapshotWriter(w io.Writer) *snapshotWriter {
+	h := fnv.New64a()
+	return &snapshotWriter{
+		w:    io.MultiWriter(w, h),
+		hash: h,
+	}
+}
+
+// WriteSection writes a named section.
+func (s *snapshotWriter) WriteSection(name string, data []byte) error {
+	if err := s.writeU32(uint32(len(name))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(s.w, name); err != nil {
+		return err
+	}
+	if err := s.writeU64(uint64(len(data))); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	return s.writeU32(crc32.ChecksumIEEE(data))
+}
+
+// Finish writes the end marker and the final hash.
+func (s *snapshotWriter) Finish() error {
+	if err := s.writeU32(snapshotEndMarker); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(s.buf[:], s.hash.Sum64())
+	// Written to the hash as well, but that does not matter anymore.
+	_, err := s.w.Write(s.buf[:8])
+	return err
+}
+
+func (s *snapshotWriter) writeU32(v uint32) error {
+	binary.LittleEndian.PutUint32(s.buf[:4], v)
+	_, err := s.w.Write(s.buf[:4])
+	return err
+}
+
+func (s *snapshotWriter) writeU64(v uint64) error {
+	binary.LittleEndian.PutUint64(s.buf[:], v)
+	_, err := s.w.Write(s.buf[:8])
+	return err
+}
+
+// snapshotReader reads and verifies sections written by a [snapshotWriter].
+type snapshotReader struct {
+	r    io.Reader
+	hash hash.Hash64
+	buf  [8]byte
+}
+
+// newSnapshotReader creates a new snapshotReader.
+func newSnapshotReader(r io.Reader) *snapshotReader {
+	h := fnv.New64a()
+	return &snapshotReader{
+		r:    io.TeeReader(r, h),
+		hash: h,
+	}
+}
+
+// ReadSection reads the next section and verifies its name and checksum.
+func (s *snapshotReader) ReadSection(name string) ([]byte, error) {
+	nameLen, err := s.readU32(name, "name length")
+	if err != nil {
+		return nil, err
+	}
+	if nameLen == snapshotEndMarker {
+		return nil, &SnapshotError{Section: name, Reason: "unexpected end of sections"}
+	}
+	if nameLen != uint32(len(name)) {
+		return nil, &SnapshotError{Section: name, Reason: fmt.Sprintf("unexpected section name length %d", nameLen)}
+	}
+	nameBytes := make([]byte, nameLen)
+	if err := s.read(nameBytes, name, "name"); err != nil {
+		return nil, err
+	}
+	if string(nameBytes) != name {
+		return nil, &SnapshotError{Section: name, Reason: fmt.Sprintf("unexpected section '%s'", string(nameBytes))}
+	}
+	dataLen, err := s.readU64(name, "data length")
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, min(dataLen, 1<<20))
+	buf := make([]byte, min(dataLen, 1<<16))
+	for remaining := dataLen; remaining > 0; {
+		chunk := buf[:min(remaining, uint64(len(buf)))]
+		if err := s.read(chunk, name, "data"); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+		remaining -= uint64(len(chunk))
+	}
+	checksum, err := s.readU32(name, "checksum")
+	if err != nil {
+		return nil, err
+	}
+	if actual := crc32.ChecksumIEEE(data); actual != checksum {
+		return nil, &SnapshotError{Section: name, Reason: fmt.Sprintf("checksum mismatch (expected %08x, got %08x)", checksum, actual)}
+	}
+	return data, nil
+}
+
+// Finish reads the end marker and verifies the final hash.
+func (s *snapshotReader) Finish() error {
+	marker, err := s.readU32("", "end marker")
+	if err != nil {
+		return err
+	}
+	if marker != snapshotEndMarker {
+		return &SnapshotError{Reason: "unexpected data after last section"}
+	}
+	expected := s.hash.Sum64()
+	actual, err := s.readU64("", "final hash")
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return &SnapshotError{Reason: fmt.Sprintf("final hash mismatch (expected %016x, got %016x)", actual, expected)}
+	}
+	return nil
+}
+
+func (s *snapshotReader) read(buf []byte, section string, what string) error {
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return &SnapshotError{Section: section, Reason: fmt.Sprintf("truncated while reading %s", what)}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *snapshotReader) readU32(section string, what string) (uint32, error) {
+	if err := s.read(s.buf[:4], section, what); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(s.buf[:4]), nil
+}
+
+func (s *snapshotReader) readU64(section string, what string) (uint64, error) {
+	if err := s.read(s.buf[:8], section, what); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(s.buf[:8]), nil
+}
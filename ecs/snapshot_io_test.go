@@ -0,0 +1,92 @@
+package ecs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestSnapshot(t *testing.T) []byte {
+	buf := bytes.Buffer{}
+	w := newSnapshotWriter(&buf)
+	assert.Nil(t, w.WriteSection("header", []byte{1, 2, 3}))
+	assert.Nil(t, w.WriteSection("archetype 1/Position", bytes.Repeat([]byte{7}, 100_000)))
+	assert.Nil(t, w.WriteSection("empty", nil))
+	assert.Nil(t, w.Finish())
+	return buf.Bytes()
+}
+
+func TestSnapshotIO(t *testing.T) {
+	data := writeTestSnapshot(t)
+
+	r := newSnapshotReader(bytes.NewReader(data))
+	sec, err := r.ReadSection("header")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, sec)
+	sec, err = r.ReadSection("archetype 1/Position")
+	assert.Nil(t, err)
+	assert.Equal(t, 100_000, len(sec))
+	sec, err = r.ReadSection("empty")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(sec))
+	assert.Nil(t, r.Finish())
+}
+
+func TestSnapshotIOCorrupt(t *testing.T) {
+	data := writeTestSnapshot(t)
+
+	// Flip a bit in the component data.
+	corrupt := append([]byte{}, data...)
+	corrupt[50_000] ^= 1
+	r := newSnapshotReader(bytes.NewReader(corrupt))
+	_, err := r.ReadSection("header")
+	assert.Nil(t, err)
+	_, err = r.ReadSection("archetype 1/Position")
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+	assert.Contains(t, err.Error(), "corrupt snapshot: section 'archetype 1/Position': checksum mismatch")
+
+	// Truncate inside a section.
+	r = newSnapshotReader(bytes.NewReader(data[:1000]))
+	_, err = r.ReadSection("header")
+	assert.Nil(t, err)
+	_, err = r.ReadSection("archetype 1/Position")
+	assert.EqualError(t, err, "corrupt snapshot: section 'archetype 1/Position': truncated while reading data")
+
+	// Truncate the final hash.
+	r = newSnapshotReader(bytes.NewReader(data[:len(data)-2]))
+	_, _ = r.ReadSection("header")
+	_, _ = r.ReadSection("archetype 1/Position")
+	_, _ = r.ReadSection("empty")
+	assert.EqualError(t, r.Finish(), "corrupt snapshot: truncated while reading final hash")
+
+	// Corrupt the final hash.
+	corrupt = append([]byte{}, data...)
+	corrupt[len(corrupt)-1] ^= 1
+	r = newSnapshotReader(bytes.NewReader(corrupt))
+	_, _ = r.ReadSection("header")
+	_, _ = r.ReadSection("archetype 1/Position")
+	_, _ = r.ReadSection("empty")
+	assert.Contains(t, r.Finish().Error(), "corrupt snapshot: final hash mismatch")
+
+	// Unexpected section.
+	r = newSnapshotReader(bytes.NewReader(data))
+	_, err = r.ReadSection("other!")
+	assert.EqualError(t, err, "corrupt snapshot: section 'other!': unexpected section 'header'")
+	r = newSnapshotReader(bytes.NewReader(data))
+	_, err = r.ReadSection("other")
+	assert.EqualError(t, err, "corrupt snapshot: section 'other': unexpected section name length 6")
+
+	// Missing and additional sections.
+	r = newSnapshotReader(bytes.NewReader(data))
+	_, _ = r.ReadSection("header")
+	_, _ = r.ReadSection("archetype 1/Position")
+	_, _ = r.ReadSection("empty")
+	_, err = r.ReadSection("more")
+	assert.EqualError(t, err, "corrupt snapshot: section 'more': unexpected end of sections")
+
+	r = newSnapshotReader(bytes.NewReader(data))
+	_, _ = r.ReadSection("header")
+	assert.EqualError(t, r.Finish(), "corrupt snapshot: unexpected data after last section")
+}
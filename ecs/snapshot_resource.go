@@ -0,0 +1,108 @@
+package ecs
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SerializableResource is implemented by resources that are stored in binary snapshots.
+// See [World.Snapshot] and [World.LoadSnapshot].
+//
+// Resources are identified by their type name, as given by [reflect.Type.String].
+// Resources that don't implement the interface are not part of snapshots.
+//
+// The version allows resources to read data written by earlier versions of the type.
+type SerializableResource interface {
+	// SnapshotVersion returns the current version of the resource's serialization format.
+	SnapshotVersion() uint32
+	// MarshalSnapshot serializes the resource.
+	MarshalSnapshot() ([]byte, error)
+	// UnmarshalSnapshot restores the resource from data written by MarshalSnapshot,
+	// with the given version of the serialization format.
+	UnmarshalSnapshot(version uint32, data []byte) error
+}
+
+// snapshotResource is a serialized resource in a binary snapshot.
+type snapshotResource struct {
+	Name    string
+	Version uint32
+	Data    []byte
+}
+
+// snapshotResources serializes all resources that implement [SerializableResource].
+func (w *World) snapshotResources() ([]byte, error) {
+	res := []snapshotResource{}
+	for i, r := range w.resources.resources {
+		if r == nil {
+			continue
+		}
+		ser, ok := r.(SerializableResource)
+		if !ok {
+			continue
+		}
+		name := w.resources.registry.Types[i].String()
+		data, err := ser.MarshalSnapshot()
+		if err != nil {
+			return nil, fmt.Errorf("serializing resource %s: %w", name, err)
+		}
+		res = append(res, snapshotResource{Name: name, Version: ser.SnapshotVersion(), Data: data})
+	}
+
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(len(res)))
+	for _, r := range res {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(r.Name)))
+		buf = append(buf, r.Name...)
+		buf = binary.LittleEndian.AppendUint32(buf, r.Version)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(r.Data)))
+		buf = append(buf, r.Data...)
+	}
+	return buf, nil
+}
+
+// decodeSnapshotResources decodes the resources section of a binary snapshot,
+// and checks that all resources are present in the world.
+func (w *World) decodeSnapshotResources(data []byte) ([]snapshotResource, []SerializableResource, error) {
+	dec := snapshotDecoder{data: data, section: "resources"}
+	res := make([]snapshotResource, dec.U32())
+	for i := range res {
+		r := &res[i]
+		r.Name = string(dec.Bytes(int(dec.U32())))
+		r.Version = dec.U32()
+		r.Data = dec.Bytes(int(dec.U32()))
+		if dec.err != nil {
+			return nil, nil, dec.err
+		}
+	}
+	if !dec.Done() {
+		return nil, nil, &SnapshotError{Section: "resources", Reason: "unexpected trailing data"}
+	}
+
+	byName := map[string]SerializableResource{}
+	for i, r := range w.resources.resources {
+		if ser, ok := r.(SerializableResource); ok {
+			byName[w.resources.registry.Types[i].String()] = ser
+		}
+	}
+	targets := make([]SerializableResource, len(res))
+	for i, r := range res {
+		ser, ok := byName[r.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: resource %s is not present in the world or not serializable", ErrSnapshotLayout, r.Name)
+		}
+		if r.Version > ser.SnapshotVersion() {
+			return nil, nil, fmt.Errorf("%w: resource %s has version %d, but only up to %d is supported", ErrSnapshotLayout, r.Name, r.Version, ser.SnapshotVersion())
+		}
+		targets[i] = ser
+	}
+	return res, targets, nil
+}
+
+// loadSnapshotResources restores decoded resources.
+func loadSnapshotResources(res []snapshotResource, targets []SerializableResource) error {
+	for i, r := range res {
+		if err := targets[i].UnmarshalSnapshot(r.Version, r.Data); err != nil {
+			return fmt.Errorf("deserializing resource %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package ecs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// snapshotArchetype describes an archetype in a binary snapshot.
+type snapshotArchetype struct {
+	ids    []ID   // Component IDs in the world.
+	target Entity // Relation target.
+	count  uint32 // Number of entities.
+}
+
+// snapshotSchema holds the leading sections of a binary snapshot, up to the archetype data.
+type snapshotSchema struct {
+	version     uint32
+	next        uint32
+	available   uint32
+	numEntities uint32
+	entityBytes []byte
+	comps       []snapshotComponent
+	numArches   uint32
+	arches      []snapshotArchetype // Archetype descriptions. Nil for format versions before 3.
+}
+
+// PrewarmSnapshot reads only the schema of a binary snapshot written by [World.Snapshot],
+// and creates all archetypes contained in it, with the capacity required for their entities.
+//
+// This avoids re-allocation of archetype storage during a subsequent [World.LoadSnapshot],
+// and allows to prepare other data structures, like cached filters, before loading.
+// Only the leading sections of the snapshot are read.
+// For snapshots written before the schema was added to the format, all archetype sections are read.
+//
+// Use this only on an empty world, directly before [World.LoadSnapshot]! Can be used after [World.Reset].
+// All component types contained in the snapshot must be registered, like for [World.LoadSnapshot].
+//
+// Returns an error wrapping [ErrSnapshotCorrupt] for corrupt or truncated data,
+// and an error wrapping [ErrSnapshotLayout] for unregistered or incompatible component types.
+// Does not modify the world in case of errors.
+//
+// Panics when called on a locked world or on a world that is not fresh or reset.
+func (w *World) PrewarmSnapshot(in io.Reader) error {
+	w.checkLocked()
+	if len(w.entityPool.entities) > 1 || w.entityPool.available > 0 {
+		panic("can set entity data only on a fresh or reset world")
+	}
+
+	sr := newSnapshotReader(in)
+	schema, err := w.readSnapshotSchema(sr)
+	if err != nil {
+		return err
+	}
+	arches := schema.arches
+	if arches == nil {
+		arches = make([]snapshotArchetype, schema.numArches)
+		for i := range arches {
+			data, err := sr.ReadSection("archetype")
+			if err != nil {
+				return err
+			}
+			dec := snapshotDecoder{data: data, section: "archetype"}
+			if arches[i], err = decodeSnapshotArchetype(&dec, schema.comps, schema.numEntities); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.prewarmArchetypes(arches)
+	return nil
+}
+
+// prewarmArchetypes creates the given archetypes and reserves capacity for their entities.
+func (w *World) prewarmArchetypes(arches []snapshotArchetype) {
+	root := w.archetypes.Get(0)
+	for i := range arches {
+		a := &arches[i]
+		arch := w.findOrCreateArchetype(root, a.ids, nil, a.target)
+		required := arch.len + a.count
+		if required > arch.cap {
+			arch.resize(capacityU32(required, arch.node.capacityIncrement))
+		}
+	}
+}
+
+// readSnapshotSchema reads the header, entities, components and schema sections of a binary snapshot.
+func (w *World) readSnapshotSchema(sr *snapshotReader) (*snapshotSchema, error) {
+	data, err := sr.ReadSection("header")
+	if err != nil {
+		return nil, err
+	}
+	schema := snapshotSchema{}
+	schema.version, err = checkSnapshotHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = sr.ReadSection("entities")
+	if err != nil {
+		return nil, err
+	}
+	dec := snapshotDecoder{data: data, section: "entities"}
+	schema.next, schema.available, schema.numEntities = dec.U32(), dec.U32(), dec.U32()
+	schema.entityBytes = dec.Bytes(int(schema.numEntities) * int(entitySize))
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	if schema.numEntities == 0 {
+		return nil, &SnapshotError{Section: "entities", Reason: "missing entity pool"}
+	}
+
+	data, err = sr.ReadSection("components")
+	if err != nil {
+		return nil, err
+	}
+	dec = snapshotDecoder{data: data, section: "components"}
+	schema.comps = decodeSnapshotComponents(&dec)
+	schema.numArches = dec.U32()
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	if err := w.matchSnapshotComponents(schema.comps); err != nil {
+		return nil, err
+	}
+
+	if schema.version < 3 {
+		return &schema, nil
+	}
+	data, err = sr.ReadSection("schema")
+	if err != nil {
+		return nil, err
+	}
+	dec = snapshotDecoder{data: data, section: "schema"}
+	schema.arches = make([]snapshotArchetype, schema.numArches)
+	for i := range schema.arches {
+		if schema.arches[i], err = decodeSnapshotArchetype(&dec, schema.comps, schema.numEntities); err != nil {
+			return nil, err
+		}
+	}
+	if !dec.Done() {
+		return nil, &SnapshotError{Section: "schema", Reason: "unexpected trailing data"}
+	}
+	return &schema, nil
+}
+
+// decodeSnapshotComponents decodes the descriptions of components.
+func decodeSnapshotComponents(dec *snapshotDecoder) []snapshotComponent {
+	comps := make([]snapshotComponent, dec.U32())
+	for i := range comps {
+		c := &comps[i]
+		c.Name = string(dec.Bytes(int(dec.U32())))
+		c.Size = dec.U32()
+		c.Signature = dec.U64()
+	}
+	return comps
+}
+
+// appendSnapshotArchetype appends the description of an archetype to a buffer.
+func appendSnapshotArchetype(buf []byte, arch *archetype, compIndex map[uint8]uint32) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(arch.node.Ids)))
+	for _, id := range arch.node.Ids {
+		buf = binary.LittleEndian.AppendUint32(buf, compIndex[id.id])
+	}
+	buf = append(buf, unsafe.Slice((*byte)(unsafe.Pointer(&arch.RelationTarget)), entitySize)...)
+	return binary.LittleEndian.AppendUint32(buf, arch.len)
+}
+
+// decodeSnapshotArchetype decodes the description of an archetype.
+func decodeSnapshotArchetype(dec *snapshotDecoder, comps []snapshotComponent, numEntities uint32) (snapshotArchetype, error) {
+	a := snapshotArchetype{}
+	a.ids = make([]ID, dec.U32())
+	for j := range a.ids {
+		idx := dec.U32()
+		if dec.err == nil && idx >= uint32(len(comps)) {
+			return a, &SnapshotError{Section: dec.section, Reason: fmt.Sprintf("component index %d out of range", idx)}
+		}
+		if dec.err == nil {
+			a.ids[j] = comps[idx].id
+		}
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&a.target)), entitySize), dec.Bytes(int(entitySize)))
+	a.count = dec.U32()
+	if dec.err != nil {
+		return a, dec.err
+	}
+	if a.target.id >= eid(numEntities) {
+		return a, &SnapshotError{Section: dec.section, Reason: "relation target out of range"}
+	}
+	return a, nil
+}
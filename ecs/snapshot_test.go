@@ -0,0 +1,304 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapshotPointer struct {
+	Name string
+}
+
+func TestWorldSnapshot(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	labelID := ComponentID[label](&w)
+
+	parent1 := w.NewEntity(labelID)
+	parent2 := w.NewEntity(labelID)
+	toRemove := []Entity{}
+	query := NewBuilder(&w, posID, velID).NewBatchQ(200)
+	for query.Next() {
+		if query.Entity().id%3 == 0 {
+			toRemove = append(toRemove, query.Entity())
+		}
+	}
+	for _, e := range toRemove {
+		w.RemoveEntity(e)
+	}
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(5, parent1)
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(7, parent2)
+	w.NewEntity()
+
+	query = w.Query(All(posID))
+	cnt := 0
+	for query.Next() {
+		pos := (*Position)(query.Get(posID))
+		pos.X, pos.Y = cnt, -cnt
+		cnt++
+	}
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&buf))
+
+	w2 := NewWorld()
+	posID2 := ComponentID[Position](&w2)
+	_ = ComponentID[Velocity](&w2)
+	relID2 := ComponentID[testRelationA](&w2)
+	_ = ComponentID[label](&w2)
+
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(buf.Bytes())))
+
+	assert.Equal(t, w.DumpEntities(), w2.DumpEntities())
+
+	q1 := w.Query(All())
+	q2 := w2.Query(All())
+	for q1.Next() {
+		assert.True(t, q2.Next())
+		assert.Equal(t, q1.Entity(), q2.Entity())
+		assert.Equal(t, q1.Mask(), q2.Mask())
+		if q1.Has(posID) {
+			assert.Equal(t, *(*Position)(q1.Get(posID)), *(*Position)(q2.Get(posID2)))
+		}
+	}
+	assert.False(t, q2.Next())
+
+	relFilter := NewRelationFilter(All(relID2), parent2)
+	q2 = w2.Query(&relFilter)
+	assert.Equal(t, 7, q2.Count())
+	q2.Close()
+
+	w2.RemoveEntity(parent2)
+	e := w2.NewEntity(posID2)
+	assert.True(t, w2.Alive(e))
+
+	w2.Reset()
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(buf.Bytes())))
+	dump1, dump2 := w.DumpEntities(), w2.DumpEntities()
+	assert.Equal(t, dump1.Entities, dump2.Entities)
+	assert.ElementsMatch(t, dump1.Alive, dump2.Alive)
+
+	assert.PanicsWithValue(t, "can set entity data only on a fresh or reset world", func() {
+		_ = w2.LoadSnapshot(bytes.NewReader(buf.Bytes()))
+	})
+}
+
+func TestWorldSnapshotErrors(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.NewEntity(posID)
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&buf))
+	data := buf.Bytes()
+
+	w2 := NewWorld()
+	err := w2.LoadSnapshot(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrSnapshotLayout))
+	assert.EqualError(t, err, "incompatible snapshot layout: component type ecs.Position is not registered")
+
+	{
+		type Position struct {
+			X float32
+			Y float32
+		}
+		w2 := NewWorld()
+		_ = ComponentID[Position](&w2)
+		err := w2.LoadSnapshot(bytes.NewReader(data))
+		assert.EqualError(t, err, "incompatible snapshot layout: memory layout of component type ecs.Position has changed")
+	}
+
+	w2 = NewWorld()
+	_ = ComponentID[Position](&w2)
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)-20] ^= 1
+	err = w2.LoadSnapshot(bytes.NewReader(corrupt))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+
+	err = w2.LoadSnapshot(bytes.NewReader(data[:len(data)/2]))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+	assert.Equal(t, 0, countEntities(&w2, All()))
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(data)))
+
+	ptrID := ComponentID[snapshotPointer](&w)
+	w.NewEntity(ptrID)
+	err = w.Snapshot(&buf)
+	assert.EqualError(t, err, "incompatible snapshot layout: component type ecs.snapshotPointer contains pointers")
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { _ = w.Snapshot(&buf) })
+	query.Close()
+}
+
+type snapshotTime struct {
+	Tick    int64
+	Version uint32
+	Fail    bool
+}
+
+func (t *snapshotTime) SnapshotVersion() uint32 {
+	return t.Version
+}
+
+func (t *snapshotTime) MarshalSnapshot() ([]byte, error) {
+	if t.Fail {
+		return nil, errors.New("marshal failed")
+	}
+	return []byte{byte(t.Tick)}, nil
+}
+
+func (t *snapshotTime) UnmarshalSnapshot(version uint32, data []byte) error {
+	if t.Fail {
+		return errors.New("unmarshal failed")
+	}
+	t.Tick = int64(data[0]) * int64(version)
+	return nil
+}
+
+func TestWorldSnapshotResources(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	w.NewEntity(posID)
+	AddResource(&w, &snapshotTime{Tick: 7, Version: 1})
+	AddResource(&w, &Position{X: 1})
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&buf))
+	data := buf.Bytes()
+
+	w2 := NewWorld()
+	_ = ComponentID[Position](&w2)
+	res := &snapshotTime{Version: 2}
+	AddResource(&w2, res)
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(data)))
+	assert.Equal(t, int64(7), res.Tick)
+	assert.Equal(t, 1, countEntities(&w2, All(posID)))
+
+	w2 = NewWorld()
+	_ = ComponentID[Position](&w2)
+	err := w2.LoadSnapshot(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, ErrSnapshotLayout))
+	assert.EqualError(t, err, "incompatible snapshot layout: resource ecs.snapshotTime is not present in the world or not serializable")
+
+	AddResource(&w2, &snapshotTime{Version: 0})
+	err = w2.LoadSnapshot(bytes.NewReader(data))
+	assert.EqualError(t, err, "incompatible snapshot layout: resource ecs.snapshotTime has version 1, but only up to 0 is supported")
+
+	w2 = NewWorld()
+	_ = ComponentID[Position](&w2)
+	AddResource(&w2, &snapshotTime{Version: 1, Fail: true})
+	err = w2.LoadSnapshot(bytes.NewReader(data))
+	assert.EqualError(t, err, "deserializing resource ecs.snapshotTime: unmarshal failed")
+	assert.Equal(t, 0, countEntities(&w2, All()))
+
+	GetResource[snapshotTime](&w).Fail = true
+	err = w.Snapshot(&buf)
+	assert.EqualError(t, err, "serializing resource ecs.snapshotTime: marshal failed")
+}
+
+func TestWorldSnapshotCorruptColumn(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	NewBuilder(&w, posID).NewBatch(10)
+	NewBuilder(&w, posID, velID).NewBatch(10)
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&buf))
+	data := buf.Bytes()
+
+	corrupt := func(section string) []byte {
+		name := binary.LittleEndian.AppendUint32(nil, uint32(len(section)))
+		name = append(name, section...)
+		idx := bytes.Index(data, name)
+		assert.Greater(t, idx, 0)
+		corrupt := append([]byte{}, data...)
+		corrupt[idx+len(name)+8] ^= 1
+		return corrupt
+	}
+
+	w2 := NewWorld()
+	_ = ComponentID[Position](&w2)
+	_ = ComponentID[Velocity](&w2)
+
+	err := w2.LoadSnapshot(bytes.NewReader(corrupt("archetype 1/ecs.Velocity")))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+	assert.ErrorContains(t, err, "corrupt snapshot: section 'archetype 1/ecs.Velocity': checksum mismatch")
+
+	err = w2.LoadSnapshot(bytes.NewReader(corrupt("archetype 0/ecs.Position")))
+	assert.ErrorContains(t, err, "corrupt snapshot: section 'archetype 0/ecs.Position': checksum mismatch")
+
+	err = w2.LoadSnapshot(bytes.NewReader(corrupt("archetype 1")))
+	assert.ErrorContains(t, err, "corrupt snapshot: section 'archetype 1': checksum mismatch")
+
+	err = w2.Diff(bytes.NewReader(corrupt("archetype 1/ecs.Position")), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "corrupt snapshot: section 'archetype 1/ecs.Position': checksum mismatch")
+
+	assert.Equal(t, 0, countEntities(&w2, All()))
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(data)))
+	assert.Equal(t, w.DumpEntities(), w2.DumpEntities())
+}
+
+func TestWorldPrewarmSnapshot(t *testing.T) {
+	w := NewWorld(NewConfig().WithCapacityIncrement(16))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+
+	parent := w.NewEntity()
+	NewBuilder(&w, posID, velID).NewBatch(100)
+	NewBuilder(&w, posID, relID).WithRelation(relID).NewBatch(20, parent)
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, w.Snapshot(&buf))
+	data := buf.Bytes()
+
+	w2 := NewWorld(NewConfig().WithCapacityIncrement(16))
+	posID2 := ComponentID[Position](&w2)
+	velID2 := ComponentID[Velocity](&w2)
+	relID2 := ComponentID[testRelationA](&w2)
+
+	assert.Nil(t, w2.PrewarmSnapshot(bytes.NewReader(data)))
+	assert.Equal(t, 0, countEntities(&w2, All()))
+
+	arches := w2.getArchetypes(All(posID2, velID2))
+	assert.Equal(t, 1, len(arches))
+	assert.Equal(t, uint32(0), arches[0].Len())
+	assert.Equal(t, uint32(112), arches[0].Cap())
+	posVel := arches[0]
+
+	relFilter := NewRelationFilter(All(relID2), parent)
+	arches = w2.getArchetypes(&relFilter)
+	assert.Equal(t, 1, len(arches))
+	assert.Equal(t, uint32(32), arches[0].Cap())
+
+	assert.Nil(t, w2.LoadSnapshot(bytes.NewReader(data)))
+	arches = w2.getArchetypes(All(posID2, velID2))
+	assert.Equal(t, posVel, arches[0])
+	assert.Equal(t, uint32(100), arches[0].Len())
+	assert.Equal(t, uint32(112), arches[0].Cap())
+	assert.Equal(t, w.DumpEntities(), w2.DumpEntities())
+
+	assert.PanicsWithValue(t, "can set entity data only on a fresh or reset world", func() {
+		_ = w2.PrewarmSnapshot(bytes.NewReader(data))
+	})
+
+	w3 := NewWorld()
+	_ = ComponentID[Position](&w3)
+	err := w3.PrewarmSnapshot(bytes.NewReader(data))
+	assert.EqualError(t, err, "incompatible snapshot layout: component type ecs.Velocity is not registered")
+	assert.Equal(t, 1, len(w3.getArchetypes(All())))
+
+	w3 = NewWorld()
+	_ = ComponentID[Position](&w3)
+	_ = ComponentID[Velocity](&w3)
+	_ = ComponentID[testRelationA](&w3)
+	err = w3.PrewarmSnapshot(bytes.NewReader(data[:200]))
+	assert.True(t, errors.Is(err, ErrSnapshotCorrupt))
+}
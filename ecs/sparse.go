@@ -0,0 +1,364 @@
+package ecs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// sparseNone marks entities without a component in a sparse set.
+const sparseNone = math.MaxUint32
+
+// SparseSets provides access to components in sparse-set storage.
+//
+// Sparse-set components are not stored in archetypes, but in a separate dense array per component type,
+// with a sparse index by entity ID. Adding or removing them does not move entities between archetypes.
+// This makes them suitable for frequently added and removed components, like status effects,
+// while hot data stays in archetypes.
+//
+// In contrast to archetype components, sparse-set components can be added and removed during [Query] iteration.
+// They are not part of an entity's [Mask], and can't be used in filters.
+// Instead, iterate over [SparseSets.Entities], or check them with [SparseSets.Has] during queries.
+//
+// Sparse-set components are removed with their entity, copied by [World.Clone],
+// and removed by [World.Reset]. They are not contained in snapshots (see [World.Snapshot]),
+// do not trigger events and are not considered by [World.Hash].
+//
+// Access it using [World.SparseSets].
+// Get IDs of sparse-set components with [SparseComponentID].
+type SparseSets struct {
+	world *World
+}
+
+// Add adds a zeroed sparse-set component to an entity, and returns a pointer to it.
+//
+// The pointer is only valid until the next addition or removal of the component to or from any entity.
+//
+// Panics if the entity already has the component.
+// Panics when called for a removed (and potentially recycled) entity.
+//
+// See also [github.com/mlange-42/arche/generic.Sparse.Add] for a generic variant.
+func (s *SparseSets) Add(entity Entity, id SparseID) unsafe.Pointer {
+	if !s.world.entityPool.Alive(entity) {
+		panic("can't add sparse-set component to a dead entity")
+	}
+	set := s.world.sparse.Set(id)
+	if set.Has(entity.id) {
+		panic(fmt.Sprintf("entity already has sparse-set component of type %v", set.Type))
+	}
+	if len(s.world.journals) > 0 {
+		s.world.recordSparse(changeSparseAdd, entity, id, set)
+	}
+	return set.Add(entity)
+}
+
+// Set copies the given component to the entity's sparse-set component, and returns a pointer to it.
+// The component must be a pointer.
+//
+// Adds the component if the entity does not have it.
+// The pointer is only valid until the next addition or removal of the component to or from any entity.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+//
+// See also [github.com/mlange-42/arche/generic.Sparse.Set] for a generic variant.
+func (s *SparseSets) Set(entity Entity, id SparseID, comp interface{}) unsafe.Pointer {
+	if !s.world.entityPool.Alive(entity) {
+		panic("can't set sparse-set component of a dead entity")
+	}
+	set := s.world.sparse.Set(id)
+	dst := set.Get(entity.id)
+	if dst == nil {
+		if len(s.world.journals) > 0 {
+			s.world.recordSparse(changeSparseAdd, entity, id, set)
+		}
+		dst = set.Add(entity)
+	} else if len(s.world.journals) > 0 {
+		s.world.recordSparse(changeSparseSet, entity, id, set)
+	}
+	reflect.NewAt(set.Type, dst).Elem().Set(reflect.ValueOf(comp).Elem())
+	return dst
+}
+
+// Get returns a pointer to the sparse-set component of an entity.
+// Returns nil if the entity has no such component, or if the entity is dead.
+//
+// The pointer is only valid until the next addition or removal of the component to or from any entity.
+//
+// See also [github.com/mlange-42/arche/generic.Sparse.Get] for a generic variant.
+func (s *SparseSets) Get(entity Entity, id SparseID) unsafe.Pointer {
+	set := s.world.sparse.sets[id.id]
+	if set == nil || !set.Contains(entity) {
+		return nil
+	}
+	return set.Get(entity.id)
+}
+
+// Has returns whether an entity has the sparse-set component.
+//
+// See also [github.com/mlange-42/arche/generic.Sparse.Has] for a generic variant.
+func (s *SparseSets) Has(entity Entity, id SparseID) bool {
+	set := s.world.sparse.sets[id.id]
+	return set != nil && set.Contains(entity)
+}
+
+// Remove removes the sparse-set component from an entity.
+//
+// Panics if the entity does not have the component.
+//
+// See also [github.com/mlange-42/arche/generic.Sparse.Remove] for a generic variant.
+func (s *SparseSets) Remove(entity Entity, id SparseID) {
+	set := s.world.sparse.sets[id.id]
+	if set == nil || !set.Contains(entity) {
+		panic("entity does not have the sparse-set component")
+	}
+	if len(s.world.journals) > 0 {
+		s.world.recordSparse(changeSparseRemove, entity, id, set)
+	}
+	set.Remove(entity.id)
+}
+
+// Ids returns the IDs of all sparse-set components of an entity.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (s *SparseSets) Ids(entity Entity) []SparseID {
+	if !s.world.entityPool.Alive(entity) {
+		panic("can't get sparse-set component IDs for a dead entity")
+	}
+	ids := []SparseID{}
+	for _, id := range s.world.sparse.registry.IDs {
+		if set := s.world.sparse.sets[id]; set != nil && set.Has(entity.id) {
+			ids = append(ids, SparseID{id})
+		}
+	}
+	return ids
+}
+
+// Len returns the number of entities with the sparse-set component.
+func (s *SparseSets) Len(id SparseID) int {
+	set := s.world.sparse.sets[id.id]
+	if set == nil {
+		return 0
+	}
+	return len(set.entities)
+}
+
+// Entities returns the entities with the sparse-set component, in storage order.
+//
+// The returned slice is the set's internal storage, for fast iteration. It must not be modified,
+// and is only valid until the next addition or removal of the component to or from any entity.
+func (s *SparseSets) Entities(id SparseID) []Entity {
+	set := s.world.sparse.sets[id.id]
+	if set == nil {
+		return nil
+	}
+	return set.entities
+}
+
+// Clear removes the sparse-set component from all entities.
+func (s *SparseSets) Clear(id SparseID) {
+	if set := s.world.sparse.sets[id.id]; set != nil {
+		if len(s.world.journals) > 0 {
+			for _, e := range set.entities {
+				s.world.recordSparse(changeSparseRemove, e, id, set)
+			}
+		}
+		set.Reset()
+	}
+}
+
+// sparseStorage holds the sparse sets of a world.
+type sparseStorage struct {
+	registry componentRegistry
+	sets     []*sparseSet
+}
+
+// newSparseStorage creates a new, empty sparse-set storage.
+func newSparseStorage() sparseStorage {
+	return sparseStorage{
+		registry: newComponentRegistry(),
+		sets:     make([]*sparseSet, MaskTotalBits),
+	}
+}
+
+// Set returns the sparse set for an ID, and creates it if required.
+func (s *sparseStorage) Set(id SparseID) *sparseSet {
+	if set := s.sets[id.id]; set != nil {
+		return set
+	}
+	tp, ok := s.registry.ComponentType(id.id)
+	if !ok {
+		panic(fmt.Sprintf("sparse-set component with ID %d is not registered", id.id))
+	}
+	set := newSparseSet(tp)
+	s.sets[id.id] = set
+	return set
+}
+
+// RemoveEntity removes all sparse-set components of an entity.
+func (s *sparseStorage) RemoveEntity(entity eid) {
+	for _, id := range s.registry.IDs {
+		if set := s.sets[id]; set != nil && set.Has(entity) {
+			set.Remove(entity)
+		}
+	}
+}
+
+// Reset removes all sparse-set components.
+func (s *sparseStorage) Reset() {
+	for _, set := range s.sets {
+		if set != nil {
+			set.Reset()
+		}
+	}
+}
+
+// Compact shrinks the storage of all sparse sets to their current number of entities.
+func (s *sparseStorage) Compact() {
+	for _, set := range s.sets {
+		if set != nil {
+			set.Compact()
+		}
+	}
+}
+
+// clone creates a deep copy of the sparse-set storage.
+func (s *sparseStorage) clone() sparseStorage {
+	c := sparseStorage{
+		registry: s.registry.clone(),
+		sets:     make([]*sparseSet, len(s.sets)),
+	}
+	for i, set := range s.sets {
+		if set != nil {
+			c.sets[i] = set.clone()
+		}
+	}
+	return c
+}
+
+// sparseSet stores the components of a single type densely, with a sparse index by entity ID.
+type sparseSet struct {
+	Type     reflect.Type   // Component type.
+	buffer   reflect.Value  // Dense component storage.
+	pointer  unsafe.Pointer // Pointer to the first component.
+	itemSize uint32         // Component size.
+	entities []Entity       // Entities, in the order of components.
+	indices  []uint32       // Dense indices by entity ID.
+}
+
+// newSparseSet creates a new, empty sparse set.
+func newSparseSet(tp reflect.Type) *sparseSet {
+	s := &sparseSet{Type: tp, itemSize: uint32(tp.Size())}
+	s.grow(1)
+	return s
+}
+
+// Has returns whether there is a component for the entity ID.
+func (s *sparseSet) Has(entity eid) bool {
+	return int(entity) < len(s.indices) && s.indices[entity] != sparseNone
+}
+
+// Contains returns whether there is a component for the entity, including its generation.
+func (s *sparseSet) Contains(entity Entity) bool {
+	return s.Has(entity.id) && s.entities[s.indices[entity.id]] == entity
+}
+
+// Get returns a pointer to the component for the entity ID, or nil.
+func (s *sparseSet) Get(entity eid) unsafe.Pointer {
+	if !s.Has(entity) {
+		return nil
+	}
+	return unsafe.Add(s.pointer, s.itemSize*s.indices[entity])
+}
+
+// Add adds a zeroed component for an entity that has none, and returns a pointer to it.
+func (s *sparseSet) Add(entity Entity) unsafe.Pointer {
+	if int(entity.id) >= len(s.indices) {
+		old := len(s.indices)
+		s.indices = append(s.indices, make([]uint32, int(entity.id)+1-old)...)
+		for i := old; i < len(s.indices); i++ {
+			s.indices[i] = sparseNone
+		}
+	}
+	index := uint32(len(s.entities))
+	if int(index) >= s.buffer.Len() {
+		s.grow(2 * s.buffer.Len())
+	}
+	s.entities = append(s.entities, entity)
+	s.indices[entity.id] = index
+	return unsafe.Add(s.pointer, s.itemSize*index)
+}
+
+// Remove removes the component for the entity ID, by swapping the last component into its place.
+func (s *sparseSet) Remove(entity eid) {
+	index := s.indices[entity]
+	last := uint32(len(s.entities) - 1)
+	if index != last {
+		moved := s.entities[last]
+		s.entities[index] = moved
+		s.indices[moved.id] = index
+		if s.itemSize > 0 {
+			copy(s.bytes(index), s.bytes(last))
+		}
+	}
+	if s.itemSize > 0 {
+		clear(s.bytes(last))
+	}
+	s.entities = s.entities[:last]
+	s.indices[entity] = sparseNone
+}
+
+// Reset removes all components.
+func (s *sparseSet) Reset() {
+	if s.itemSize > 0 {
+		clear(unsafe.Slice((*byte)(s.pointer), len(s.entities)*int(s.itemSize)))
+	}
+	for _, e := range s.entities {
+		s.indices[e.id] = sparseNone
+	}
+	s.entities = s.entities[:0]
+}
+
+// Compact shrinks the component storage to the number of components,
+// and the sparse index to the highest entity ID with a component.
+func (s *sparseSet) Compact() {
+	if required := max(len(s.entities), 1); s.buffer.Len() > required {
+		s.grow(required)
+	}
+	s.entities = slices.Clip(s.entities)
+
+	n := len(s.indices)
+	for n > 0 && s.indices[n-1] == sparseNone {
+		n--
+	}
+	s.indices = append([]uint32(nil), s.indices[:n]...)
+}
+
+// grow re-allocates the component storage with the given capacity.
+func (s *sparseSet) grow(capacity int) {
+	old := s.buffer
+	s.buffer = reflect.New(reflect.ArrayOf(capacity, s.Type)).Elem()
+	s.pointer = s.buffer.Addr().UnsafePointer()
+	if old.IsValid() {
+		reflect.Copy(s.buffer, old)
+	}
+}
+
+// bytes returns the raw bytes of the component at the given dense index.
+func (s *sparseSet) bytes(index uint32) []byte {
+	return unsafe.Slice((*byte)(unsafe.Add(s.pointer, s.itemSize*index)), s.itemSize)
+}
+
+// clone creates a deep copy of the sparse set.
+func (s *sparseSet) clone() *sparseSet {
+	c := &sparseSet{
+		Type:     s.Type,
+		itemSize: s.itemSize,
+		entities: append([]Entity(nil), s.entities...),
+		indices:  append([]uint32(nil), s.indices...),
+	}
+	c.grow(s.buffer.Len())
+	reflect.Copy(c.buffer, s.buffer)
+	return c
+}
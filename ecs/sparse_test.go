@@ -0,0 +1,139 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusEffect struct {
+	Remaining int
+}
+
+func TestSparseSets(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	effectID := SparseComponentID[statusEffect](&w)
+	labelID := SparseComponentID[label](&w)
+	sparse := w.SparseSets()
+
+	tp, ok := SparseComponentType(&w, effectID)
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(statusEffect{}), tp)
+	_, ok = SparseComponentType(&w, SparseID{id: 10})
+	assert.False(t, ok)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	e3 := w.NewEntity(posID)
+	arch := w.entities[e1.id].arch
+
+	assert.False(t, sparse.Has(e1, effectID))
+	assert.Nil(t, sparse.Get(e1, effectID))
+	assert.Equal(t, 0, sparse.Len(effectID))
+	assert.Nil(t, sparse.Entities(effectID))
+
+	(*statusEffect)(sparse.Add(e1, effectID)).Remaining = 1
+	sparse.Set(e2, effectID, &statusEffect{Remaining: 2})
+	sparse.Set(e3, effectID, &statusEffect{Remaining: 3})
+	sparse.Set(e3, effectID, &statusEffect{Remaining: 4})
+	sparse.Add(e2, labelID)
+
+	assert.Equal(t, arch, w.entities[e1.id].arch)
+	assert.Equal(t, 3, sparse.Len(effectID))
+	assert.Equal(t, []Entity{e1, e2, e3}, sparse.Entities(effectID))
+	assert.Equal(t, statusEffect{Remaining: 4}, *(*statusEffect)(sparse.Get(e3, effectID)))
+	assert.True(t, sparse.Has(e2, labelID))
+	assert.False(t, sparse.Has(e1, labelID))
+	assert.Equal(t, []SparseID{effectID, labelID}, sparse.Ids(e2))
+	assert.Equal(t, []SparseID{effectID}, sparse.Ids(e1))
+
+	sparse.Remove(e1, effectID)
+	assert.False(t, sparse.Has(e1, effectID))
+	assert.Equal(t, []Entity{e3, e2}, sparse.Entities(effectID))
+	assert.Equal(t, statusEffect{Remaining: 2}, *(*statusEffect)(sparse.Get(e2, effectID)))
+	assert.Equal(t, statusEffect{Remaining: 4}, *(*statusEffect)(sparse.Get(e3, effectID)))
+
+	assert.PanicsWithValue(t, "entity already has sparse-set component of type ecs.statusEffect", func() { sparse.Add(e2, effectID) })
+	assert.PanicsWithValue(t, "entity does not have the sparse-set component", func() { sparse.Remove(e1, effectID) })
+	assert.PanicsWithValue(t, "sparse-set component with ID 10 is not registered", func() { sparse.Add(e1, SparseID{id: 10}) })
+
+	query := w.Query(All(posID))
+	for query.Next() {
+		if !sparse.Has(query.Entity(), effectID) {
+			sparse.Add(query.Entity(), effectID)
+		}
+	}
+	assert.Equal(t, 3, sparse.Len(effectID))
+
+	w.RemoveEntity(e2)
+	assert.Equal(t, 2, sparse.Len(effectID))
+	assert.Equal(t, 0, sparse.Len(labelID))
+	assert.False(t, sparse.Has(e2, effectID))
+	assert.Nil(t, sparse.Get(e2, effectID))
+	assert.PanicsWithValue(t, "can't add sparse-set component to a dead entity", func() { sparse.Add(e2, effectID) })
+	assert.PanicsWithValue(t, "can't set sparse-set component of a dead entity", func() { sparse.Set(e2, effectID, &statusEffect{}) })
+	assert.PanicsWithValue(t, "can't get sparse-set component IDs for a dead entity", func() { sparse.Ids(e2) })
+
+	recycled := w.NewEntity(posID)
+	assert.Equal(t, e2.id, recycled.id)
+	assert.False(t, sparse.Has(recycled, effectID))
+	sparse.Add(recycled, effectID)
+	assert.False(t, sparse.Has(e2, effectID))
+	assert.Equal(t, statusEffect{}, *(*statusEffect)(sparse.Get(recycled, effectID)))
+
+	w.Batch().RemoveEntities(All(posID))
+	assert.Equal(t, 0, sparse.Len(effectID))
+
+	e4 := w.NewEntity()
+	sparse.Add(e4, effectID)
+	sparse.Clear(effectID)
+	assert.Equal(t, 0, sparse.Len(effectID))
+	assert.False(t, sparse.Has(e4, effectID))
+
+	sparse.Add(e4, effectID)
+	w.Reset()
+	assert.Equal(t, 0, sparse.Len(effectID))
+}
+
+func TestSparseSetsGrow(t *testing.T) {
+	w := NewWorld()
+	effectID := SparseComponentID[statusEffect](&w)
+	sparse := w.SparseSets()
+
+	entities := []Entity{}
+	query := NewBuilder(&w).NewBatchQ(100)
+	for query.Next() {
+		entities = append(entities, query.Entity())
+	}
+	for i, e := range entities {
+		sparse.Set(e, effectID, &statusEffect{Remaining: i})
+	}
+	for i := 0; i < len(entities); i += 2 {
+		sparse.Remove(entities[i], effectID)
+	}
+	assert.Equal(t, 50, sparse.Len(effectID))
+	for i, e := range entities {
+		if i%2 == 0 {
+			assert.False(t, sparse.Has(e, effectID))
+			continue
+		}
+		assert.Equal(t, statusEffect{Remaining: i}, *(*statusEffect)(sparse.Get(e, effectID)))
+	}
+}
+
+func TestSparseSetsClone(t *testing.T) {
+	w := NewWorld()
+	effectID := SparseComponentID[statusEffect](&w)
+	e := w.NewEntity()
+	w.SparseSets().Set(e, effectID, &statusEffect{Remaining: 5})
+
+	c := w.Clone()
+	w.SparseSets().Set(e, effectID, &statusEffect{Remaining: 6})
+
+	assert.Equal(t, statusEffect{Remaining: 5}, *(*statusEffect)(c.SparseSets().Get(e, effectID)))
+	assert.Equal(t, effectID, SparseComponentID[statusEffect](c))
+	c.RemoveEntity(e)
+	assert.True(t, w.SparseSets().Has(e, effectID))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // World provide statistics for an [ecs.World].
@@ -25,6 +26,14 @@ type World struct {
 	Memory int
 	// Number of cached filters.
 	CachedFilters int
+	// Iteration statistics of labeled filters, sorted by total time in descending order.
+	Queries []Query
+	// Entity lifetime statistics. Nil if lifetime tracking is not enabled.
+	Lifetimes *Lifetimes
+	// Leaked entity statistics. Nil if leak detection is not enabled.
+	Leaks *Leaks
+	// Timing statistics of systems, as collected by a scheduler. Empty if the world is not run by a scheduler.
+	Systems []System
 }
 
 // Entities provide statistics about [ecs.World] entities.
@@ -35,10 +44,36 @@ type Entities struct {
 	Total int
 	// Recycled/available entities.
 	Recycled int
+	// Alive entities with the [ecs.Hidden] marker. These are included in Used.
+	Hidden int
 	// Current capacity of the entities list.
 	Capacity int
 }
 
+// Lifetimes provide statistics about entity lifetimes, in ticks of [ecs.World.Tick].
+type Lifetimes struct {
+	// Number of entities created since tracking started.
+	Created int
+	// Number of entities removed since tracking started.
+	Removed int
+	// Mean lifetime of removed entities.
+	Mean float64
+	// Histogram of lifetimes of removed entities.
+	// Bin 0 counts entities removed in the tick of their creation,
+	// bin i > 0 counts lifetimes in the interval [2^(i-1), 2^i).
+	Histogram []int
+}
+
+// Leaks provide statistics about leaked entities, as detected by leak detection.
+type Leaks struct {
+	// Number of entities that currently have no components, or only marker components.
+	Orphans int
+	// Number of orphans that are currently considered leaked.
+	Leaked int
+	// Total number of entities reported as leaked since detection started.
+	Reported int
+}
+
 // Node provide statistics for an archetype graph node.
 type Node struct {
 	// Total number of archetypes, incl. inactive.
@@ -79,6 +114,47 @@ type Archetype struct {
 	Memory int
 }
 
+// Query provide iteration statistics for a labeled filter.
+type Query struct {
+	// Label of the filter.
+	Label string
+	// Number of queries created with the filter.
+	Queries int
+	// Number of non-empty archetypes visited.
+	Archetypes int
+	// Number of entities visited.
+	Entities int
+	// Total time between creation and closing of queries.
+	Time time.Duration
+}
+
+// System provide timing statistics for a system of a scheduler.
+type System struct {
+	// Name of the system, derived from its type.
+	Name string
+	// Phase of the system in the scheduler.
+	Phase string
+	// Number of update runs.
+	Runs int
+	// Duration of the last update.
+	Last time.Duration
+	// Average duration of all updates.
+	Avg time.Duration
+	// Maximum duration of all updates.
+	Max time.Duration
+}
+
+// Tree provide statistics for a tree of entities, formed by an entity relation.
+type Tree struct {
+	// Number of entities in the tree, including the root.
+	Entities int
+	// Memory used by the entities of the tree and their components, in bytes.
+	// Does not include unused capacity.
+	Memory int
+	// Maximum depth of the tree. A root without children has depth 0.
+	Depth int
+}
+
 func (s *World) String() string {
 	b := strings.Builder{}
 
@@ -93,11 +169,25 @@ func (s *World) String() string {
 	}
 	fmt.Fprintf(&b, "  Components: %s\n", strings.Join(typeNames, ", "))
 	fmt.Fprint(&b, s.Entities.String())
+	if s.Lifetimes != nil {
+		fmt.Fprint(&b, s.Lifetimes.String())
+	}
+	if s.Leaks != nil {
+		fmt.Fprint(&b, s.Leaks.String())
+	}
 
 	for i := range s.Nodes {
 		fmt.Fprint(&b, s.Nodes[i].String())
 	}
 
+	for i := range s.Queries {
+		fmt.Fprint(&b, s.Queries[i].String())
+	}
+
+	for i := range s.Systems {
+		fmt.Fprint(&b, s.Systems[i].String())
+	}
+
 	return b.String()
 }
 
@@ -105,6 +195,14 @@ func (s *Entities) String() string {
 	return fmt.Sprintf("Entities -- Used: %d, Recycled: %d, Total: %d, Capacity: %d\n", s.Used, s.Recycled, s.Total, s.Capacity)
 }
 
+func (s *Lifetimes) String() string {
+	return fmt.Sprintf("Lifetimes -- Created: %d, Removed: %d, Mean: %.1f, Histogram: %v\n", s.Created, s.Removed, s.Mean, s.Histogram)
+}
+
+func (s *Leaks) String() string {
+	return fmt.Sprintf("Leaks -- Orphans: %d, Leaked: %d, Reported: %d\n", s.Orphans, s.Leaked, s.Reported)
+}
+
 func (s *Node) String() string {
 	if !s.IsActive {
 		return ""
@@ -120,3 +218,21 @@ func (s *Node) String() string {
 		s.Components, s.Size, s.Capacity, float64(s.Memory)/1024.0, s.MemoryPerEntity, strings.Join(typeNames, ", "),
 	)
 }
+
+func (s *Tree) String() string {
+	return fmt.Sprintf("Tree -- Entities: %d, Memory: %.1f kB, Depth: %d\n", s.Entities, float64(s.Memory)/1024.0, s.Depth)
+}
+
+func (s *Query) String() string {
+	return fmt.Sprintf(
+		"Query -- Label: %s, Queries: %d, Archetypes: %d, Entities: %d, Time: %v\n",
+		s.Label, s.Queries, s.Archetypes, s.Entities, s.Time,
+	)
+}
+
+func (s *System) String() string {
+	return fmt.Sprintf(
+		"System -- Name: %s, Phase: %s, Runs: %d, Last: %v, Avg: %v, Max: %v\n",
+		s.Name, s.Phase, s.Runs, s.Last, s.Avg, s.Max,
+	)
+}
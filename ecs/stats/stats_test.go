@@ -29,6 +29,12 @@ func TestStats(t *testing.T) {
 				ComponentTypes: []reflect.Type{reflect.TypeOf(1)},
 			},
 		},
+		Queries: []Query{
+			{Label: "query", Queries: 2, Archetypes: 3, Entities: 100},
+		},
+		Systems: []System{
+			{Name: "Move", Phase: "Update", Runs: 10, Last: 2, Avg: 3, Max: 5},
+		},
 	}
 	fmt.Println(stats.String())
 
@@ -0,0 +1,62 @@
+package ecs
+
+import "sync"
+
+// SyncResource is a resource wrapper that guards a value with a [sync.RWMutex],
+// for resources that are shared with background goroutines, like asset loaders or network readers.
+//
+// Add the wrapper as a resource, and hand the pointer to the goroutines that share it.
+// The world's [Resources] themselves are not synchronized,
+// so goroutines should not get the resource from the world while it is in use.
+//
+// Example:
+//
+//	assets := ecs.NewSyncResource(Assets{})
+//	ecs.AddResource(&world, assets)
+//
+//	go loadAssets(assets) // Calls assets.Write(...)
+//
+//	// In a system:
+//	assets := ecs.GetResource[ecs.SyncResource[Assets]](world)
+//	assets.Read(func(a *Assets) {
+//		// ...
+//	})
+type SyncResource[T any] struct {
+	mu    sync.RWMutex
+	value T
+}
+
+// NewSyncResource creates a new [SyncResource] with the given initial value.
+func NewSyncResource[T any](value T) *SyncResource[T] {
+	return &SyncResource[T]{value: value}
+}
+
+// Read calls the given function with a pointer to the value, holding a read lock.
+// The pointer must not be used for writing, and must not be retained after the function returns.
+func (r *SyncResource[T]) Read(fn func(value *T)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn(&r.value)
+}
+
+// Write calls the given function with a pointer to the value, holding a write lock.
+// The pointer must not be retained after the function returns.
+func (r *SyncResource[T]) Write(fn func(value *T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.value)
+}
+
+// Load returns a copy of the value.
+func (r *SyncResource[T]) Load() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
+}
+
+// Store replaces the value.
+func (r *SyncResource[T]) Store(value T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.value = value
+}
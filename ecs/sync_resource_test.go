@@ -0,0 +1,34 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncResource(t *testing.T) {
+	w := NewWorld()
+	res := NewSyncResource(Position{X: 1})
+	AddResource(&w, res)
+	assert.Equal(t, res, GetResource[SyncResource[Position]](&w))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				res.Write(func(p *Position) { p.X++ })
+				res.Read(func(p *Position) { _ = p.X })
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, Position{X: 1001}, res.Load())
+	res.Store(Position{Y: 5})
+	res.Read(func(p *Position) {
+		assert.Equal(t, Position{Y: 5}, *p)
+	})
+}
@@ -0,0 +1,103 @@
+package ecs
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// tagFieldType is the type of the single field of tag component types.
+var tagFieldType = reflect.TypeOf([0]byte{})
+
+// tagType creates a unique zero-sized component type for a tag name.
+//
+// Distinct struct tags result in distinct types, so each name gets its own component type.
+func tagType(name string) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Tag", Type: tagFieldType, Tag: reflect.StructTag("arche:" + strconv.Quote(name))},
+	})
+}
+
+// TagID returns the component [ID] for a string-keyed tag.
+// Registers the tag if it is not already registered.
+//
+// Tags are zero-sized components, created from their name at runtime.
+// They are intended for scripting and editor layers that can't use Go types.
+// As ordinary components, tags count towards the limit of [MaskTotalBits] component types.
+//
+// Panics if called on a locked world and the tag is not registered yet.
+//
+// See also [World.QueryTag], [World.AddTag] and [World.RemoveTag].
+func TagID(w *World, name string) ID {
+	if id, ok := w.tags[name]; ok {
+		return id
+	}
+	id := w.componentID(tagType(name))
+	if w.tags == nil {
+		w.tags = map[string]ID{}
+	}
+	w.tags[name] = id
+	return id
+}
+
+// AddTag adds string-keyed tags to an [Entity].
+//
+// Panics in the same cases as [World.Add].
+//
+// See also [TagID].
+func (w *World) AddTag(entity Entity, tags ...string) {
+	w.Exchange(entity, w.tagIDs(tags), nil)
+}
+
+// RemoveTag removes string-keyed tags from an [Entity].
+//
+// Panics in the same cases as [World.Remove].
+//
+// See also [TagID].
+func (w *World) RemoveTag(entity Entity, tags ...string) {
+	w.Exchange(entity, nil, w.tagIDs(tags))
+}
+
+// HasTag returns whether an [Entity] has a string-keyed tag.
+// Returns false for tags that are not registered, without registering them.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+//
+// See also [TagID].
+func (w *World) HasTag(entity Entity, tag string) bool {
+	id, ok := w.tags[tag]
+	if !ok {
+		if !w.entityPool.Alive(entity) {
+			panic("can't check for component of a dead entity")
+		}
+		return false
+	}
+	return w.Has(entity, id)
+}
+
+// QueryTag creates a [Query] over all entities that have all the given string-keyed tags.
+//
+// Compiles to an ordinary [Mask] filter, see [World.Query] for details.
+// Tags that are not registered result in an empty query, without registering them.
+// For combining tags with other components, use [TagID] to get the tags' IDs.
+func (w *World) QueryTag(tags ...string) Query {
+	mask := Mask{}
+	for _, t := range tags {
+		id, ok := w.tags[t]
+		if !ok {
+			// No entity can have an unregistered tag, so match nothing.
+			filter := All(ID{}).Without(ID{})
+			return w.Query(&filter)
+		}
+		mask.Set(id, true)
+	}
+	return w.Query(mask)
+}
+
+// tagIDs converts tag names to component IDs.
+func (w *World) tagIDs(tags []string) []ID {
+	ids := make([]ID, len(tags))
+	for i, t := range tags {
+		ids[i] = TagID(w, t)
+	}
+	return ids
+}
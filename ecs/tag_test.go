@@ -0,0 +1,70 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	enemyID := TagID(&w, "enemy")
+	bossID := TagID(&w, "boss")
+	assert.NotEqual(t, enemyID, bossID)
+	assert.Equal(t, enemyID, TagID(&w, "enemy"))
+
+	info, ok := ComponentInfo(&w, enemyID)
+	assert.True(t, ok)
+	assert.Equal(t, uintptr(0), info.Type.Size())
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	e3 := w.NewEntity()
+
+	w.AddTag(e1, "enemy")
+	w.AddTag(e2, "enemy", "boss")
+	w.AddTag(e3, "boss")
+
+	assert.True(t, w.HasTag(e1, "enemy"))
+	assert.False(t, w.HasTag(e1, "boss"))
+	assert.True(t, w.Has(e2, bossID))
+
+	query := w.QueryTag("enemy")
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	query = w.QueryTag("enemy", "boss")
+	assert.Equal(t, 1, query.Count())
+	for query.Next() {
+		assert.Equal(t, e2, query.Entity())
+	}
+
+	w.RemoveTag(e2, "enemy")
+	assert.False(t, w.HasTag(e2, "enemy"))
+
+	query = w.QueryTag("boss")
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	numIDs := len(ComponentIDs(&w))
+	query = w.QueryTag("unknown")
+	assert.Equal(t, 0, query.Count())
+	assert.False(t, w.HasTag(e1, "unknown"))
+	assert.PanicsWithValue(t, "attempt to register a new component in a locked world",
+		func() { TagID(&w, "other") })
+	query.Close()
+
+	query = w.QueryTag("boss", "unknown")
+	assert.Equal(t, 0, query.Count())
+	query.Close()
+	assert.Equal(t, numIDs, len(ComponentIDs(&w)))
+
+	w.RemoveEntity(e3)
+	assert.PanicsWithValue(t, "can't check for component of a dead entity",
+		func() { w.HasTag(e3, "unknown") })
+
+	assert.PanicsWithValue(t, "entity does not have a component of type struct { Tag [0]uint8 \"arche:\\\"enemy\\\"\" }, can't remove",
+		func() { w.RemoveTag(e2, "enemy") })
+}
@@ -0,0 +1,119 @@
+package ecs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
+// TreeStats provide statistics for a tree of entities, formed by an entity relation.
+//
+// See [World.TreeStats].
+type TreeStats struct {
+	Root Entity // Root entity of the tree.
+	stats.Tree
+}
+
+// TreeStats aggregates entity counts and memory over the trees formed by a [Relation] component.
+//
+// Each entity with the relation component is a child of its relation target.
+// Trees are rooted at entities that are targets of the relation, but don't have a target themselves,
+// at entities that have the relation component with a zero target,
+// and at entities with a relation target that was removed.
+// Entities in cycles are not part of any tree.
+//
+// Memory per entity is calculated from the entity's components, as in [stats.Node].
+// The result is sorted by the number of entities in descending order, and by memory for equal counts.
+// The calculation iterates all entities with the relation, so it should not be used in performance-critical code.
+//
+// Panics if the given component is not a relation component.
+func (w *World) TreeStats(relation ID) []TreeStats {
+	if !w.registry.IsRelation.Get(relation) {
+		panic(fmt.Sprintf("not a relation component: %v", w.registry.Types[relation.id]))
+	}
+
+	children := map[Entity][]Entity{}
+	hasParent := map[Entity]bool{}
+	roots := []Entity{}
+	for _, node := range w.relationNodes {
+		if !node.IsActive || !node.HasRelation || node.Relation != relation {
+			continue
+		}
+		arches := node.Archetypes()
+		ln := arches.Len()
+		var i int32
+		for i = 0; i < ln; i++ {
+			arch := arches.Get(i)
+			if !arch.IsActive() {
+				continue
+			}
+			target := arch.RelationTarget
+			var j uint32
+			for j = 0; j < arch.len; j++ {
+				entity := arch.GetEntity(j)
+				if target.IsZero() {
+					roots = append(roots, entity)
+					continue
+				}
+				children[target] = append(children[target], entity)
+				hasParent[entity] = true
+			}
+		}
+	}
+	for parent, ch := range children {
+		if !w.entityPool.Alive(parent) {
+			roots = append(roots, ch...)
+			continue
+		}
+		if !hasParent[parent] {
+			roots = append(roots, parent)
+		}
+	}
+
+	result := make([]TreeStats, 0, len(roots))
+	type item struct {
+		Entity Entity
+		Depth  int
+	}
+	stack := []item{}
+	for _, root := range roots {
+		tree := TreeStats{Root: root}
+		stack = append(stack[:0], item{root, 0})
+		for len(stack) > 0 {
+			it := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			tree.Entities++
+			tree.Memory += w.entityMemory(it.Entity)
+			if it.Depth > tree.Depth {
+				tree.Depth = it.Depth
+			}
+			for _, child := range children[it.Entity] {
+				stack = append(stack, item{child, it.Depth + 1})
+			}
+		}
+		result = append(result, tree)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if a.Entities != b.Entities {
+			return a.Entities > b.Entities
+		}
+		if a.Memory != b.Memory {
+			return a.Memory > b.Memory
+		}
+		return a.Root.id < b.Root.id
+	})
+	return result
+}
+
+// entityMemory returns the memory used by an entity and its components, in bytes.
+func (w *World) entityMemory(entity Entity) int {
+	mem := int(entitySize)
+	for _, tp := range w.entities[entity.id].arch.node.Types {
+		mem += int(tp.Size())
+	}
+	return mem
+}
@@ -0,0 +1,47 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldTreeStats(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	relID := ComponentID[ChildOf](&w)
+
+	root1 := w.NewEntity(posID)
+	root2 := w.NewEntity()
+	single := w.NewEntity(relID)
+
+	child1 := NewBuilder(&w, relID).WithRelation(relID).New(root1)
+	child2 := NewBuilder(&w, relID, posID).WithRelation(relID).New(root1)
+	NewBuilder(&w, relID).WithRelation(relID).NewBatch(3, child2)
+	NewBuilder(&w, relID).WithRelation(relID).New(root2)
+	w.NewEntity(posID)
+
+	trees := w.TreeStats(relID)
+	posSize := int(reflect.TypeOf(Position{}).Size())
+	relSize := int(reflect.TypeOf(ChildOf{}).Size())
+	ent := int(entitySize)
+
+	assert.Equal(t, []TreeStats{
+		{Root: root1, Tree: stats.Tree{Entities: 6, Memory: 6*ent + 2*posSize + 5*relSize, Depth: 2}},
+		{Root: root2, Tree: stats.Tree{Entities: 2, Memory: 2*ent + relSize, Depth: 1}},
+		{Root: single, Tree: stats.Tree{Entities: 1, Memory: ent + relSize, Depth: 0}},
+	}, trees)
+
+	w.RemoveEntity(root1)
+	trees = w.TreeStats(relID)
+	assert.Equal(t, 4, len(trees))
+	assert.Equal(t, child2, trees[0].Root)
+	assert.Equal(t, 4, trees[0].Entities)
+	assert.Equal(t, child1, trees[3].Root)
+
+	assert.PanicsWithValue(t, "not a relation component: ecs.Position", func() { w.TreeStats(posID) })
+	fmt.Print(trees[0].String())
+}
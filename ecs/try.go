@@ -0,0 +1,95 @@
+package ecs
+
+import "errors"
+
+// ErrWorldLocked is returned by the error-returning variants of world operations,
+// like [World.TryNewEntity] or [World.TryAdd], when they are called on a locked world.
+// Check for it using [errors.Is].
+//
+// The world is locked during [Query] iteration, and while a [View] is open.
+var ErrWorldLocked = errors.New("attempt to modify a locked world")
+
+// TryNewEntity is like [World.NewEntity], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Intended for frameworks that embed Arche and want to degrade gracefully.
+// Other panics of [World.NewEntity] are not affected.
+func (w *World) TryNewEntity(comps ...ID) (Entity, error) {
+	if w.IsLocked() {
+		return Entity{}, ErrWorldLocked
+	}
+	return w.NewEntity(comps...), nil
+}
+
+// TryNewEntityWith is like [World.NewEntityWith], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.NewEntityWith] are not affected.
+func (w *World) TryNewEntityWith(comps ...Component) (Entity, error) {
+	if w.IsLocked() {
+		return Entity{}, ErrWorldLocked
+	}
+	return w.NewEntityWith(comps...), nil
+}
+
+// TryRemoveEntity is like [World.RemoveEntity], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.RemoveEntity] are not affected.
+func (w *World) TryRemoveEntity(entity Entity) error {
+	if w.IsLocked() {
+		return ErrWorldLocked
+	}
+	w.RemoveEntity(entity)
+	return nil
+}
+
+// TryAdd is like [World.Add], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.Add] are not affected.
+func (w *World) TryAdd(entity Entity, comps ...ID) error {
+	return w.TryExchange(entity, comps, nil)
+}
+
+// TryAssign is like [World.Assign], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.Assign] are not affected.
+func (w *World) TryAssign(entity Entity, comps ...Component) error {
+	if w.IsLocked() {
+		return ErrWorldLocked
+	}
+	w.Assign(entity, comps...)
+	return nil
+}
+
+// TryRemove is like [World.Remove], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.Remove] are not affected.
+func (w *World) TryRemove(entity Entity, comps ...ID) error {
+	return w.TryExchange(entity, nil, comps)
+}
+
+// TryExchange is like [World.Exchange], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+//
+// Other panics of [World.Exchange] are not affected.
+func (w *World) TryExchange(entity Entity, add []ID, rem []ID) error {
+	if w.IsLocked() {
+		return ErrWorldLocked
+	}
+	w.Exchange(entity, add, rem)
+	return nil
+}
+
+// TryReset is like [World.Reset], but returns [ErrWorldLocked] instead of panicking
+// when called on a locked world.
+func (w *World) TryReset() error {
+	if w.IsLocked() {
+		return ErrWorldLocked
+	}
+	w.Reset()
+	return nil
+}
@@ -0,0 +1,55 @@
+package ecs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldTry(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	e1, err := w.TryNewEntity(posID)
+	assert.Nil(t, err)
+	assert.True(t, w.Has(e1, posID))
+
+	e2, err := w.TryNewEntityWith(Component{ID: posID, Comp: &Position{X: 1, Y: 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(e2, posID)))
+
+	assert.Nil(t, w.TryAdd(e1, velID))
+	assert.True(t, w.Has(e1, velID))
+	assert.Nil(t, w.TryRemove(e1, velID))
+	assert.False(t, w.Has(e1, velID))
+	assert.Nil(t, w.TryExchange(e1, []ID{velID}, []ID{posID}))
+	assert.Equal(t, []ID{velID}, w.Ids(e1))
+	assert.Nil(t, w.TryAssign(e1, Component{ID: posID, Comp: &Position{X: 3}}))
+	assert.Equal(t, 3, (*Position)(w.Get(e1, posID)).X)
+	assert.Nil(t, w.TryRemoveEntity(e2))
+	assert.False(t, w.Alive(e2))
+
+	query := w.Query(All())
+
+	_, err = w.TryNewEntity(posID)
+	assert.True(t, errors.Is(err, ErrWorldLocked))
+	_, err = w.TryNewEntityWith(Component{ID: posID, Comp: &Position{}})
+	assert.Equal(t, ErrWorldLocked, err)
+	assert.Equal(t, ErrWorldLocked, w.TryAdd(e1, velID))
+	assert.Equal(t, ErrWorldLocked, w.TryRemove(e1, velID))
+	assert.Equal(t, ErrWorldLocked, w.TryExchange(e1, nil, []ID{velID}))
+	assert.Equal(t, ErrWorldLocked, w.TryAssign(e1, Component{ID: posID, Comp: &Position{}}))
+	assert.Equal(t, ErrWorldLocked, w.TryRemoveEntity(e1))
+	assert.Equal(t, ErrWorldLocked, w.TryReset())
+	assert.Equal(t, 1, w.entityPool.Len())
+	assert.Equal(t, []ID{posID, velID}, w.Ids(e1))
+
+	query.Close()
+
+	assert.PanicsWithValue(t, "can't remove a dead entity", func() { _ = w.TryRemoveEntity(e2) })
+
+	assert.Nil(t, w.TryReset())
+	assert.Equal(t, 0, w.entityPool.Len())
+}
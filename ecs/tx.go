@@ -0,0 +1,164 @@
+package ecs
+
+import "reflect"
+
+// Tx is a transaction on a [World], for applying or discarding a sequence of changes atomically.
+//
+// All changes to the world after [World.Begin] can be discarded with [Tx.Rollback],
+// which restores entities, components, relation targets, disabled states, sparse-set components (see [SparseSets]),
+// the world tick and resource values to their state when the transaction began.
+// [Tx.Commit] keeps the changes.
+//
+// Transactions are intended for editor tooling and speculative game logic, like validating a player action.
+// While a transaction is active, the world records its changes in a journal:
+// created and removed entities, added and removed components with their values,
+// and values overwritten by [World.Set], [World.SetField] and [SparseSets.Set].
+// A rollback reverts them in reverse order, so the cost of a transaction is proportional to the number of changes,
+// not to the size of the world. Only [World.Reset], [World.LoadEntities], [World.LoadSnapshot] and [World.ApplyDiff]
+// record a copy of the entire world (see [World.Clone]).
+//
+// Component values changed through pointers, e.g. from [World.Get] or a [Query], are not recorded
+// and not rolled back. Use [World.Set] for changes that should be rolled back.
+// Resource values are copied when the transaction begins. Pointers, slices and maps in components and resources
+// are shared with the recorded values, so changes through them are not rolled back.
+//
+// Registered components, resources and filters, as well as listeners, hooks, extensions, cascade policies and
+// other settings are not affected by a rollback, so that IDs and cached filters stay valid.
+// Rollback does not emit any events to the world's [Listener], and does not call remove hooks.
+// Thus, a [ResultCache] requires a manual [ResultCache.Invalidate] after a rollback.
+// Entities removed during the transaction are alive again after a rollback, with their previous generation.
+// Entities created during the transaction must not be used after a rollback, as their IDs may not exist anymore.
+// The order of entities in archetypes may differ from the order before the transaction,
+// and entity lifetime statistics are not rolled back.
+// Entities reserved with [World.Reserve] and not yet realized are dropped.
+//
+// Create a transaction with [World.Begin].
+//
+// Example:
+//
+//	tx := world.Begin()
+//	// Apply the player action...
+//	if !valid {
+//		tx.Rollback()
+//	} else {
+//		tx.Commit()
+//	}
+type Tx struct {
+	world     *World
+	journal   *journal
+	resources []any // Resources when the transaction began.
+	values    []any // Copies of the resource values when the transaction began.
+}
+
+// Begin begins a transaction, and returns it.
+// See [Tx] for details.
+//
+// Transactions can be nested. Rolling back a transaction restores the state when it began,
+// regardless of other transactions committed or rolled back in the meantime.
+//
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (w *World) Begin() *Tx {
+	w.checkLocked()
+	return &Tx{
+		world:     w,
+		journal:   w.beginJournal(),
+		resources: append([]any(nil), w.resources.resources...),
+		values:    w.resourceValues(),
+	}
+}
+
+// Commit commits the transaction, keeping all changes since [World.Begin].
+//
+// Panics if the transaction is already committed or rolled back.
+func (tx *Tx) Commit() {
+	tx.finish()
+}
+
+// Rollback discards all changes since [World.Begin], restoring the world to its state when the transaction began.
+// See [Tx] for details.
+//
+// Panics if the transaction is already committed or rolled back.
+// Panics when called on a locked world.
+// Do not use during [Query] iteration!
+func (tx *Tx) Rollback() {
+	w := tx.world
+	w.checkLocked()
+	journal, resources, values := tx.journal, tx.resources, tx.values
+	tx.finish()
+	w.revert(journal, 0)
+	w.restoreResources(resources, values)
+}
+
+// finish marks the transaction as finished, and stops recording changes.
+func (tx *Tx) finish() {
+	if tx.journal == nil {
+		panic("transaction is already finished")
+	}
+	tx.world.endJournal(tx.journal)
+	tx.journal = nil
+	tx.resources = nil
+	tx.values = nil
+}
+
+// restore moves the entity and component state of a clone created by [World.Clone] into the world.
+// Resources are restored to the given pointers, with values copied from the clone where present.
+func (w *World) restore(c *World, resources []any) {
+	w.entities = c.entities
+	w.targetEntities = c.targetEntities
+	w.entityPool = c.entityPool
+	w.archetypes = c.archetypes
+	w.archetypeData = c.archetypeData
+	w.nodes = c.nodes
+	w.nodeData = c.nodeData
+	w.nodePointers = c.nodePointers
+	w.relationNodes = c.relationNodes
+	w.tick = c.tick
+	w.disabled = c.disabled
+	w.disabledCount = c.disabledCount
+	w.cascadeTargets = c.cascadeTargets
+	w.sparse = c.sparse
+	w.lifetimes = c.lifetimes
+	w.changes = c.changes
+	w.quotas = c.quotas
+
+	numNodes := w.nodeData.Len()
+	var i int32
+	for i = 0; i < numNodes; i++ {
+		w.nodeData.Get(i).memory = w.memory
+	}
+	w.memory.Add(c.memory.total - w.memory.total)
+
+	for i := range w.filterCache.filters {
+		e := &w.filterCache.filters[i]
+		e.Archetypes = pointers[archetype]{w.getArchetypes(e.Filter)}
+		if e.Indices != nil {
+			w.filterCache.mapArchetypes(e)
+		}
+	}
+
+	w.restoreResources(resources, c.resources.resources)
+}
+
+// resourceValues returns copies of the values of all resources.
+func (w *World) resourceValues() []any {
+	values := make([]any, len(w.resources.resources))
+	for i, res := range w.resources.resources {
+		if res != nil {
+			values[i] = cloneResource(res)
+		}
+	}
+	return values
+}
+
+// restoreResources restores resources to the given pointers, with values copied from saved resources where present.
+func (w *World) restoreResources(resources []any, saved []any) {
+	for i, res := range resources {
+		if res != nil && saved[i] != nil {
+			if value := reflect.ValueOf(res); value.Kind() == reflect.Pointer && value.Type() == reflect.TypeOf(saved[i]) {
+				value.Elem().Set(reflect.ValueOf(saved[i]).Elem())
+			}
+		}
+		w.resources.resources[i] = res
+	}
+}
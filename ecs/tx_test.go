@@ -0,0 +1,306 @@
+package ecs
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldTxRollback(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	statusID := SparseComponentID[statusEffect](&w)
+	resID := AddResource(&w, &Position{X: 1})
+	res := w.Resources().Get(resID).(*Position)
+
+	filter := All(posID)
+	cached := w.Cache().Register(&filter)
+
+	target := w.NewEntity(posID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := w.NewEntity(relID)
+	w.Relations().Set(e2, relID, target)
+	(*Position)(w.Get(e1, posID)).X = 10
+	(*statusEffect)(w.SparseSets().Add(e1, statusID)).Remaining = 3
+
+	tx := w.Begin()
+
+	w.NewEntity(posID)
+	w.RemoveEntity(e1)
+	w.Remove(target, posID)
+	target2 := w.NewEntity()
+	w.Relations().Set(e2, relID, target2)
+	res.X = 2
+	w.NewEntity(posID, relID)
+	_ = ComponentID[label](&w)
+
+	tx.Rollback()
+
+	assert.True(t, w.Alive(e1))
+	assert.True(t, w.Alive(target))
+	assert.Equal(t, 3, w.entityPool.Len())
+	assert.Equal(t, 4, len(w.entityPool.entities))
+	assert.True(t, w.Has(target, posID))
+	assert.Equal(t, 10, (*Position)(w.Get(e1, posID)).X)
+	assert.Equal(t, target, w.Relations().Get(e2, relID))
+	assert.Equal(t, 3, (*statusEffect)(w.SparseSets().Get(e1, statusID)).Remaining)
+	assert.Equal(t, 1, res.X)
+	assert.Same(t, res, w.Resources().Get(resID))
+	indexMemory := cap(w.entities)*int(entityIndexSize) + w.entityPool.TotalCap()*int(entitySize)
+	assert.Equal(t, w.Stats().Memory-indexMemory, w.ComponentMemory())
+
+	query := w.Query(&cached)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	relFilter := NewRelationFilter(All(relID), target)
+	query = w.Query(&relFilter)
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	e4 := w.NewEntity(posID, velID)
+	assert.Equal(t, Entity{id: 4, gen: 0}, e4)
+	assert.True(t, w.Has(e4, velID))
+
+	assert.PanicsWithValue(t, "transaction is already finished", func() { tx.Rollback() })
+	assert.PanicsWithValue(t, "transaction is already finished", func() { tx.Commit() })
+}
+
+func TestWorldTxCommit(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	tx := w.Begin()
+	e := w.NewEntity(posID)
+	tx.Commit()
+
+	assert.True(t, w.Alive(e))
+	assert.PanicsWithValue(t, "transaction is already finished", func() { tx.Rollback() })
+
+	query := w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Begin() })
+	tx = func() *Tx { query.Close(); return w.Begin() }()
+
+	query = w.Query(All())
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { tx.Rollback() })
+	query.Close()
+	tx.Rollback()
+	assert.True(t, w.Alive(e))
+}
+
+func TestWorldTxNested(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	outer := w.Begin()
+	e1 := w.NewEntity(posID)
+	inner := w.Begin()
+	e2 := w.NewEntity(posID)
+	inner.Rollback()
+
+	assert.True(t, w.Alive(e1))
+	assert.Equal(t, e2, w.NewEntity(posID))
+
+	outer.Rollback()
+	assert.Equal(t, 0, w.entityPool.Len())
+}
+
+func TestWorldTxRollbackRandom(t *testing.T) {
+	configs := []Config{
+		NewConfig(),
+		NewConfig().WithStableOrder(true),
+		NewConfig().WithRemovalGracePeriod(2),
+	}
+	for c, config := range configs {
+		for seed := int64(0); seed < 20; seed++ {
+			w := NewWorld(config)
+			posID := ComponentID[Position](&w)
+			velID := ComponentID[Velocity](&w)
+			relID := ComponentID[testRelationA](&w)
+			statusID := SparseComponentID[statusEffect](&w)
+			if seed%2 == 1 {
+				w.SetCascade(relID, CascadeRemoveEntity)
+			}
+			rng := rand.New(rand.NewSource(seed))
+
+			txRandomOps(&w, rng, 50, posID, velID, relID, statusID)
+			before := captureTxState(&w, statusID)
+
+			outer := w.Begin()
+			txRandomOps(&w, rng, 50, posID, velID, relID, statusID)
+			middle := captureTxState(&w, statusID)
+
+			inner := w.Begin()
+			txRandomOps(&w, rng, 50, posID, velID, relID, statusID)
+			if seed%3 == 0 {
+				inner.Rollback()
+				assert.Equal(t, middle, captureTxState(&w, statusID), "config %d, seed %d", c, seed)
+			} else {
+				inner.Commit()
+			}
+			txRandomOps(&w, rng, 20, posID, velID, relID, statusID)
+			outer.Rollback()
+
+			assert.Equal(t, before, captureTxState(&w, statusID), "config %d, seed %d", c, seed)
+			assert.Empty(t, w.journals)
+			assert.Empty(t, w.verifyInvariants(), "config %d, seed %d", c, seed)
+			txRandomOps(&w, rng, 50, posID, velID, relID, statusID)
+		}
+	}
+}
+
+// txState captures the state of a world that is restored by a rollback.
+type txState struct {
+	hash      uint64
+	pool      []Entity
+	next      eid
+	available uint32
+	disabled  []Entity
+	status    map[Entity]int
+	tick      uint64
+}
+
+func captureTxState(w *World, statusID SparseID) txState {
+	st := txState{
+		hash:      w.Hash(),
+		pool:      append([]Entity(nil), w.entityPool.entities...),
+		next:      w.entityPool.next,
+		available: w.entityPool.available,
+		status:    map[Entity]int{},
+		tick:      w.tick,
+	}
+	for _, e := range txAlive(w) {
+		if w.IsDisabled(e) {
+			st.disabled = append(st.disabled, e)
+		}
+	}
+	for _, arch := range w.getArchetypes(All()) {
+		var disabled uint32
+		var i uint32
+		for i = 0; i < arch.Len(); i++ {
+			if w.isDisabled(arch.GetEntity(i).id) {
+				disabled++
+			}
+		}
+		if disabled != arch.disabled {
+			panic("inconsistent disabled count")
+		}
+	}
+	for _, e := range w.SparseSets().Entities(statusID) {
+		st.status[e] = (*statusEffect)(w.SparseSets().Get(e, statusID)).Remaining
+	}
+	return st
+}
+
+// txAlive returns all alive entities, in the order of their IDs.
+func txAlive(w *World) []Entity {
+	alive := []Entity{}
+	for id := 1; id < len(w.entityPool.entities); id++ {
+		if e := w.entityPool.entities[id]; e.id == eid(id) {
+			alive = append(alive, e)
+		}
+	}
+	return alive
+}
+
+// txRandomOps applies random changes to a world.
+func txRandomOps(w *World, rng *rand.Rand, count int, posID, velID, relID ID, statusID SparseID) {
+	for i := 0; i < count; i++ {
+		alive := txAlive(w)
+		if len(alive) == 0 || rng.Intn(5) == 0 {
+			e := w.NewEntity(posID)
+			if rng.Intn(2) == 0 {
+				w.Add(e, velID)
+			}
+			continue
+		}
+		e := alive[rng.Intn(len(alive))]
+		dying := w.hasDying && w.Has(e, w.dyingID)
+		switch rng.Intn(12) {
+		case 0:
+			if !dying {
+				w.RemoveEntity(e)
+			}
+		case 1:
+			if w.Has(e, velID) {
+				w.Remove(e, velID)
+			} else {
+				w.Add(e, velID)
+			}
+		case 2:
+			if w.Has(e, posID) {
+				w.Set(e, posID, &Position{X: rng.Intn(100), Y: rng.Intn(100)})
+			}
+		case 3:
+			target := alive[rng.Intn(len(alive))]
+			if target == e {
+				break
+			}
+			if !w.Has(e, relID) {
+				w.Add(e, relID)
+			}
+			w.Relations().Set(e, relID, target)
+		case 4:
+			if w.IsDisabled(e) {
+				w.Enable(e)
+			} else {
+				w.Disable(e)
+			}
+		case 5:
+			if w.SparseSets().Has(e, statusID) && rng.Intn(2) == 0 {
+				w.SparseSets().Remove(e, statusID)
+			} else {
+				w.SparseSets().Set(e, statusID, &statusEffect{Remaining: rng.Intn(10)})
+			}
+		case 6:
+			if rng.Intn(2) == 0 {
+				filter := All(posID).Without(velID)
+				w.Batch().Add(&filter, velID)
+			} else {
+				w.Batch().Remove(All(velID), velID)
+			}
+		case 7:
+			w.Tick()
+		case 8:
+			NewBuilder(w, posID, velID).NewBatch(rng.Intn(5) + 1)
+		case 9:
+			if rng.Intn(4) == 0 {
+				filter := All(velID).Without(posID)
+				w.Batch().RemoveEntities(&filter)
+			}
+		case 10:
+			w.Batch().SetRelation(All(relID), relID, Entity{})
+		case 11:
+			if w.Has(e, posID) {
+				if err := w.SetField(e, posID, "X", rng.Intn(100)); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+}
+
+func TestWorldTxRollbackReset(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	statusID := SparseComponentID[statusEffect](&w)
+	resID := AddResource(&w, &Position{X: 1})
+
+	e := w.NewEntity(posID)
+	w.Set(e, posID, &Position{X: 5})
+	w.SparseSets().Set(e, statusID, &statusEffect{Remaining: 2})
+	before := captureTxState(&w, statusID)
+
+	tx := w.Begin()
+	w.Reset()
+	w.NewEntity()
+	w.SparseSets().Clear(statusID)
+	tx.Rollback()
+
+	assert.Equal(t, before, captureTxState(&w, statusID))
+	assert.Equal(t, 1, w.Resources().Get(resID).(*Position).X)
+	assert.Empty(t, w.verifyInvariants())
+}
@@ -2,9 +2,6 @@ package ecs
 
 import "reflect"
 
-// Eid is the entity identifier/index type.
-type eid uint32
-
 // ID is the component identifier type.
 type ID struct {
 	id uint8
@@ -19,6 +16,11 @@ type ResID struct {
 	id uint8
 }
 
+// SparseID is the identifier type for components in sparse-set storage. See [SparseSets].
+type SparseID struct {
+	id uint8
+}
+
 // Component is a component ID/pointer pair.
 //
 // It is a helper for [World.Assign], [World.NewEntityWith] and [NewBuilderWith].
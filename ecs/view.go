@@ -0,0 +1,150 @@
+package ecs
+
+import "unsafe"
+
+// View is a read-only handle to a [World], for concurrent access from multiple goroutines.
+//
+// While a view is open, the world is locked, like during query iteration.
+// Structural changes like creating and removing entities, or adding and removing components, panic.
+// The view itself provides only read access, so it can be shared by goroutines without synchronization.
+//
+// Component values accessed through a view must not be modified while other goroutines read them,
+// neither through the view nor through the world. Sparse-set components are not locked by views,
+// and must not be changed while a view is open either.
+//
+// Create a view with [World.View], and close it with [View.Close] after all goroutines have finished using it.
+//
+// Example:
+//
+//	view := world.View()
+//
+//	var wg sync.WaitGroup
+//	for i := 0; i < 4; i++ {
+//		wg.Add(1)
+//		go func() {
+//			defer wg.Done()
+//			query := view.Query(filter)
+//			for query.Next() {
+//				// ...
+//			}
+//		}()
+//	}
+//	wg.Wait()
+//
+//	view.Close()
+type View struct {
+	world   *World
+	lockBit uint8
+	closed  bool
+}
+
+// View creates a read-only [View] of the world, for concurrent access from multiple goroutines.
+//
+// Locks the world until [View.Close] is called.
+// Counts towards the limit of [MaskTotalBits] (256) simultaneous locks, like queries.
+func (w *World) View() *View {
+	return &View{
+		world:   w,
+		lockBit: w.lock(),
+	}
+}
+
+// Close closes the view and unlocks the world.
+//
+// Must be called only after all goroutines have finished using the view,
+// and from the goroutine that owns the world.
+//
+// Panics if the view is already closed.
+func (v *View) Close() {
+	if v.closed {
+		panic("view is already closed")
+	}
+	v.closed = true
+	w := v.world
+	w.unlock(v.lockBit)
+
+	if !w.IsLocked() && w.hasReserved() {
+		w.realizeReserved()
+	}
+	if w.commands != nil && w.commands.Len() > 0 && !w.IsLocked() {
+		w.commands.Flush()
+	}
+}
+
+// Alive reports whether an entity is still alive.
+func (v *View) Alive(entity Entity) bool {
+	return v.world.Alive(entity)
+}
+
+// Get returns a pointer to the given component of an [Entity].
+// Returns nil if the entity has no such component.
+//
+// The pointer must only be used for reading.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (v *View) Get(entity Entity, comp ID) unsafe.Pointer {
+	return v.world.Get(entity, comp)
+}
+
+// Has returns whether an [Entity] has a given component.
+//
+// Panics when called for a removed (and potentially recycled) entity.
+func (v *View) Has(entity Entity, comp ID) bool {
+	return v.world.Has(entity, comp)
+}
+
+// Mask returns the archetype [Mask] for the given [Entity].
+func (v *View) Mask(entity Entity) Mask {
+	return v.world.Mask(entity)
+}
+
+// Ids returns the component IDs for the archetype of the given [Entity].
+//
+// Returns a copy of the archetype's component IDs slice.
+func (v *View) Ids(entity Entity) []ID {
+	return v.world.Ids(entity)
+}
+
+// GetRelation returns the target entity for an entity relation.
+//
+// Panics:
+//   - when called for a removed (and potentially recycled) entity.
+//   - when called for a missing component.
+//   - when called for a component that is not a relation.
+func (v *View) GetRelation(entity Entity, comp ID) Entity {
+	return v.world.getRelation(entity, comp)
+}
+
+// Resource returns a pointer to the given resource.
+// Returns nil if there is no such resource.
+//
+// The resource must only be used for reading.
+func (v *View) Resource(id ResID) interface{} {
+	return v.world.resources.Get(id)
+}
+
+// Query creates a [Query] iterator for reading.
+//
+// In contrast to [World.Query], the query does not lock the world, as the view already holds a lock.
+// Iteration statistics of labeled filters are not recorded.
+// Queries of a view can be created and iterated concurrently from multiple goroutines.
+//
+// Panics for filters with a [ChangeFilter], as these modify the world's change tracking state.
+func (v *View) Query(filter Filter) Query {
+	w := v.world
+	var query Query
+	if cached, ok := filter.(*CachedFilter); ok {
+		if changeFilterOf(cached.filter) != nil {
+			panic("can't use change filters in a view")
+		}
+		entry := w.filterCache.get(cached)
+		query = newCachedQuery(w, cached.filter, v.lockBit, entry.Archetypes.pointers)
+	} else {
+		if changeFilterOf(filter) != nil {
+			panic("can't use change filters in a view")
+		}
+		query = newQuery(w, filter, v.lockBit, w.nodePointers)
+	}
+	query.isView = true
+	return query
+}
@@ -0,0 +1,125 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldView(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+	relID := ComponentID[testRelationA](&w)
+	resID := AddResource(&w, &Position{X: 5})
+
+	target := w.NewEntity(posID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := w.NewEntity(relID)
+	w.Relations().Set(e2, relID, target)
+	(*Position)(w.Get(e1, posID)).X = 10
+
+	view := w.View()
+	assert.True(t, w.IsLocked())
+
+	assert.True(t, view.Alive(e1))
+	assert.True(t, view.Has(e1, velID))
+	assert.False(t, view.Has(target, velID))
+	assert.Equal(t, 10, (*Position)(view.Get(e1, posID)).X)
+	assert.Equal(t, All(posID, velID), view.Mask(e1))
+	assert.Equal(t, []ID{posID, velID}, view.Ids(e1))
+	assert.Equal(t, target, view.GetRelation(e2, relID))
+	assert.Equal(t, &Position{X: 5}, view.Resource(resID))
+
+	query := view.Query(All(posID))
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+	assert.True(t, w.IsLocked())
+
+	filter := All(posID)
+	cached := w.Cache().Register(filter)
+	query = view.Query(&cached)
+	cnt := 0
+	for query.Next() {
+		cnt++
+	}
+	assert.Equal(t, 2, cnt)
+	assert.True(t, w.IsLocked())
+
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.NewEntity(posID) })
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.Add(target, velID) })
+	assert.PanicsWithValue(t, "attempt to modify a locked world", func() { w.RemoveEntity(e1) })
+	assert.PanicsWithValue(t, "can't use change filters in a view", func() {
+		view.Query(NewChangeFilter(All(posID)).Changed(posID))
+	})
+
+	worldQuery := w.Query(All(posID))
+	worldQuery.Close()
+	assert.True(t, w.IsLocked())
+
+	view.Close()
+	assert.False(t, w.IsLocked())
+	assert.PanicsWithValue(t, "view is already closed", func() { view.Close() })
+
+	w.NewEntity(posID)
+}
+
+func TestWorldViewFlush(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	view := w.View()
+	reserved := w.Reserve(2)
+	view.Close()
+
+	assert.True(t, w.Alive(reserved[0]))
+	assert.True(t, w.Alive(reserved[1]))
+
+	w.NewEntity(posID)
+}
+
+func TestWorldViewConcurrent(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	entities := []Entity{}
+	for i := 0; i < 100; i++ {
+		e := w.NewEntity(posID)
+		if i%2 == 0 {
+			w.Add(e, velID)
+		}
+		(*Position)(w.Get(e, posID)).X = i
+		entities = append(entities, e)
+	}
+
+	view := w.View()
+
+	var wg sync.WaitGroup
+	sums := make([]int, 8)
+	counts := make([]int, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			query := view.Query(All(posID))
+			for query.Next() {
+				sums[g] += (*Position)(query.Get(posID)).X
+			}
+			for _, e := range entities {
+				if view.Has(e, velID) {
+					counts[g]++
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	view.Close()
+
+	for g := 0; g < 8; g++ {
+		assert.Equal(t, 4950, sums[g])
+		assert.Equal(t, 50, counts[g])
+	}
+	assert.False(t, w.IsLocked())
+}
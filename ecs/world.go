@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/mlange-42/arche/ecs/stats"
@@ -31,6 +32,34 @@ type World struct {
 	registry       componentRegistry         // Component registry.
 	filterCache    Cache                     // Cache for registered filters.
 	stats          stats.World               // Cached world statistics
+	extensions     []Extension               // Installed extensions.
+	tags           map[string]ID             // Component IDs of string-keyed tags.
+	tick           uint64                    // Current world tick. See [World.Tick].
+	dyingID        ID                        // Component ID for soft-deleted entities.
+	hasDying       bool                      // Whether soft-deletion is enabled.
+	archetypeSlots []archetypeSlot           // Registered archetype user data slots.
+	commands       *CommandBuffer            // Automatically flushed command buffer.
+	lifetimes      *lifetimeTracker          // Entity lifetime tracking. Nil if not enabled.
+	leaks          *leakTracker              // Leaked entity detection. Nil if not enabled. See [World.SetLeakDetection].
+	changes        *changeTracker            // Component change tracking. Nil if not enabled.
+	capacityHints  []uint32                  // Capacity increment hints by component ID. See [World.SetCapacityIncrement].
+	capacityHinted Mask                      // Components with capacity increment hints.
+	quotas         *quotaTracker             // Entity origins and quotas. Nil if not used.
+	metadata       []*ComponentMeta          // Field metadata by component ID, created on demand. See [ComponentMetadata].
+	eventSequence  uint64                    // Sequence number of the last notified event.
+	namespaces     map[string]Namespace      // Reserved component ID namespaces.
+	disabled       bitSet                    // Whether entities are disabled. See [World.Disable].
+	disabledCount  int                       // Number of disabled entities.
+	cascades       []Cascade                 // Policies for removed relation targets, by component ID. See [World.SetCascade].
+	cascadeTargets []Entity                  // Removed relation targets with pending cascade policies.
+	hashExcluded   Mask                      // Components excluded from [World.Hash].
+	removeHooks    []RemoveHook              // Hooks for removed components, by component ID. See [World.SetRemoveHook].
+	hookedRemove   Mask                      // Components with remove hooks.
+	sparse         sparseStorage             // Sparse-set component storage. See [World.SparseSets].
+	memory         *memoryTracker            // Memory of archetype storage. See [World.ComponentMemory].
+	profile        *profiler                 // Profiling context. Nil if not enabled. See [Config.Profiling].
+	journals       []*journal                // Active change journals of transactions and histories. See [World.Begin].
+	epoch          uint64                    // Counter of restores and replacements of the world's state. See [World.Epoch].
 }
 
 // NewWorld creates a new [World] from an optional [Config].
@@ -75,6 +104,7 @@ func (w *World) NewEntity(comps ...ID) Entity {
 	}
 
 	entity := w.createEntity(arch)
+	w.checkInvariants("NewEntity")
 
 	if w.listener != nil {
 		var newRel *ID
@@ -84,7 +114,7 @@ func (w *World) NewEntity(comps ...ID) Entity {
 		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
 		trigger := w.listener.Subscriptions() & bits
 		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
+			w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
 		}
 	}
 	return entity
@@ -124,6 +154,7 @@ func (w *World) NewEntityWith(comps ...Component) Entity {
 	for _, c := range comps {
 		w.copyTo(entity, c.ID, c.Comp)
 	}
+	w.checkInvariants("NewEntityWith")
 
 	if w.listener != nil {
 		var newRel *ID
@@ -133,7 +164,7 @@ func (w *World) NewEntityWith(comps ...Component) Entity {
 		bits := subscription(true, false, len(comps) > 0, false, newRel != nil, newRel != nil)
 		trigger := w.listener.Subscriptions() & bits
 		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, newRel) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
+			w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
 		}
 	}
 	return entity
@@ -141,6 +172,10 @@ func (w *World) NewEntityWith(comps ...Component) Entity {
 
 // RemoveEntity removes an [Entity], making it eligible for recycling.
 //
+// If soft-deletion is enabled via [Config.RemovalGracePeriod],
+// the entity is only marked as dying. See [World.Dying] for details.
+// Removing an entity that is already dying reclaims it immediately.
+//
 // Panics when called on a locked world or for an already removed entity.
 // Do not use during [Query] iteration!
 func (w *World) RemoveEntity(entity Entity) {
@@ -150,39 +185,63 @@ func (w *World) RemoveEntity(entity Entity) {
 		panic("can't remove a dead entity")
 	}
 
+	if w.hasDying {
+		w.markDying(entity)
+	} else {
+		w.removeEntity(entity)
+	}
+	if len(w.cascadeTargets) > 0 {
+		w.applyCascades()
+	}
+	w.checkInvariants("RemoveEntity")
+}
+
+// removeEntity removes an entity immediately, without soft-deletion.
+func (w *World) removeEntity(entity Entity) {
 	index := &w.entities[entity.id]
 	oldArch := index.arch
 
+	if w.hasRemoveHooks(&oldArch.Mask) {
+		lock := w.lock()
+		w.callRemoveHooks(oldArch, index.index, entity, &oldArch.Mask)
+		w.unlock(lock)
+	}
+
 	if w.listener != nil {
 		var oldRel *ID
 		if oldArch.HasRelationComponent {
 			oldRel = &oldArch.RelationComponent
 		}
-		var oldIds []ID
-		if len(oldArch.node.Ids) > 0 {
-			oldIds = oldArch.node.Ids
-		}
+		oldMask, oldIds := w.visibleComponents(oldArch)
 
 		bits := subscription(false, true, false, len(oldIds) > 0, oldRel != nil, oldRel != nil)
 		trigger := w.listener.Subscriptions() & bits
-		if trigger != 0 && subscribes(trigger, nil, &oldArch.Mask, w.listener.Components(), oldRel, nil) {
+		if trigger != 0 && subscribes(trigger, nil, &oldMask, w.listener.Components(), oldRel, nil) {
 			lock := w.lock()
-			w.listener.Notify(w, EntityEvent{Entity: entity, Removed: oldArch.Mask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: oldArch.RelationTarget, EventTypes: bits})
+			w.notify(EntityEvent{Entity: entity, Removed: oldMask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: oldArch.RelationTarget, EventTypes: bits})
 			w.unlock(lock)
 		}
 	}
 
-	swapped := oldArch.Remove(index.index)
+	if len(w.journals) > 0 {
+		w.recordRemove(entity, oldArch, index.index)
+	}
+	w.removeDisabled(entity.id, oldArch)
+	w.removeFromArchetype(oldArch, index.index)
 
 	w.entityPool.Recycle(entity)
-
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
+	if w.lifetimes != nil {
+		w.lifetimes.Remove(entity.id, w.tick)
 	}
+	if w.quotas != nil {
+		w.quotas.Remove(entity.id)
+	}
+	w.sparse.RemoveEntity(entity.id)
+
 	index.arch = nil
 
 	if w.targetEntities.Get(entity.id) {
+		w.queueCascade(entity)
 		w.cleanupArchetypes(entity)
 		w.targetEntities.Set(entity.id, false)
 	}
@@ -195,6 +254,21 @@ func (w *World) Alive(entity Entity) bool {
 	return w.entityPool.Alive(entity)
 }
 
+// EntityAt returns the alive entity with the given ID, including its current generation.
+// Returns false if there is no alive entity with that ID.
+//
+// Intended for debugging and tooling, where entities are identified by their ID only.
+func (w *World) EntityAt(id uint64) (Entity, bool) {
+	if id == 0 || id >= uint64(len(w.entityPool.entities)) {
+		return Entity{}, false
+	}
+	entity := w.entityPool.entities[id]
+	if uint64(entity.id) != id {
+		return Entity{}, false
+	}
+	return entity, true
+}
+
 // Get returns a pointer to the given component of an [Entity].
 // Returns nil if the entity has no such component.
 //
@@ -297,9 +371,16 @@ func (w *World) Assign(entity Entity, comps ...Component) {
 //   - if the entity does not have a component of that type.
 //   - when called on a locked world. Do not use during [Query] iteration!
 //
+// Notifies an [event.ComponentSet] event if the [Listener] subscribes to it.
+//
 // See also [github.com/mlange-42/arche/generic.Map.Set] for a generic variant.
 func (w *World) Set(entity Entity, id ID, comp interface{}) unsafe.Pointer {
-	return w.copyTo(entity, id, comp)
+	if len(w.journals) > 0 && w.Has(entity, id) {
+		w.recordSet(entity, id)
+	}
+	ptr := w.copyTo(entity, id, comp)
+	w.notifySet(entity, id)
+	return ptr
 }
 
 // Remove removes components from an entity.
@@ -331,6 +412,17 @@ func (w *World) Exchange(entity Entity, add []ID, rem []ID) {
 	w.exchange(entity, add, rem, ID{}, false, Entity{})
 }
 
+// Epoch returns a counter that is incremented whenever changes to the world are rolled back,
+// or its state is replaced as a whole.
+// This happens with [Tx.Rollback], [History.Undo] and [History.Redo],
+// and with [World.Reset], [World.LoadEntities], [World.LoadSnapshot] and [World.ApplyDiff].
+//
+// Caches of data derived from the world can use it to detect when they need to be rebuilt.
+// See [github.com/mlange-42/arche/generic.Shared] for an example.
+func (w *World) Epoch() uint64 {
+	return w.epoch
+}
+
 // Reset removes all entities and resources from the world.
 //
 // Does NOT free reserved memory, remove archetypes, clear the registry, clear cached filters, etc.
@@ -340,18 +432,43 @@ func (w *World) Exchange(entity Entity, add []ID, rem []ID) {
 // Accelerates re-populating the world by a factor of 2-3.
 func (w *World) Reset() {
 	w.checkLocked()
+	w.epoch++
+	if len(w.journals) > 0 {
+		w.recordState()
+	}
 
 	w.entities = w.entities[:1]
 	w.targetEntities.Reset()
+	w.disabled.Reset()
+	w.disabledCount = 0
+	w.cascadeTargets = w.cascadeTargets[:0]
 	w.entityPool.Reset()
 	w.locks.Reset()
 	w.resources.reset()
+	w.sparse.Reset()
+	w.tick = 0
+	if w.lifetimes != nil {
+		w.lifetimes.Reset()
+	}
+	if w.leaks != nil {
+		w.leaks.Reset()
+	}
+	if w.changes != nil {
+		w.changes.Reset()
+	}
+	if w.quotas != nil {
+		w.quotas.Reset()
+	}
+	if w.commands != nil {
+		w.commands.Reset()
+	}
 
 	len := w.nodes.Len()
 	var i int32
 	for i = 0; i < len; i++ {
 		w.nodes.Get(i).Reset(w.Cache())
 	}
+	w.checkInvariants("Reset")
 }
 
 // Query creates a [Query] iterator.
@@ -369,10 +486,30 @@ func (w *World) Reset() {
 func (w *World) Query(filter Filter) Query {
 	l := w.lock()
 	if cached, ok := filter.(*CachedFilter); ok {
-		return newCachedQuery(w, cached.filter, l, w.filterCache.get(cached).Archetypes.pointers)
+		entry := w.filterCache.get(cached)
+		query := newCachedQuery(w, cached.filter, l, entry.Archetypes.pointers)
+		if entry.Stats != nil {
+			entry.Stats.queries++
+			query.stats = entry.Stats
+			query.start = time.Now()
+		}
+		if cf := changeFilterOf(cached.filter); cf != nil {
+			w.initChangeQuery(&query, cf)
+		}
+		if w.profile != nil {
+			w.startQueryProfile(&query, filter)
+		}
+		return query
 	}
 
-	return newQuery(w, filter, l, w.nodePointers)
+	query := newQuery(w, filter, l, w.nodePointers)
+	if cf := changeFilterOf(filter); cf != nil {
+		w.initChangeQuery(&query, cf)
+	}
+	if w.profile != nil {
+		w.startQueryProfile(&query, filter)
+	}
+	return query
 }
 
 // Resources of the world.
@@ -406,6 +543,13 @@ func (w *World) Relations() *Relations {
 	return &Relations{world: w}
 }
 
+// SparseSets of the world.
+//
+// Provides access to components in sparse-set storage, see [SparseSets].
+func (w *World) SparseSets() *SparseSets {
+	return &SparseSets{world: w}
+}
+
 // IsLocked returns whether the world is locked by any queries.
 func (w *World) IsLocked() bool {
 	return w.locks.IsLocked()
@@ -452,9 +596,15 @@ func (w *World) Stats() *stats.World {
 		Recycled: w.entityPool.Available(),
 		Capacity: w.entityPool.TotalCap(),
 	}
+	if hiddenID, ok := w.hiddenID(); ok {
+		w.stats.Entities.Hidden = w.hiddenCount(hiddenID)
+	}
 
 	compCount := len(w.registry.Components)
-	types := append([]reflect.Type{}, w.registry.Types[:compCount]...)
+	types := make([]reflect.Type, 0, compCount)
+	for _, iid := range w.registry.IDs {
+		types = append(types, w.registry.Types[iid])
+	}
 
 	memory := cap(w.entities)*int(entityIndexSize) + w.entityPool.TotalCap()*int(entitySize)
 
@@ -485,8 +635,30 @@ func (w *World) Stats() *stats.World {
 	w.stats.Locked = w.IsLocked()
 	w.stats.Memory = memory
 	w.stats.CachedFilters = len(w.filterCache.filters)
+	w.stats.Queries = w.filterCache.stats(w.stats.Queries)
+	if w.lifetimes != nil {
+		if w.stats.Lifetimes == nil {
+			w.stats.Lifetimes = &stats.Lifetimes{}
+		}
+		w.lifetimes.Stats(w.stats.Lifetimes)
+	}
+	if w.leaks != nil {
+		if w.stats.Leaks == nil {
+			w.stats.Leaks = &stats.Leaks{}
+		}
+		w.leaks.Stats(w.stats.Leaks)
+	} else {
+		w.stats.Leaks = nil
+	}
 	w.stats.ActiveNodeCount = cntActive
 
+	w.stats.Systems = w.stats.Systems[:0]
+	for _, ext := range w.extensions {
+		if st, ok := ext.(StatsExtension); ok {
+			st.Stats(&w.stats)
+		}
+	}
+
 	return &w.stats
 }
 
@@ -497,9 +669,22 @@ func (w *World) Stats() *stats.World {
 func (w *World) DumpEntities() EntityDump {
 	alive := []uint32{}
 
-	query := w.Query(All())
-	for query.Next() {
-		alive = append(alive, uint32(query.Entity().id))
+	filters := []Filter{All()}
+	if w.hasDying {
+		filters = append(filters, w.Dying(All()))
+	}
+	if hiddenID, ok := w.hiddenID(); ok {
+		filters = append(filters, All(hiddenID))
+		if w.hasDying {
+			filters = append(filters, w.Dying(All(hiddenID)))
+		}
+	}
+	for _, filter := range filters {
+		query := w.Query(filter)
+		query.withDisabled = true
+		for query.Next() {
+			alive = append(alive, uint32(query.Entity().id))
+		}
 	}
 
 	data := EntityDump{
@@ -526,9 +711,13 @@ func (w *World) DumpEntities() EntityDump {
 func (w *World) LoadEntities(data *EntityDump) {
 	w.checkLocked()
 
-	if len(w.entityPool.entities) > 1 || w.entityPool.available > 0 {
+	if len(w.entityPool.entities) > 1 || w.entityPool.available > 0 || w.hasReserved() {
 		panic("can set entity data only on a fresh or reset world")
 	}
+	w.epoch++
+	if len(w.journals) > 0 {
+		w.recordState()
+	}
 
 	capacity := capacity(len(data.Entities), w.config.CapacityIncrement)
 
@@ -538,6 +727,7 @@ func (w *World) LoadEntities(data *EntityDump) {
 	w.entityPool.entities = entities
 	w.entityPool.next = eid(data.Next)
 	w.entityPool.available = data.Available
+	w.entityPool.DropReserved()
 
 	w.entities = make([]entityIndex, len(data.Entities), capacity)
 	w.targetEntities = bitSet{}
@@ -548,5 +738,12 @@ func (w *World) LoadEntities(data *EntityDump) {
 		entity := w.entityPool.entities[idx]
 		archIdx := arch.Alloc(entity)
 		w.entities[entity.id] = entityIndex{arch: arch, index: archIdx}
+		if w.lifetimes != nil {
+			w.lifetimes.Load(entity.id, w.tick)
+		}
+		if w.changes != nil {
+			w.changes.Create(entity.id, &arch.Mask)
+		}
 	}
+	w.checkInvariants("LoadEntities")
 }
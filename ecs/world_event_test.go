@@ -26,6 +26,7 @@ func TestWorldListener(t *testing.T) {
 	assert.Equal(t, EntityEvent{
 		Entity:     e0,
 		EventTypes: event.EntityCreated,
+		Sequence:   1,
 	}, events[len(events)-1])
 
 	w.RemoveEntity(e0)
@@ -33,6 +34,7 @@ func TestWorldListener(t *testing.T) {
 	assert.Equal(t, EntityEvent{
 		Entity:     e0,
 		EventTypes: event.EntityRemoved,
+		Sequence:   2,
 	}, events[len(events)-1])
 
 	e0 = w.NewEntity(posID, velID)
@@ -42,6 +44,7 @@ func TestWorldListener(t *testing.T) {
 		Added:      All(posID, velID),
 		AddedIDs:   []ID{posID, velID},
 		EventTypes: event.EntityCreated | event.ComponentAdded,
+		Sequence:   3,
 	}, events[len(events)-1])
 
 	w.RemoveEntity(e0)
@@ -51,6 +54,7 @@ func TestWorldListener(t *testing.T) {
 		Removed:    All(posID, velID),
 		RemovedIDs: []ID{posID, velID},
 		EventTypes: event.EntityRemoved | event.ComponentRemoved,
+		Sequence:   4,
 	}, events[len(events)-1])
 
 	e0 = w.NewEntityWith(Component{posID, &Position{}}, Component{velID, &Velocity{}}, Component{relID, &relationComp{}})
@@ -61,6 +65,7 @@ func TestWorldListener(t *testing.T) {
 		AddedIDs:    []ID{posID, velID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    5,
 	}, events[len(events)-1])
 
 	w.Add(e0, rotID)
@@ -72,6 +77,7 @@ func TestWorldListener(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: &relID,
 		EventTypes:  event.ComponentAdded,
+		Sequence:    6,
 	}, events[len(events)-1])
 
 	w.Remove(e0, posID)
@@ -83,6 +89,7 @@ func TestWorldListener(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: &relID,
 		EventTypes:  event.ComponentRemoved,
+		Sequence:    7,
 	}, events[len(events)-1])
 
 	e1 := w.NewEntity(posID)
@@ -93,6 +100,7 @@ func TestWorldListener(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: &relID,
 		EventTypes:  event.TargetChanged,
+		Sequence:    9,
 	}, events[len(events)-1])
 
 	w.Remove(e0, relID)
@@ -105,6 +113,7 @@ func TestWorldListener(t *testing.T) {
 		NewRelation: nil,
 		OldTarget:   e1,
 		EventTypes:  event.ComponentRemoved | event.RelationChanged | event.TargetChanged,
+		Sequence:    10,
 	}, events[len(events)-1])
 
 }
@@ -133,6 +142,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    11,
 	}, events[len(events)-1])
 
 	query := builder.NewBatchQ(10)
@@ -145,6 +155,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    21,
 	}, events[len(events)-1])
 
 	builder.NewBatch(10, parent)
@@ -156,6 +167,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    31,
 	}, events[len(events)-1])
 
 	query = builder.NewBatchQ(10, parent)
@@ -168,6 +180,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    41,
 	}, events[len(events)-1])
 
 	builder = NewBuilderWith(&w,
@@ -184,6 +197,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    51,
 	}, events[len(events)-1])
 
 	query = builder.NewBatchQ(10)
@@ -196,6 +210,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    61,
 	}, events[len(events)-1])
 
 	builder.NewBatch(10, parent)
@@ -207,6 +222,7 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    71,
 	}, events[len(events)-1])
 
 	query = builder.NewBatchQ(10, parent)
@@ -219,5 +235,73 @@ func TestWorldListenerBuilder(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    81,
 	}, events[len(events)-1])
 }
+
+func TestWorldEventSequence(t *testing.T) {
+	w := NewWorld()
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+	listener.Subscribe = event.EntityCreated | event.EntityRemoved
+	w.SetListener(&listener)
+
+	posID := ComponentID[Position](&w)
+
+	e0 := w.NewEntity()
+	w.Add(e0, posID)
+	w.Remove(e0, posID)
+	NewBuilder(&w, posID).NewBatch(5)
+	w.RemoveEntity(e0)
+
+	assert.Equal(t, 7, len(events))
+	for i, e := range events {
+		assert.Equal(t, uint64(i+1), e.Sequence)
+	}
+
+	w.Reset()
+	w.NewEntity()
+	assert.Equal(t, uint64(8), events[len(events)-1].Sequence)
+}
+
+func TestWorldListenerSet(t *testing.T) {
+	w := NewWorld()
+
+	events := []EntityEvent{}
+	listener := newTestListener(func(world *World, e EntityEvent) {
+		events = append(events, e)
+	})
+	w.SetListener(&listener)
+
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	e0 := w.NewEntity(posID, velID)
+	e1 := w.NewEntity(posID)
+	events = events[:0]
+
+	w.Set(e0, posID, &Position{X: 1})
+	assert.Equal(t, 0, len(events))
+
+	listener.Subscribe |= event.ComponentSet
+	w.Set(e0, posID, &Position{X: 2})
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, EntityEvent{
+		Entity:     e0,
+		SetID:      &posID,
+		EventTypes: event.ComponentSet,
+		Sequence:   3,
+	}, events[0])
+
+	cnt := Apply(w.Batch(), All(posID), func(pos *Position) { pos.X++ })
+	assert.Equal(t, 2, cnt)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, []Entity{e1, e0}, []Entity{events[1].Entity, events[2].Entity})
+	assert.Equal(t, posID, *events[2].SetID)
+	assert.Equal(t, 3, (*Position)(w.Get(e0, posID)).X)
+
+	assert.Equal(t, event.Subscription(0), event.All&event.ComponentSet)
+}
@@ -1,8 +1,10 @@
 package ecs
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/mlange-42/arche/ecs/event"
@@ -16,6 +18,12 @@ func fromConfig(conf Config) World {
 	if conf.RelationCapacityIncrement < 1 {
 		conf.RelationCapacityIncrement = conf.CapacityIncrement
 	}
+	if conf.RemovalGracePeriod < 0 {
+		panic("invalid RemovalGracePeriod in config, must be >= 0")
+	}
+	if conf.IdleMaintenanceRuns < 0 {
+		panic("invalid IdleMaintenanceRuns in config, must be >= 0")
+	}
 	entities := make([]entityIndex, 1, conf.CapacityIncrement)
 	entities[0] = entityIndex{arch: nil, index: 0}
 	targetEntities := bitSet{}
@@ -34,10 +42,23 @@ func fromConfig(conf Config) World {
 		locks:          lockMask{},
 		listener:       nil,
 		resources:      newResources(),
+		sparse:         newSparseStorage(),
+		memory:         &memoryTracker{},
 		filterCache:    newCache(),
 	}
+	w.filterCache.stableOrder = conf.StableOrder
 	node := w.createArchetypeNode(Mask{}, ID{}, false)
 	w.createArchetype(node, Entity{}, false)
+	if conf.EntityLifetimes {
+		w.lifetimes = &lifetimeTracker{}
+	}
+	if conf.Profiling {
+		w.profile = &profiler{ctx: context.Background()}
+	}
+	if conf.RemovalGracePeriod > 0 {
+		w.dyingID = ComponentID[dying](&w)
+		w.hasDying = true
+	}
 	return w
 }
 
@@ -66,7 +87,7 @@ func (w *World) newEntityTarget(targetID ID, target Entity, comps ...ID) Entity
 		bits := subscription(true, false, len(comps) > 0, false, true, true)
 		trigger := w.listener.Subscriptions() & bits
 		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: &targetID, EventTypes: bits})
+			w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: &targetID, EventTypes: bits})
 		}
 	}
 	return entity
@@ -103,7 +124,7 @@ func (w *World) newEntityTargetWith(targetID ID, target Entity, comps ...Compone
 		bits := subscription(true, false, len(comps) > 0, false, true, true)
 		trigger := w.listener.Subscriptions() & bits
 		if trigger != 0 && subscribes(trigger, &arch.Mask, nil, w.listener.Components(), nil, &targetID) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: &targetID, EventTypes: bits})
+			w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: &targetID, EventTypes: bits})
 		}
 	}
 	return entity
@@ -127,7 +148,7 @@ func (w *World) newEntities(count int, targetID ID, hasTarget bool, target Entit
 			for i = 0; i < cnt; i++ {
 				idx := startIdx + i
 				entity := arch.GetEntity(idx)
-				w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
+				w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: comps, NewRelation: newRel, EventTypes: bits})
 			}
 		}
 	}
@@ -172,7 +193,7 @@ func (w *World) newEntitiesWith(count int, targetID ID, hasTarget bool, target E
 			for i = 0; i < cnt; i++ {
 				idx := startIdx + i
 				entity := arch.GetEntity(idx)
-				w.listener.Notify(w, EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
+				w.notify(EntityEvent{Entity: entity, Added: arch.Mask, AddedIDs: ids, NewRelation: newRel, EventTypes: bits})
 			}
 		}
 	}
@@ -223,6 +244,7 @@ func (w *World) newEntitiesNoNotify(count int, targetID ID, hasTarget bool, targ
 
 	startIdx := arch.Len()
 	w.createEntities(arch, uint32(count))
+	w.checkInvariants("Batch.New")
 
 	return arch, startIdx
 }
@@ -267,13 +289,27 @@ func (w *World) newEntitiesWithNoNotify(count int, targetID ID, hasTarget bool,
 			w.copyTo(entity, c.ID, c.Comp)
 		}
 	}
+	w.checkInvariants("Batch.NewWith")
 
 	return arch, startIdx
 }
 
 // createEntity creates an Entity and adds it to the given archetype.
 func (w *World) createEntity(arch *archetype) Entity {
+	if w.hasReserved() {
+		w.realizeReserved()
+	}
+	fresh := w.entityPool.available == 0
 	entity := w.entityPool.Get()
+	w.placeEntity(arch, entity)
+	if len(w.journals) > 0 {
+		w.recordCreate(entity, fresh, arch)
+	}
+	return entity
+}
+
+// placeEntity adds a new entity from the entity pool to the given archetype.
+func (w *World) placeEntity(arch *archetype, entity Entity) {
 	idx := arch.Alloc(entity)
 	len := len(w.entities)
 	if int(entity.id) == len {
@@ -289,14 +325,23 @@ func (w *World) createEntity(arch *archetype) Entity {
 		w.entities[entity.id] = entityIndex{arch: arch, index: idx}
 		w.targetEntities.Set(entity.id, false)
 	}
-	return entity
+	if w.lifetimes != nil {
+		w.lifetimes.Create(entity.id, w.tick)
+	}
+	if w.changes != nil {
+		w.changes.Create(entity.id, &arch.Mask)
+	}
 }
 
 // createEntity creates multiple Entities and adds them to the given archetype.
 func (w *World) createEntities(arch *archetype, count uint32) {
+	if w.hasReserved() {
+		w.realizeReserved()
+	}
 	startIdx := arch.Len()
 	arch.AllocN(count)
 
+	journaling := len(w.journals) > 0
 	len := len(w.entities)
 	required := len + int(count) - w.entityPool.Available()
 	capacity := capacity(required, w.config.CapacityIncrement)
@@ -312,10 +357,20 @@ func (w *World) createEntities(arch *archetype, count uint32) {
 	var i uint32
 	for i = 0; i < count; i++ {
 		idx := startIdx + i
+		fresh := w.entityPool.available == 0
 		entity := w.entityPool.Get()
 		arch.SetEntity(idx, entity)
 		w.entities[entity.id] = entityIndex{arch: arch, index: idx}
 		w.targetEntities.Set(entity.id, false)
+		if w.lifetimes != nil {
+			w.lifetimes.Create(entity.id, w.tick)
+		}
+		if w.changes != nil {
+			w.changes.Create(entity.id, &arch.Mask)
+		}
+		if journaling {
+			w.recordCreate(entity, fresh, arch)
+		}
 	}
 }
 
@@ -328,6 +383,10 @@ func (w *World) createEntities(arch *archetype, count uint32) {
 func (w *World) removeEntities(filter Filter) int {
 	w.checkLocked()
 
+	if w.hasDying && !isDyingFilter(filter) {
+		return w.markDyingBatch(filter)
+	}
+
 	lock := w.lock()
 
 	var bits event.Subscription
@@ -346,42 +405,59 @@ func (w *World) removeEntities(filter Filter) int {
 		}
 
 		count += ln
+		w.callRemoveHooksArch(arch, &arch.Mask)
 
 		var oldRel *ID
 		var oldIds []ID
+		var oldMask Mask
 		if w.listener != nil {
 			if arch.HasRelationComponent {
 				oldRel = &arch.RelationComponent
 			}
-			if len(arch.node.Ids) > 0 {
-				oldIds = arch.node.Ids
-			}
+			oldMask, oldIds = w.visibleComponents(arch)
 			bits = subscription(false, true, false, len(oldIds) > 0, oldRel != nil, oldRel != nil)
 			trigger := w.listener.Subscriptions() & bits
-			listen = trigger != 0 && subscribes(trigger, nil, &arch.Mask, w.listener.Components(), oldRel, nil)
+			listen = trigger != 0 && subscribes(trigger, nil, &oldMask, w.listener.Components(), oldRel, nil)
 		}
 
 		var j uint32
 		for j = 0; j < ln; j++ {
 			entity := arch.GetEntity(j)
 			if listen {
-				w.listener.Notify(w, EntityEvent{Entity: entity, Removed: arch.Mask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: arch.RelationTarget, EventTypes: bits})
+				w.notify(EntityEvent{Entity: entity, Removed: oldMask, RemovedIDs: oldIds, OldRelation: oldRel, OldTarget: arch.RelationTarget, EventTypes: bits})
+			}
+			if len(w.journals) > 0 {
+				w.recordRemove(entity, arch, j)
 			}
 			index := &w.entities[entity.id]
 			index.arch = nil
+			w.removeDisabled(entity.id, nil)
 
 			if w.targetEntities.Get(entity.id) {
+				w.queueCascade(entity)
 				w.cleanupArchetypes(entity)
 				w.targetEntities.Set(entity.id, false)
 			}
 
 			w.entityPool.Recycle(entity)
+			if w.lifetimes != nil {
+				w.lifetimes.Remove(entity.id, w.tick)
+			}
+			if w.quotas != nil {
+				w.quotas.Remove(entity.id)
+			}
+			w.sparse.RemoveEntity(entity.id)
 		}
 		arch.Reset()
 		w.cleanupArchetype(arch)
 	}
 	w.unlock(lock)
 
+	if len(w.cascadeTargets) > 0 {
+		w.applyCascades()
+	}
+	w.checkInvariants("Batch.RemoveEntities")
+
 	return int(count)
 }
 
@@ -449,25 +525,36 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relation ID, hasRela
 		}
 	}
 
-	oldIDs := oldArch.Components()
+	if len(rem) > 0 && !w.hookedRemove.IsZero() {
+		kept := mask.Not()
+		removed := oldMask.And(&kept)
+		if w.hasRemoveHooks(&removed) {
+			lock := w.lock()
+			w.callRemoveHooks(oldArch, index.index, entity, &removed)
+			w.unlock(lock)
+		}
+	}
 
 	arch := w.findOrCreateArchetype(oldArch, add, rem, target)
 	newIndex := arch.Alloc(entity)
 
-	for _, id := range oldIDs {
+	// Only components with data are copied. Adding or removing tags only moves the entity.
+	for _, id := range oldArch.node.dataIds {
 		if mask.Get(id) {
 			comp := oldArch.Get(index.index, id)
 			arch.SetPointer(newIndex, id, comp)
 		}
 	}
 
-	swapped := oldArch.Remove(index.index)
-
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
+	if len(w.journals) > 0 {
+		w.recordMove(entity, oldArch, index.index, arch)
 	}
+	w.removeFromArchetype(oldArch, index.index)
 	w.entities[entity.id] = entityIndex{arch: arch, index: newIndex}
+	w.moveDisabled(entity.id, oldArch, arch)
+	if w.changes != nil {
+		w.changes.Move(entity.id, oldArch, arch)
+	}
 
 	var oldRel *ID
 	if oldArch.HasRelationComponent {
@@ -480,6 +567,7 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relation ID, hasRela
 	}
 
 	w.cleanupArchetype(oldArch)
+	w.checkInvariants("Exchange")
 
 	if w.listener != nil {
 		var newRel *ID
@@ -499,7 +587,7 @@ func (w *World) exchange(entity Entity, add []ID, rem []ID, relation ID, hasRela
 			added := arch.Mask.And(&changed)
 			removed := oldMask.And(&changed)
 			if subscribes(trigger, &added, &removed, w.listener.Components(), oldRel, newRel) {
-				w.listener.Notify(w,
+				w.notify(
 					EntityEvent{Entity: entity, Added: added, Removed: removed,
 						AddedIDs: add, RemovedIDs: rem, OldRelation: oldRel, NewRelation: newRel,
 						OldTarget: oldTarget, EventTypes: bits},
@@ -590,13 +678,14 @@ func (w *World) exchangeBatchNoNotify(filter Filter, add []ID, rem []ID, relatio
 		newArch, start := w.exchangeArch(arch, archLen, add, rem, relation, hasRelation, target)
 		batches.Add(newArch, arch, start, newArch.Len())
 	}
+	w.checkInvariants("Batch.Exchange")
 
 	return int(totalEntities)
 }
 
 func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, rem []ID, relation ID, hasRelation bool, target Entity) (*archetype, uint32) {
 	mask := w.getExchangeMask(oldArch.Mask, add, rem)
-	oldIDs := oldArch.Components()
+	oldIDs := oldArch.node.dataIds
 
 	if hasRelation {
 		if !mask.Get(relation) {
@@ -620,6 +709,12 @@ func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, re
 		}
 	}
 
+	if len(rem) > 0 && !w.hookedRemove.IsZero() {
+		kept := mask.Not()
+		removed := oldArch.Mask.And(&kept)
+		w.callRemoveHooksArch(oldArch, &removed)
+	}
+
 	arch := w.findOrCreateArchetype(oldArch, add, rem, target)
 
 	startIdx := arch.Len()
@@ -630,10 +725,16 @@ func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, re
 	for i = 0; i < count; i++ {
 		idx := startIdx + i
 		entity := oldArch.GetEntity(i)
+		if len(w.journals) > 0 {
+			w.recordMove(entity, oldArch, i, arch)
+		}
 		index := &w.entities[entity.id]
 		arch.SetEntity(idx, entity)
 		index.arch = arch
 		index.index = idx
+		if w.changes != nil {
+			w.changes.Move(entity.id, oldArch, arch)
+		}
 
 		for _, id := range oldIDs {
 			if mask.Get(id) {
@@ -647,6 +748,8 @@ func (w *World) exchangeArch(oldArch *archetype, oldArchLen uint32, add []ID, re
 		w.targetEntities.Set(target.id, true)
 	}
 
+	arch.disabled += oldArch.disabled
+
 	// Theoretically, it could be oldArchLen < oldArch.Len(),
 	// which means we can't reset the archetype.
 	// However, this should not be possible as processing an entity twice
@@ -720,18 +823,20 @@ func (w *World) setRelation(entity Entity, comp ID, target Entity) {
 	}
 
 	newIndex := arch.Alloc(entity)
-	for _, id := range oldArch.node.Ids {
+	for _, id := range oldArch.node.dataIds {
 		comp := oldArch.Get(index.index, id)
 		arch.SetPointer(newIndex, id, comp)
 	}
 
-	swapped := oldArch.Remove(index.index)
-
-	if swapped {
-		swapEntity := oldArch.GetEntity(index.index)
-		w.entities[swapEntity.id].index = index.index
+	if len(w.journals) > 0 {
+		w.recordMove(entity, oldArch, index.index, arch)
 	}
+	w.removeFromArchetype(oldArch, index.index)
 	w.entities[entity.id] = entityIndex{arch: arch, index: newIndex}
+	w.moveDisabled(entity.id, oldArch, arch)
+	if w.changes != nil {
+		w.changes.Move(entity.id, oldArch, arch)
+	}
 
 	if !target.IsZero() {
 		w.targetEntities.Set(target.id, true)
@@ -739,11 +844,12 @@ func (w *World) setRelation(entity Entity, comp ID, target Entity) {
 
 	oldTarget := oldArch.RelationTarget
 	w.cleanupArchetype(oldArch)
+	w.checkInvariants("SetRelation")
 
 	if w.listener != nil {
 		trigger := w.listener.Subscriptions() & event.TargetChanged
 		if trigger != 0 && subscribes(trigger, nil, nil, w.listener.Components(), &comp, &comp) {
-			w.listener.Notify(w, EntityEvent{Entity: entity, OldRelation: &comp, NewRelation: &comp, OldTarget: oldTarget, EventTypes: event.TargetChanged})
+			w.notify(EntityEvent{Entity: entity, OldRelation: &comp, NewRelation: &comp, OldTarget: oldTarget, EventTypes: event.TargetChanged})
 		}
 	}
 }
@@ -794,6 +900,7 @@ func (w *World) setRelationBatchNoNotify(filter Filter, comp ID, target Entity,
 		newArch, start, end := w.setRelationArch(arch, archLen, comp, target)
 		batches.Add(newArch, arch, start, end)
 	}
+	w.checkInvariants("Batch.SetRelation")
 	return int(totalEntities)
 }
 
@@ -807,7 +914,7 @@ func (w *World) setRelationArch(oldArch *archetype, oldArchLen uint32, comp ID,
 	//	return oldArch, 0, oldArchLen
 	//}
 
-	oldIDs := oldArch.Components()
+	oldIDs := oldArch.node.dataIds
 
 	arch := oldArch.node.GetArchetype(target)
 	if arch == nil {
@@ -822,10 +929,16 @@ func (w *World) setRelationArch(oldArch *archetype, oldArchLen uint32, comp ID,
 	for i = 0; i < count; i++ {
 		idx := startIdx + i
 		entity := oldArch.GetEntity(i)
+		if len(w.journals) > 0 {
+			w.recordMove(entity, oldArch, i, arch)
+		}
 		index := &w.entities[entity.id]
 		arch.SetEntity(idx, entity)
 		index.arch = arch
 		index.index = idx
+		if w.changes != nil {
+			w.changes.Move(entity.id, oldArch, arch)
+		}
 
 		for _, id := range oldIDs {
 			comp := oldArch.Get(i, id)
@@ -837,6 +950,8 @@ func (w *World) setRelationArch(oldArch *archetype, oldArchLen uint32, comp ID,
 		w.targetEntities.Set(target.id, true)
 	}
 
+	arch.disabled += oldArch.disabled
+
 	// Theoretically, it could be oldArchLen < oldArch.Len(),
 	// which means we can't reset the archetype.
 	// However, this should not be possible as processing an entity twice
@@ -853,6 +968,42 @@ func (w *World) checkRelation(arch *archetype, comp ID) {
 	}
 }
 
+// checkIsRelation checks that a component is a relation.
+func (w *World) checkIsRelation(comp ID) {
+	if !w.registry.IsRelation.Get(comp) {
+		panic(fmt.Sprintf("not a relation component: %v", w.registry.Types[comp.id]))
+	}
+}
+
+// appendDescendants appends all descendants of an entity along a relation to a slice, in breadth-first order.
+// Entities are visited only once, so relation cycles are handled.
+func (w *World) appendDescendants(entities []Entity, parent Entity, comp ID) []Entity {
+	start := len(entities)
+	visited := bitSet{}
+	visited.ExtendTo(len(w.entities))
+	visited.Set(parent.id, true)
+	for _, e := range entities {
+		visited.Set(e.id, true)
+	}
+	filter := NewRelationFilter(All(comp), parent)
+	for {
+		query := w.Query(&filter)
+		for query.Next() {
+			e := query.Entity()
+			if visited.Get(e.id) {
+				continue
+			}
+			visited.Set(e.id, true)
+			entities = append(entities, e)
+		}
+		if start >= len(entities) {
+			return entities
+		}
+		filter.Target = entities[start]
+		start++
+	}
+}
+
 func (w *World) relationError(arch *archetype, comp ID) {
 	if !arch.HasComponent(comp) {
 		panic(fmt.Sprintf("entity does not have relation component %v", w.registry.Types[comp.id]))
@@ -884,10 +1035,29 @@ func (w *World) copyTo(entity Entity, id ID, comp interface{}) unsafe.Pointer {
 	}
 	index := &w.entities[entity.id]
 	arch := index.arch
+	if w.changes != nil {
+		w.changes.Change(entity.id, id)
+	}
 
 	return arch.Set(index.index, id, comp)
 }
 
+// listensSet returns whether the listener subscribes to value changes of the given component.
+func (w *World) listensSet(id ID) bool {
+	if w.listener == nil || !w.listener.Subscriptions().Contains(event.ComponentSet) {
+		return false
+	}
+	subs := w.listener.Components()
+	return subs == nil || subs.Get(id)
+}
+
+// notifySet notifies the listener about a value change of a component, if it subscribes to it.
+func (w *World) notifySet(entity Entity, id ID) {
+	if w.listensSet(id) {
+		w.notify(EntityEvent{Entity: entity, SetID: &id, EventTypes: event.ComponentSet})
+	}
+}
+
 // Tries to find an archetype by traversing the archetype graph,
 // searching by mask and extending the graph if necessary.
 // A new archetype is created for the final graph node if not already present.
@@ -971,16 +1141,21 @@ func (w *World) findArchetypeSlow(mask Mask) (*archNode, bool) {
 
 // Creates a node in the archetype graph.
 func (w *World) createArchetypeNode(mask Mask, relation ID, hasRelation bool) *archNode {
-	capInc := w.config.CapacityIncrement
-	if hasRelation {
-		capInc = w.config.RelationCapacityIncrement
-	}
-
+	capInc := w.capacityIncrement(&mask, hasRelation)
 	types := mask.toTypes(&w.registry)
 
 	w.nodeData.Add(nodeData{})
 	w.nodes.Add(newArchNode(mask, w.nodeData.Get(w.nodeData.Len()-1), relation, hasRelation, capInc, types))
 	nd := w.nodes.Get(w.nodes.Len() - 1)
+	nd.IsDying = w.hasDying && mask.Get(w.dyingID)
+	for _, tp := range types {
+		if tp.Type == hiddenType {
+			nd.IsHidden = true
+			nd.hiddenID = tp.ID
+		}
+	}
+	nd.allocator = w.config.Allocator
+	nd.memory = w.memory
 	w.relationNodes = append(w.relationNodes, nd)
 	w.nodePointers = append(w.nodePointers, nd)
 
@@ -992,18 +1167,19 @@ func (w *World) createArchetypeNode(mask Mask, relation ID, hasRelation bool) *a
 // and with a capacity of 1 otherwise.
 func (w *World) createArchetype(node *archNode, target Entity, forStorage bool) *archetype {
 	var arch *archetype
-	layouts := capacityNonZero(w.registry.Count(), int(layoutChunkSize))
-
 	if node.HasRelation {
-		arch = node.CreateArchetype(uint8(layouts), target)
+		arch = node.CreateArchetype(target)
 	} else {
 		w.archetypes.Add(archetype{})
 		w.archetypeData.Add(archetypeData{})
 		archIndex := w.archetypes.Len() - 1
 		arch = w.archetypes.Get(archIndex)
-		arch.Init(node, w.archetypeData.Get(archIndex), archIndex, forStorage, uint8(layouts), Entity{})
+		arch.Init(node, w.archetypeData.Get(archIndex), archIndex, forStorage, Entity{})
 		node.SetArchetype(arch)
 	}
+	if len(w.archetypeSlots) > 0 {
+		w.initArchetypeSlots(arch)
+	}
 	w.filterCache.addArchetype(arch)
 	return arch
 }
@@ -1022,10 +1198,11 @@ func (w *World) getArchetypes(filter Filter) []*archetype {
 			continue
 		}
 
-		if rf, ok := filter.(*RelationFilter); ok {
-			target := rf.Target
-			if arch, ok := nd.archetypeMap[target]; ok {
-				arches = append(arches, arch)
+		if tf, ok := filter.(targetFilter); ok {
+			for _, target := range tf.targets() {
+				if arch, ok := nd.archetypeMap[target]; ok {
+					arches = append(arches, arch)
+				}
 			}
 			continue
 		}
@@ -1057,6 +1234,22 @@ func (w *World) cleanupArchetype(arch *archetype) {
 	w.removeArchetype(arch)
 }
 
+// removeFromArchetype removes the entity at the given index from an archetype,
+// and updates the indices of moved entities. Preserves the order of entities if configured.
+func (w *World) removeFromArchetype(arch *archetype, index uint32) {
+	if w.config.StableOrder {
+		arch.RemoveStable(index)
+		for i := index; i < arch.len; i++ {
+			w.entities[arch.GetEntity(i).id].index = i
+		}
+		return
+	}
+	if arch.Remove(index) {
+		swapEntity := arch.GetEntity(index)
+		w.entities[swapEntity.id].index = index
+	}
+}
+
 // Removes empty archetypes that have a target relation to the given entity.
 func (w *World) cleanupArchetypes(target Entity) {
 	for _, node := range w.relationNodes {
@@ -1072,15 +1265,6 @@ func (w *World) removeArchetype(arch *archetype) {
 	w.Cache().removeArchetype(arch)
 }
 
-// Extend the number of access layouts in archetypes.
-func (w *World) extendArchetypeLayouts(count uint8) {
-	len := w.nodes.Len()
-	var i int32
-	for i = 0; i < len; i++ {
-		w.nodes.Get(i).ExtendArchetypeLayouts(count)
-	}
-}
-
 // componentID returns the ID for a component type, and registers it if not already registered.
 func (w *World) componentID(tp reflect.Type) ID {
 	id, newID := w.registry.ComponentID(tp)
@@ -1089,9 +1273,6 @@ func (w *World) componentID(tp reflect.Type) ID {
 			w.registry.unregisterLastComponent()
 			panic("attempt to register a new component in a locked world")
 		}
-		if id > 0 && id%layoutChunkSize == 0 {
-			w.extendArchetypeLayouts(id + layoutChunkSize)
-		}
 	}
 	return ID{id: id}
 }
@@ -1102,17 +1283,47 @@ func (w *World) resourceID(tp reflect.Type) ResID {
 	return ResID{id: id}
 }
 
+// sparseID returns the ID for a sparse-set component type, and registers it if not already registered.
+func (w *World) sparseID(tp reflect.Type) SparseID {
+	id, _ := w.sparse.registry.ComponentID(tp)
+	return SparseID{id: id}
+}
+
 // closeQuery closes a query and unlocks the world.
 func (w *World) closeQuery(query *Query) {
 	query.nodeIndex = -2
 	query.archIndex = -2
+	if query.isView {
+		return
+	}
 	w.unlock(query.lockBit)
 
+	if query.stats != nil {
+		query.stats.time += time.Since(query.start)
+	}
+	if query.profile != nil {
+		w.endQueryProfile(query)
+	}
+
 	if w.listener != nil {
 		if arch, ok := query.nodeArchetypes.(*batchArchetypes); ok {
 			w.notifyQuery(arch)
 		}
 	}
+
+	if !w.IsLocked() && w.hasReserved() {
+		w.realizeReserved()
+	}
+	if w.commands != nil && w.commands.Len() > 0 && !w.IsLocked() {
+		w.commands.Flush()
+	}
+}
+
+// notify sends an event to the listener, and assigns the next sequence number.
+func (w *World) notify(evt EntityEvent) {
+	w.eventSequence++
+	evt.Sequence = w.eventSequence
+	w.listener.Notify(w, evt)
 }
 
 // notifies the listener for all entities on a batch query.
@@ -1130,7 +1341,7 @@ func (w *World) notifyQuery(batchArch *batchArchetypes) {
 		event := EntityEvent{
 			Entity{}, arch.Mask, Mask{}, batchArch.Added, batchArch.Removed,
 			nil, newRel,
-			Entity{}, 0,
+			Entity{}, nil, 0, 0,
 		}
 
 		oldArch := batchArch.OldArchetype[i]
@@ -1164,7 +1375,7 @@ func (w *World) notifyQuery(batchArch *batchArchetypes) {
 			for e = start; e < end; e++ {
 				entity := arch.GetEntity(e)
 				event.Entity = entity
-				w.listener.Notify(w, event)
+				w.notify(event)
 			}
 		}
 	}
@@ -203,6 +203,33 @@ func TestWorldIds(t *testing.T) {
 	assert.PanicsWithValue(t, "can't get component IDs for a dead entity", func() { _ = w.Ids(e1) })
 }
 
+func TestWorldEntityAt(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity()
+
+	e, ok := w.EntityAt(uint64(e2.id))
+	assert.True(t, ok)
+	assert.Equal(t, e2, e)
+
+	w.RemoveEntity(e1)
+	_, ok = w.EntityAt(uint64(e1.id))
+	assert.False(t, ok)
+	_, ok = w.EntityAt(0)
+	assert.False(t, ok)
+	_, ok = w.EntityAt(100)
+	assert.False(t, ok)
+
+	e3 := w.NewEntity()
+	e, ok = w.EntityAt(uint64(e1.id))
+	assert.True(t, ok)
+	assert.Equal(t, e3, e)
+	assert.Equal(t, e1.id, e.id)
+	assert.NotEqual(t, e1.gen, e.gen)
+}
+
 func TestWorldLabels(t *testing.T) {
 	w := NewWorld()
 
@@ -345,6 +372,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		AddedIDs:    []ID{posID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    202,
 	}, events[201])
 
 	filter := All(posID, relID)
@@ -388,6 +416,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		NewRelation: &relID,
 		OldTarget:   target1,
 		EventTypes:  event.ComponentAdded | event.ComponentRemoved,
+		Sequence:    502,
 	}, events[501])
 
 	query = w.Query(All(posID))
@@ -412,6 +441,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		NewRelation: nil,
 		OldTarget:   target1,
 		EventTypes:  event.ComponentRemoved | event.RelationChanged | event.TargetChanged,
+		Sequence:    702,
 	}, events[701])
 
 	cnt = w.Batch().RemoveEntities(All(posID))
@@ -424,6 +454,7 @@ func TestWorldExchangeBatch(t *testing.T) {
 		Removed:    All(posID),
 		RemovedIDs: []ID{posID},
 		EventTypes: event.EntityRemoved | event.ComponentRemoved,
+		Sequence:   802,
 	}, events[801])
 
 	assert.Equal(t, []ID{velID}, events[202].AddedIDs)
@@ -847,6 +878,7 @@ func TestWorldRelationSet(t *testing.T) {
 		NewRelation: &relID,
 		OldTarget:   Entity{},
 		EventTypes:  event.TargetChanged,
+		Sequence:    4,
 	}, events[len(events)-1])
 
 	assert.Equal(t, targ, world.Relations().Get(e1, relID))
@@ -864,6 +896,7 @@ func TestWorldRelationSet(t *testing.T) {
 		NewRelation: &relID,
 		OldTarget:   targ,
 		EventTypes:  event.TargetChanged,
+		Sequence:    5,
 	}, events[len(events)-1])
 
 	assert.PanicsWithValue(t, "not a relation component: ecs.rotation",
@@ -893,6 +926,7 @@ func TestWorldRelationSet(t *testing.T) {
 		OldRelation: &relID,
 		NewRelation: nil,
 		EventTypes:  event.ComponentRemoved | event.RelationChanged | event.TargetChanged,
+		Sequence:    6,
 	}, events[len(events)-1])
 
 	assert.PanicsWithValue(t, "entity does not have relation component ecs.testRelationA",
@@ -1481,6 +1515,7 @@ func TestWorldBatchRemove(t *testing.T) {
 	assert.Equal(t, EntityEvent{
 		Entity:     target3,
 		EventTypes: event.EntityCreated,
+		Sequence:   3,
 	}, events[len(events)-1])
 
 	builder := NewBuilder(&world, rotID, relID).WithRelation(relID)
@@ -1496,6 +1531,7 @@ func TestWorldBatchRemove(t *testing.T) {
 		AddedIDs:    []ID{rotID, relID},
 		NewRelation: &relID,
 		EventTypes:  event.EntityCreated | event.ComponentAdded | event.RelationChanged | event.TargetChanged,
+		Sequence:    33,
 	}, events[len(events)-1])
 
 	filter := All(rotID).Exclusive()
@@ -1516,6 +1552,7 @@ func TestWorldBatchRemove(t *testing.T) {
 		OldRelation: &relID,
 		OldTarget:   target1,
 		EventTypes:  event.EntityRemoved | event.ComponentRemoved | event.RelationChanged | event.TargetChanged,
+		Sequence:    43,
 	}, events[len(events)-1])
 
 	relFilter = NewRelationFilter(All(rotID, relID), target2)
@@ -1530,6 +1567,7 @@ func TestWorldBatchRemove(t *testing.T) {
 	assert.Equal(t, EntityEvent{
 		Entity:     Entity{3, 0},
 		EventTypes: event.EntityRemoved,
+		Sequence:   56,
 	}, events[len(events)-1])
 
 	relFilter = NewRelationFilter(All(rotID, relID), target3)
@@ -1658,6 +1696,39 @@ func TestWorldRemoveGC(t *testing.T) {
 	w.NewEntity(compID)
 }
 
+func TestWorldEpoch(t *testing.T) {
+	w := NewWorld()
+	posID := ComponentID[Position](&w)
+	assert.Equal(t, uint64(0), w.Epoch())
+
+	w.NewEntity(posID)
+	assert.Equal(t, uint64(0), w.Epoch())
+
+	tx := w.Begin()
+	w.NewEntity(posID)
+	tx.Commit()
+	assert.Equal(t, uint64(0), w.Epoch())
+
+	tx = w.Begin()
+	w.NewEntity(posID)
+	tx.Rollback()
+	assert.Equal(t, uint64(1), w.Epoch())
+
+	h := NewHistory(&w, 4)
+	h.Checkpoint()
+	w.NewEntity(posID)
+	assert.True(t, h.Undo())
+	assert.Equal(t, uint64(2), w.Epoch())
+	assert.True(t, h.Redo())
+	assert.Equal(t, uint64(3), w.Epoch())
+
+	dump := w.DumpEntities()
+	w.Reset()
+	assert.Equal(t, uint64(4), w.Epoch())
+	w.LoadEntities(&dump)
+	assert.Equal(t, uint64(5), w.Epoch())
+}
+
 func TestWorldResetGC(t *testing.T) {
 	w := NewWorld()
 	compID := ComponentID[withSlice](&w)
@@ -1851,8 +1922,8 @@ func TestWorldExtendLayouts(t *testing.T) {
 	t0 = (*testStruct0)(w.Get(e, id0))
 	assert.Equal(t, t0.Val, int32(100))
 
-	assert.Equal(t, 16, len(w.archetypes.Get(0).layouts))
-	assert.Equal(t, 16, len(w.archetypes.Get(1).layouts))
+	assert.Equal(t, 1, len(w.archetypes.Get(0).layouts))
+	assert.Equal(t, 1, len(w.archetypes.Get(1).layouts))
 
 	lock := w.lock()
 	assert.PanicsWithValue(t, "attempt to register a new component in a locked world",
@@ -1864,8 +1935,10 @@ func TestWorldExtendLayouts(t *testing.T) {
 	id16 := ComponentID[testStruct16](&w)
 	_ = id16
 
-	assert.Equal(t, 32, len(w.archetypes.Get(0).layouts))
-	assert.Equal(t, 32, len(w.archetypes.Get(1).layouts))
+	assert.Equal(t, 1, len(w.archetypes.Get(0).layouts))
+	assert.Equal(t, 1, len(w.archetypes.Get(1).layouts))
+	assert.Nil(t, w.Get(e, id16))
+	assert.False(t, w.Has(e, id16))
 
 	t0 = (*testStruct0)(w.Get(e, id0))
 	assert.Equal(t, int32(100), t0.Val)
@@ -1913,3 +1986,71 @@ func TestWorldPointerStressTest(t *testing.T) {
 		runtime.GC()
 	}
 }
+
+func TestWorldStableOrder(t *testing.T) {
+	collect := func(w *World, filter Filter) []Entity {
+		entities := []Entity{}
+		query := w.Query(filter)
+		for query.Next() {
+			entities = append(entities, query.Entity())
+		}
+		return entities
+	}
+
+	w := NewWorld(NewConfig().WithStableOrder(true).WithCapacityIncrement(4))
+	posID := ComponentID[Position](&w)
+	velID := ComponentID[Velocity](&w)
+
+	entities := []Entity{}
+	for i := 0; i < 10; i++ {
+		e := w.NewEntity(posID)
+		(*Position)(w.Get(e, posID)).X = i
+		entities = append(entities, e)
+	}
+
+	w.RemoveEntity(entities[2])
+	w.Add(entities[5], velID)
+	w.RemoveEntity(entities[0])
+
+	expected := []Entity{entities[1], entities[3], entities[4], entities[6], entities[7], entities[8], entities[9], entities[5]}
+	assert.Equal(t, expected, collect(&w, All(posID)))
+	for _, e := range expected {
+		assert.Equal(t, int(e.id-1), (*Position)(w.Get(e, posID)).X)
+	}
+
+	w = NewWorld()
+	posID = ComponentID[Position](&w)
+	entities = entities[:0]
+	for i := 0; i < 4; i++ {
+		entities = append(entities, w.NewEntity(posID))
+	}
+	w.RemoveEntity(entities[0])
+	assert.Equal(t, []Entity{entities[3], entities[1], entities[2]}, collect(&w, All(posID)))
+}
+
+func TestWorldStableOrderCache(t *testing.T) {
+	w := NewWorld(NewConfig().WithStableOrder(true))
+	relID := ComponentID[testRelationA](&w)
+
+	filter := w.Cache().Register(All(relID))
+	targets := []Entity{}
+	for i := 0; i < 5; i++ {
+		target := w.NewEntity()
+		targets = append(targets, target)
+		NewBuilder(&w, relID).WithRelation(relID).New(target)
+	}
+
+	relFilter := NewRelationFilter(All(relID), targets[1])
+	w.Batch().RemoveEntities(&relFilter)
+	w.RemoveEntity(targets[1])
+
+	query := w.Query(&filter)
+	found := []Entity{}
+	for query.Next() {
+		found = append(found, query.Relation(relID))
+	}
+	assert.Equal(t, []Entity{targets[0], targets[2], targets[3], targets[4]}, found)
+
+	c := w.Clone()
+	assert.True(t, c.filterCache.stableOrder)
+}
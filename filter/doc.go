@@ -12,6 +12,7 @@
 //   - [AnyNOT] matches missing components.
 //   - [AND], [OR], [XOR] logically combine two filters.
 //   - [NOT] inverts any other filter.
+//   - [Parse] creates filters from textual expressions like "Position & !Frozen | Dead".
 //
 // All filters that wrap other filters ([AND], [OR], [XOR], [NOT]) ignore potential relation targets
 // of any wrapped ecs.RelationFilter (see [github.com/mlange-42/arche/ecs.RelationFilter]).
@@ -0,0 +1,214 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// ParseError describes a syntax error or an unknown component name in a filter expression.
+// See [Parse].
+type ParseError struct {
+	Expr string // The parsed expression.
+	Pos  int    // Byte position of the error in the expression.
+	Msg  string // Description of the problem.
+}
+
+// Error returns the error message.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("filter expression '%s', position %d: %s", e.Expr, e.Pos, e.Msg)
+}
+
+// Parse creates a filter from a textual expression, like "Position & Velocity & !Frozen | Dead".
+// Component names are resolved with [ecs.World.ComponentIDByName],
+// so components must be registered with [ecs.World.RegisterComponent] before.
+//
+// Operators, in order of precedence:
+//   - ( ) for grouping
+//   - ! for negation, see [NOT]
+//   - & for conjunction, see [AND]
+//   - ^ for exclusive disjunction, see [XOR]
+//   - | for disjunction, see [OR]
+//
+// Conjunctions of plain and negated component names, like "Position & Velocity & !Frozen",
+// result in an [ecs.Mask] or [ecs.MaskFilter] for fast matching.
+// Other expressions result in a tree of logic filters.
+//
+// Component names can contain any characters except for white space and operators.
+// Returns a [ParseError] for syntax errors and unknown component names.
+func Parse(w *ecs.World, expr string) (ecs.Filter, error) {
+	p := parser{world: w, expr: expr}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return nil, p.error(fmt.Sprintf("unexpected '%c'", p.expr[p.pos]))
+	}
+	return f.filter(), nil
+}
+
+// MustParse is like [Parse], but panics on errors.
+// Useful for filter expressions that are known at compile time.
+func MustParse(w *ecs.World, expr string) ecs.Filter {
+	f, err := Parse(w, expr)
+	if err != nil {
+		panic(err.Error())
+	}
+	return f
+}
+
+// parser is a recursive descent parser for filter expressions.
+type parser struct {
+	world *ecs.World
+	expr  string
+	pos   int
+}
+
+// term is an intermediate parse result.
+// Conjunctions of plain and negated components are collected into include and exclude,
+// to be converted into masks.
+type term struct {
+	f       ecs.Filter // Non-mask filter, or nil.
+	include []ecs.ID   // Included components, if f is nil.
+	exclude []ecs.ID   // Excluded components, if f is nil.
+}
+
+// filter converts the term to an [ecs.Filter].
+func (t *term) filter() ecs.Filter {
+	if t.f != nil {
+		return t.f
+	}
+	if len(t.exclude) == 0 {
+		return ecs.All(t.include...)
+	}
+	f := ecs.All(t.include...).Without(t.exclude...)
+	return &f
+}
+
+// parseOr parses a disjunction of exclusive disjunctions.
+func (p *parser) parseOr() (*term, error) {
+	left, err := p.parseXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept('|') {
+		right, err := p.parseXor()
+		if err != nil {
+			return nil, err
+		}
+		left = &term{f: Or(left.filter(), right.filter())}
+	}
+	return left, nil
+}
+
+// parseXor parses an exclusive disjunction of conjunctions.
+func (p *parser) parseXor() (*term, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept('^') {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &term{f: XOr(left.filter(), right.filter())}
+	}
+	return left, nil
+}
+
+// parseAnd parses a conjunction of unary terms.
+func (p *parser) parseAnd() (*term, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept('&') {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left.f == nil && right.f == nil {
+			left = &term{
+				include: append(left.include, right.include...),
+				exclude: append(left.exclude, right.exclude...),
+			}
+			continue
+		}
+		left = &term{f: And(left.filter(), right.filter())}
+	}
+	return left, nil
+}
+
+// parseUnary parses a negation, a parenthesized expression or a component name.
+func (p *parser) parseUnary() (*term, error) {
+	p.skipSpace()
+	if p.accept('!') {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if inner.f == nil && len(inner.include) == 1 && len(inner.exclude) == 0 {
+			return &term{exclude: inner.include}, nil
+		}
+		return &term{f: Not(inner.filter())}, nil
+	}
+	if p.accept('(') {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(')') {
+			return nil, p.error("missing ')'")
+		}
+		return inner, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) && !isOperator(rune(p.expr[p.pos])) && !unicode.IsSpace(rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.expr) {
+			return nil, p.error("unexpected end of expression")
+		}
+		return nil, p.error(fmt.Sprintf("unexpected '%c'", p.expr[p.pos]))
+	}
+	name := p.expr[start:p.pos]
+	id, ok := p.world.ComponentIDByName(name)
+	if !ok {
+		return nil, &ParseError{Expr: p.expr, Pos: start, Msg: fmt.Sprintf("unknown component '%s'", name)}
+	}
+	return &term{include: []ecs.ID{id}}, nil
+}
+
+// accept consumes the given operator if it is next, ignoring white space.
+func (p *parser) accept(op byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.expr) && p.expr[p.pos] == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipSpace advances the position to the next non-white space character.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.expr) && unicode.IsSpace(rune(p.expr[p.pos])) {
+		p.pos++
+	}
+}
+
+// error creates a [ParseError] at the current position.
+func (p *parser) error(msg string) error {
+	return &ParseError{Expr: p.expr, Pos: p.pos, Msg: msg}
+}
+
+// isOperator reports whether a character is an operator or a parenthesis.
+func isOperator(c rune) bool {
+	return strings.ContainsRune("&|^!()", c)
+}
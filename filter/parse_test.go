@@ -0,0 +1,90 @@
+package filter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	f "github.com/mlange-42/arche/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	w := ecs.NewWorld()
+	ids := RegisterAll(&w)
+	names := []string{"Position", "Velocity", "Frozen", "Dead", "game.Health"}
+	types := []reflect.Type{
+		reflect.TypeOf(TestStruct0{}), reflect.TypeOf(TestStruct1{}), reflect.TypeOf(TestStruct2{}),
+		reflect.TypeOf(TestStruct3{}), reflect.TypeOf(TestStruct4{}),
+	}
+	for i, name := range names {
+		w.RegisterComponent(name, types[i])
+	}
+	pos, vel, frozen, dead, health := ids[0], ids[1], ids[2], ids[3], ids[4]
+
+	filter, err := f.Parse(&w, "Position & Velocity")
+	assert.Nil(t, err)
+	assert.Equal(t, ecs.All(pos, vel), filter)
+
+	filter, err = f.Parse(&w, "  Position&Velocity & !Frozen  ")
+	assert.Nil(t, err)
+	maskFilter := ecs.All(pos, vel).Without(frozen)
+	assert.Equal(t, &maskFilter, filter)
+
+	filter, err = f.Parse(&w, "game.Health")
+	assert.Nil(t, err)
+	assert.Equal(t, ecs.All(health), filter)
+
+	filter = f.MustParse(&w, "Position & Velocity & !Frozen | Dead")
+	assert.True(t, match(filter, ecs.All(pos, vel)))
+	assert.True(t, match(filter, ecs.All(dead)))
+	assert.True(t, match(filter, ecs.All(pos, vel, frozen, dead)))
+	assert.False(t, match(filter, ecs.All(pos, vel, frozen)))
+	assert.False(t, match(filter, ecs.All(pos)))
+
+	filter = f.MustParse(&w, "Position & (Velocity | Dead)")
+	assert.True(t, match(filter, ecs.All(pos, vel)))
+	assert.True(t, match(filter, ecs.All(pos, dead)))
+	assert.False(t, match(filter, ecs.All(pos)))
+	assert.False(t, match(filter, ecs.All(vel, dead)))
+
+	filter = f.MustParse(&w, "!(Position & Velocity)")
+	assert.True(t, match(filter, ecs.All(pos)))
+	assert.False(t, match(filter, ecs.All(pos, vel)))
+
+	filter = f.MustParse(&w, "Position ^ Velocity")
+	assert.True(t, match(filter, ecs.All(pos)))
+	assert.True(t, match(filter, ecs.All(vel)))
+	assert.False(t, match(filter, ecs.All(pos, vel)))
+
+	filter = f.MustParse(&w, "!!Position")
+	assert.True(t, match(filter, ecs.All(pos)))
+	assert.False(t, match(filter, ecs.All(vel)))
+
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"", 0, "unexpected end of expression"},
+		{"Position &", 10, "unexpected end of expression"},
+		{"Position & Foo", 11, "unknown component 'Foo'"},
+		{"(Position", 9, "missing ')'"},
+		{"Position)", 8, "unexpected ')'"},
+		{"Position Velocity", 9, "unexpected 'V'"},
+		{"& Position", 0, "unexpected '&'"},
+	}
+	for _, tt := range tests {
+		_, err := f.Parse(&w, tt.expr)
+		var parseErr *f.ParseError
+		assert.True(t, errors.As(err, &parseErr), tt.expr)
+		assert.Equal(t, tt.pos, parseErr.Pos, tt.expr)
+		assert.Equal(t, tt.msg, parseErr.Msg, tt.expr)
+	}
+
+	_, err = f.Parse(&w, "Position & Foo")
+	assert.Equal(t, "filter expression 'Position & Foo', position 11: unknown component 'Foo'", err.Error())
+
+	assert.Panics(t, func() { f.MustParse(&w, "Foo") })
+}
@@ -22,6 +22,7 @@ type query struct {
 	TypesReturn   string
 	Variables     string
 	ReturnAll     string
+	ReturnOpt     string
 	ReturnAllSafe string
 	Include       string
 	Components    string
@@ -152,6 +153,7 @@ func generateQueries() {
 		fullTypes := ""
 		include := ""
 		returnAll := ""
+		returnOpt := ""
 		idTypes := ""
 		idAssign := ""
 		variables := ""
@@ -164,9 +166,11 @@ func generateQueries() {
 			idTypes = "id" + strings.Join(numbers[:i], " ecs.ID\n\tid") + " ecs.ID"
 			for j := 0; j < i; j++ {
 				returnAll += fmt.Sprintf("(*%s)(q.Query.Get(q.id%d))", typeLetters[j], j)
+				returnOpt += fmt.Sprintf("(*%s)(getOptional(&q.Query, q.id%d))", typeLetters[j], j)
 				idAssign += fmt.Sprintf("	id%d: f.compiled.Ids[%d],\n", j, j)
 				if j < i-1 {
 					returnAll += ",\n"
+					returnOpt += ",\n"
 				}
 			}
 		} else {
@@ -180,6 +184,7 @@ func generateQueries() {
 			TypesFull:   fullTypes,
 			Variables:   variables,
 			ReturnAll:   returnAll,
+			ReturnOpt:   returnOpt,
 			Include:     include,
 			IDTypes:     idTypes,
 			IDAssign:    idAssign,
@@ -19,6 +19,7 @@ type compiledQuery struct {
 	Relation       ecs.ID
 	Target         ecs.Entity
 	HasRelation    bool
+	HasOptional    bool
 	compiled       bool
 	targetCompiled bool
 	locked         bool
@@ -37,6 +38,7 @@ func (q *compiledQuery) Compile(w *ecs.World, include, optional, exclude []Comp,
 	q.Ids = toIds(w, include)
 
 	incl := toMaskOptional(w, q.Ids, optional)
+	q.HasOptional = len(optional) > 0
 	var excl ecs.Mask
 	if exclusive {
 		excl = incl.Not()
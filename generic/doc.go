@@ -13,6 +13,9 @@
 //   - [Map1], [Map2], etc. provide generic access to multiple components using world access,
 //     like [Map1.Get], [Map1.Add], [Map1.Remove], etc.
 //   - [Exchange] allows to add, remove and exchange components, incl. as batch operations.
+//   - [Tag] allows to add, remove and check zero-sized tag components, incl. as batch operations.
+//   - [Shared] provides shared components, with a value stored once per archetype instead of per entity.
+//   - [Sparse] provides generic access to a component in sparse-set storage, see [ecs.SparseSets].
 //   - [Resource] provides generic access to a resource from [ecs.Resources].
 //
 // # ECS Manipulations
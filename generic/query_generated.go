@@ -132,6 +132,7 @@ func (f *Filter0) Query(w *ecs.World, target ...ecs.Entity) Query0 {
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 	}
 }
 
@@ -170,6 +171,7 @@ type Query0 struct {
 
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Relation returns the target entity for the query's relation.
@@ -327,6 +329,7 @@ func (f *Filter1[A]) Query(w *ecs.World, target ...ecs.Entity) Query1[A] {
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 	}
 }
@@ -367,12 +370,17 @@ type Query1[A any] struct {
 	id0         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query1[A]) Get() *A {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0))
+	}
 	return (*A)(q.Query.Get(q.id0))
 }
 
@@ -532,6 +540,7 @@ func (f *Filter2[A, B]) Query(w *ecs.World, target ...ecs.Entity) Query2[A, B] {
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 	}
@@ -574,12 +583,18 @@ type Query2[A any, B any] struct {
 	id1         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query2[A, B]) Get() (*A, *B) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1))
 }
@@ -741,6 +756,7 @@ func (f *Filter3[A, B, C]) Query(w *ecs.World, target ...ecs.Entity) Query3[A, B
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -785,12 +801,19 @@ type Query3[A any, B any, C any] struct {
 	id2         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query3[A, B, C]) Get() (*A, *B, *C) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2))
@@ -954,6 +977,7 @@ func (f *Filter4[A, B, C, D]) Query(w *ecs.World, target ...ecs.Entity) Query4[A
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -1000,12 +1024,20 @@ type Query4[A any, B any, C any, D any] struct {
 	id3         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query4[A, B, C, D]) Get() (*A, *B, *C, *D) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -1171,6 +1203,7 @@ func (f *Filter5[A, B, C, D, E]) Query(w *ecs.World, target ...ecs.Entity) Query
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -1219,12 +1252,21 @@ type Query5[A any, B any, C any, D any, E any] struct {
 	id4         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query5[A, B, C, D, E]) Get() (*A, *B, *C, *D, *E) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -1392,6 +1434,7 @@ func (f *Filter6[A, B, C, D, E, F]) Query(w *ecs.World, target ...ecs.Entity) Qu
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -1442,12 +1485,22 @@ type Query6[A any, B any, C any, D any, E any, F any] struct {
 	id5         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query6[A, B, C, D, E, F]) Get() (*A, *B, *C, *D, *E, *F) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -1617,6 +1670,7 @@ func (f *Filter7[A, B, C, D, E, F, G]) Query(w *ecs.World, target ...ecs.Entity)
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -1669,12 +1723,23 @@ type Query7[A any, B any, C any, D any, E any, F any, G any] struct {
 	id6         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query7[A, B, C, D, E, F, G]) Get() (*A, *B, *C, *D, *E, *F, *G) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -1846,6 +1911,7 @@ func (f *Filter8[A, B, C, D, E, F, G, H]) Query(w *ecs.World, target ...ecs.Enti
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -1900,12 +1966,24 @@ type Query8[A any, B any, C any, D any, E any, F any, G any, H any] struct {
 	id7         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query8[A, B, C, D, E, F, G, H]) Get() (*A, *B, *C, *D, *E, *F, *G, *H) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6)),
+			(*H)(getOptional(&q.Query, q.id7))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -2079,6 +2157,7 @@ func (f *Filter9[A, B, C, D, E, F, G, H, I]) Query(w *ecs.World, target ...ecs.E
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -2135,12 +2214,25 @@ type Query9[A any, B any, C any, D any, E any, F any, G any, H any, I any] struc
 	id8         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query9[A, B, C, D, E, F, G, H, I]) Get() (*A, *B, *C, *D, *E, *F, *G, *H, *I) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6)),
+			(*H)(getOptional(&q.Query, q.id7)),
+			(*I)(getOptional(&q.Query, q.id8))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -2316,6 +2408,7 @@ func (f *Filter10[A, B, C, D, E, F, G, H, I, J]) Query(w *ecs.World, target ...e
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -2374,12 +2467,26 @@ type Query10[A any, B any, C any, D any, E any, F any, G any, H any, I any, J an
 	id9         ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query10[A, B, C, D, E, F, G, H, I, J]) Get() (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6)),
+			(*H)(getOptional(&q.Query, q.id7)),
+			(*I)(getOptional(&q.Query, q.id8)),
+			(*J)(getOptional(&q.Query, q.id9))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -2557,6 +2664,7 @@ func (f *Filter11[A, B, C, D, E, F, G, H, I, J, K]) Query(w *ecs.World, target .
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -2617,12 +2725,27 @@ type Query11[A any, B any, C any, D any, E any, F any, G any, H any, I any, J an
 	id10        ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query11[A, B, C, D, E, F, G, H, I, J, K]) Get() (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J, *K) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6)),
+			(*H)(getOptional(&q.Query, q.id7)),
+			(*I)(getOptional(&q.Query, q.id8)),
+			(*J)(getOptional(&q.Query, q.id9)),
+			(*K)(getOptional(&q.Query, q.id10))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
@@ -2802,6 +2925,7 @@ func (f *Filter12[A, B, C, D, E, F, G, H, I, J, K, L]) Query(w *ecs.World, targe
 		Query:       w.Query(filter),
 		relation:    f.compiled.Relation,
 		hasRelation: f.compiled.HasRelation,
+		hasOptional: f.compiled.HasOptional,
 		id0:         f.compiled.Ids[0],
 		id1:         f.compiled.Ids[1],
 		id2:         f.compiled.Ids[2],
@@ -2864,12 +2988,28 @@ type Query12[A any, B any, C any, D any, E any, F any, G any, H any, I any, J an
 	id11        ecs.ID
 	relation    ecs.ID
 	hasRelation bool
+	hasOptional bool
 }
 
 // Get returns all queried components for the current query iterator position.
 //
 // Use [ecs.Query.Entity] to get the current Entity.
+// Returns nil for optional components the entity does not have.
 func (q *Query12[A, B, C, D, E, F, G, H, I, J, K, L]) Get() (*A, *B, *C, *D, *E, *F, *G, *H, *I, *J, *K, *L) {
+	if q.hasOptional {
+		return (*A)(getOptional(&q.Query, q.id0)),
+			(*B)(getOptional(&q.Query, q.id1)),
+			(*C)(getOptional(&q.Query, q.id2)),
+			(*D)(getOptional(&q.Query, q.id3)),
+			(*E)(getOptional(&q.Query, q.id4)),
+			(*F)(getOptional(&q.Query, q.id5)),
+			(*G)(getOptional(&q.Query, q.id6)),
+			(*H)(getOptional(&q.Query, q.id7)),
+			(*I)(getOptional(&q.Query, q.id8)),
+			(*J)(getOptional(&q.Query, q.id9)),
+			(*K)(getOptional(&q.Query, q.id10)),
+			(*L)(getOptional(&q.Query, q.id11))
+	}
 	return (*A)(q.Query.Get(q.id0)),
 		(*B)(q.Query.Get(q.id1)),
 		(*C)(q.Query.Get(q.id2)),
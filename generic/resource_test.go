@@ -14,6 +14,7 @@ func TestGenericResource(t *testing.T) {
 	assert.Equal(t, ecs.ResourceID[testStruct0](&w), get.ID())
 
 	assert.False(t, get.Has())
+	assert.Nil(t, get.Get())
 	get.Add(&testStruct0{100})
 
 	assert.True(t, get.Has())
@@ -23,6 +24,7 @@ func TestGenericResource(t *testing.T) {
 
 	get.Remove()
 	assert.False(t, get.Has())
+	assert.Nil(t, get.Get())
 }
 
 func ExampleResource() {
@@ -0,0 +1,192 @@
+package generic
+
+import (
+	"github.com/mlange-42/arche/ecs"
+)
+
+// sharedRelation is the relation component that links entities to the value entity of their shared value.
+type sharedRelation[T comparable] struct {
+	ecs.Relation
+}
+
+// sharedValue is the component that holds a shared value on its value entity.
+type sharedValue[T comparable] struct {
+	Value T
+}
+
+// Shared provides shared components, whose value is stored once per archetype rather than per entity.
+// Entities with the same shared value are grouped into the same archetype.
+// This reduces memory for large groups of entities with the same value, like a material or chunk coordinates.
+//
+// Shared components are built on entity relations (see [ecs.Relation]):
+// each distinct value is stored on a value entity, and entities with that value have a
+// relation component targeting it. The relation component is zero-sized, so entities store no data for it.
+// As an entity can have only a single relation component, a shared component can't be combined
+// with other relations on the same entity.
+//
+// Value entities are created on demand, and can be removed with [Shared.Cleanup] when no longer used.
+// They must not be removed otherwise.
+// Value entities have the [ecs.Hidden] marker, so they are not matched by queries unless requested explicitly.
+//
+// The mapping from values to value entities is only a cache, and the value entities in the world are authoritative.
+// Thus, a Shared stays valid after [ecs.World.LoadSnapshot], transaction rollbacks and [ecs.History] navigation,
+// and a new Shared for a world restored from a snapshot or clone picks up the existing value entities.
+// The cache is rebuilt from the value entities in the world when the world's [ecs.World.Epoch] changes.
+//
+// Create one with [NewShared].
+//
+// Example:
+//
+//	material := generic.NewShared[Material](&world)
+//	material.Add(entity, Material{Color: 1, Roughness: 0.5})
+//	m := material.Get(entity)
+type Shared[T comparable] struct {
+	id     ecs.ID
+	value  Map[sharedValue[T]]
+	hidden ecs.ID
+	world  *ecs.World
+	values map[T]ecs.Entity
+	epoch  uint64 // Epoch of the world when the cache was built.
+}
+
+// NewShared creates a new [Shared] for a comparable value type.
+//
+// Each value type has its own relation component. Shared instances for the same type
+// in the same world should be avoided, as they don't share their value entities.
+func NewShared[T comparable](w *ecs.World) *Shared[T] {
+	s := &Shared[T]{
+		id:     ecs.ComponentID[sharedRelation[T]](w),
+		value:  NewMap[sharedValue[T]](w),
+		hidden: ecs.ComponentID[ecs.Hidden](w),
+		world:  w,
+		values: map[T]ecs.Entity{},
+	}
+	s.rebuild()
+	return s
+}
+
+// ID returns the ID of the relation component for this shared component.
+// Use it in filters, and for relation filters with [Shared.Target].
+func (s *Shared[T]) ID() ecs.ID {
+	return s.id
+}
+
+// Target returns the value entity for a shared value, and whether the value is in use.
+// Use it as the relation target in filters, to query all entities with the value.
+func (s *Shared[T]) Target(value T) (ecs.Entity, bool) {
+	return s.lookup(value)
+}
+
+// Add adds the shared component with the given value to an entity.
+//
+// Panics if the entity already has the shared component, or another relation component.
+func (s *Shared[T]) Add(entity ecs.Entity, value T) {
+	s.world.Relations().Exchange(entity, []ecs.ID{s.id}, nil, s.id, s.target(value))
+}
+
+// AddBatch adds the shared component with the given value to all entities matching the given filter.
+// Returns the number of affected entities.
+//
+// Panics if any of the entities already has the shared component, or another relation component.
+func (s *Shared[T]) AddBatch(filter ecs.Filter, value T) int {
+	return s.world.Relations().ExchangeBatch(filter, []ecs.ID{s.id}, nil, s.id, s.target(value))
+}
+
+// Set sets the shared value of an entity, moving it to the archetype of the new value.
+//
+// Panics if the entity does not have the shared component.
+func (s *Shared[T]) Set(entity ecs.Entity, value T) {
+	s.world.Relations().Set(entity, s.id, s.target(value))
+}
+
+// SetBatch sets the shared value of all entities matching the given filter.
+// Returns the number of affected entities.
+//
+// Panics if any of the entities does not have the shared component.
+func (s *Shared[T]) SetBatch(filter ecs.Filter, value T) int {
+	return s.world.Relations().SetBatch(filter, s.id, s.target(value))
+}
+
+// Remove removes the shared component from an entity.
+//
+// Panics if the entity does not have the shared component.
+func (s *Shared[T]) Remove(entity ecs.Entity) {
+	s.world.Remove(entity, s.id)
+}
+
+// Has returns whether the entity has the shared component.
+func (s *Shared[T]) Has(entity ecs.Entity) bool {
+	return s.world.Has(entity, s.id)
+}
+
+// Get returns the shared value of an entity.
+//
+// The value is a copy. Use [Shared.Set] to change it for individual entities.
+//
+// Panics if the entity does not have the shared component.
+func (s *Shared[T]) Get(entity ecs.Entity) T {
+	return s.value.Get(s.world.Relations().Get(entity, s.id)).Value
+}
+
+// Cleanup removes value entities of values that are no longer used by any entity.
+// Returns the number of removed values.
+//
+// Panics when called on a locked world.
+func (s *Shared[T]) Cleanup() int {
+	s.rebuild()
+	count := 0
+	for value, target := range s.values {
+		filter := ecs.NewRelationFilter(ecs.All(s.id), target)
+		query := s.world.Query(&filter)
+		used := query.Count() > 0
+		query.Close()
+		if used {
+			continue
+		}
+		s.world.RemoveEntity(target)
+		delete(s.values, value)
+		count++
+	}
+	return count
+}
+
+// target returns the value entity for a value, and creates it if required.
+func (s *Shared[T]) target(value T) ecs.Entity {
+	if target, ok := s.lookup(value); ok {
+		return target
+	}
+	target := s.world.NewEntity(s.value.ID(), s.hidden)
+	s.value.Get(target).Value = value
+	s.values[value] = target
+	return target
+}
+
+// lookup returns the value entity for a value, and whether it exists.
+// Rebuilds the cache if the world's state was restored or replaced,
+// or if the cached entity does not hold the value anymore.
+func (s *Shared[T]) lookup(value T) (ecs.Entity, bool) {
+	if s.epoch != s.world.Epoch() {
+		s.rebuild()
+	}
+	target, ok := s.values[value]
+	if ok && !s.holds(target, value) {
+		s.rebuild()
+		target, ok = s.values[value]
+	}
+	return target, ok
+}
+
+// holds checks whether an entity is an alive value entity with the given value.
+func (s *Shared[T]) holds(target ecs.Entity, value T) bool {
+	return s.world.Alive(target) && s.world.Has(target, s.value.ID()) && s.value.Get(target).Value == value
+}
+
+// rebuild rebuilds the cache of value entities from the world.
+func (s *Shared[T]) rebuild() {
+	s.epoch = s.world.Epoch()
+	clear(s.values)
+	query := s.world.Query(ecs.All(s.value.ID(), s.hidden))
+	for query.Next() {
+		s.values[(*sharedValue[T])(query.Get(s.value.ID())).Value] = query.Entity()
+	}
+}
@@ -0,0 +1,207 @@
+package generic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMaterial struct {
+	Color     int
+	Roughness float64
+}
+
+func TestShared(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	material := NewShared[testMaterial](&w)
+	assert.Equal(t, ecs.ComponentID[sharedRelation[testMaterial]](&w), material.ID())
+
+	red := testMaterial{Color: 1, Roughness: 0.5}
+	blue := testMaterial{Color: 2}
+
+	e1 := posMap.NewWith(&testStruct0{val: 1})
+	e2 := posMap.NewWith(&testStruct0{val: 2})
+	e3 := posMap.NewWith(&testStruct0{val: 3})
+
+	_, ok := material.Target(red)
+	assert.False(t, ok)
+
+	assert.False(t, material.Has(e1))
+	material.Add(e1, red)
+	material.Add(e2, red)
+	material.Add(e3, blue)
+	assert.True(t, material.Has(e1))
+	assert.Equal(t, red, material.Get(e1))
+	assert.Equal(t, red, material.Get(e2))
+	assert.Equal(t, blue, material.Get(e3))
+	assert.Equal(t, testStruct0{val: 1}, *posMap.Get(e1))
+
+	redTarget, ok := material.Target(red)
+	assert.True(t, ok)
+	filter := ecs.NewRelationFilter(ecs.All(material.ID()), redTarget)
+	query := w.Query(&filter)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+
+	material.Set(e2, blue)
+	assert.Equal(t, blue, material.Get(e2))
+	query = w.Query(&filter)
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+
+	material.Remove(e1)
+	assert.False(t, material.Has(e1))
+	assert.Panics(t, func() { material.Get(e1) })
+
+	assert.Equal(t, 1, material.Cleanup())
+	_, ok = material.Target(red)
+	assert.False(t, ok)
+	assert.Equal(t, 0, material.Cleanup())
+	assert.Equal(t, blue, material.Get(e3))
+
+	material.Add(e1, red)
+	assert.Equal(t, red, material.Get(e1))
+}
+
+func TestSharedBatch(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	material := NewShared[testMaterial](&w)
+
+	red := testMaterial{Color: 1}
+	blue := testMaterial{Color: 2}
+
+	posMap.NewBatch(10)
+	assert.Equal(t, 10, material.AddBatch(ecs.All(posMap.id0), red))
+
+	filter := ecs.All(posMap.id0, material.ID())
+	query := w.Query(filter)
+	for query.Next() {
+		assert.Equal(t, red, material.Get(query.Entity()))
+	}
+
+	assert.Equal(t, 10, material.SetBatch(filter, blue))
+	query = w.Query(filter)
+	for query.Next() {
+		assert.Equal(t, blue, material.Get(query.Entity()))
+	}
+
+	assert.Equal(t, 1, material.Cleanup())
+}
+
+func TestSharedHidden(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	material := NewShared[testMaterial](&w)
+
+	posMap.NewBatch(10)
+	material.AddBatch(ecs.All(posMap.id0), testMaterial{Color: 1})
+	e := posMap.New()
+	material.Add(e, testMaterial{Color: 2})
+
+	query := w.Query(ecs.All())
+	assert.Equal(t, 11, query.Count())
+	query.Close()
+
+	stats := w.Stats()
+	assert.Equal(t, 13, stats.Entities.Used)
+	assert.Equal(t, 2, stats.Entities.Hidden)
+
+	query = w.Query(ecs.All(ecs.ComponentID[ecs.Hidden](&w)))
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+}
+
+func TestSharedSnapshot(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	material := NewShared[testMaterial](&w)
+
+	red := testMaterial{Color: 1}
+	blue := testMaterial{Color: 2}
+
+	e1 := posMap.New()
+	e2 := posMap.New()
+	material.Add(e1, red)
+	material.Add(e2, blue)
+	redTarget, _ := material.Target(red)
+
+	var buf bytes.Buffer
+	assert.Nil(t, w.Snapshot(&buf))
+
+	material.Remove(e1)
+	assert.Equal(t, 1, material.Cleanup())
+
+	w.Reset()
+	assert.Nil(t, w.LoadSnapshot(&buf))
+
+	target, ok := material.Target(red)
+	assert.True(t, ok)
+	assert.Equal(t, redTarget, target)
+	assert.Equal(t, red, material.Get(e1))
+
+	e3 := posMap.New()
+	material.Add(e3, red)
+	assert.Equal(t, redTarget, w.Relations().Get(e3, material.ID()))
+
+	restored := NewShared[testMaterial](&w)
+	target, ok = restored.Target(blue)
+	assert.True(t, ok)
+	assert.Equal(t, w.Relations().Get(e2, material.ID()), target)
+
+	hidden := ecs.All(ecs.ComponentID[ecs.Hidden](&w))
+	query := w.Query(hidden)
+	assert.Equal(t, 2, query.Count())
+	query.Close()
+}
+
+func TestSharedRollback(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	material := NewShared[testMaterial](&w)
+	hidden := ecs.All(ecs.ComponentID[ecs.Hidden](&w))
+
+	red := testMaterial{Color: 1}
+	e1 := posMap.New()
+	material.Add(e1, red)
+	redTarget, _ := material.Target(red)
+
+	tx := w.Begin()
+	material.Remove(e1)
+	assert.Equal(t, 1, material.Cleanup())
+	_, ok := material.Target(red)
+	assert.False(t, ok)
+	tx.Rollback()
+
+	target, ok := material.Target(red)
+	assert.True(t, ok)
+	assert.Equal(t, redTarget, target)
+
+	e2 := posMap.New()
+	material.Add(e2, red)
+	assert.Equal(t, redTarget, w.Relations().Get(e2, material.ID()))
+
+	query := w.Query(hidden)
+	assert.Equal(t, 1, query.Count())
+	query.Close()
+}
+
+func BenchmarkSharedDistinctValues_10000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		w := ecs.NewWorld()
+		posMap := NewMap1[testStruct0](&w)
+		material := NewShared[testMaterial](&w)
+		entities := make([]ecs.Entity, 10000)
+		for j := range entities {
+			entities[j] = posMap.New()
+		}
+		b.StartTimer()
+		for j, e := range entities {
+			material.Add(e, testMaterial{Color: j})
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package generic
+
+import "github.com/mlange-42/arche/ecs"
+
+// Sparse provides a type-safe way to access a component type in sparse-set storage.
+//
+// See [ecs.SparseSets] for details on sparse-set components.
+//
+// Create one with [NewSparse].
+type Sparse[T any] struct {
+	id    ecs.SparseID
+	world *ecs.World
+}
+
+// NewSparse creates a new [Sparse] for a sparse-set component type.
+//
+// See also [ecs.SparseSets].
+func NewSparse[T any](w *ecs.World) Sparse[T] {
+	return Sparse[T]{
+		id:    ecs.SparseComponentID[T](w),
+		world: w,
+	}
+}
+
+// ID returns the sparse-set component ID for this Sparse.
+func (s *Sparse[T]) ID() ecs.SparseID {
+	return s.id
+}
+
+// Add adds a zeroed component to the given entity, and returns a pointer to it.
+//
+// See also [ecs.SparseSets.Add].
+func (s *Sparse[T]) Add(entity ecs.Entity) *T {
+	return (*T)(s.world.SparseSets().Add(entity, s.id))
+}
+
+// Set overwrites the component for the given entity, and adds it if the entity does not have it.
+//
+// See also [ecs.SparseSets.Set].
+func (s *Sparse[T]) Set(entity ecs.Entity, comp *T) *T {
+	sets := s.world.SparseSets()
+	ptr := (*T)(sets.Get(entity, s.id))
+	if ptr == nil {
+		ptr = (*T)(sets.Add(entity, s.id))
+	}
+	*ptr = *comp
+	return ptr
+}
+
+// Get gets the component for the given entity.
+// Returns nil if the entity does not have the component.
+//
+// See also [ecs.SparseSets.Get].
+func (s *Sparse[T]) Get(entity ecs.Entity) *T {
+	return (*T)(s.world.SparseSets().Get(entity, s.id))
+}
+
+// Has returns whether the entity has the component.
+//
+// See also [ecs.SparseSets.Has].
+func (s *Sparse[T]) Has(entity ecs.Entity) bool {
+	return s.world.SparseSets().Has(entity, s.id)
+}
+
+// Remove removes the component from the given entity.
+//
+// See also [ecs.SparseSets.Remove].
+func (s *Sparse[T]) Remove(entity ecs.Entity) {
+	s.world.SparseSets().Remove(entity, s.id)
+}
+
+// Len returns the number of entities with the component.
+//
+// See also [ecs.SparseSets.Len].
+func (s *Sparse[T]) Len() int {
+	return s.world.SparseSets().Len(s.id)
+}
+
+// Entities returns the entities with the component, in storage order.
+// The returned slice must not be modified.
+//
+// See also [ecs.SparseSets.Entities].
+func (s *Sparse[T]) Entities() []ecs.Entity {
+	return s.world.SparseSets().Entities(s.id)
+}
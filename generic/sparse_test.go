@@ -0,0 +1,36 @@
+package generic
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparse(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	sparse := NewSparse[testStruct1](&w)
+	assert.Equal(t, ecs.SparseComponentID[testStruct1](&w), sparse.ID())
+
+	e1 := posMap.New()
+	e2 := posMap.New()
+
+	assert.False(t, sparse.Has(e1))
+	assert.Nil(t, sparse.Get(e1))
+
+	sparse.Add(e1).val = 1
+	sparse.Set(e2, &testStruct1{val: 2})
+	assert.True(t, sparse.Has(e1))
+	assert.Equal(t, testStruct1{val: 1}, *sparse.Get(e1))
+	assert.Equal(t, testStruct1{val: 2}, *sparse.Get(e2))
+	sparse.Set(e1, &testStruct1{val: 3})
+	assert.Equal(t, testStruct1{val: 3}, *sparse.Get(e1))
+
+	assert.Equal(t, 2, sparse.Len())
+	assert.Equal(t, []ecs.Entity{e1, e2}, sparse.Entities())
+
+	sparse.Remove(e1)
+	assert.False(t, sparse.Has(e1))
+	assert.Equal(t, 1, sparse.Len())
+}
@@ -0,0 +1,87 @@
+package generic
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Tag provides a type-safe way to add, remove and check a tag component.
+// Tags are components of zero size, like empty structs, which carry no data.
+//
+// Tags have no storage columns. Adding or removing a tag only moves the entity
+// to another archetype, without copying data of the tag itself.
+//
+// Create one with [NewTag].
+type Tag[T any] struct {
+	id    ecs.ID
+	world *ecs.World
+}
+
+// NewTag creates a new [Tag] for a zero-sized component type.
+//
+// Panics if the type has a non-zero size.
+func NewTag[T any](w *ecs.World) Tag[T] {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	if tp.Size() != 0 {
+		panic(fmt.Sprintf("can't use %s as a tag: type has non-zero size", tp))
+	}
+	return Tag[T]{
+		id:    ecs.ComponentID[T](w),
+		world: w,
+	}
+}
+
+// ID returns the component ID for this Tag.
+func (t *Tag[T]) ID() ecs.ID {
+	return t.id
+}
+
+// Add adds the tag to an entity.
+//
+// See also [ecs.World.Add].
+func (t *Tag[T]) Add(entity ecs.Entity) {
+	t.world.Add(entity, t.id)
+}
+
+// Remove removes the tag from an entity.
+//
+// See also [ecs.World.Remove].
+func (t *Tag[T]) Remove(entity ecs.Entity) {
+	t.world.Remove(entity, t.id)
+}
+
+// Has returns whether the entity has the tag.
+//
+// See also [ecs.World.Has].
+func (t *Tag[T]) Has(entity ecs.Entity) bool {
+	return t.world.Has(entity, t.id)
+}
+
+// Toggle adds the tag to an entity if it does not have it, and removes it otherwise.
+// Returns whether the entity has the tag afterwards.
+func (t *Tag[T]) Toggle(entity ecs.Entity) bool {
+	if t.world.Has(entity, t.id) {
+		t.world.Remove(entity, t.id)
+		return false
+	}
+	t.world.Add(entity, t.id)
+	return true
+}
+
+// AddBatch adds the tag to all entities matching the given filter.
+// Returns the number of affected entities.
+//
+// See also [ecs.Batch.Add].
+func (t *Tag[T]) AddBatch(filter ecs.Filter) int {
+	return t.world.Batch().Add(filter, t.id)
+}
+
+// RemoveBatch removes the tag from all entities matching the given filter.
+// Returns the number of affected entities.
+//
+// See also [ecs.Batch.Remove].
+func (t *Tag[T]) RemoveBatch(filter ecs.Filter) int {
+	return t.world.Batch().Remove(filter, t.id)
+}
@@ -0,0 +1,41 @@
+package generic
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type enemyTag struct{}
+
+func TestTag(t *testing.T) {
+	w := ecs.NewWorld()
+	posMap := NewMap1[testStruct0](&w)
+	tag := NewTag[enemyTag](&w)
+	assert.Equal(t, ecs.ComponentID[enemyTag](&w), tag.ID())
+
+	e := posMap.NewWith(&testStruct0{val: 5})
+	assert.False(t, tag.Has(e))
+	tag.Add(e)
+	assert.True(t, tag.Has(e))
+	assert.Equal(t, testStruct0{val: 5}, *posMap.Get(e))
+	tag.Remove(e)
+	assert.False(t, tag.Has(e))
+
+	assert.True(t, tag.Toggle(e))
+	assert.True(t, tag.Has(e))
+	assert.False(t, tag.Toggle(e))
+	assert.False(t, tag.Has(e))
+
+	posMap.NewBatch(10)
+	filter := ecs.All(posMap.id0)
+	assert.Equal(t, 11, tag.AddBatch(filter))
+	assert.True(t, tag.Has(e))
+	assert.Equal(t, 11, tag.RemoveBatch(filter))
+	assert.False(t, tag.Has(e))
+
+	assert.PanicsWithValue(t, "can't use generic.testStruct0 as a tag: type has non-zero size", func() {
+		NewTag[testStruct0](&w)
+	})
+}
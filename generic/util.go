@@ -2,6 +2,7 @@ package generic
 
 import (
 	"reflect"
+	"unsafe"
 
 	"github.com/mlange-42/arche/ecs"
 )
@@ -57,6 +58,14 @@ func toMaskOptional(w *ecs.World, include []ecs.ID, optional []Comp) ecs.Mask {
 	return mask
 }
 
+// getOptional returns a pointer to a component at the query's position, or nil if the entity does not have it.
+func getOptional(q *ecs.Query, id ecs.ID) unsafe.Pointer {
+	if !q.Has(id) {
+		return nil
+	}
+	return q.Get(id)
+}
+
 func newEntity(w *ecs.World, ids []ecs.ID, relation ecs.ID, hasRelation bool, target ...ecs.Entity) ecs.Entity {
 	if len(target) == 0 {
 		return w.NewEntity(ids...)
@@ -0,0 +1,16 @@
+// Package inspect provides an opt-in HTTP handler serving JSON views of an [github.com/mlange-42/arche/ecs.World],
+// for debugging running simulations.
+//
+// The [Handler] serves the following endpoints, relative to where it is mounted:
+//
+//   - /stats — world statistics, see [github.com/mlange-42/arche/ecs.World.Stats]
+//   - /archetypes — all active archetypes with their components and entity counts
+//   - /entity?id=<ID> — the components, including sparse-set components, of the alive entity with the given ID
+//   - /query?q=<query> — the result of a read-only query, see [github.com/mlange-42/arche/sqlq]
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package inspect
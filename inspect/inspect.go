@@ -0,0 +1,231 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/stats"
+	"github.com/mlange-42/arche/sqlq"
+)
+
+// statsJSON is the JSON representation of world statistics.
+type statsJSON struct {
+	Entities      stats.Entities   // Entity statistics.
+	Components    []string         // Names of component types, indexed by component ID.
+	Locked        bool             // Whether the world is locked.
+	Nodes         int              // Number of archetype graph nodes.
+	ActiveNodes   int              // Number of active archetype graph nodes.
+	Archetypes    int              // Number of active archetypes.
+	Memory        int              // Total memory reserved for entities and components, in bytes.
+	CachedFilters int              // Number of cached filters.
+	Queries       []stats.Query    // Statistics of labeled cached filters.
+	Lifetimes     *stats.Lifetimes `json:",omitempty"` // Entity lifetime statistics, if enabled.
+	Leaks         *stats.Leaks     `json:",omitempty"` // Leaked entity statistics, if enabled.
+	Systems       []stats.System   `json:",omitempty"` // Timing statistics of systems, if run by a scheduler.
+}
+
+// archetypeJSON is the JSON representation of an archetype.
+type archetypeJSON struct {
+	Components  []string // Names of the archetype's component types.
+	HasRelation bool     // Whether the archetype has a relation component.
+	Size        int      // Number of entities in the archetype.
+	Capacity    int      // Capacity of the archetype.
+	Memory      int      // Memory reserved by the archetype, in bytes.
+}
+
+// entityJSON is the JSON representation of an entity and its components.
+type entityJSON struct {
+	Entity     ecs.Entity                 // The entity, as ID and generation.
+	Components map[string]json.RawMessage // Components by type name.
+	Sparse     map[string]json.RawMessage `json:",omitempty"` // Sparse-set components by type name.
+	Targets    map[string]ecs.Entity      `json:",omitempty"` // Relation targets by component type name.
+	Disabled   bool                       // Whether the entity is disabled.
+}
+
+// Handler is an [http.Handler] that serves JSON views of a world. See the package documentation for endpoints.
+//
+// As the world is not safe for concurrent use,
+// the handler acquires the lock given to [NewHandler] while accessing it.
+// The same lock must be held by the simulation while it modifies the world, e.g. during each update step.
+//
+// Only GET requests are served.
+type Handler struct {
+	world *ecs.World
+	lock  sync.Locker
+	mux   *http.ServeMux
+}
+
+// NewHandler creates a new [Handler] for a world.
+//
+// The lock is acquired for each request, and can be nil if the world is not modified concurrently.
+//
+// To serve the handler under a path prefix, use [http.StripPrefix]:
+//
+//	http.Handle("/debug/arche/", http.StripPrefix("/debug/arche", inspect.NewHandler(&world, &mutex)))
+func NewHandler(world *ecs.World, lock sync.Locker) *Handler {
+	h := &Handler{
+		world: world,
+		lock:  lock,
+		mux:   http.NewServeMux(),
+	}
+	h.mux.HandleFunc("/stats", h.serveStats)
+	h.mux.HandleFunc("/archetypes", h.serveArchetypes)
+	h.mux.HandleFunc("/entity", h.serveEntity)
+	h.mux.HandleFunc("/query", h.serveQuery)
+	return h
+}
+
+// ServeHTTP serves a request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.lock != nil {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// serveStats serves world statistics.
+func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
+	st := h.world.Stats()
+	data := statsJSON{
+		Entities:      st.Entities,
+		Components:    typeNames(st.ComponentTypes),
+		Locked:        st.Locked,
+		Nodes:         len(st.Nodes),
+		ActiveNodes:   st.ActiveNodeCount,
+		Memory:        st.Memory,
+		CachedFilters: st.CachedFilters,
+		Queries:       st.Queries,
+		Lifetimes:     st.Lifetimes,
+		Leaks:         st.Leaks,
+		Systems:       st.Systems,
+	}
+	for i := range st.Nodes {
+		data.Archetypes += st.Nodes[i].ActiveArchetypeCount
+	}
+	writeJSON(w, &data)
+}
+
+// serveArchetypes serves a list of all active archetypes.
+func (h *Handler) serveArchetypes(w http.ResponseWriter, r *http.Request) {
+	st := h.world.Stats()
+	data := []archetypeJSON{}
+	for i := range st.Nodes {
+		node := &st.Nodes[i]
+		if !node.IsActive {
+			continue
+		}
+		comps := typeNames(node.ComponentTypes)
+		for _, arch := range node.Archetypes {
+			if !arch.IsActive {
+				continue
+			}
+			data = append(data, archetypeJSON{
+				Components:  comps,
+				HasRelation: node.HasRelation,
+				Size:        arch.Size,
+				Capacity:    arch.Capacity,
+				Memory:      arch.Memory,
+			})
+		}
+	}
+	writeJSON(w, &data)
+}
+
+// serveEntity serves the components of an entity, given by its ID.
+func (h *Handler) serveEntity(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid or missing entity id", http.StatusBadRequest)
+		return
+	}
+	entity, ok := h.world.EntityAt(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("entity %d is not alive", id), http.StatusNotFound)
+		return
+	}
+
+	data := entityJSON{
+		Entity:     entity,
+		Components: map[string]json.RawMessage{},
+		Disabled:   h.world.IsDisabled(entity),
+	}
+	for _, compID := range h.world.Ids(entity) {
+		info, _ := ecs.ComponentInfo(h.world, compID)
+		name := info.Type.String()
+		value := reflect.NewAt(info.Type, h.world.Get(entity, compID)).Interface()
+		js, err := json.Marshal(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to serialize component %s: %s", name, err), http.StatusInternalServerError)
+			return
+		}
+		data.Components[name] = js
+		if info.IsRelation {
+			if data.Targets == nil {
+				data.Targets = map[string]ecs.Entity{}
+			}
+			data.Targets[name] = h.world.Relations().Get(entity, compID)
+		}
+	}
+	sparse := h.world.SparseSets()
+	for _, compID := range sparse.Ids(entity) {
+		tp, _ := ecs.SparseComponentType(h.world, compID)
+		name := tp.String()
+		value := reflect.NewAt(tp, sparse.Get(entity, compID)).Interface()
+		js, err := json.Marshal(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to serialize component %s: %s", name, err), http.StatusInternalServerError)
+			return
+		}
+		if data.Sparse == nil {
+			data.Sparse = map[string]json.RawMessage{}
+		}
+		data.Sparse[name] = js
+	}
+	writeJSON(w, &data)
+}
+
+// serveQuery serves the result of a query in the language of package sqlq.
+func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+	res, err := sqlq.Query(h.world, query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// typeNames returns the names of the given types.
+func typeNames(types []reflect.Type) []string {
+	names := make([]string, len(types))
+	for i, tp := range types {
+		names[i] = tp.String()
+	}
+	return names
+}
+
+// writeJSON writes a value as a JSON response.
+func writeJSON(w http.ResponseWriter, value any) {
+	js, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(js)
+}
@@ -0,0 +1,160 @@
+package inspect_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/inspect"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Velocity struct {
+	X float64
+	Y float64
+}
+
+type ChildOf struct {
+	ecs.Relation
+}
+
+type Poisoned struct {
+	Damage float64
+}
+
+func get(t *testing.T, h http.Handler, url string) (int, map[string]any, []any) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil, nil
+	}
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var obj map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &obj); err == nil {
+		return rec.Code, obj, nil
+	}
+	var arr []any
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &arr))
+	return rec.Code, nil, arr
+}
+
+func TestHandler(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+	childID := ecs.ComponentID[ChildOf](&world)
+
+	parent := world.NewEntity(posID)
+	ecs.NewBuilder(&world, posID, velID).NewBatch(10)
+	child := ecs.NewBuilder(&world, posID, childID).WithRelation(childID).New(parent)
+	(*Position)(world.Get(child, posID)).X = 5
+	poisonID := ecs.SparseComponentID[Poisoned](&world)
+	world.SparseSets().Set(child, poisonID, &Poisoned{Damage: 2})
+
+	h := inspect.NewHandler(&world, &sync.Mutex{})
+
+	code, stats, _ := get(t, h, "/stats")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 12.0, stats["Entities"].(map[string]any)["Used"])
+	assert.Equal(t, []any{"inspect_test.Position", "inspect_test.Velocity", "inspect_test.ChildOf"}, stats["Components"])
+	assert.Equal(t, 4.0, stats["Archetypes"])
+
+	code, _, arches := get(t, h, "/archetypes")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 4, len(arches))
+	last := arches[3].(map[string]any)
+	assert.Equal(t, []any{"inspect_test.Position", "inspect_test.ChildOf"}, last["Components"])
+	assert.Equal(t, true, last["HasRelation"])
+	assert.Equal(t, 1.0, last["Size"])
+
+	code, entity, _ := get(t, h, "/entity?id=12")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []any{12.0, 0.0}, entity["Entity"])
+	assert.Equal(t, map[string]any{"X": 5.0, "Y": 0.0}, entity["Components"].(map[string]any)["inspect_test.Position"])
+	assert.Equal(t, map[string]any{"inspect_test.ChildOf": []any{1.0, 0.0}}, entity["Targets"])
+	assert.Equal(t, map[string]any{"inspect_test.Poisoned": map[string]any{"Damage": 2.0}}, entity["Sparse"])
+	assert.Equal(t, false, entity["Disabled"])
+
+	world.RemoveEntity(parent)
+	code, _, _ = get(t, h, "/entity?id=1")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _, _ = get(t, h, "/entity?id=100")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _, _ = get(t, h, "/entity?id=0")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _, _ = get(t, h, "/entity")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = get(t, h, "/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stats", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandlerPrefix(t *testing.T) {
+	world := ecs.NewWorld()
+	world.NewEntity()
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/arche/", http.StripPrefix("/debug/arche", inspect.NewHandler(&world, nil)))
+
+	code, stats, _ := get(t, mux, "/debug/arche/stats")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1.0, stats["Entities"].(map[string]any)["Used"])
+}
+
+func TestHandlerQuery(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	world.NewEntity(posID)
+	e := world.NewEntity(posID, velID)
+	(*Position)(world.Get(e, posID)).X = 3
+
+	h := inspect.NewHandler(&world, nil)
+
+	code, res, _ := get(t, h, "/query?q="+url.QueryEscape("select Position.X from entities where Has(Velocity)"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []any{"Position.X"}, res["Columns"])
+	assert.Equal(t, []any{[]any{2.0, 0.0}}, res["Entities"])
+	assert.Equal(t, []any{[]any{3.0}}, res["Rows"])
+
+	code, _, _ = get(t, h, "/query?q="+url.QueryEscape("select Foo from entities"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = get(t, h, "/query")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+type moveSystem struct{}
+
+func (s *moveSystem) Initialize(w *ecs.World) {}
+func (s *moveSystem) Update(w *ecs.World)     {}
+func (s *moveSystem) Finalize(w *ecs.World)   {}
+
+func TestHandlerSystems(t *testing.T) {
+	s := systems.New()
+	s.AddSystem(&moveSystem{})
+	s.Initialize()
+	s.Update()
+
+	h := inspect.NewHandler(&s.World, nil)
+
+	code, res, _ := get(t, h, "/stats")
+	assert.Equal(t, http.StatusOK, code)
+	sys := res["Systems"].([]any)
+	assert.Equal(t, 1, len(sys))
+	assert.Equal(t, "moveSystem", sys[0].(map[string]any)["Name"])
+	assert.Equal(t, 1.0, sys[0].(map[string]any)["Runs"])
+}
@@ -0,0 +1,185 @@
+package interp
+
+import (
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/generic"
+)
+
+// LerpFunc interpolates between two component states, and writes the result to out.
+// Parameter t is in the range [0, 1], where 0 corresponds to from and 1 to to.
+type LerpFunc[T any] func(from, to *T, t float64, out *T)
+
+// state is a timestamped component state.
+type state[T any] struct {
+	Time  float64
+	Value T
+}
+
+// history is a ring buffer of timestamped states, ordered by time.
+type history[T any] struct {
+	states []state[T]
+	start  int
+}
+
+// Len returns the number of states.
+func (h *history[T]) Len() int {
+	return len(h.states)
+}
+
+// At returns the state at the given index, counted from the oldest state.
+func (h *history[T]) At(index int) *state[T] {
+	return &h.states[(h.start+index)%len(h.states)]
+}
+
+// Buffer keeps the last N replicated states of a component per entity, with timestamps,
+// and samples interpolated states into the live components of a world.
+//
+// States are added with [Buffer.Push], typically when receiving authoritative snapshots from a server.
+// [Buffer.Apply] writes the interpolated states for a given render time into the live components,
+// typically once per frame with a render time slightly behind the latest received snapshot.
+//
+// Times are in arbitrary, but consistent units, like seconds or ticks.
+//
+// Example:
+//
+//	lerp := func(from, to *Position, t float64, out *Position) {
+//		out.X = from.X + (to.X-from.X)*t
+//		out.Y = from.Y + (to.Y-from.Y)*t
+//	}
+//	buffer := interp.NewBuffer(&world, 3, lerp)
+//
+//	// When receiving a snapshot.
+//	buffer.Push(entity, serverTime, &pos)
+//
+//	// When rendering.
+//	buffer.Apply(renderTime)
+type Buffer[T any] struct {
+	mapper   generic.Map[T]
+	world    *ecs.World
+	lerp     LerpFunc[T]
+	size     int
+	entities map[ecs.Entity]*history[T]
+}
+
+// NewBuffer creates a new [Buffer] that keeps up to size states per entity.
+//
+// Panics if size is smaller than 2.
+func NewBuffer[T any](world *ecs.World, size int, lerp LerpFunc[T]) *Buffer[T] {
+	if size < 2 {
+		panic("interpolation buffer size must be at least 2")
+	}
+	return &Buffer[T]{
+		mapper:   generic.NewMap[T](world),
+		world:    world,
+		lerp:     lerp,
+		size:     size,
+		entities: map[ecs.Entity]*history[T]{},
+	}
+}
+
+// Push adds a state of an entity's component at the given time.
+//
+// When the buffer for the entity is full, the oldest state is dropped.
+// A state with the same time as the entity's latest state replaces it.
+// States older than the entity's latest state arrived out of order, and are ignored.
+// Returns whether the state was added.
+func (b *Buffer[T]) Push(entity ecs.Entity, time float64, value *T) bool {
+	h, ok := b.entities[entity]
+	if !ok {
+		h = &history[T]{states: make([]state[T], 0, b.size)}
+		b.entities[entity] = h
+	}
+	n := h.Len()
+	if n > 0 {
+		latest := h.At(n - 1)
+		if time < latest.Time {
+			return false
+		}
+		if time == latest.Time {
+			latest.Value = *value
+			return true
+		}
+	}
+	if n < b.size {
+		h.states = append(h.states, state[T]{Time: time, Value: *value})
+		return true
+	}
+	h.states[h.start] = state[T]{Time: time, Value: *value}
+	h.start = (h.start + 1) % n
+	return true
+}
+
+// Sample returns the interpolated state of an entity at the given time.
+//
+// Times before the oldest or after the latest state are clamped to these states, i.e. there is no extrapolation.
+// Returns false if there are no states for the entity.
+func (b *Buffer[T]) Sample(entity ecs.Entity, time float64) (T, bool) {
+	var out T
+	h, ok := b.entities[entity]
+	if !ok || h.Len() == 0 {
+		return out, false
+	}
+	b.sample(h, time, &out)
+	return out, true
+}
+
+// Apply writes the interpolated states at the given time into the live components of all buffered entities.
+//
+// Entities that were removed from the world are removed from the buffer.
+// Entities that do not have the component are skipped.
+// Returns the number of updated entities.
+func (b *Buffer[T]) Apply(time float64) int {
+	count := 0
+	for entity, h := range b.entities {
+		if !b.world.Alive(entity) {
+			delete(b.entities, entity)
+			continue
+		}
+		if !b.mapper.HasUnchecked(entity) {
+			continue
+		}
+		b.sample(h, time, b.mapper.GetUnchecked(entity))
+		count++
+	}
+	return count
+}
+
+// Remove removes all states of an entity from the buffer.
+func (b *Buffer[T]) Remove(entity ecs.Entity) {
+	delete(b.entities, entity)
+}
+
+// Len returns the number of buffered states of an entity.
+func (b *Buffer[T]) Len(entity ecs.Entity) int {
+	if h, ok := b.entities[entity]; ok {
+		return h.Len()
+	}
+	return 0
+}
+
+// Reset removes all states from the buffer.
+func (b *Buffer[T]) Reset() {
+	clear(b.entities)
+}
+
+// sample interpolates the states of a non-empty history at the given time.
+func (b *Buffer[T]) sample(h *history[T], time float64, out *T) {
+	n := h.Len()
+	if first := h.At(0); time <= first.Time {
+		*out = first.Value
+		return
+	}
+	if last := h.At(n - 1); time >= last.Time {
+		*out = last.Value
+		return
+	}
+	for i := 1; i < n; i++ {
+		to := h.At(i)
+		if time > to.Time {
+			continue
+		}
+		from := h.At(i - 1)
+		b.lerp(&from.Value, &to.Value, (time-from.Time)/(to.Time-from.Time), out)
+		return
+	}
+}
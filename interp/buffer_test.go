@@ -0,0 +1,99 @@
+package interp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/interp"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Velocity struct {
+	X float64
+	Y float64
+}
+
+func lerpPosition(from, to *Position, t float64, out *Position) {
+	out.X = from.X + (to.X-from.X)*t
+	out.Y = from.Y + (to.Y-from.Y)*t
+}
+
+func TestBuffer(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	e1 := world.NewEntity(posID)
+	e2 := world.NewEntity(velID)
+
+	buffer := interp.NewBuffer(&world, 3, lerpPosition)
+
+	_, ok := buffer.Sample(e1, 0)
+	assert.False(t, ok)
+
+	assert.True(t, buffer.Push(e1, 1, &Position{X: 10}))
+	assert.True(t, buffer.Push(e1, 2, &Position{X: 20, Y: 10}))
+	assert.False(t, buffer.Push(e1, 1.5, &Position{X: 100}))
+	assert.Equal(t, 2, buffer.Len(e1))
+
+	pos, ok := buffer.Sample(e1, 1.5)
+	assert.True(t, ok)
+	assert.Equal(t, Position{X: 15, Y: 5}, pos)
+	pos, _ = buffer.Sample(e1, 0)
+	assert.Equal(t, Position{X: 10}, pos)
+	pos, _ = buffer.Sample(e1, 5)
+	assert.Equal(t, Position{X: 20, Y: 10}, pos)
+
+	assert.True(t, buffer.Push(e1, 2, &Position{X: 30}))
+	assert.True(t, buffer.Push(e1, 3, &Position{X: 40}))
+	assert.True(t, buffer.Push(e1, 4, &Position{X: 50}))
+	assert.Equal(t, 3, buffer.Len(e1))
+
+	pos, _ = buffer.Sample(e1, 1)
+	assert.Equal(t, Position{X: 30}, pos)
+	pos, _ = buffer.Sample(e1, 3.5)
+	assert.Equal(t, Position{X: 45}, pos)
+
+	buffer.Push(e2, 1, &Position{X: 1})
+	assert.Equal(t, 1, buffer.Apply(2.5))
+	assert.Equal(t, Position{X: 35}, *(*Position)(world.Get(e1, posID)))
+
+	world.RemoveEntity(e1)
+	assert.Equal(t, 0, buffer.Apply(2.5))
+	assert.Equal(t, 0, buffer.Len(e1))
+	assert.Equal(t, 1, buffer.Len(e2))
+
+	buffer.Remove(e2)
+	assert.Equal(t, 0, buffer.Len(e2))
+
+	buffer.Push(e2, 1, &Position{X: 1})
+	buffer.Reset()
+	assert.Equal(t, 0, buffer.Len(e2))
+
+	assert.PanicsWithValue(t, "interpolation buffer size must be at least 2", func() {
+		interp.NewBuffer(&world, 1, lerpPosition)
+	})
+}
+
+func ExampleBuffer() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	entity := world.NewEntity(posID)
+
+	buffer := interp.NewBuffer(&world, 3, lerpPosition)
+
+	// Authoritative states received from a server.
+	buffer.Push(entity, 0.0, &Position{X: 0, Y: 0})
+	buffer.Push(entity, 0.1, &Position{X: 10, Y: 20})
+
+	// Render between the states.
+	buffer.Apply(0.025)
+	fmt.Println(*(*Position)(world.Get(entity, posID)))
+	// Output: {2.5 5}
+}
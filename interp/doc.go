@@ -0,0 +1,13 @@
+// Package interp provides client-side interpolation buffers for replicated component state.
+//
+// A [Buffer] keeps the last N authoritative states of a component per entity, with timestamps,
+// e.g. as received from server snapshots. [Buffer.Apply] samples the states at a render time
+// and writes the interpolated values into the live components of an [github.com/mlange-42/arche/ecs.World].
+// This allows render worlds to smooth between snapshots that arrive at a lower rate than frames are rendered.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package interp
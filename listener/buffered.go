@@ -0,0 +1,76 @@
+package listener
+
+import (
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// Buffered listener that collects events and delivers them to a wrapped listener in a batch.
+//
+// Events are delivered in the order they occurred, at the end of each tick
+// (see [ecs.World.Tick] and [ecs.TickListener]), or when calling [Buffered.Flush] manually.
+// This avoids the cost of synchronous delivery in hot loops,
+// and prevents the wrapped listener from seeing half-applied batch operations.
+//
+// Note that the wrapped listener sees the world in its state at the flush point, not at the time of the event.
+// Particularly, entities of events may have been removed in the meantime, which can be checked with [ecs.World.Alive].
+// Events emitted while flushing are delivered in the same flush.
+//
+// Subscriptions are those of the wrapped listener.
+// To use a Buffered listener together with other listeners, add it to a [Dispatch].
+type Buffered struct {
+	listener ecs.Listener
+	events   []ecs.EntityEvent
+	flushing bool
+}
+
+// NewBuffered creates a new [Buffered] listener, wrapping the given listener.
+func NewBuffered(listener ecs.Listener) Buffered {
+	return Buffered{
+		listener: listener,
+	}
+}
+
+// Len returns the number of buffered events.
+func (l *Buffered) Len() int {
+	return len(l.events)
+}
+
+// Flush delivers all buffered events to the wrapped listener, in the order they occurred.
+func (l *Buffered) Flush(world *ecs.World) {
+	if l.flushing {
+		return
+	}
+	l.flushing = true
+	defer func() { l.flushing = false }()
+
+	for i := 0; i < len(l.events); i++ {
+		l.listener.Notify(world, l.events[i])
+	}
+	for i := range l.events {
+		l.events[i] = ecs.EntityEvent{}
+	}
+	l.events = l.events[:0]
+}
+
+// Tick flushes the listener. Called by [ecs.World.Tick].
+func (l *Buffered) Tick(world *ecs.World) {
+	l.Flush(world)
+}
+
+// Notify the listener.
+//
+// Buffers a copy of the event, as slices and pointers of events are only valid during notification.
+func (l *Buffered) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	l.events = append(l.events, copyEvent(&evt))
+}
+
+// Subscriptions of the listener.
+func (l *Buffered) Subscriptions() event.Subscription {
+	return l.listener.Subscriptions()
+}
+
+// Components the listener subscribes to.
+func (l *Buffered) Components() *ecs.Mask {
+	return l.listener.Components()
+}
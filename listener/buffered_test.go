@@ -0,0 +1,74 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffered(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	evt := []ecs.EntityEvent{}
+	cb := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			evt = append(evt, e)
+		},
+		event.Entities|event.Components,
+		posID,
+	)
+	ls := listener.NewBuffered(&cb)
+	w.SetListener(&ls)
+
+	assert.Equal(t, event.Entities|event.Components, ls.Subscriptions())
+	assert.Equal(t, ecs.All(posID), *ls.Components())
+
+	e1 := w.NewEntity(posID)
+	w.NewEntity(velID)
+	w.Add(e1, velID)
+	w.Remove(e1, posID)
+	assert.Equal(t, 2, ls.Len())
+	assert.Empty(t, evt)
+
+	w.Tick()
+	assert.Equal(t, 0, ls.Len())
+	assert.Equal(t, 2, len(evt))
+	assert.Equal(t, e1, evt[0].Entity)
+	assert.True(t, evt[0].Contains(event.EntityCreated))
+	assert.Equal(t, []ecs.ID{posID}, evt[0].AddedIDs)
+	assert.True(t, evt[1].Contains(event.ComponentRemoved))
+	assert.Equal(t, []ecs.ID{posID}, evt[1].RemovedIDs)
+
+	w.NewEntity(posID)
+	ls.Flush(&w)
+	assert.Equal(t, 3, len(evt))
+	ls.Flush(&w)
+	assert.Equal(t, 3, len(evt))
+}
+
+func TestBufferedDispatch(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	count := 0
+	cb := listener.NewCallback(
+		func(w *ecs.World, e ecs.EntityEvent) {
+			count++
+		},
+		event.EntityCreated,
+	)
+	buf := listener.NewBuffered(&cb)
+	ls := listener.NewDispatch(&buf)
+	w.SetListener(&ls)
+
+	w.NewEntity(posID)
+	w.NewEntity(posID)
+	assert.Equal(t, 0, count)
+	w.Tick()
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,123 @@
+package listener
+
+import (
+	"sync/atomic"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// Overflow is the policy of a [Channel] listener for events that don't fit into its channel.
+type Overflow uint8
+
+const (
+	// DropNewest drops events that don't fit into the channel. This is the default.
+	DropNewest Overflow = iota
+	// DropOldest drops the oldest event in the channel to make room for a new one.
+	DropOldest
+	// Block blocks the world until the consumer makes room in the channel.
+	// Use with care, as a slow or stopped consumer stalls the world.
+	Block
+)
+
+// Channel listener that pushes events into a buffered channel,
+// for consumption by other goroutines, like logging or telemetry, off the hot path.
+//
+// Events are copied, so consumers can use them safely.
+// Consumers must not access the world, except for synchronized accesses.
+//
+// When the channel is full, events are dropped or delivery blocks, depending on the [Overflow] policy.
+// Dropped events are counted, see [Channel.Dropped].
+//
+// To use a Channel together with other listeners, add it to a [Dispatch].
+type Channel struct {
+	events   chan ecs.EntityEvent
+	subs     event.Subscription
+	overflow Overflow
+	dropped  atomic.Uint64
+	closed   bool
+}
+
+// NewChannel creates a new [Channel] listener for the given event types, with a channel buffer of the given size.
+// Returns the listener, and the channel to receive events from.
+//
+// The listener subscribes to all components.
+// Set the [Overflow] policy with [Channel.SetOverflow]; the default is [DropNewest].
+//
+// Example:
+//
+//	ls, events := listener.NewChannel(event.Entities, 1024)
+//	world.SetListener(ls)
+//
+//	go func() {
+//		for evt := range events {
+//			log.Println(evt.Entity, evt.EventTypes)
+//		}
+//	}()
+func NewChannel(subs event.Subscription, buf int) (*Channel, <-chan ecs.EntityEvent) {
+	l := &Channel{
+		events: make(chan ecs.EntityEvent, buf),
+		subs:   subs,
+	}
+	return l, l.events
+}
+
+// SetOverflow sets the policy for events that don't fit into the channel.
+func (l *Channel) SetOverflow(policy Overflow) {
+	l.overflow = policy
+}
+
+// Dropped returns the number of events dropped due to a full channel.
+// Can be called from any goroutine.
+func (l *Channel) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// Close closes the channel, so that consumers ranging over it terminate.
+// Events after closing are ignored.
+func (l *Channel) Close() {
+	if l.closed {
+		return
+	}
+	l.closed = true
+	close(l.events)
+}
+
+// Notify the listener.
+func (l *Channel) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	if l.closed {
+		return
+	}
+	cp := copyEvent(&evt)
+	switch l.overflow {
+	case Block:
+		l.events <- cp
+		return
+	case DropOldest:
+		select {
+		case l.events <- cp:
+			return
+		default:
+		}
+		select {
+		case <-l.events:
+			l.dropped.Add(1)
+		default:
+		}
+	}
+	select {
+	case l.events <- cp:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Subscriptions of the listener.
+func (l *Channel) Subscriptions() event.Subscription {
+	return l.subs
+}
+
+// Components the listener subscribes to.
+func (l *Channel) Components() *ecs.Mask {
+	return nil
+}
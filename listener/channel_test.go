@@ -0,0 +1,70 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannel(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	ls, events := listener.NewChannel(event.EntityCreated|event.ComponentAdded, 2)
+	w.SetListener(ls)
+	assert.Equal(t, event.EntityCreated|event.ComponentAdded, ls.Subscriptions())
+	assert.Nil(t, ls.Components())
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(posID)
+	w.NewEntity(posID)
+	assert.Equal(t, uint64(1), ls.Dropped())
+
+	evt := <-events
+	assert.Equal(t, e1, evt.Entity)
+	assert.Equal(t, []ecs.ID{posID}, evt.AddedIDs)
+	evt = <-events
+	assert.Equal(t, e2, evt.Entity)
+
+	ls.SetOverflow(listener.DropOldest)
+	w.NewEntity(posID)
+	e4 := w.NewEntity(posID)
+	e5 := w.NewEntity(posID)
+	assert.Equal(t, uint64(2), ls.Dropped())
+	assert.Equal(t, e4, (<-events).Entity)
+	assert.Equal(t, e5, (<-events).Entity)
+
+	ls.Close()
+	ls.Close()
+	w.NewEntity(posID)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestChannelBlock(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	ls, events := listener.NewChannel(event.EntityCreated, 0)
+	ls.SetOverflow(listener.Block)
+	w.SetListener(ls)
+
+	received := make(chan int)
+	go func() {
+		count := 0
+		for range events {
+			count++
+		}
+		received <- count
+	}()
+
+	for i := 0; i < 100; i++ {
+		w.NewEntity(posID)
+	}
+	ls.Close()
+	assert.Equal(t, 100, <-received)
+	assert.Equal(t, uint64(0), ls.Dropped())
+}
@@ -57,12 +57,25 @@ func (l *Dispatch) AddListener(ls ecs.Listener) {
 func (l *Dispatch) Notify(world *ecs.World, evt ecs.EntityEvent) {
 	for _, ls := range l.listeners {
 		trigger := ls.Subscriptions() & evt.EventTypes
-		if trigger != 0 && subscribes(trigger, &evt.Added, &evt.Removed, ls.Components(), evt.OldRelation, evt.NewRelation) {
+		if trigger == 0 {
+			continue
+		}
+		subs := ls.Components()
+		if subscribes(trigger, &evt.Added, &evt.Removed, subs, evt.OldRelation, evt.NewRelation) || subscribesSet(trigger, subs, evt.SetID) {
 			ls.Notify(world, evt)
 		}
 	}
 }
 
+// Tick notifies all sub-listeners that implement [ecs.TickListener]. Called by [ecs.World.Tick].
+func (l *Dispatch) Tick(world *ecs.World) {
+	for _, ls := range l.listeners {
+		if tl, ok := ls.(ecs.TickListener); ok {
+			tl.Tick(world)
+		}
+	}
+}
+
 // Subscriptions of the listener.
 func (l *Dispatch) Subscriptions() event.Subscription {
 	return l.events
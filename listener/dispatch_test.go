@@ -201,3 +201,25 @@ func ExampleDispatch() {
 	// Component event on Position
 	// Entity event
 }
+
+func TestDispatchSet(t *testing.T) {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	h1 := EventHandler{}
+	l1 := listener.NewCallback(h1.Notify, event.ComponentSet, posID)
+	h2 := EventHandler{}
+	l2 := listener.NewCallback(h2.Notify, event.All)
+
+	ls := listener.NewDispatch(&l1, &l2)
+	world.SetListener(&ls)
+
+	e := world.NewEntity(posID, velID)
+	world.Set(e, posID, &Position{X: 1})
+	world.Set(e, velID, &Velocity{X: 1})
+
+	assert.Equal(t, 1, len(h1.events))
+	assert.Equal(t, posID, *h1.events[0].SetID)
+	assert.Equal(t, 1, len(h2.events))
+}
@@ -0,0 +1,90 @@
+package listener
+
+import (
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// Observer listener that collects entities that start to match a filter.
+//
+// Entities are collected when they are created matching the filter,
+// or when component additions or removals make them match the filter.
+// Use [Observer.Drain] to retrieve them, e.g. once per frame.
+// This avoids scanning whole archetypes for the few entities that just changed.
+//
+// Entities that stop matching the filter or are removed before the next call to [Observer.Drain] are dropped.
+// Relation targets of an ecs.RelationFilter are ignored (see [github.com/mlange-42/arche/ecs.RelationFilter]).
+//
+// To use an Observer together with other listeners, add it to a [Dispatch].
+type Observer struct {
+	filter   ecs.Filter
+	entities []ecs.Entity
+	pending  map[ecs.Entity]struct{}
+	drained  []ecs.Entity
+}
+
+// NewObserver creates a new [Observer] for the given filter.
+func NewObserver(filter ecs.Filter) Observer {
+	return Observer{
+		filter:  filter,
+		pending: map[ecs.Entity]struct{}{},
+	}
+}
+
+// Drain returns the entities that started to match the observer's filter since the last call,
+// in the order in which they started to match, and clears them from the observer.
+//
+// The returned slice is re-used and only valid until the next call to Drain.
+func (l *Observer) Drain() []ecs.Entity {
+	l.drained = l.drained[:0]
+	for _, e := range l.entities {
+		if _, ok := l.pending[e]; ok {
+			l.drained = append(l.drained, e)
+			delete(l.pending, e)
+		}
+	}
+	l.entities = l.entities[:0]
+	return l.drained
+}
+
+// Len returns the number of entities that are currently collected.
+func (l *Observer) Len() int {
+	return len(l.pending)
+}
+
+// Notify the listener.
+func (l *Observer) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	if evt.Contains(event.EntityRemoved) {
+		delete(l.pending, evt.Entity)
+		return
+	}
+
+	mask := world.Mask(evt.Entity)
+	if !l.filter.Matches(&mask) {
+		delete(l.pending, evt.Entity)
+		return
+	}
+	if !evt.Contains(event.EntityCreated) {
+		notAdded := evt.Added.Not()
+		old := mask.And(&notAdded)
+		old = old.Or(&evt.Removed)
+		if l.filter.Matches(&old) {
+			return
+		}
+	}
+	if _, ok := l.pending[evt.Entity]; ok {
+		return
+	}
+	l.pending[evt.Entity] = struct{}{}
+	l.entities = append(l.entities, evt.Entity)
+}
+
+// Subscriptions of the listener.
+func (l *Observer) Subscriptions() event.Subscription {
+	return event.Entities | event.Components
+}
+
+// Components the listener subscribes to.
+func (l *Observer) Components() *ecs.Mask {
+	return nil
+}
@@ -0,0 +1,96 @@
+package listener_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	filter := ecs.All(posID).Without(velID)
+	obs := listener.NewObserver(&filter)
+	w.SetListener(&obs)
+
+	assert.Equal(t, event.Entities|event.Components, obs.Subscriptions())
+	assert.Nil(t, obs.Components())
+
+	e1 := w.NewEntity(posID)
+	e2 := w.NewEntity(velID)
+	e3 := w.NewEntity(posID, velID)
+	e4 := w.NewEntity(posID)
+
+	assert.Equal(t, 2, obs.Len())
+	assert.Equal(t, []ecs.Entity{e1, e4}, obs.Drain())
+	assert.Equal(t, 0, obs.Len())
+	assert.Empty(t, obs.Drain())
+
+	w.Exchange(e2, []ecs.ID{posID}, []ecs.ID{velID})
+	w.Remove(e3, velID)
+	w.Remove(e4, posID)
+	w.Add(e4, posID)
+	assert.Equal(t, []ecs.Entity{e2, e3, e4}, obs.Drain())
+
+	// Entities that match already are not collected again.
+	w.Add(e1, velID)
+	w.Remove(e1, velID)
+	assert.Equal(t, []ecs.Entity{e1}, obs.Drain())
+
+	// Entities that stop matching or are removed are dropped.
+	e5 := w.NewEntity(posID)
+	e6 := w.NewEntity(posID)
+	w.Add(e5, velID)
+	w.RemoveEntity(e6)
+	assert.Equal(t, 0, obs.Len())
+	assert.Empty(t, obs.Drain())
+
+	// Leaving and re-entering before draining collects once.
+	w.Remove(e5, velID)
+	w.Add(e5, velID)
+	w.Remove(e5, velID)
+	assert.Equal(t, []ecs.Entity{e5}, obs.Drain())
+
+	ecs.NewBuilder(&w, posID).NewBatch(10)
+	assert.Equal(t, 10, len(obs.Drain()))
+}
+
+func TestObserverDispatch(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+
+	obs := listener.NewObserver(ecs.All(posID, velID))
+	counter := 0
+	cb := listener.NewCallback(func(w *ecs.World, e ecs.EntityEvent) { counter++ }, event.EntityCreated)
+	ls := listener.NewDispatch(&obs, &cb)
+	w.SetListener(&ls)
+
+	e := w.NewEntity(posID)
+	w.Add(e, velID)
+	assert.Equal(t, 1, counter)
+	assert.Equal(t, []ecs.Entity{e}, obs.Drain())
+}
+
+func ExampleObserver() {
+	world := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&world)
+	velID := ecs.ComponentID[Velocity](&world)
+
+	obs := listener.NewObserver(ecs.All(posID, velID))
+	world.SetListener(&obs)
+
+	e := world.NewEntity(posID)
+	world.Add(e, velID)
+
+	for _, e := range obs.Drain() {
+		fmt.Println(world.Has(e, velID))
+	}
+	// Output: true
+}
@@ -0,0 +1,309 @@
+package listener
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+)
+
+// record is a single recorded event, written as one line of JSON.
+type record struct {
+	Entity ecs.Entity                 // The entity that was changed.
+	Create bool                       `json:",omitempty"` // Whether the entity was created.
+	Remove bool                       `json:",omitempty"` // Whether the entity was removed.
+	Add    map[string]json.RawMessage `json:",omitempty"` // Added components with their values, by type name.
+	Del    []string                   `json:",omitempty"` // Removed components, by type name.
+	Set    map[string]json.RawMessage `json:",omitempty"` // Overwritten component values, by type name.
+	Target *ecs.Entity                `json:",omitempty"` // New relation target, for relation and target changes.
+}
+
+// Recorder listener that writes all events to a log, for replaying them with [Replay].
+//
+// The log contains entity creation and removal, component additions and removals with component values,
+// relation target changes and value changes of components.
+// Component values are marshaled using [encoding/json], so only exported fields are recorded.
+// The log is written as one JSON object per line.
+//
+// Values of added components are recorded at the time of the event.
+// Later value changes are recorded for [event.ComponentSet] events,
+// i.e. for [github.com/mlange-42/arche/ecs.World.Set] and similar methods.
+// Values written through component pointers can be recorded explicitly with [Recorder.RecordSet].
+//
+// Writing stops at the first error, which is reported by [Recorder.Err].
+// The recorder does not buffer the writer, so wrapping it in a [bufio.Writer] is recommended.
+//
+// To use a Recorder together with other listeners, add it to a [Dispatch].
+type Recorder struct {
+	encoder *json.Encoder
+	names   map[ecs.ID]string
+	err     error
+}
+
+// NewRecorder creates a new [Recorder] that writes to the given writer.
+func NewRecorder(out io.Writer) *Recorder {
+	return &Recorder{
+		encoder: json.NewEncoder(out),
+		names:   map[ecs.ID]string{},
+	}
+}
+
+// Err returns the first error that occurred during recording, or nil.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// RecordSet records the current value of a component of an entity,
+// e.g. after it was changed through a component pointer.
+func (r *Recorder) RecordSet(world *ecs.World, entity ecs.Entity, comp ecs.ID) {
+	rec := record{Entity: entity, Set: map[string]json.RawMessage{}}
+	r.addValue(world, entity, comp, rec.Set)
+	r.write(&rec)
+}
+
+// Notify the listener.
+func (r *Recorder) Notify(world *ecs.World, evt ecs.EntityEvent) {
+	if r.err != nil {
+		return
+	}
+	rec := record{Entity: evt.Entity}
+
+	if evt.Contains(event.EntityRemoved) {
+		rec.Remove = true
+		r.write(&rec)
+		return
+	}
+	if evt.Contains(event.ComponentSet) {
+		rec.Set = map[string]json.RawMessage{}
+		r.addValue(world, evt.Entity, *evt.SetID, rec.Set)
+		r.write(&rec)
+		return
+	}
+
+	added := evt.AddedIDs
+	if evt.Contains(event.EntityCreated) {
+		rec.Create = true
+		added = world.Ids(evt.Entity)
+	}
+	if len(added) > 0 {
+		rec.Add = map[string]json.RawMessage{}
+		for _, id := range added {
+			r.addValue(world, evt.Entity, id, rec.Add)
+		}
+	}
+	for _, id := range evt.RemovedIDs {
+		rec.Del = append(rec.Del, r.name(world, id))
+	}
+	if evt.NewRelation != nil {
+		target := world.Relations().Get(evt.Entity, *evt.NewRelation)
+		rec.Target = &target
+	}
+	r.write(&rec)
+}
+
+// Subscriptions of the listener.
+func (r *Recorder) Subscriptions() event.Subscription {
+	return event.All | event.ComponentSet
+}
+
+// Components the listener subscribes to.
+func (r *Recorder) Components() *ecs.Mask {
+	return nil
+}
+
+// addValue adds the marshaled value of a component to a map.
+func (r *Recorder) addValue(world *ecs.World, entity ecs.Entity, id ecs.ID, values map[string]json.RawMessage) {
+	name := r.name(world, id)
+	info, _ := ecs.ComponentInfo(world, id)
+	js, err := json.Marshal(reflect.NewAt(info.Type, world.Get(entity, id)).Interface())
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to record component %s: %w", name, err)
+	}
+	values[name] = js
+}
+
+// write writes a record, unless an error occurred before.
+func (r *Recorder) write(rec *record) {
+	if r.err != nil {
+		return
+	}
+	r.err = r.encoder.Encode(rec)
+}
+
+// name returns the type name of a component.
+func (r *Recorder) name(world *ecs.World, id ecs.ID) string {
+	if name, ok := r.names[id]; ok {
+		return name
+	}
+	info, _ := ecs.ComponentInfo(world, id)
+	name := info.Type.String()
+	r.names[id] = name
+	return name
+}
+
+// Replay re-applies a log written by a [Recorder] to a world.
+// Returns the number of replayed records.
+//
+// This is a shortcut for a one-time replay with a new [Mirror]. See there for details.
+func Replay(world *ecs.World, in io.Reader) (int, error) {
+	return NewMirror(world).Replay(in)
+}
+
+// Mirror replays logs written by a [Recorder] into a secondary world, like a render-thread copy or a spectator world.
+//
+// A mirror maps entities of the recorded world to entities of the secondary world,
+// and keeps that mapping between calls to [Mirror.Replay].
+// Thus, a recorded event stream can be replayed in chunks, e.g. once per frame.
+// Use [Mirror.Entity] to get the mirrored entity of a recorded entity.
+//
+// The world should be fresh, or in the state of the recorded world when recording started.
+// All recorded component types must be registered in the world, e.g. with [ecs.ComponentID].
+// Replayed entities may have other IDs than recorded ones.
+// Cascade policies (see [ecs.World.SetCascade]) and other automatic changes should not be used in the world,
+// as their effects are part of the log.
+type Mirror struct {
+	world    *ecs.World
+	compIDs  map[string]ecs.ID
+	entities map[ecs.Entity]ecs.Entity
+}
+
+// NewMirror creates a new [Mirror] that replays logs into the given world.
+func NewMirror(world *ecs.World) *Mirror {
+	return &Mirror{
+		world:    world,
+		compIDs:  map[string]ecs.ID{},
+		entities: map[ecs.Entity]ecs.Entity{},
+	}
+}
+
+// Entity returns the mirrored entity for an entity of the recorded world,
+// and whether there is such an entity.
+func (m *Mirror) Entity(recorded ecs.Entity) (ecs.Entity, bool) {
+	e, ok := m.entities[recorded]
+	return e, ok
+}
+
+// Len returns the number of mirrored entities.
+func (m *Mirror) Len() int {
+	return len(m.entities)
+}
+
+// Replay re-applies a log written by a [Recorder] to the mirror's world, until the end of the reader.
+// Returns the number of replayed records.
+//
+// Returns an error for malformed logs, unregistered component types,
+// or records of entities that were not created by logs replayed by this mirror.
+// Changes of records before the failing one remain applied.
+// Panics when called on a locked world.
+func (m *Mirror) Replay(in io.Reader) (int, error) {
+	decoder := json.NewDecoder(in)
+	count := 0
+	for {
+		rec := record{}
+		if err := decoder.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			return count, err
+		}
+		if err := m.apply(&rec); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// resolve returns the component ID for a type name.
+func (m *Mirror) resolve(name string) (ecs.ID, error) {
+	if id, ok := m.compIDs[name]; ok {
+		return id, nil
+	}
+	for _, id := range ecs.ComponentIDs(m.world) {
+		info, _ := ecs.ComponentInfo(m.world, id)
+		m.compIDs[info.Type.String()] = id
+	}
+	id, ok := m.compIDs[name]
+	if !ok {
+		return id, fmt.Errorf("component type %s is not registered", name)
+	}
+	return id, nil
+}
+
+// apply applies a single record to the world.
+func (m *Mirror) apply(rec *record) error {
+	world := m.world
+
+	add := make([]ecs.ID, 0, len(rec.Add))
+	relation, hasRelation := ecs.ID{}, false
+	for name := range rec.Add {
+		id, err := m.resolve(name)
+		if err != nil {
+			return err
+		}
+		add = append(add, id)
+		if info, _ := ecs.ComponentInfo(world, id); info.IsRelation {
+			relation, hasRelation = id, true
+		}
+	}
+	del := make([]ecs.ID, 0, len(rec.Del))
+	for _, name := range rec.Del {
+		id, err := m.resolve(name)
+		if err != nil {
+			return err
+		}
+		del = append(del, id)
+	}
+	var target ecs.Entity
+	if rec.Target != nil && !rec.Target.IsZero() {
+		var ok bool
+		if target, ok = m.entities[*rec.Target]; !ok {
+			return fmt.Errorf("relation target %v was not created by the log", *rec.Target)
+		}
+	}
+
+	var entity ecs.Entity
+	if rec.Create {
+		entity = world.NewEntity()
+		m.entities[rec.Entity] = entity
+	} else {
+		var ok bool
+		if entity, ok = m.entities[rec.Entity]; !ok || !world.Alive(entity) {
+			return fmt.Errorf("entity %v was not created by the log", rec.Entity)
+		}
+	}
+
+	switch {
+	case rec.Remove:
+		world.RemoveEntity(entity)
+		delete(m.entities, rec.Entity)
+	case hasRelation:
+		world.Relations().Exchange(entity, add, del, relation, target)
+	case len(add) > 0 || len(del) > 0:
+		world.Exchange(entity, add, del)
+	case rec.Target != nil:
+		for _, id := range world.Ids(entity) {
+			if info, _ := ecs.ComponentInfo(world, id); info.IsRelation {
+				world.Relations().Set(entity, id, target)
+				break
+			}
+		}
+	}
+
+	for _, values := range []map[string]json.RawMessage{rec.Add, rec.Set} {
+		for name, js := range values {
+			id, err := m.resolve(name)
+			if err != nil {
+				return err
+			}
+			info, _ := ecs.ComponentInfo(world, id)
+			if err := json.Unmarshal(js, reflect.NewAt(info.Type, world.Get(entity, id)).Interface()); err != nil {
+				return fmt.Errorf("failed to replay component %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
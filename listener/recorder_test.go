@@ -0,0 +1,166 @@
+package listener_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/event"
+	"github.com/mlange-42/arche/generic"
+	"github.com/mlange-42/arche/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	relID := ecs.ComponentID[Relation1](&w)
+
+	buf := bytes.Buffer{}
+	rec := listener.NewRecorder(&buf)
+	w.SetListener(rec)
+
+	assert.Equal(t, event.All|event.ComponentSet, rec.Subscriptions())
+	assert.Nil(t, rec.Components())
+
+	parent := w.NewEntityWith(ecs.Component{ID: posID, Comp: &Position{X: 1, Y: 2}})
+	e1 := w.NewEntity(posID)
+	w.Set(e1, posID, &Position{X: 3})
+	w.Add(e1, velID)
+	(*Velocity)(w.Get(e1, velID)).X = 4
+	rec.RecordSet(&w, e1, velID)
+
+	e2 := w.NewEntity()
+	w.Relations().Exchange(e2, []ecs.ID{relID}, nil, relID, parent)
+	w.Relations().Set(e2, relID, e1)
+	removed := w.NewEntity(velID)
+	w.Remove(e1, posID)
+	w.RemoveEntity(removed)
+
+	batch := generic.NewMap1[Position](&w)
+	batch.NewBatch(3)
+
+	assert.Nil(t, rec.Err())
+	assert.Equal(t, 14, strings.Count(buf.String(), "\n"))
+
+	w2 := ecs.NewWorld()
+	_ = ecs.ComponentID[Position](&w2)
+	_ = ecs.ComponentID[Velocity](&w2)
+	_ = ecs.ComponentID[Relation1](&w2)
+
+	count, err := listener.Replay(&w2, bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, 14, count)
+
+	assert.Equal(t, w.Hash(), w2.Hash())
+
+	query := w2.Query(ecs.All(posID))
+	assert.Equal(t, 4, query.Count())
+	query.Close()
+}
+
+func TestRecorderReplay(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	relID := ecs.ComponentID[Relation1](&w)
+
+	buf := bytes.Buffer{}
+	rec := listener.NewRecorder(&buf)
+	w.SetListener(rec)
+
+	parent := w.NewEntityWith(ecs.Component{ID: posID, Comp: &Position{X: 1, Y: 2}})
+	child := w.NewEntity(velID)
+	w.Relations().Exchange(child, []ecs.ID{relID}, nil, relID, parent)
+	w.Set(child, velID, &Velocity{X: 5})
+
+	w2 := ecs.NewWorld()
+	posID2 := ecs.ComponentID[Position](&w2)
+	velID2 := ecs.ComponentID[Velocity](&w2)
+	relID2 := ecs.ComponentID[Relation1](&w2)
+	w2.RemoveEntity(w2.NewEntity())
+
+	_, err := listener.Replay(&w2, bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+
+	query := w2.Query(ecs.All(relID2))
+	assert.True(t, query.Next())
+	assert.Equal(t, Velocity{X: 5}, *(*Velocity)(query.Get(velID2)))
+	target := w2.Relations().Get(query.Entity(), relID2)
+	query.Close()
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w2.Get(target, posID2)))
+
+	w3 := ecs.NewWorld()
+	_ = ecs.ComponentID[Position](&w3)
+	_, err = listener.Replay(&w3, bytes.NewReader(buf.Bytes()))
+	assert.Equal(t, "component type listener_test.Velocity is not registered", err.Error())
+
+	w4 := ecs.NewWorld()
+	_ = ecs.ComponentID[Position](&w4)
+	count, err := listener.Replay(&w4, strings.NewReader(`{"Entity":[5,0],"Remove":true}`))
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "entity {5 0} was not created by the log", err.Error())
+
+	_, err = listener.Replay(&w4, strings.NewReader(`{"Entity":`))
+	assert.NotNil(t, err)
+}
+
+func TestMirror(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	buf := bytes.Buffer{}
+	rec := listener.NewRecorder(&buf)
+	w.SetListener(rec)
+
+	w2 := ecs.NewWorld()
+	posID2 := ecs.ComponentID[Position](&w2)
+	w2.NewEntity()
+	mirror := listener.NewMirror(&w2)
+
+	e1 := w.NewEntityWith(ecs.Component{ID: posID, Comp: &Position{X: 1}})
+	e2 := w.NewEntity(posID)
+
+	count, err := mirror.Replay(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, mirror.Len())
+
+	m1, ok := mirror.Entity(e1)
+	assert.True(t, ok)
+	assert.NotEqual(t, e1, m1)
+	assert.Equal(t, Position{X: 1}, *(*Position)(w2.Get(m1, posID2)))
+
+	// The mapping is kept between replays.
+	w.Set(e1, posID, &Position{X: 3})
+	w.RemoveEntity(e2)
+
+	count, err = mirror.Replay(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, mirror.Len())
+	assert.Equal(t, Position{X: 3}, *(*Position)(w2.Get(m1, posID2)))
+	_, ok = mirror.Entity(e2)
+	assert.False(t, ok)
+}
+
+type failWriter struct{}
+
+func (w failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestRecorderError(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	rec := listener.NewRecorder(failWriter{})
+	w.SetListener(rec)
+
+	w.NewEntity(posID)
+	w.NewEntity(posID)
+	assert.Equal(t, "write failed", rec.Err().Error())
+}
@@ -37,3 +37,38 @@ func subscribes(trigger event.Subscription, added *ecs.Mask, removed *ecs.Mask,
 	}
 	return false
 }
+
+// Returns whether a listener is interested in a value change event based on component subscriptions.
+//
+// Argument trigger should only contain the subscription bits that triggered the event.
+func subscribesSet(trigger event.Subscription, subs *ecs.Mask, set *ecs.ID) bool {
+	return trigger.Contains(event.ComponentSet) && set != nil && subs != nil && subs.Get(*set)
+}
+
+// copyEvent copies an event, including its slices and pointers, as these are only valid during notification.
+func copyEvent(evt *ecs.EntityEvent) ecs.EntityEvent {
+	cp := *evt
+	cp.AddedIDs = copyIDs(evt.AddedIDs)
+	cp.RemovedIDs = copyIDs(evt.RemovedIDs)
+	cp.OldRelation = copyID(evt.OldRelation)
+	cp.NewRelation = copyID(evt.NewRelation)
+	cp.SetID = copyID(evt.SetID)
+	return cp
+}
+
+// copyIDs copies a slice of component IDs.
+func copyIDs(ids []ecs.ID) []ecs.ID {
+	if ids == nil {
+		return nil
+	}
+	return append([]ecs.ID{}, ids...)
+}
+
+// copyID copies a component ID pointer.
+func copyID(id *ecs.ID) *ecs.ID {
+	if id == nil {
+		return nil
+	}
+	cp := *id
+	return &cp
+}
@@ -0,0 +1,15 @@
+// Package serde provides JSON serialization and deserialization of an entire [github.com/mlange-42/arche/ecs.World].
+//
+// Serialization covers entities (including their IDs and generations), components,
+// relation targets and resources.
+// Component and resource types are identified by their type names, as given by [reflect.Type.String].
+// Resources to serialize can be selected with option [Resources], while the application reconstructs the rest.
+// Further, [github.com/mlange-42/arche/ecs.Prefab] entity templates can be serialized with [SerializePrefab],
+// and filters with [SerializeFilter].
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package serde
@@ -0,0 +1,227 @@
+package serde
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/filter"
+)
+
+// Filter kinds in the JSON representation of filters.
+const (
+	filterMask          = "Mask"
+	filterMaskFilter    = "MaskFilter"
+	filterRelation      = "Relation"
+	filterMultiRelation = "MultiRelation"
+	filterAny           = "Any"
+	filterNoneOf        = "NoneOf"
+	filterAnyNot        = "AnyNot"
+	filterAnd           = "And"
+	filterOr            = "Or"
+	filterXOr           = "XOr"
+	filterNot           = "Not"
+)
+
+// filterJSON is the JSON representation of a filter.
+type filterJSON struct {
+	Kind    string       // Filter kind.
+	Include []string     `json:",omitempty"` // Type names of included components, for mask filters.
+	Exclude []string     `json:",omitempty"` // Type names of excluded components, for [ecs.MaskFilter].
+	Filter  *filterJSON  `json:",omitempty"` // Wrapped filter, for relation filters and [filter.NOT].
+	L       *filterJSON  `json:",omitempty"` // Left filter, for binary logic filters.
+	R       *filterJSON  `json:",omitempty"` // Right filter, for binary logic filters.
+	Target  *ecs.Entity  `json:",omitempty"` // Relation target, for [ecs.RelationFilter].
+	Targets []ecs.Entity `json:",omitempty"` // Relation targets, for [ecs.MultiRelationFilter].
+}
+
+// SerializeFilter serializes a filter to JSON, e.g. for saving custom inspector views or tool configurations.
+//
+// Supports [ecs.Mask], [ecs.MaskFilter], [ecs.RelationFilter], [ecs.MultiRelationFilter]
+// and the logic filters of package [github.com/mlange-42/arche/filter], in any combination.
+// Components are identified by their type names, relation targets by entity ID and generation.
+//
+// Returns an error for other filter types, like [ecs.CachedFilter].
+func SerializeFilter(world *ecs.World, f ecs.Filter) ([]byte, error) {
+	data, err := encodeFilter(ecs.ComponentIDs(world), world, f)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// DeserializeFilter deserializes a filter from JSON, as produced by [SerializeFilter].
+//
+// All component types contained in the JSON must be registered in the given world,
+// e.g. using [ecs.ComponentID].
+// Filters are re-created as pointers, except for [ecs.Mask], [filter.ANY], [filter.NoneOF] and [filter.AnyNOT].
+//
+// Returns an error for malformed JSON, unknown filter kinds or unregistered types.
+func DeserializeFilter(jsonData []byte, world *ecs.World) (ecs.Filter, error) {
+	data := filterJSON{}
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return nil, err
+	}
+	types := map[string]ecs.ID{}
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		types[info.Type.String()] = id
+	}
+	return decodeFilter(&data, types)
+}
+
+// encodeFilter creates the JSON representation of a filter.
+func encodeFilter(ids []ecs.ID, world *ecs.World, f ecs.Filter) (*filterJSON, error) {
+	var err error
+	data := filterJSON{}
+	switch f := f.(type) {
+	case ecs.Mask:
+		data.Kind, data.Include = filterMask, maskTypes(ids, world, f)
+	case *ecs.Mask:
+		data.Kind, data.Include = filterMask, maskTypes(ids, world, *f)
+	case *ecs.MaskFilter:
+		data.Kind, data.Include, data.Exclude = filterMaskFilter, maskTypes(ids, world, f.Include), maskTypes(ids, world, f.Exclude)
+	case *ecs.RelationFilter:
+		target := f.Target
+		data.Kind, data.Target = filterRelation, &target
+		data.Filter, err = encodeFilter(ids, world, f.Filter)
+	case *ecs.MultiRelationFilter:
+		data.Kind, data.Targets = filterMultiRelation, f.Targets
+		data.Filter, err = encodeFilter(ids, world, f.Filter)
+	case filter.ANY:
+		data.Kind, data.Include = filterAny, maskTypes(ids, world, ecs.Mask(f))
+	case filter.NoneOF:
+		data.Kind, data.Include = filterNoneOf, maskTypes(ids, world, ecs.Mask(f))
+	case filter.AnyNOT:
+		data.Kind, data.Include = filterAnyNot, maskTypes(ids, world, ecs.Mask(f))
+	case *filter.AND:
+		data.Kind = filterAnd
+		data.L, data.R, err = encodeFilterPair(ids, world, f.L, f.R)
+	case *filter.OR:
+		data.Kind = filterOr
+		data.L, data.R, err = encodeFilterPair(ids, world, f.L, f.R)
+	case *filter.XOR:
+		data.Kind = filterXOr
+		data.L, data.R, err = encodeFilterPair(ids, world, f.L, f.R)
+	case *filter.NOT:
+		data.Kind = filterNot
+		data.Filter, err = encodeFilter(ids, world, f.F)
+	default:
+		return nil, fmt.Errorf("filter type %v is not supported for serialization", reflect.TypeOf(f))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// encodeFilterPair creates the JSON representations of the two filters of a binary logic filter.
+func encodeFilterPair(ids []ecs.ID, world *ecs.World, l, r ecs.Filter) (*filterJSON, *filterJSON, error) {
+	left, err := encodeFilter(ids, world, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	right, err := encodeFilter(ids, world, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
+// maskTypes returns the sorted type names of all components in a mask.
+func maskTypes(ids []ecs.ID, world *ecs.World, mask ecs.Mask) []string {
+	names := []string{}
+	for _, id := range ids {
+		if mask.Get(id) {
+			info, _ := ecs.ComponentInfo(world, id)
+			names = append(names, info.Type.String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeFilter creates a filter from its JSON representation.
+func decodeFilter(data *filterJSON, types map[string]ecs.ID) (ecs.Filter, error) {
+	switch data.Kind {
+	case filterMask, filterAny, filterNoneOf, filterAnyNot:
+		mask, err := decodeMask(data.Include, types)
+		if err != nil {
+			return nil, err
+		}
+		switch data.Kind {
+		case filterAny:
+			return filter.ANY(mask), nil
+		case filterNoneOf:
+			return filter.NoneOF(mask), nil
+		case filterAnyNot:
+			return filter.AnyNOT(mask), nil
+		}
+		return mask, nil
+	case filterMaskFilter:
+		include, err := decodeMask(data.Include, types)
+		if err != nil {
+			return nil, err
+		}
+		exclude, err := decodeMask(data.Exclude, types)
+		if err != nil {
+			return nil, err
+		}
+		return &ecs.MaskFilter{Include: include, Exclude: exclude}, nil
+	case filterRelation, filterMultiRelation, filterNot:
+		if data.Filter == nil {
+			return nil, fmt.Errorf("missing wrapped filter in %s filter", data.Kind)
+		}
+		inner, err := decodeFilter(data.Filter, types)
+		if err != nil {
+			return nil, err
+		}
+		switch data.Kind {
+		case filterRelation:
+			if data.Target == nil {
+				return nil, fmt.Errorf("missing target in %s filter", data.Kind)
+			}
+			f := ecs.NewRelationFilter(inner, *data.Target)
+			return &f, nil
+		case filterMultiRelation:
+			f := ecs.NewMultiRelationFilter(inner, data.Targets...)
+			return &f, nil
+		}
+		return filter.Not(inner), nil
+	case filterAnd, filterOr, filterXOr:
+		if data.L == nil || data.R == nil {
+			return nil, fmt.Errorf("missing operand in %s filter", data.Kind)
+		}
+		left, err := decodeFilter(data.L, types)
+		if err != nil {
+			return nil, err
+		}
+		right, err := decodeFilter(data.R, types)
+		if err != nil {
+			return nil, err
+		}
+		switch data.Kind {
+		case filterAnd:
+			return filter.And(left, right), nil
+		case filterOr:
+			return filter.Or(left, right), nil
+		}
+		return filter.XOr(left, right), nil
+	}
+	return nil, fmt.Errorf("unknown filter kind '%s'", data.Kind)
+}
+
+// decodeMask creates a mask from component type names.
+func decodeMask(names []string, types map[string]ecs.ID) (ecs.Mask, error) {
+	ids := make([]ecs.ID, len(names))
+	for i, name := range names {
+		id, ok := types[name]
+		if !ok {
+			return ecs.Mask{}, fmt.Errorf("component type %s is not registered", name)
+		}
+		ids[i] = id
+	}
+	return ecs.All(ids...), nil
+}
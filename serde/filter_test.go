@@ -0,0 +1,100 @@
+package serde_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/filter"
+	"github.com/mlange-42/arche/serde"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializeDeserializeFilter(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	labelID := ecs.ComponentID[Label](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	parent1 := w.NewEntity()
+	parent2 := w.NewEntity()
+
+	maskFilter := ecs.All(posID).Without(labelID)
+	relFilter := ecs.NewRelationFilter(ecs.All(relID), parent1)
+	multiFilter := ecs.NewMultiRelationFilter(ecs.All(relID, posID), parent1, parent2)
+
+	filters := []struct {
+		in  ecs.Filter
+		out ecs.Filter
+	}{
+		{ecs.All(posID, velID), ecs.All(posID, velID)},
+		{ecs.All(), ecs.All()},
+		{&maskFilter, &maskFilter},
+		{&relFilter, &relFilter},
+		{&multiFilter, &multiFilter},
+		{filter.Any(posID, velID), filter.Any(posID, velID)},
+		{filter.NoneOf(labelID), filter.NoneOf(labelID)},
+		{filter.AnyNot(posID, labelID), filter.AnyNot(posID, labelID)},
+		{
+			filter.Or(filter.And(ecs.All(posID), filter.Not(ecs.All(labelID))), filter.XOr(ecs.All(velID), &maskFilter)),
+			filter.Or(filter.And(ecs.All(posID), filter.Not(ecs.All(labelID))), filter.XOr(ecs.All(velID), &maskFilter)),
+		},
+	}
+
+	for _, f := range filters {
+		jsonData, err := serde.SerializeFilter(&w, f.in)
+		assert.Nil(t, err)
+
+		w2 := ecs.NewWorld()
+		_ = ecs.ComponentID[Label](&w2)
+		_ = ecs.ComponentID[ChildOf](&w2)
+		_ = ecs.ComponentID[Velocity](&w2)
+		_ = ecs.ComponentID[Position](&w2)
+
+		f2, err := serde.DeserializeFilter(jsonData, &w2)
+		assert.Nil(t, err)
+
+		jsonData2, err := serde.SerializeFilter(&w2, f2)
+		assert.Nil(t, err)
+		assert.JSONEq(t, string(jsonData), string(jsonData2))
+
+		f3, err := serde.DeserializeFilter(jsonData, &w)
+		assert.Nil(t, err)
+		assert.Equal(t, f.out, f3)
+	}
+
+	jsonData, err := serde.SerializeFilter(&w, &maskFilter)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Kind":"MaskFilter","Include":["serde_test.Position"],"Exclude":["serde_test.Label"]}`, string(jsonData))
+}
+
+func TestSerializeDeserializeFilterErrors(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+
+	cached := w.Cache().Register(ecs.All(posID))
+	_, err := serde.SerializeFilter(&w, &cached)
+	assert.EqualError(t, err, "filter type *ecs.CachedFilter is not supported for serialization")
+
+	_, err = serde.SerializeFilter(&w, filter.Not(&cached))
+	assert.EqualError(t, err, "filter type *ecs.CachedFilter is not supported for serialization")
+
+	w2 := ecs.NewWorld()
+	_, err = serde.DeserializeFilter([]byte(`{"Kind":"Mask","Include":["serde_test.Position"]}`), &w2)
+	assert.EqualError(t, err, "component type serde_test.Position is not registered")
+
+	_, err = serde.DeserializeFilter([]byte(`{"Kind":"Foo"}`), &w2)
+	assert.EqualError(t, err, "unknown filter kind 'Foo'")
+
+	_, err = serde.DeserializeFilter([]byte(`{"Kind":"Not"}`), &w2)
+	assert.EqualError(t, err, "missing wrapped filter in Not filter")
+
+	_, err = serde.DeserializeFilter([]byte(`{"Kind":"And","L":{"Kind":"Mask"}}`), &w2)
+	assert.EqualError(t, err, "missing operand in And filter")
+
+	_, err = serde.DeserializeFilter([]byte(`{"Kind":"Relation","Filter":{"Kind":"Mask"}}`), &w2)
+	assert.EqualError(t, err, "missing target in Relation filter")
+
+	_, err = serde.DeserializeFilter([]byte(`{`), &w2)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,48 @@
+package serde
+
+import "github.com/mlange-42/arche/ecs"
+
+// Option is an option for [Serialize].
+type Option func(o *options)
+
+// options for serialization.
+type options struct {
+	resources       []ecs.ResID
+	selectResources bool
+}
+
+// newOptions creates options from the given option functions.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Resources is an [Option] that selects the resources to serialize.
+// Without this option, all resources are serialized.
+// Calling it without arguments serializes no resources.
+//
+// This allows to save and restore selected resources with the world,
+// while the application reconstructs the rest, like resources that are not serializable.
+// [Deserialize] leaves resources that are not in the JSON untouched.
+func Resources(ids ...ecs.ResID) Option {
+	return func(o *options) {
+		o.resources = append(o.resources, ids...)
+		o.selectResources = true
+	}
+}
+
+// includesResource reports whether a resource is selected for serialization.
+func (o *options) includesResource(id ecs.ResID) bool {
+	if !o.selectResources {
+		return true
+	}
+	for _, r := range o.resources {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
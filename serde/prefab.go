@@ -0,0 +1,126 @@
+package serde
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// prefabJSON is the JSON representation of a prefab.
+type prefabJSON struct {
+	Name       string
+	Components []componentJSON
+	Children   []childJSON `json:",omitempty"`
+}
+
+// componentJSON is the JSON representation of a prefab component value.
+type componentJSON struct {
+	Type  string          // Type name.
+	Value json.RawMessage // Component value.
+}
+
+// childJSON is the JSON representation of prefab children.
+type childJSON struct {
+	Prefab   prefabJSON
+	Relation string // Type name of the relation component.
+	Count    int
+}
+
+// SerializePrefab serializes an [ecs.Prefab] to JSON, including its children.
+//
+// Component values are marshaled using [encoding/json].
+// Hence, only exported fields are serialized.
+func SerializePrefab(prefab *ecs.Prefab) ([]byte, error) {
+	data, err := encodePrefab(prefab)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&data)
+}
+
+// DeserializePrefab deserializes an [ecs.Prefab] from JSON, as produced by [SerializePrefab].
+//
+// All component types contained in the JSON must be registered in the given world,
+// e.g. using [ecs.ComponentID].
+// The world is not modified.
+//
+// Returns an error for malformed JSON, unregistered types or invalid component values.
+func DeserializePrefab(jsonData []byte, world *ecs.World) (*ecs.Prefab, error) {
+	data := prefabJSON{}
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return nil, err
+	}
+	types := map[string]ecs.CompInfo{}
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		types[info.Type.String()] = info
+	}
+	return decodePrefab(&data, types)
+}
+
+// encodePrefab creates the JSON representation of a prefab.
+func encodePrefab(prefab *ecs.Prefab) (prefabJSON, error) {
+	data := prefabJSON{
+		Name:       prefab.Name(),
+		Components: []componentJSON{},
+	}
+	for _, c := range prefab.Components() {
+		name := reflect.TypeOf(c).Elem().String()
+		js, err := json.Marshal(c)
+		if err != nil {
+			return data, fmt.Errorf("failed to serialize component %s of prefab %s: %w", name, prefab.Name(), err)
+		}
+		data.Components = append(data.Components, componentJSON{Type: name, Value: js})
+	}
+	for _, child := range prefab.Children() {
+		childData, err := encodePrefab(child.Prefab)
+		if err != nil {
+			return data, err
+		}
+		data.Children = append(data.Children, childJSON{
+			Prefab:   childData,
+			Relation: child.Relation.String(),
+			Count:    child.Count,
+		})
+	}
+	return data, nil
+}
+
+// decodePrefab creates a prefab from its JSON representation.
+func decodePrefab(data *prefabJSON, types map[string]ecs.CompInfo) (*ecs.Prefab, error) {
+	comps := make([]any, len(data.Components))
+	for i, c := range data.Components {
+		info, ok := types[c.Type]
+		if !ok {
+			return nil, fmt.Errorf("component type %s is not registered", c.Type)
+		}
+		value := reflect.New(info.Type).Interface()
+		if err := json.Unmarshal(c.Value, value); err != nil {
+			return nil, fmt.Errorf("failed to deserialize component %s of prefab %s: %w", c.Type, data.Name, err)
+		}
+		comps[i] = value
+	}
+	prefab := ecs.NewPrefab(data.Name, comps...)
+
+	for i := range data.Children {
+		child := &data.Children[i]
+		info, ok := types[child.Relation]
+		if !ok {
+			return nil, fmt.Errorf("component type %s is not registered", child.Relation)
+		}
+		if !info.IsRelation {
+			return nil, fmt.Errorf("component type %s is not a relation", child.Relation)
+		}
+		if child.Count < 1 {
+			return nil, fmt.Errorf("invalid child count %d in prefab %s", child.Count, data.Name)
+		}
+		childPrefab, err := decodePrefab(&child.Prefab, types)
+		if err != nil {
+			return nil, err
+		}
+		prefab.WithChild(childPrefab, info.Type, child.Count)
+	}
+	return prefab, nil
+}
@@ -0,0 +1,73 @@
+package serde_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/serde"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializeDeserializePrefab(t *testing.T) {
+	child := ecs.NewPrefab("child", &Velocity{X: 3, Y: 4})
+	prefab := ecs.NewPrefab("parent", &Position{X: 1, Y: 2}, &Label{}).
+		WithChild(child, reflect.TypeOf(ChildOf{}), 2)
+
+	jsonData, err := serde.SerializePrefab(prefab)
+	assert.Nil(t, err)
+
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	_ = ecs.ComponentID[Label](&w)
+	relID := ecs.ComponentID[ChildOf](&w)
+
+	prefab2, err := serde.DeserializePrefab(jsonData, &w)
+	assert.Nil(t, err)
+	assert.Equal(t, "parent", prefab2.Name())
+	assert.Equal(t, prefab.Components(), prefab2.Components())
+	assert.Equal(t, 1, len(prefab2.Children()))
+	assert.Equal(t, child.Components(), prefab2.Children()[0].Prefab.Components())
+
+	parent := prefab2.Spawn(&w)
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w.Get(parent, posID)))
+
+	filter := ecs.NewRelationFilter(ecs.All(velID, relID), parent)
+	query := w.Query(&filter)
+	assert.Equal(t, 2, query.Count())
+	for query.Next() {
+		assert.Equal(t, Velocity{X: 3, Y: 4}, *(*Velocity)(query.Get(velID)))
+	}
+}
+
+func TestDeserializePrefabErrors(t *testing.T) {
+	prefab := ecs.NewPrefab("parent", &Position{}).
+		WithChild(ecs.NewPrefab("child"), reflect.TypeOf(ChildOf{}), 1)
+	jsonData, err := serde.SerializePrefab(prefab)
+	assert.Nil(t, err)
+
+	w := ecs.NewWorld()
+	_, err = serde.DeserializePrefab(jsonData, &w)
+	assert.EqualError(t, err, "component type serde_test.Position is not registered")
+
+	_ = ecs.ComponentID[Position](&w)
+	_, err = serde.DeserializePrefab(jsonData, &w)
+	assert.EqualError(t, err, "component type serde_test.ChildOf is not registered")
+
+	_, err = serde.DeserializePrefab([]byte(`{"Name":"p","Components":[{"Type":"serde_test.Position","Value":{"X":"a"}}]}`), &w)
+	assert.Contains(t, err.Error(), "failed to deserialize component serde_test.Position of prefab p")
+
+	_, err = serde.DeserializePrefab([]byte(`{"Name":"p","Components":[],"Children":[{"Prefab":{"Name":"c"},"Relation":"serde_test.Position","Count":1}]}`), &w)
+	assert.EqualError(t, err, "component type serde_test.Position is not a relation")
+
+	_ = ecs.ComponentID[ChildOf](&w)
+	_, err = serde.DeserializePrefab([]byte(`{"Name":"p","Components":[],"Children":[{"Prefab":{"Name":"c"},"Relation":"serde_test.ChildOf","Count":0}]}`), &w)
+	assert.EqualError(t, err, "invalid child count 0 in prefab p")
+
+	_, err = serde.DeserializePrefab([]byte(`{`), &w)
+	assert.NotNil(t, err)
+
+	_, err = serde.SerializePrefab(ecs.NewPrefab("invalid", &Invalid{Func: func() {}}))
+	assert.Contains(t, err.Error(), "failed to serialize component serde_test.Invalid of prefab invalid")
+}
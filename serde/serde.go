@@ -0,0 +1,195 @@
+package serde
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// worldJSON is the JSON representation of a world.
+type worldJSON struct {
+	World      ecs.EntityDump             // Entity pool state.
+	Types      []string                   // Names of all component types in use.
+	Components []entityJSON               // Components of all alive entities, in the order of World.Alive.
+	Resources  map[string]json.RawMessage // Resources by type name.
+}
+
+// entityJSON is the JSON representation of the components of a single entity.
+type entityJSON struct {
+	Components map[string]json.RawMessage // Components by type name.
+	Target     *ecs.Entity                `json:",omitempty"` // Relation target, if the entity has a relation with a non-zero target.
+}
+
+// Serialize a world to JSON.
+//
+// Serializes all entities with their IDs and generations, all components,
+// relation targets and all resources.
+// Components and resources are marshaled using [encoding/json].
+// Hence, only exported fields are serialized.
+//
+// Resources to serialize can be selected with option [Resources].
+//
+// Panics when called on a locked world.
+func Serialize(world *ecs.World, opts ...Option) ([]byte, error) {
+	if world.IsLocked() {
+		panic("attempt to serialize a locked world")
+	}
+	options := newOptions(opts)
+	dump := world.DumpEntities()
+
+	types := map[ecs.ID]string{}
+	data := worldJSON{
+		World:      dump,
+		Types:      []string{},
+		Components: make([]entityJSON, len(dump.Alive)),
+		Resources:  map[string]json.RawMessage{},
+	}
+
+	for i, idx := range dump.Alive {
+		entity := dump.Entities[idx]
+		comps := map[string]json.RawMessage{}
+		for _, id := range world.Ids(entity) {
+			info, _ := ecs.ComponentInfo(world, id)
+			name, ok := types[id]
+			if !ok {
+				name = info.Type.String()
+				types[id] = name
+				data.Types = append(data.Types, name)
+			}
+			value := reflect.NewAt(info.Type, world.Get(entity, id)).Interface()
+			js, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize component %s: %w", name, err)
+			}
+			comps[name] = js
+
+			if info.IsRelation {
+				target := world.Relations().Get(entity, id)
+				if !target.IsZero() {
+					data.Components[i].Target = &target
+				}
+			}
+		}
+		data.Components[i].Components = comps
+	}
+
+	for _, id := range ecs.ResourceIDs(world) {
+		if !world.Resources().Has(id) || !options.includesResource(id) {
+			continue
+		}
+		tp, _ := ecs.ResourceType(world, id)
+		js, err := json.Marshal(world.Resources().Get(id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize resource %s: %w", tp.String(), err)
+		}
+		data.Resources[tp.String()] = js
+	}
+
+	return json.Marshal(&data)
+}
+
+// Deserialize a world from JSON, as produced by [Serialize].
+//
+// The world must be fresh or reset (see [ecs.World.Reset]),
+// and all component and resource types contained in the JSON must be registered,
+// e.g. using [ecs.ComponentID] and [ecs.ResourceID].
+// Resources that are already present in the world are overwritten by deserialized values.
+//
+// The deserialized world has the same entities (in terms of ID, generation and alive state) as the original world.
+// Relations to targets that were dead at serialization time are restored without target.
+//
+// Returns an error for malformed JSON or unregistered types, which are detected before the world is modified.
+// Errors in individual component or resource values may leave the world partially deserialized.
+// Panics if the world is locked or not fresh.
+func Deserialize(jsonData []byte, world *ecs.World) error {
+	data := worldJSON{}
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return err
+	}
+	if len(data.Components) != len(data.World.Alive) {
+		return fmt.Errorf("found components for %d entities, but %d entities are alive", len(data.Components), len(data.World.Alive))
+	}
+
+	compIDs := map[string]ecs.ID{}
+	for _, id := range ecs.ComponentIDs(world) {
+		info, _ := ecs.ComponentInfo(world, id)
+		compIDs[info.Type.String()] = id
+	}
+	for _, name := range data.Types {
+		if _, ok := compIDs[name]; !ok {
+			return fmt.Errorf("component type %s is not registered", name)
+		}
+	}
+	resIDs := map[string]ecs.ResID{}
+	for _, id := range ecs.ResourceIDs(world) {
+		tp, _ := ecs.ResourceType(world, id)
+		resIDs[tp.String()] = id
+	}
+	for name := range data.Resources {
+		if _, ok := resIDs[name]; !ok {
+			return fmt.Errorf("resource type %s is not registered", name)
+		}
+	}
+	for i, idx := range data.World.Alive {
+		if int(idx) >= len(data.World.Entities) {
+			return fmt.Errorf("alive entity index %d out of range", idx)
+		}
+		for name := range data.Components[i].Components {
+			if _, ok := compIDs[name]; !ok {
+				return fmt.Errorf("component type %s is not registered", name)
+			}
+		}
+	}
+
+	world.LoadEntities(&data.World)
+
+	ids := []ecs.ID{}
+	for i, idx := range data.World.Alive {
+		entity := data.World.Entities[idx]
+		comps := &data.Components[i]
+
+		ids = ids[:0]
+		relation, hasRelation := ecs.ID{}, false
+		for name := range comps.Components {
+			id := compIDs[name]
+			ids = append(ids, id)
+			if info, _ := ecs.ComponentInfo(world, id); info.IsRelation {
+				relation, hasRelation = id, true
+			}
+		}
+		if hasRelation && comps.Target != nil && world.Alive(*comps.Target) {
+			world.Relations().Exchange(entity, ids, nil, relation, *comps.Target)
+		} else {
+			world.Add(entity, ids...)
+		}
+
+		for name, js := range comps.Components {
+			id := compIDs[name]
+			info, _ := ecs.ComponentInfo(world, id)
+			value := reflect.NewAt(info.Type, world.Get(entity, id)).Interface()
+			if err := json.Unmarshal(js, value); err != nil {
+				return fmt.Errorf("failed to deserialize component %s: %w", name, err)
+			}
+		}
+	}
+
+	for name, js := range data.Resources {
+		id := resIDs[name]
+		if world.Resources().Has(id) {
+			if err := json.Unmarshal(js, world.Resources().Get(id)); err != nil {
+				return fmt.Errorf("failed to deserialize resource %s: %w", name, err)
+			}
+			continue
+		}
+		tp, _ := ecs.ResourceType(world, id)
+		value := reflect.New(tp).Interface()
+		if err := json.Unmarshal(js, value); err != nil {
+			return fmt.Errorf("failed to deserialize resource %s: %w", name, err)
+		}
+		world.Resources().Add(id, value)
+	}
+
+	return nil
+}
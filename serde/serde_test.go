@@ -0,0 +1,141 @@
+package serde_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/serde"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Velocity struct {
+	X float64
+	Y float64
+}
+
+type ChildOf struct {
+	ecs.Relation
+}
+
+type Label struct{}
+
+type Time struct {
+	Tick int
+}
+
+type Invalid struct {
+	Func func()
+}
+
+func TestSerializeDeserialize(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	velID := ecs.ComponentID[Velocity](&w)
+	childID := ecs.ComponentID[ChildOf](&w)
+	labelID := ecs.ComponentID[Label](&w)
+	ecs.AddResource(&w, &Time{Tick: 10})
+
+	parent := w.NewEntity(labelID)
+	e1 := w.NewEntity(posID, velID)
+	e2 := ecs.NewBuilder(&w, posID, childID).WithRelation(childID).New(parent)
+	removed := w.NewEntity(posID)
+	w.RemoveEntity(removed)
+	e3 := w.NewEntity(posID)
+
+	*(*Position)(w.Get(e1, posID)) = Position{X: 1, Y: 2}
+	*(*Velocity)(w.Get(e1, velID)) = Velocity{X: 3, Y: 4}
+	*(*Position)(w.Get(e2, posID)) = Position{X: 5, Y: 6}
+
+	js, err := serde.Serialize(&w)
+	assert.Nil(t, err)
+
+	w2 := ecs.NewWorld()
+	posID2 := ecs.ComponentID[Position](&w2)
+	velID2 := ecs.ComponentID[Velocity](&w2)
+	childID2 := ecs.ComponentID[ChildOf](&w2)
+	labelID2 := ecs.ComponentID[Label](&w2)
+	_ = ecs.ResourceID[Time](&w2)
+
+	err = serde.Deserialize(js, &w2)
+	assert.Nil(t, err)
+
+	assert.True(t, w2.Alive(parent))
+	assert.True(t, w2.Alive(e1))
+	assert.True(t, w2.Alive(e2))
+	assert.True(t, w2.Alive(e3))
+	assert.False(t, w2.Alive(removed))
+
+	assert.True(t, w2.Has(parent, labelID2))
+	assert.Equal(t, Position{X: 1, Y: 2}, *(*Position)(w2.Get(e1, posID2)))
+	assert.Equal(t, Velocity{X: 3, Y: 4}, *(*Velocity)(w2.Get(e1, velID2)))
+	assert.Equal(t, Position{X: 5, Y: 6}, *(*Position)(w2.Get(e2, posID2)))
+	assert.Equal(t, parent, w2.Relations().Get(e2, childID2))
+	assert.Equal(t, []ecs.ID{posID2}, w2.Ids(e3))
+	assert.Equal(t, Time{Tick: 10}, *ecs.GetResource[Time](&w2))
+
+	js2, err := serde.Serialize(&w2)
+	assert.Nil(t, err)
+	assert.Equal(t, string(js), string(js2))
+
+	e4 := w2.NewEntity()
+	assert.NotEqual(t, removed, e4)
+	assert.False(t, w2.Alive(removed))
+}
+
+func TestSerializeResources(t *testing.T) {
+	w := ecs.NewWorld()
+	timeID := ecs.AddResource(&w, &Time{Tick: 10})
+	ecs.AddResource(&w, &Invalid{})
+
+	_, err := serde.Serialize(&w)
+	assert.NotNil(t, err)
+
+	js, err := serde.Serialize(&w, serde.Resources(timeID))
+	assert.Nil(t, err)
+
+	w2 := ecs.NewWorld()
+	_ = ecs.ResourceID[Time](&w2)
+	assert.Nil(t, serde.Deserialize(js, &w2))
+	assert.Equal(t, Time{Tick: 10}, *ecs.GetResource[Time](&w2))
+
+	js, err = serde.Serialize(&w, serde.Resources())
+	assert.Nil(t, err)
+	w3 := ecs.NewWorld()
+	assert.Nil(t, serde.Deserialize(js, &w3))
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	w.NewEntity(posID)
+	ecs.AddResource(&w, &Time{Tick: 10})
+
+	js, err := serde.Serialize(&w)
+	assert.Nil(t, err)
+
+	w2 := ecs.NewWorld()
+	err = serde.Deserialize(js, &w2)
+	assert.EqualError(t, err, "component type serde_test.Position is not registered")
+
+	_ = ecs.ComponentID[Position](&w2)
+	err = serde.Deserialize(js, &w2)
+	assert.EqualError(t, err, "resource type serde_test.Time is not registered")
+
+	err = serde.Deserialize([]byte("{"), &w2)
+	assert.NotNil(t, err)
+
+	w3 := ecs.NewWorld()
+	invID := ecs.ComponentID[Invalid](&w3)
+	w3.NewEntity(invID)
+	_, err = serde.Serialize(&w3)
+	assert.EqualError(t, err, "failed to serialize component serde_test.Invalid: json: unsupported type: func()")
+
+	query := w3.Query(ecs.All())
+	assert.PanicsWithValue(t, "attempt to serialize a locked world", func() { _, _ = serde.Serialize(&w3) })
+	query.Close()
+}
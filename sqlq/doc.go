@@ -0,0 +1,30 @@
+// Package sqlq executes simple, read-only SQL-like queries against an [github.com/mlange-42/arche/ecs.World],
+// for debugging consoles and test assertions.
+//
+// Queries have the form
+//
+//	select <columns> from entities [where <condition>] [limit <n>]
+//
+// Columns are component names, optionally followed by a field path, like "Health" or "Position.X".
+// Conditions combine Has(<component>) and comparisons of fields with literals,
+// like "Position.X >= 10" or "Name = 'player'", using and, or, not and parentheses.
+// Keywords are case-insensitive.
+//
+// Example:
+//
+//	res, err := sqlq.Query(&world, "select Position.X, Health from entities where Has(Enemy) and Health.Value < 10")
+//	if err != nil {
+//		// ...
+//	}
+//	fmt.Println(res)
+//
+// Queries are compiled to regular [github.com/mlange-42/arche/ecs.Query] iteration,
+// with required components derived from the condition, and reflective field access.
+// See [Compile] for details.
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package sqlq
@@ -0,0 +1,95 @@
+package sqlq
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a lexical token.
+type tokenKind uint8
+
+const (
+	tokenEOF    tokenKind = iota // End of the query.
+	tokenIdent                   // Keyword, component name or column reference.
+	tokenNumber                  // Numeric literal.
+	tokenString                  // Quoted string literal.
+	tokenSymbol                  // Punctuation or comparison operator.
+)
+
+// token is a lexical token of a query.
+type token struct {
+	kind tokenKind
+	text string // Text of the token. Unquoted for string literals.
+	pos  int    // Byte position in the query.
+}
+
+// is reports whether the token is the given keyword, case-insensitive, or the given symbol.
+func (t token) is(text string) bool {
+	if t.kind == tokenIdent {
+		return strings.EqualFold(t.text, text)
+	}
+	return t.kind == tokenSymbol && t.text == text
+}
+
+// lex splits a query into tokens. The last token is always of kind tokenEOF.
+func lex(query string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(query) {
+		c := rune(query[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(query) && isIdentPart(rune(query[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: query[start:i], pos: start})
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(query) && unicode.IsDigit(rune(query[i+1]))):
+			start := i
+			i++
+			for i < len(query) && (unicode.IsDigit(rune(query[i])) || query[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: query[start:i], pos: start})
+		case c == '\'':
+			start := i
+			end := strings.IndexByte(query[i+1:], '\'')
+			if end < 0 {
+				return nil, &Error{Query: query, Pos: start, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: query[i+1 : i+1+end], pos: start})
+			i += end + 2
+		case strings.ContainsRune("(),*", c):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(c), pos: i})
+			i++
+		case strings.ContainsRune("=!<>", c):
+			start := i
+			i++
+			if i < len(query) && query[i] == '=' {
+				i++
+			}
+			op := query[start:i]
+			if op == "!" {
+				return nil, &Error{Query: query, Pos: start, Msg: "unexpected '!'"}
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: op, pos: start})
+		default:
+			return nil, &Error{Query: query, Pos: i, Msg: fmt.Sprintf("unexpected '%c'", c)}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(query)}), nil
+}
+
+// isIdentStart reports whether a character can start an identifier.
+func isIdentStart(c rune) bool {
+	return unicode.IsLetter(c) || c == '_'
+}
+
+// isIdentPart reports whether a character can be part of an identifier.
+// Dots are included for column references and qualified type names.
+func isIdentPart(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.'
+}
@@ -0,0 +1,289 @@
+package sqlq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Compile parses a query and resolves its components and fields against a world.
+//
+// Components are resolved by the names registered with [ecs.World.RegisterComponent],
+// by the plain type name like "Position", or by the qualified type name like "main.Position".
+// Field paths can address fields of nested structs, like "Body.Position.X".
+// Unexported fields can be accessed as well.
+//
+// Required components for the underlying [ecs.Query] are derived from Has conditions
+// and comparisons that are combined with and at the top level of the condition.
+// Comparisons on entities without the component are false.
+// Numeric fields can be compared with numbers, strings with quoted strings, and booleans with true or false.
+// Booleans and other types only support = and !=.
+//
+// Returns an [Error] for syntax errors, unknown components or fields, and invalid comparisons.
+func Compile(w *ecs.World, query string) (*Statement, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{world: w, query: query, tokens: tokens}
+	return p.parseStatement()
+}
+
+// parser is a recursive descent parser for queries.
+type parser struct {
+	world  *ecs.World
+	query  string
+	tokens []token
+	pos    int
+}
+
+// parseStatement parses an entire query.
+func (p *parser) parseStatement() (*Statement, error) {
+	stmt := Statement{world: p.world, limit: -1}
+	if err := p.expect("select"); err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.next()
+		if tok.kind != tokenIdent || tok.is("from") {
+			return nil, p.errorAt(tok, "expected column")
+		}
+		col, err := p.resolveColumn(tok)
+		if err != nil {
+			return nil, err
+		}
+		stmt.columns = append(stmt.columns, col)
+		stmt.names = append(stmt.names, tok.text)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("entities"); err != nil {
+		return nil, err
+	}
+	if p.accept("where") {
+		where, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.where = where
+		stmt.filter = ecs.All(requiredComponents(where)...)
+	}
+	if p.accept("limit") {
+		tok := p.next()
+		n, err := strconv.Atoi(tok.text)
+		if tok.kind != tokenNumber || err != nil || n < 0 {
+			return nil, p.errorAt(tok, "expected non-negative integer limit")
+		}
+		stmt.limit = n
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected '%s'", tok.text))
+	}
+	return &stmt, nil
+}
+
+// parseOr parses a disjunction of conjunctions.
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orPredicate{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses a conjunction of unary conditions.
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andPredicate{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses a negation, a parenthesized condition, a Has condition or a comparison.
+func (p *parser) parseUnary() (predicate, error) {
+	if p.accept("not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notPredicate{inner}, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	if p.accept("has") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			return nil, p.errorAt(tok, "expected component")
+		}
+		id, ok := resolveComponent(p.world, tok.text)
+		if !ok {
+			return nil, p.errorAt(tok, fmt.Sprintf("unknown component '%s'", tok.text))
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &hasPredicate{id}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a comparison of a column with a literal.
+func (p *parser) parseComparison() (predicate, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return nil, p.errorAt(tok, "expected condition")
+	}
+	col, err := p.resolveColumn(tok)
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.next()
+	op, ok := comparisonOps[opTok.text]
+	if opTok.kind != tokenSymbol || !ok {
+		return nil, p.errorAt(opTok, "expected comparison operator")
+	}
+	litTok := p.next()
+	cmp := comparePredicate{column: col, op: op}
+
+	kind := valueKind(col.tp)
+	switch {
+	case litTok.kind == tokenNumber && kind == kindNumber:
+		v, err := strconv.ParseFloat(litTok.text, 64)
+		if err != nil {
+			return nil, p.errorAt(litTok, fmt.Sprintf("invalid number '%s'", litTok.text))
+		}
+		cmp.value = v
+	case litTok.kind == tokenString && kind == kindString:
+		cmp.value = litTok.text
+	case litTok.kind == tokenIdent && (litTok.is("true") || litTok.is("false")) && kind == kindOther:
+		if col.tp.Kind() != reflect.Bool {
+			return nil, p.errorAt(litTok, fmt.Sprintf("can't compare %v with a boolean", col.tp))
+		}
+		cmp.value = litTok.is("true")
+	case litTok.kind == tokenEOF || litTok.kind == tokenSymbol:
+		return nil, p.errorAt(litTok, "expected literal")
+	default:
+		return nil, p.errorAt(litTok, fmt.Sprintf("can't compare %v with '%s'", col.tp, litTok.text))
+	}
+	if kind == kindOther && op != opEq && op != opNe {
+		return nil, p.errorAt(opTok, fmt.Sprintf("operator %s is not supported for %v", opTok.text, col.tp))
+	}
+	return &cmp, nil
+}
+
+// resolveColumn resolves a column reference, i.e. a component name with an optional field path.
+// Tries the longest component name first, to allow for qualified type names.
+func (p *parser) resolveColumn(tok token) (column, error) {
+	parts := strings.Split(tok.text, ".")
+	for i := len(parts); i > 0; i-- {
+		id, ok := resolveComponent(p.world, strings.Join(parts[:i], "."))
+		if !ok {
+			continue
+		}
+		info, _ := ecs.ComponentInfo(p.world, id)
+		col := column{comp: id, tp: info.Type}
+		for _, name := range parts[i:] {
+			if col.tp.Kind() != reflect.Struct {
+				return column{}, p.errorAt(tok, fmt.Sprintf("%v has no field %s", col.tp, name))
+			}
+			f, ok := col.tp.FieldByName(name)
+			if !ok {
+				return column{}, p.errorAt(tok, fmt.Sprintf("%v has no field %s", col.tp, name))
+			}
+			// Promoted fields of embedded structs have offsets relative to the embedded struct.
+			for _, idx := range f.Index {
+				if col.tp.Kind() != reflect.Struct {
+					return column{}, p.errorAt(tok, fmt.Sprintf("field %s of %v is promoted through a pointer", name, info.Type))
+				}
+				sf := col.tp.Field(idx)
+				col.offset += sf.Offset
+				col.tp = sf.Type
+			}
+		}
+		return col, nil
+	}
+	return column{}, p.errorAt(tok, fmt.Sprintf("unknown component '%s'", parts[0]))
+}
+
+// resolveComponent resolves a component by registered name, plain type name or qualified type name.
+func resolveComponent(w *ecs.World, name string) (ecs.ID, bool) {
+	if id, ok := w.ComponentIDByName(name); ok {
+		return id, true
+	}
+	for _, id := range ecs.ComponentIDs(w) {
+		info, _ := ecs.ComponentInfo(w, id)
+		if info.Type.Name() == name || info.Type.String() == name {
+			return id, true
+		}
+	}
+	return ecs.ID{}, false
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token. Returns tokenEOF tokens at the end.
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given keyword or symbol.
+func (p *parser) accept(text string) bool {
+	if p.peek().is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the next token if it is the given keyword or symbol, and returns an error otherwise.
+func (p *parser) expect(text string) error {
+	if p.accept(text) {
+		return nil
+	}
+	return p.errorAt(p.peek(), fmt.Sprintf("expected '%s'", text))
+}
+
+// errorAt creates an [Error] at the position of a token.
+func (p *parser) errorAt(tok token, msg string) error {
+	return &Error{Query: p.query, Pos: tok.pos, Msg: msg}
+}
@@ -0,0 +1,159 @@
+package sqlq
+
+import (
+	"reflect"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// predicate is a compiled condition of a where clause.
+type predicate interface {
+	// eval evaluates the condition for the entity at the query's position.
+	eval(query *ecs.Query) bool
+}
+
+// hasPredicate checks for a component.
+type hasPredicate struct {
+	comp ecs.ID
+}
+
+func (p *hasPredicate) eval(query *ecs.Query) bool {
+	return query.Has(p.comp)
+}
+
+// notPredicate inverts a condition.
+type notPredicate struct {
+	inner predicate
+}
+
+func (p *notPredicate) eval(query *ecs.Query) bool {
+	return !p.inner.eval(query)
+}
+
+// andPredicate combines two conditions using and.
+type andPredicate struct {
+	left  predicate
+	right predicate
+}
+
+func (p *andPredicate) eval(query *ecs.Query) bool {
+	return p.left.eval(query) && p.right.eval(query)
+}
+
+// orPredicate combines two conditions using or.
+type orPredicate struct {
+	left  predicate
+	right predicate
+}
+
+func (p *orPredicate) eval(query *ecs.Query) bool {
+	return p.left.eval(query) || p.right.eval(query)
+}
+
+// compareOp is a comparison operator.
+type compareOp uint8
+
+const (
+	opEq compareOp = iota
+	opNe
+	opLt
+	opLe
+	opGt
+	opGe
+)
+
+// comparisonOps maps operator symbols to comparison operators.
+var comparisonOps = map[string]compareOp{
+	"=":  opEq,
+	"!=": opNe,
+	"<":  opLt,
+	"<=": opLe,
+	">":  opGt,
+	">=": opGe,
+}
+
+// comparePredicate compares a column with a literal value.
+type comparePredicate struct {
+	column column
+	op     compareOp
+	value  any // float64 for numeric columns, string for string columns, bool otherwise.
+}
+
+func (p *comparePredicate) eval(query *ecs.Query) bool {
+	v := p.column.value(query)
+	if v == nil {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch lit := p.value.(type) {
+	case float64:
+		return compare(toFloat(rv), lit, p.op)
+	case string:
+		return compare(rv.String(), lit, p.op)
+	case bool:
+		return (rv.Bool() == lit) == (p.op == opEq)
+	}
+	return false
+}
+
+// compare compares two ordered values.
+func compare[T float64 | string](a, b T, op compareOp) bool {
+	switch op {
+	case opEq:
+		return a == b
+	case opNe:
+		return a != b
+	case opLt:
+		return a < b
+	case opLe:
+		return a <= b
+	case opGt:
+		return a > b
+	}
+	return a >= b
+}
+
+// Value kinds for comparisons.
+const (
+	kindNumber = iota
+	kindString
+	kindOther
+)
+
+// valueKind returns the kind of a type for comparisons.
+func valueKind(tp reflect.Type) int {
+	switch tp.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return kindNumber
+	case reflect.String:
+		return kindString
+	}
+	return kindOther
+}
+
+// toFloat converts a numeric value to float64.
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// requiredComponents returns the components required by a condition,
+// i.e. those of Has conditions and comparisons that are combined with and at the top level.
+func requiredComponents(p predicate) []ecs.ID {
+	switch p := p.(type) {
+	case *hasPredicate:
+		return []ecs.ID{p.comp}
+	case *comparePredicate:
+		return []ecs.ID{p.column.comp}
+	case *andPredicate:
+		return append(requiredComponents(p.left), requiredComponents(p.right)...)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package sqlq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"unsafe"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Error describes a syntax error or an unresolved name in a query.
+type Error struct {
+	Query string // The query.
+	Pos   int    // Byte position of the error in the query.
+	Msg   string // Description of the problem.
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("query '%s', position %d: %s", e.Query, e.Pos, e.Msg)
+}
+
+// Result is the result of a query.
+type Result struct {
+	Columns  []string     // Column names, as given in the query.
+	Entities []ecs.Entity // Matched entities, one per row.
+	Rows     [][]any      // Values of the columns, one row per entity. Values are copies.
+}
+
+// String formats the result as a table, with a leading column for the entities.
+func (r *Result) String() string {
+	b := strings.Builder{}
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Entity\t%s\n", strings.Join(r.Columns, "\t"))
+	for i, row := range r.Rows {
+		fmt.Fprintf(tw, "%v", r.Entities[i])
+		for _, v := range row {
+			fmt.Fprintf(tw, "\t%v", v)
+		}
+		fmt.Fprintln(tw)
+	}
+	_ = tw.Flush()
+	return b.String()
+}
+
+// Statement is a compiled query, bound to a world. Create it with [Compile].
+//
+// A statement can be run repeatedly, e.g. in test assertions after each simulation step.
+type Statement struct {
+	world   *ecs.World
+	columns []column
+	names   []string
+	filter  ecs.Mask
+	where   predicate
+	limit   int
+}
+
+// column is a compiled column reference.
+type column struct {
+	comp   ecs.ID       // The referenced component.
+	offset uintptr      // Offset of the referenced field in the component.
+	tp     reflect.Type // Type of the referenced field, or of the component.
+}
+
+// value returns a copy of the column's value for the entity at the query's position,
+// or nil if the entity does not have the component.
+func (c *column) value(query *ecs.Query) any {
+	if !query.Has(c.comp) {
+		return nil
+	}
+	return reflect.NewAt(c.tp, unsafe.Add(query.Get(c.comp), c.offset)).Elem().Interface()
+}
+
+// Query compiles and runs a query on a world. See [Compile] for details.
+func Query(w *ecs.World, query string) (*Result, error) {
+	stmt, err := Compile(w, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Run(), nil
+}
+
+// Run executes the statement, and returns the matching entities and column values.
+//
+// Disabled entities are not included, like in regular queries.
+// The world is locked while the statement runs, but can be locked already.
+func (s *Statement) Run() *Result {
+	res := &Result{
+		Columns:  s.names,
+		Entities: []ecs.Entity{},
+		Rows:     [][]any{},
+	}
+	query := s.world.Query(s.filter)
+	for query.Next() {
+		if s.where != nil && !s.where.eval(&query) {
+			continue
+		}
+		if s.limit >= 0 && len(res.Rows) >= s.limit {
+			query.Close()
+			break
+		}
+		row := make([]any, len(s.columns))
+		for i := range s.columns {
+			row[i] = s.columns[i].value(&query)
+		}
+		res.Entities = append(res.Entities, query.Entity())
+		res.Rows = append(res.Rows, row)
+	}
+	return res
+}
@@ -0,0 +1,186 @@
+package sqlq_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/sqlq"
+	"github.com/stretchr/testify/assert"
+)
+
+type Position struct {
+	X float64
+	Y float64
+}
+
+type Health struct {
+	Value int
+	max   int
+}
+
+type Name struct {
+	Name string
+}
+
+type Body struct {
+	Pos    Position
+	Static bool
+}
+
+type Enemy struct{}
+
+func newWorld() (ecs.World, []ecs.Entity) {
+	w := ecs.NewWorld()
+	posID := ecs.ComponentID[Position](&w)
+	healthID := ecs.ComponentID[Health](&w)
+	nameID := ecs.ComponentID[Name](&w)
+	enemyID := ecs.ComponentID[Enemy](&w)
+	bodyID := ecs.ComponentID[Body](&w)
+	w.RegisterComponent("hp", reflect.TypeOf(Health{}))
+
+	player := w.NewEntityWith(
+		ecs.Component{ID: posID, Comp: &Position{X: 1, Y: 2}},
+		ecs.Component{ID: healthID, Comp: &Health{Value: 100, max: 100}},
+		ecs.Component{ID: nameID, Comp: &Name{Name: "player"}},
+	)
+	e1 := w.NewEntityWith(
+		ecs.Component{ID: posID, Comp: &Position{X: 10, Y: 20}},
+		ecs.Component{ID: healthID, Comp: &Health{Value: 5, max: 50}},
+		ecs.Component{ID: enemyID, Comp: &Enemy{}},
+	)
+	e2 := w.NewEntityWith(
+		ecs.Component{ID: posID, Comp: &Position{X: 30, Y: 40}},
+		ecs.Component{ID: healthID, Comp: &Health{Value: 50, max: 50}},
+		ecs.Component{ID: enemyID, Comp: &Enemy{}},
+	)
+	rock := w.NewEntityWith(
+		ecs.Component{ID: bodyID, Comp: &Body{Pos: Position{X: 7}, Static: true}},
+	)
+	return w, []ecs.Entity{player, e1, e2, rock}
+}
+
+func TestQuery(t *testing.T) {
+	w, e := newWorld()
+
+	res, err := sqlq.Query(&w, "select Position.X, Health from entities where Has(Enemy)")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Position.X", "Health"}, res.Columns)
+	assert.Equal(t, []ecs.Entity{e[1], e[2]}, res.Entities)
+	assert.Equal(t, [][]any{{10.0, Health{5, 50}}, {30.0, Health{50, 50}}}, res.Rows)
+
+	res, err = sqlq.Query(&w, "SELECT hp.Value FROM entities WHERE Has(Enemy) AND hp.Value < 10")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[1]}, res.Entities)
+	assert.Equal(t, [][]any{{5}}, res.Rows)
+
+	res, err = sqlq.Query(&w, "select Health.max from entities where not Has(Enemy)")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[0], e[3]}, res.Entities)
+	assert.Equal(t, [][]any{{100}, {nil}}, res.Rows)
+
+	res, err = sqlq.Query(&w, "select Name from entities where Name.Name = 'player' or Body.Static = true")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[0], e[3]}, res.Entities)
+	assert.Equal(t, [][]any{{Name{"player"}}, {nil}}, res.Rows)
+
+	res, err = sqlq.Query(&w, "select Body.Pos.X, sqlq_test.Enemy from entities where (Body.Pos.X >= 7 and Body.Pos.X <= 7) or Position.X > 20")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[2], e[3]}, res.Entities)
+	assert.Equal(t, [][]any{{nil, Enemy{}}, {7.0, nil}}, res.Rows)
+
+	res, err = sqlq.Query(&w, "select Position from entities where Position.Y != 2 limit 1")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[1]}, res.Entities)
+	assert.False(t, w.IsLocked())
+
+	res, err = sqlq.Query(&w, "select Position.X from entities where Position.X > -5 limit 0")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(res.Rows))
+	assert.False(t, w.IsLocked())
+
+	res, err = sqlq.Query(&w, "select Health.Value from entities where Has(Enemy)")
+	assert.Nil(t, err)
+	assert.Equal(t, "Entity  Health.Value\n{2 0}   5\n{3 0}   50\n", res.String())
+}
+
+func TestCompile(t *testing.T) {
+	w, e := newWorld()
+	healthID := ecs.ComponentID[Health](&w)
+
+	stmt, err := sqlq.Compile(&w, "select Health.Value from entities where Health.Value < 10")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e[1]}, stmt.Run().Entities)
+
+	(*Health)(w.Get(e[2], healthID)).Value = 1
+	assert.Equal(t, []ecs.Entity{e[1], e[2]}, stmt.Run().Entities)
+
+	query := w.Query(ecs.All())
+	assert.Equal(t, 2, len(stmt.Run().Rows))
+	query.Close()
+}
+
+func TestCompileErrors(t *testing.T) {
+	w, _ := newWorld()
+
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"", 0, "expected 'select'"},
+		{"select from entities", 7, "expected column"},
+		{"select Foo from entities", 7, "unknown component 'Foo'"},
+		{"select Position.Z from entities", 7, "sqlq_test.Position has no field Z"},
+		{"select Position.X.Y from entities", 7, "float64 has no field Y"},
+		{"select Position entities", 16, "expected 'from'"},
+		{"select Position from world", 21, "expected 'entities'"},
+		{"select Position from entities where", 35, "expected condition"},
+		{"select Position from entities where Has(Foo)", 40, "unknown component 'Foo'"},
+		{"select Position from entities where Has Enemy", 40, "expected '('"},
+		{"select Position from entities where Has(Enemy", 45, "expected ')'"},
+		{"select Position from entities where (Has(Enemy)", 47, "expected ')'"},
+		{"select Position from entities where Position.X", 46, "expected comparison operator"},
+		{"select Position from entities where Position.X <", 48, "expected literal"},
+		{"select Position from entities where Position.X < 'a'", 49, "can't compare float64 with 'a'"},
+		{"select Position from entities where Name.Name < 1", 48, "can't compare string with '1'"},
+		{"select Position from entities where Body.Static < true", 48, "operator < is not supported for bool"},
+		{"select Position from entities where Position = true", 47, "can't compare sqlq_test.Position with a boolean"},
+		{"select Position from entities where Position.X < 1.2.3", 49, "invalid number '1.2.3'"},
+		{"select Position from entities limit x", 36, "expected non-negative integer limit"},
+		{"select Position from entities limit -1", 36, "expected non-negative integer limit"},
+		{"select Position from entities foo", 30, "unexpected 'foo'"},
+		{"select Position from entities where Name.Name = 'abc", 48, "unterminated string"},
+		{"select Position from entities where Position.X ! 1", 47, "unexpected '!'"},
+		{"select Position; from entities", 15, "unexpected ';'"},
+	}
+	for _, tt := range tests {
+		_, err := sqlq.Compile(&w, tt.query)
+		var qErr *sqlq.Error
+		if !assert.True(t, errors.As(err, &qErr), tt.query) {
+			continue
+		}
+		assert.Equal(t, tt.msg, qErr.Msg, tt.query)
+		assert.Equal(t, tt.pos, qErr.Pos, tt.query)
+	}
+
+	_, err := sqlq.Query(&w, "select Foo from entities")
+	assert.EqualError(t, err, "query 'select Foo from entities', position 7: unknown component 'Foo'")
+}
+
+type Unit struct {
+	Level int
+	Health
+}
+
+func TestQueryPromotedFields(t *testing.T) {
+	w := ecs.NewWorld()
+	unitID := ecs.ComponentID[Unit](&w)
+	e := w.NewEntityWith(ecs.Component{ID: unitID, Comp: &Unit{Level: 3, Health: Health{Value: 42, max: 50}}})
+
+	res, err := sqlq.Query(&w, "select Unit.Value, Unit.max, Unit.Health.Value from entities where Unit.Level = 3")
+	assert.Nil(t, err)
+	assert.Equal(t, []ecs.Entity{e}, res.Entities)
+	assert.Equal(t, [][]any{{42, 50, 42}}, res.Rows)
+}
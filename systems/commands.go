@@ -0,0 +1,67 @@
+package systems
+
+import "github.com/mlange-42/arche/ecs"
+
+// CommandSystem is a [System] that records structural changes into its own [ecs.CommandBuffer].
+//
+// The scheduler calls [CommandSystem.UpdateCommands] instead of [System.Update],
+// with a buffer owned by the scheduler for this system.
+// This allows systems to freely create and remove entities, or add and remove components,
+// during iteration of their own queries.
+//
+// The buffer is flushed after the system's update, or after its [Phase],
+// depending on the scheduler's [FlushMode], see [Scheduler.SetFlushMode].
+//
+// [ParallelSystem] instances don't get a command buffer.
+type CommandSystem interface {
+	System
+	// UpdateCommands updates the system, with the system's command buffer.
+	UpdateCommands(w *ecs.World, cmd *ecs.CommandBuffer)
+}
+
+// FlushMode determines when the command buffers of [CommandSystem] instances are flushed.
+type FlushMode uint8
+
+const (
+	// FlushAfterSystem flushes the command buffer of a system directly after its update.
+	FlushAfterSystem FlushMode = iota
+	// FlushAfterPhase flushes the command buffers of all systems of a [Phase] after the phase,
+	// in schedule order.
+	FlushAfterPhase
+)
+
+// SetFlushMode sets when the command buffers of [CommandSystem] instances are flushed.
+// The default is [FlushAfterSystem].
+func (s *Scheduler) SetFlushMode(mode FlushMode) {
+	s.flushMode = mode
+}
+
+// FlushMode returns the scheduler's [FlushMode].
+func (s *Scheduler) FlushMode() FlushMode {
+	return s.flushMode
+}
+
+// updateSystem updates the non-parallel system with the given index,
+// and flushes its command buffer if required.
+func (s *Scheduler) updateSystem(idx int) {
+	info := &s.info[idx]
+	if info.CmdSystem == nil {
+		s.systems[idx].Update(&s.World)
+		return
+	}
+	info.CmdSystem.UpdateCommands(&s.World, info.Buffer)
+	if s.flushMode == FlushAfterSystem {
+		info.Buffer.Flush()
+	}
+}
+
+// flushPhase flushes the command buffers of the systems with indices in [from, to),
+// and the world's command buffer.
+func (s *Scheduler) flushPhase(from, to int) {
+	for i := from; i < to; i++ {
+		if buf := s.info[i].Buffer; buf != nil {
+			buf.Flush()
+		}
+	}
+	s.World.Commands().Flush()
+}
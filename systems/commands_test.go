@@ -0,0 +1,63 @@
+package systems_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+// spawnSystem removes all entities with a position, and creates a new one for each, using its command buffer.
+type spawnSystem struct {
+	recordSystem
+	PosID ecs.ID
+}
+
+func (s *spawnSystem) UpdateCommands(w *ecs.World, cmd *ecs.CommandBuffer) {
+	query := w.Query(ecs.All(s.PosID))
+	for query.Next() {
+		cmd.RemoveEntity(query.Entity())
+		cmd.NewEntity(s.PosID)
+	}
+	*s.Log = append(*s.Log, "commands "+s.Name)
+}
+
+func TestSchedulerCommands(t *testing.T) {
+	for _, mode := range []systems.FlushMode{systems.FlushAfterSystem, systems.FlushAfterPhase} {
+		s := systems.New()
+		s.SetFlushMode(mode)
+		assert.Equal(t, mode, s.FlushMode())
+
+		posID := ecs.ComponentID[position](&s.World)
+		e := s.World.NewEntity(posID)
+		s.World.NewEntity(posID)
+
+		log := []string{}
+		alive := []bool{}
+		spawner := &spawnSystem{recordSystem: recordSystem{Name: "S", Log: &log}, PosID: posID}
+		counter := &callbackSystem{recordSystem: recordSystem{Name: "C", Log: &log}}
+		counter.OnUpdate = func(w *ecs.World) {
+			alive = append(alive, w.Alive(e))
+		}
+		s.AddSystem(spawner)
+		s.AddSystem(counter)
+
+		s.Initialize()
+		s.Update()
+		assert.Equal(t, []string{"init S", "init C", "commands S", "update C"}, log)
+		assert.Equal(t, []bool{mode == systems.FlushAfterPhase}, alive)
+		assert.False(t, s.World.Alive(e))
+		assert.Equal(t, 2, countAlive(&s.World))
+
+		s.Update()
+		assert.Equal(t, 2, countAlive(&s.World))
+	}
+}
+
+// countAlive counts the entities of a world, by iterating a query.
+func countAlive(w *ecs.World) int {
+	query := w.Query(ecs.All())
+	defer query.Close()
+	return query.Count()
+}
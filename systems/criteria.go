@@ -0,0 +1,81 @@
+package systems
+
+import "github.com/mlange-42/arche/ecs"
+
+// Criterion is a run criterion for a [System], see [Scheduler.RunIf].
+//
+// It is evaluated in each update step, directly before the system would run,
+// and the system is skipped if it returns false.
+type Criterion func(w *ecs.World) bool
+
+// RunIf sets run criteria for a system, replacing any previous criteria.
+// In each update step, the system runs only if all criteria are met.
+// Calling it without criteria removes all criteria of the system.
+//
+// This allows to encode e.g. game state machines (menu vs. gameplay) without checks inside every system.
+//
+// Example:
+//
+//	scheduler.RunIf(&MenuSystem{}, systems.InState(Menu))
+//	scheduler.RunIf(&SpawnSystem{}, systems.InState(Playing), systems.Every(10))
+//
+// Panics if the system is not in the scheduler.
+func (s *Scheduler) RunIf(sys System, criteria ...Criterion) {
+	idx := s.index(sys)
+	if idx < 0 {
+		panic("system is not in the scheduler")
+	}
+	s.info[idx].Criteria = append([]Criterion{}, criteria...)
+}
+
+// shouldRun reports whether all run criteria of the system with the given index are met.
+func (s *Scheduler) shouldRun(idx int) bool {
+	for _, c := range s.info[idx].Criteria {
+		if !c(&s.World) {
+			return false
+		}
+	}
+	return true
+}
+
+// Every returns a [Criterion] that is met every n ticks of the world, see [ecs.World.CurrentTick].
+// It is met in the first update step of a new world.
+//
+// Panics if n is not positive.
+func Every(n uint64) Criterion {
+	if n == 0 {
+		panic("interval must be > 0")
+	}
+	return func(w *ecs.World) bool {
+		return w.CurrentTick()%n == 0
+	}
+}
+
+// InState returns a [Criterion] that is met while the world's resource of type T equals the given value.
+// It is not met if there is no such resource.
+//
+// Example:
+//
+//	type GameState int
+//
+//	const (
+//		Menu GameState = iota
+//		Playing
+//	)
+//
+//	state := Menu
+//	ecs.AddResource(&scheduler.World, &state)
+//	scheduler.RunIf(&MenuSystem{}, systems.InState(Menu))
+func InState[T comparable](value T) Criterion {
+	return func(w *ecs.World) bool {
+		res, ok := w.Resources().Get(ecs.ResourceID[T](w)).(*T)
+		return ok && *res == value
+	}
+}
+
+// Not returns a [Criterion] that is met if the given criterion is not met.
+func Not(c Criterion) Criterion {
+	return func(w *ecs.World) bool {
+		return !c(w)
+	}
+}
@@ -0,0 +1,59 @@
+package systems_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type gameState int
+
+const (
+	menu gameState = iota
+	playing
+)
+
+func TestSchedulerRunIf(t *testing.T) {
+	log := []string{}
+	m := &recordSystem{Name: "M", Log: &log}
+	p := &recordSystem{Name: "P", Log: &log}
+	e := &recordSystem{Name: "E", Log: &log}
+
+	s := systems.New()
+	s.AddSystem(m)
+	s.AddSystem(p)
+	s.AddSystem(e)
+
+	assert.PanicsWithValue(t, "system is not in the scheduler", func() { s.RunIf(&recordSystem{}, systems.Every(2)) })
+	assert.PanicsWithValue(t, "interval must be > 0", func() { systems.Every(0) })
+
+	s.RunIf(m, systems.InState(menu))
+	s.RunIf(p, systems.InState(playing))
+	s.RunIf(e, systems.Every(2), systems.Not(systems.InState(menu)))
+
+	s.Initialize()
+	log = log[:0]
+	s.Update()
+	assert.Equal(t, []string{"update E"}, log)
+
+	state := menu
+	ecs.AddResource(&s.World, &state)
+
+	log = log[:0]
+	s.Update()
+	s.Update()
+	assert.Equal(t, []string{"update M", "update M"}, log)
+
+	state = playing
+	log = log[:0]
+	s.Update()
+	s.Update()
+	assert.Equal(t, []string{"update P", "update P", "update E"}, log)
+
+	s.RunIf(e)
+	log = log[:0]
+	s.Update()
+	assert.Equal(t, []string{"update P", "update E"}, log)
+}
@@ -0,0 +1,8 @@
+// Package systems provides a [System] interface and a [Scheduler] for running systems on a [github.com/mlange-42/arche/ecs.World].
+//
+// See the top level module [github.com/mlange-42/arche] for an overview.
+//
+// 🕮 Also read Arche's [User Guide]!
+//
+// [User Guide]: https://mlange-42.github.io/arche/
+package systems
@@ -0,0 +1,118 @@
+package systems
+
+import (
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// FixedStep is a resource for running a [Scheduler] with a fixed simulation timestep,
+// decoupled from the render rate. It is evaluated by [Scheduler.Advance].
+//
+// Each [Scheduler] adds a FixedStep to its world's resources,
+// so that drawing systems can access the interpolation factor [FixedStep.Alpha]
+// with [github.com/mlange-42/arche/ecs.GetResource].
+// See also [Interpolate] for keeping the previous state of components.
+type FixedStep struct {
+	Step     time.Duration // The fixed simulation timestep. Must be positive.
+	MaxSteps int           // Maximum number of steps per call to [Scheduler.Advance], to prevent a spiral of death. Zero for no limit.
+	Alpha    float64       // Interpolation factor between the previous and the current step, in [0, 1). Set by [Scheduler.Advance].
+	acc      time.Duration // Accumulated time not yet simulated.
+}
+
+// NewFixedStep creates a new [FixedStep] with the given timestep, and at most 5 steps per call to [Scheduler.Advance].
+func NewFixedStep(step time.Duration) *FixedStep {
+	return &FixedStep{Step: step, MaxSteps: 5}
+}
+
+// Accumulated returns the elapsed time not yet simulated.
+func (f *FixedStep) Accumulated() time.Duration {
+	return f.acc
+}
+
+// next returns the number of steps to run for the given elapsed time, and updates the interpolation factor.
+func (f *FixedStep) next(elapsed time.Duration, speed *Speed) int {
+	if f.Step <= 0 {
+		panic("invalid fixed timestep, must be > 0")
+	}
+	steps := speed.pending
+	speed.pending = 0
+	if !speed.Paused {
+		if speed.Scale < 0 {
+			panic("invalid time scale, must be >= 0")
+		}
+		f.acc += time.Duration(float64(elapsed) * speed.Scale)
+		timed := int(f.acc / f.Step)
+		if f.MaxSteps > 0 && timed > f.MaxSteps {
+			// Drop the time that can't be caught up with.
+			timed = f.MaxSteps
+			f.acc = f.Step * time.Duration(timed)
+		}
+		f.acc -= f.Step * time.Duration(timed)
+		steps += timed
+	}
+	f.Alpha = float64(f.acc) / float64(f.Step)
+	return steps
+}
+
+// Advance runs the update steps for the given elapsed real time, with the fixed timestep of the [FixedStep] resource.
+// Returns the number of steps run.
+//
+// Use this in render loops with a variable frame rate, calling it once per frame with the time since the last frame.
+// Remaining time is carried over to the next call, and determines [FixedStep.Alpha] for interpolation during drawing.
+// Elapsed time is scaled and paused by the [Speed] resource, and single steps requested with [Speed.Step] are run
+// in addition.
+//
+// The scheduler keeps its [FixedStep] also when the resource is removed from the world, e.g. by [ecs.World.Reset].
+// Get it with [Scheduler.FixedStep] in that case.
+//
+// Panics if the scheduler is not initialized, or if it is finalized.
+func (s *Scheduler) Advance(elapsed time.Duration) int {
+	if !s.initialized {
+		panic("scheduler is not initialized")
+	}
+	s.checkFinalized()
+	steps := s.fixed.next(elapsed, s.speed)
+	for i := 0; i < steps; i++ {
+		s.Update()
+	}
+	return steps
+}
+
+// FixedStep returns the scheduler's [FixedStep] controller.
+func (s *Scheduler) FixedStep() *FixedStep {
+	return s.fixed
+}
+
+// Previous is a component holding the value of component T at the previous update step.
+// See [Interpolate].
+type Previous[T any] struct {
+	Value T // Value of the component at the previous step.
+}
+
+// Interpolate marks component T as interpolatable.
+// Before each update step, the scheduler copies the value of T of all entities into their [Previous] component.
+// Entities with T, but without a Previous component, get one, with the current value.
+//
+// Together with [FixedStep.Alpha], drawing systems can interpolate between the previous and the current state.
+//
+// Example:
+//
+//	systems.Interpolate[Position](scheduler)
+//	// ... in a drawing system:
+//	alpha := ecs.GetResource[systems.FixedStep](world).Alpha
+//	x := prev.Value.X + (pos.X-prev.Value.X)*alpha
+func Interpolate[T any](s *Scheduler) {
+	compID := ecs.ComponentID[T](&s.World)
+	prevID := ecs.ComponentID[Previous[T]](&s.World)
+	missing := ecs.All(compID).Without(prevID)
+	filter := ecs.All(compID, prevID)
+
+	s.preUpdate = append(s.preUpdate, func(w *ecs.World) {
+		w.Batch().Add(&missing, prevID)
+		query := w.Query(filter)
+		for query.Next() {
+			(*Previous[T])(query.Get(prevID)).Value = *(*T)(query.Get(compID))
+		}
+	})
+}
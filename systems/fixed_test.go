@@ -0,0 +1,83 @@
+package systems_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/generic"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	X float64
+}
+
+func TestFixedStep(t *testing.T) {
+	s := systems.New()
+	fixed := ecs.GetResource[systems.FixedStep](&s.World)
+	assert.Equal(t, s.FixedStep(), fixed)
+	assert.Equal(t, time.Second/60, fixed.Step)
+
+	fixed.Step = 10 * time.Millisecond
+	fixed.MaxSteps = 0
+
+	assert.PanicsWithValue(t, "scheduler is not initialized", func() { s.Advance(time.Millisecond) })
+	s.Initialize()
+
+	assert.Equal(t, 0, s.Advance(5*time.Millisecond))
+	assert.InDelta(t, 0.5, fixed.Alpha, 1e-9)
+	assert.Equal(t, 5*time.Millisecond, fixed.Accumulated())
+
+	assert.Equal(t, 2, s.Advance(17*time.Millisecond))
+	assert.InDelta(t, 0.2, fixed.Alpha, 1e-9)
+	assert.Equal(t, uint64(2), s.Step())
+
+	speed := s.Speed()
+	speed.Scale = 2
+	assert.Equal(t, 2, s.Advance(9*time.Millisecond))
+	assert.InDelta(t, 0.0, fixed.Alpha, 1e-9)
+
+	speed.Paused = true
+	assert.Equal(t, 0, s.Advance(time.Second))
+	speed.Step(3)
+	assert.Equal(t, 3, s.Advance(time.Second))
+	assert.Equal(t, uint64(7), s.Step())
+
+	speed.Paused = false
+	speed.Scale = 1
+	fixed.MaxSteps = 5
+	assert.Equal(t, 5, s.Advance(time.Second+5*time.Millisecond))
+	assert.Equal(t, time.Duration(0), fixed.Accumulated())
+
+	fixed.Step = 0
+	assert.PanicsWithValue(t, "invalid fixed timestep, must be > 0", func() { s.Advance(time.Millisecond) })
+
+	s.Finalize()
+	assert.PanicsWithValue(t, "scheduler is already finalized", func() { s.Advance(time.Millisecond) })
+}
+
+func TestInterpolate(t *testing.T) {
+	s := systems.New()
+	systems.Interpolate[position](s)
+
+	posMap := generic.NewMap1[position](&s.World)
+	prevMap := generic.NewMap1[systems.Previous[position]](&s.World)
+
+	e := posMap.NewWith(&position{X: 1})
+
+	var seen []float64
+	cb := &callbackSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	cb.OnUpdate = func(w *ecs.World) {
+		pos := posMap.Get(e)
+		seen = append(seen, prevMap.Get(e).Value.X)
+		pos.X++
+	}
+	s.AddSystem(cb)
+	s.Run(3)
+
+	assert.Equal(t, []float64{1, 2, 3}, seen)
+	assert.Equal(t, 4.0, posMap.Get(e).X)
+	assert.Equal(t, 3.0, prevMap.Get(e).Value.X)
+}
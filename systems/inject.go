@@ -0,0 +1,91 @@
+package systems
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// injectResources sets the fields of a system that are tagged for resource injection.
+// Returns the IDs of the injected resources, split by read-only and write access.
+//
+// Resource fields are exported pointer fields with tag `arche:"resource"`.
+// Further options, separated by commas, are "read" for read-only access,
+// and "optional" for leaving the field nil if the resource is not present.
+//
+// Panics for invalid fields, and for missing resources that are not optional.
+func injectResources(w *ecs.World, sys System) (read []ecs.ResID, write []ecs.ResID) {
+	value := reflect.ValueOf(sys)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return nil, nil
+	}
+	value = value.Elem()
+	tp := value.Type()
+
+	var resTypes map[reflect.Type]ecs.ResID
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		tag, ok := field.Tag.Lookup("arche")
+		if !ok {
+			continue
+		}
+		isResource, readOnly, optional := false, false, false
+		for _, opt := range strings.Split(tag, ",") {
+			switch strings.TrimSpace(opt) {
+			case "resource":
+				isResource = true
+			case "read":
+				readOnly = true
+			case "optional":
+				optional = true
+			}
+		}
+		if !isResource {
+			continue
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("resource field %s of system %T must be exported", field.Name, sys))
+		}
+		if field.Type.Kind() != reflect.Pointer {
+			panic(fmt.Sprintf("resource field %s of system %T must be a pointer", field.Name, sys))
+		}
+
+		if resTypes == nil {
+			resTypes = map[reflect.Type]ecs.ResID{}
+			for _, id := range ecs.ResourceIDs(w) {
+				if t, ok := ecs.ResourceType(w, id); ok {
+					resTypes[t] = id
+				}
+			}
+		}
+		id, ok := resTypes[field.Type.Elem()]
+		if !ok || !w.Resources().Has(id) {
+			if optional {
+				value.Field(i).SetZero()
+				continue
+			}
+			panic(fmt.Sprintf("resource %v for field %s of system %T is not present", field.Type.Elem(), field.Name, sys))
+		}
+		value.Field(i).Set(reflect.ValueOf(w.Resources().Get(id)))
+		if readOnly {
+			read = append(read, id)
+		} else {
+			write = append(write, id)
+		}
+	}
+	return read, write
+}
+
+// initializeSystem injects resources into the system with the given index, and initializes it.
+// For a [ParallelSystem], injected resources are added to its [Access].
+func (s *Scheduler) initializeSystem(idx int) {
+	sys := s.systems[idx]
+	read, write := injectResources(&s.World, sys)
+	if access := s.info[idx].Access; access != nil {
+		access.ReadResources = append(access.ReadResources, read...)
+		access.WriteResources = append(access.WriteResources, write...)
+	}
+	sys.Initialize(&s.World)
+}
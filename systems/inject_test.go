@@ -0,0 +1,77 @@
+package systems_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type gameTime struct {
+	Tick int
+}
+
+type bounds struct {
+	Width float64
+}
+
+type overlay struct{}
+
+type injectedSystem struct {
+	recordSystem
+	Time     *gameTime `arche:"resource,read"`
+	Bounds   *bounds   `arche:"resource"`
+	Overlay  *overlay  `arche:"resource, optional"`
+	Position *position
+}
+
+type unexportedSystem struct {
+	recordSystem
+	time *gameTime `arche:"resource"`
+}
+
+type valueSystem struct {
+	recordSystem
+	Time gameTime `arche:"resource"`
+}
+
+type injectedParallelSystem struct {
+	parallelSystem
+	Time *gameTime `arche:"resource"`
+}
+
+func TestSchedulerInject(t *testing.T) {
+	s := systems.New()
+	timeID := ecs.AddResource(&s.World, &gameTime{Tick: 5})
+	ecs.AddResource(&s.World, &bounds{Width: 100})
+
+	sys := &injectedSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	s.AddSystem(sys)
+	assert.Nil(t, sys.Time)
+
+	s.Initialize()
+	assert.Equal(t, 5, sys.Time.Tick)
+	assert.Equal(t, 100.0, sys.Bounds.Width)
+	assert.Nil(t, sys.Overlay)
+	assert.Nil(t, sys.Position)
+
+	par := &injectedParallelSystem{parallelSystem: parallelSystem{recordSystem: recordSystem{Log: &[]string{}}}}
+	s.AddSystem(par)
+	assert.Equal(t, 5, par.Time.Tick)
+	access, ok := s.AccessOf(par)
+	assert.True(t, ok)
+	assert.Equal(t, systems.Access{WriteResources: []ecs.ResID{timeID}}, access)
+	_, ok = s.AccessOf(sys)
+	assert.False(t, ok)
+
+	assert.PanicsWithValue(t, "resource field time of system *systems_test.unexportedSystem must be exported",
+		func() { s.AddSystem(&unexportedSystem{recordSystem: recordSystem{Log: &[]string{}}}) })
+	assert.PanicsWithValue(t, "resource field Time of system *systems_test.valueSystem must be a pointer",
+		func() { s.AddSystem(&valueSystem{recordSystem: recordSystem{Log: &[]string{}}}) })
+
+	s2 := systems.New()
+	s2.AddSystem(&injectedSystem{recordSystem: recordSystem{Log: &[]string{}}})
+	assert.PanicsWithValue(t, "resource systems_test.gameTime for field Time of system *systems_test.injectedSystem is not present",
+		func() { s2.Initialize() })
+}
@@ -0,0 +1,135 @@
+package systems
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Access declares the components and resources a [ParallelSystem] reads and writes.
+//
+// Two systems conflict if one of them writes a component or resource the other one reads or writes.
+// IDs listed under both read and write are treated as written.
+type Access struct {
+	Read           []ecs.ID    // Components the system reads.
+	Write          []ecs.ID    // Components the system writes.
+	ReadResources  []ecs.ResID // Resources the system reads.
+	WriteResources []ecs.ResID // Resources the system writes.
+}
+
+// conflicts reports whether two accesses conflict.
+func (a *Access) conflicts(other *Access) bool {
+	return writesAny(a.Write, other.Read, other.Write) ||
+		writesAny(other.Write, a.Read, a.Write) ||
+		writesAny(a.WriteResources, other.ReadResources, other.WriteResources) ||
+		writesAny(other.WriteResources, a.ReadResources, a.WriteResources)
+}
+
+// writesAny reports whether any of the written IDs is contained in one of the other slices.
+func writesAny[T comparable](write []T, read []T, other []T) bool {
+	for _, id := range write {
+		for _, r := range read {
+			if id == r {
+				return true
+			}
+		}
+		for _, w := range other {
+			if id == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParallelSystem is a [System] that declares its data access,
+// so that the [Scheduler] can run it concurrently with other parallel systems.
+//
+// Consecutive parallel systems of the same [Phase] run concurrently as long as their [Access] does not conflict.
+// A conflicting system, or a plain [System], starts a new group after the previous group has finished.
+// Thus, systems with conflicting access still see each other's changes in schedule order.
+//
+// Parallel systems are updated through a read-only [ecs.View] of the world,
+// which does not allow structural changes. They must only modify the components and resources
+// they declare as written, and must not call methods of the [Scheduler] or of the world's command buffer.
+//
+// The scheduler calls [ParallelSystem.UpdateParallel] instead of [System.Update].
+// Update is not used by the scheduler, but can run the system on a world outside a scheduler,
+// typically by opening a view and calling UpdateParallel.
+type ParallelSystem interface {
+	System
+	// Access returns the components and resources the system accesses.
+	// Called once when the system is added to a [Scheduler].
+	Access(w *ecs.World) Access
+	// UpdateParallel updates the system through a read-only view of the world.
+	UpdateParallel(v *ecs.View)
+}
+
+// AccessOf returns the [Access] of a [ParallelSystem], including injected resources,
+// and whether the system is a parallel system in the scheduler.
+//
+// The slices of the returned access must not be modified.
+func (s *Scheduler) AccessOf(sys System) (Access, bool) {
+	idx := s.index(sys)
+	if idx < 0 || s.info[idx].Access == nil {
+		return Access{}, false
+	}
+	return *s.info[idx].Access, true
+}
+
+// fitsBatch reports whether a system with the given access can run concurrently with the systems of a batch.
+func (s *Scheduler) fitsBatch(batch []int, access *Access) bool {
+	for _, idx := range batch {
+		if s.info[idx].Access.conflicts(access) {
+			return false
+		}
+	}
+	return true
+}
+
+// runBatch runs a batch of parallel systems concurrently, through a view of the world.
+// Panics of the systems are re-raised after all systems have finished.
+func (s *Scheduler) runBatch(batch []int) {
+	if len(batch) == 0 {
+		return
+	}
+	view := s.World.View()
+	if len(batch) == 1 {
+		defer view.Close()
+		start := time.Now()
+		s.updateParallel(s.systems[batch[0]].(ParallelSystem), view)
+		s.info[batch[0]].record(time.Since(start))
+		return
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var recovered any
+	for _, idx := range batch {
+		sys := s.systems[idx].(ParallelSystem)
+		timing := &s.info[idx].systemTiming
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					if recovered == nil {
+						recovered = r
+					}
+					mu.Unlock()
+				}
+			}()
+			start := time.Now()
+			s.updateParallel(sys, view)
+			timing.record(time.Since(start))
+		}()
+	}
+	wg.Wait()
+	view.Close()
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
@@ -0,0 +1,97 @@
+package systems_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type velocity struct {
+	X float64
+}
+
+// parallelSystem runs a callback through a view.
+type parallelSystem struct {
+	recordSystem
+	Reads    []ecs.ID
+	Writes   []ecs.ID
+	OnUpdate func(v *ecs.View)
+}
+
+func (s *parallelSystem) Access(w *ecs.World) systems.Access {
+	return systems.Access{Read: s.Reads, Write: s.Writes}
+}
+
+func (s *parallelSystem) UpdateParallel(v *ecs.View) {
+	s.OnUpdate(v)
+}
+
+func TestSchedulerParallel(t *testing.T) {
+	s := systems.New()
+	posID := ecs.ComponentID[position](&s.World)
+	velID := ecs.ComponentID[velocity](&s.World)
+
+	// Both systems wait for each other, which only succeeds if they run concurrently.
+	barrier := make(chan struct{})
+	timeout := make(chan bool, 2)
+	wait := func(v *ecs.View) {
+		select {
+		case barrier <- struct{}{}:
+			timeout <- false
+		case <-barrier:
+			timeout <- false
+		case <-time.After(time.Second):
+			timeout <- true
+		}
+	}
+	a := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}, Writes: []ecs.ID{posID}, OnUpdate: wait}
+	b := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}, Writes: []ecs.ID{velID}, OnUpdate: wait}
+	s.AddSystem(a)
+	s.AddSystem(b)
+
+	s.Initialize()
+	s.Update()
+	assert.False(t, <-timeout)
+	assert.False(t, <-timeout)
+	assert.Equal(t, []string{"init "}, *a.Log)
+}
+
+func TestSchedulerParallelConflict(t *testing.T) {
+	s := systems.New()
+	posID := ecs.ComponentID[position](&s.World)
+	velID := ecs.ComponentID[velocity](&s.World)
+	e := s.World.NewEntity(posID, velID)
+
+	log := []string{}
+	write := &parallelSystem{recordSystem: recordSystem{Log: &log}, Writes: []ecs.ID{posID}}
+	write.OnUpdate = func(v *ecs.View) {
+		(*position)(v.Get(e, posID)).X++
+		log = append(log, "write")
+	}
+	read := &parallelSystem{recordSystem: recordSystem{Log: &log}, Reads: []ecs.ID{posID}}
+	read.OnUpdate = func(v *ecs.View) {
+		assert.Equal(t, 1.0, (*position)(v.Get(e, posID)).X)
+		log = append(log, "read")
+	}
+	plain := &callbackSystem{recordSystem: recordSystem{Log: &log}}
+	plain.OnUpdate = func(w *ecs.World) {
+		assert.False(t, w.IsLocked())
+	}
+	s.AddSystem(write)
+	s.AddSystem(read)
+	s.AddSystem(plain)
+
+	s.Initialize()
+	s.Update()
+	assert.Equal(t, []string{"init ", "init ", "init ", "write", "read", "update "}, log)
+
+	failing := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}, Writes: []ecs.ID{velID}}
+	failing.OnUpdate = func(v *ecs.View) { panic("test") }
+	s.RemoveSystem(read)
+	s.AddSystemBefore(failing, write)
+	assert.PanicsWithValue(t, "test", func() { s.Update() })
+	assert.False(t, s.World.IsLocked())
+}
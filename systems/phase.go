@@ -0,0 +1,92 @@
+package systems
+
+import "fmt"
+
+// Phase is a named stage of a [Scheduler]'s update step.
+//
+// Phases are run in order, and each phase runs its systems in schedule order.
+// Between phases, the world's command buffer from [github.com/mlange-42/arche/ecs.World.Commands] is flushed,
+// so that structural changes recorded in one phase are visible to all systems of the following phases.
+//
+// Each scheduler starts with the phases [PreUpdate], [Update], [PostUpdate] and [Render].
+// Custom phases can be inserted with [Scheduler.AddPhaseBefore] and [Scheduler.AddPhaseAfter].
+type Phase string
+
+// Default phases, in update order.
+const (
+	PreUpdate  Phase = "PreUpdate"  // Phase for input handling and preparation.
+	Update     Phase = "Update"     // Phase for the simulation logic. Default phase for [Scheduler.AddSystem].
+	PostUpdate Phase = "PostUpdate" // Phase for e.g. physics, collision handling and cleanup.
+	Render     Phase = "Render"     // Phase for systems that prepare or perform rendering.
+)
+
+// defaultPhases returns the phases of a new [Scheduler].
+func defaultPhases() []Phase {
+	return []Phase{PreUpdate, Update, PostUpdate, Render}
+}
+
+// AddPhaseBefore inserts a custom [Phase] directly before another phase.
+//
+// Panics if the phase is already in the scheduler, or if the other phase is not.
+func (s *Scheduler) AddPhaseBefore(phase Phase, before Phase) {
+	s.addPhase(phase, before, false)
+}
+
+// AddPhaseAfter inserts a custom [Phase] directly after another phase.
+//
+// Panics if the phase is already in the scheduler, or if the other phase is not.
+func (s *Scheduler) AddPhaseAfter(phase Phase, after Phase) {
+	s.addPhase(phase, after, true)
+}
+
+// Phases returns the scheduler's phases, in update order.
+//
+// The returned slice must not be modified.
+func (s *Scheduler) Phases() []Phase {
+	return s.phases
+}
+
+// AddSystemTo adds a [System] to the end of the given [Phase].
+//
+// Panics if the system is already added, if the phase is not in the scheduler,
+// or if the scheduler is finalized.
+func (s *Scheduler) AddSystemTo(phase Phase, sys System) {
+	s.phaseIndex(phase)
+	s.addSystem(sys, nil, true, phase)
+}
+
+// PhaseOf returns the [Phase] of a system, and whether the system is in the scheduler.
+func (s *Scheduler) PhaseOf(sys System) (Phase, bool) {
+	idx := s.index(sys)
+	if idx < 0 {
+		return "", false
+	}
+	return s.info[idx].Phase, true
+}
+
+// addPhase inserts a phase relative to another phase.
+func (s *Scheduler) addPhase(phase Phase, other Phase, after bool) {
+	for _, p := range s.phases {
+		if p == phase {
+			panic(fmt.Sprintf("phase %q is already in the scheduler", phase))
+		}
+	}
+	idx := s.phaseIndex(other)
+	if after {
+		idx++
+	}
+	s.phases = append(s.phases, "")
+	copy(s.phases[idx+1:], s.phases[idx:])
+	s.phases[idx] = phase
+}
+
+// phaseIndex returns the index of a phase.
+// Panics if the phase is not in the scheduler.
+func (s *Scheduler) phaseIndex(phase Phase) int {
+	for i, p := range s.phases {
+		if p == phase {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("phase %q is not in the scheduler", phase))
+}
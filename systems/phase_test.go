@@ -0,0 +1,67 @@
+package systems_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerPhases(t *testing.T) {
+	log := []string{}
+	a := &recordSystem{Name: "A", Log: &log}
+	b := &recordSystem{Name: "B", Log: &log}
+	c := &recordSystem{Name: "C", Log: &log}
+	d := &recordSystem{Name: "D", Log: &log}
+	e := &recordSystem{Name: "E", Log: &log}
+
+	s := systems.New()
+	assert.Equal(t, []systems.Phase{systems.PreUpdate, systems.Update, systems.PostUpdate, systems.Render}, s.Phases())
+
+	s.AddPhaseAfter("Physics", systems.Update)
+	s.AddPhaseBefore("Input", systems.PreUpdate)
+	assert.Equal(t, []systems.Phase{"Input", systems.PreUpdate, systems.Update, "Physics", systems.PostUpdate, systems.Render}, s.Phases())
+	assert.PanicsWithValue(t, `phase "Physics" is already in the scheduler`, func() { s.AddPhaseAfter("Physics", systems.Render) })
+	assert.PanicsWithValue(t, `phase "Audio" is not in the scheduler`, func() { s.AddPhaseAfter("X", "Audio") })
+	assert.PanicsWithValue(t, `phase "Audio" is not in the scheduler`, func() { s.AddSystemTo("Audio", a) })
+
+	s.AddSystemTo(systems.Render, a)
+	s.AddSystem(b)
+	s.AddSystemTo("Physics", c)
+	s.AddSystemTo("Input", d)
+	s.AddSystemBefore(e, a)
+	assert.Equal(t, []systems.System{d, b, c, e, a}, s.Systems())
+
+	phase, ok := s.PhaseOf(e)
+	assert.True(t, ok)
+	assert.Equal(t, systems.Render, phase)
+	_, ok = s.PhaseOf(&recordSystem{})
+	assert.False(t, ok)
+
+	s.Initialize()
+	log = log[:0]
+	s.Update()
+	assert.Equal(t, []string{"update D", "update B", "update C", "update E", "update A"}, log)
+}
+
+func TestSchedulerPhaseFlush(t *testing.T) {
+	s := systems.New()
+
+	counts := []int{}
+	spawner := &callbackSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	spawner.OnUpdate = func(w *ecs.World) {
+		w.Commands().NewEntity()
+		counts = append(counts, w.Stats().Entities.Used)
+	}
+	counter := &callbackSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	counter.OnUpdate = func(w *ecs.World) {
+		counts = append(counts, w.Stats().Entities.Used)
+	}
+	s.AddSystemTo(systems.PreUpdate, spawner)
+	s.AddSystemTo(systems.PostUpdate, counter)
+
+	s.Run(2)
+	assert.Equal(t, []int{0, 1, 1, 2}, counts)
+	assert.Equal(t, 0, s.World.Commands().Len())
+}
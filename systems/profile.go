@@ -0,0 +1,40 @@
+package systems
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// ProfileSystemLabel is the pprof label key for systems.
+//
+// If profiling is enabled for the scheduler's world via [ecs.Config.Profiling],
+// each system update runs with this label, with the system's type name as value,
+// and in a runtime/trace region of the same name.
+// Queries of the system carry both the system's label and [ecs.ProfileQueryLabel].
+const ProfileSystemLabel = "arche.system"
+
+// profileSystem updates a system that is not run in parallel, instrumented for profiling.
+func (s *Scheduler) profileSystem(idx int) {
+	s.World.Profile(ProfileSystemLabel, systemName(s.systems[idx]), func() {
+		s.updateSystem(idx)
+	})
+}
+
+// updateParallel updates a parallel system through a view, instrumented for profiling if enabled.
+// Safe to call concurrently, as the world's profiling context is only read.
+func (s *Scheduler) updateParallel(sys ParallelSystem, view *ecs.View) {
+	ctx, ok := s.World.ProfileContext()
+	if !ok {
+		sys.UpdateParallel(view)
+		return
+	}
+	name := systemName(sys)
+	pprof.Do(ctx, pprof.Labels(ProfileSystemLabel, name), func(ctx context.Context) {
+		trace.WithRegion(ctx, ProfileSystemLabel+" "+name, func() {
+			sys.UpdateParallel(view)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package systems_test
+
+import (
+	"runtime/pprof"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerProfiling(t *testing.T) {
+	s := systems.New(ecs.NewConfig().WithProfiling(true))
+	posID := ecs.ComponentID[position](&s.World)
+	s.World.NewEntity(posID)
+
+	labels := []string{}
+	a := &callbackSystem{recordSystem: recordSystem{Name: "A", Log: &[]string{}}}
+	a.OnUpdate = func(w *ecs.World) {
+		ctx, ok := w.ProfileContext()
+		assert.True(t, ok)
+		value, _ := pprof.Label(ctx, systems.ProfileSystemLabel)
+		labels = append(labels, value)
+
+		query := w.Query(ecs.All(posID))
+		ctx, _ = w.ProfileContext()
+		value, _ = pprof.Label(ctx, ecs.ProfileQueryLabel)
+		labels = append(labels, value)
+		query.Close()
+	}
+	b := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}, Reads: []ecs.ID{posID}}
+	b.OnUpdate = func(v *ecs.View) {}
+	c := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}, Reads: []ecs.ID{posID}}
+	c.OnUpdate = func(v *ecs.View) {}
+
+	s.AddSystem(a)
+	s.AddSystem(b)
+	s.AddSystem(c)
+	s.Initialize()
+	s.Update()
+
+	assert.Equal(t, []string{"callbackSystem", "systems_test.position"}, labels)
+	ctx, _ := s.World.ProfileContext()
+	_, ok := pprof.Label(ctx, systems.ProfileSystemLabel)
+	assert.False(t, ok)
+
+	st := s.SystemStats()
+	assert.Equal(t, 1, st[1].Runs)
+	assert.Equal(t, 1, st[2].Runs)
+}
@@ -0,0 +1,346 @@
+package systems
+
+import (
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+)
+
+// Scheduler owns an [ecs.World] and runs [System] instances on it.
+//
+// Systems are grouped into ordered phases, see [Phase] and [Scheduler.AddSystemTo].
+// Within a phase, systems are updated in the order they were added,
+// which can be controlled with [Scheduler.AddSystemBefore] and [Scheduler.AddSystemAfter].
+//
+// Systems added or removed during an update take effect after the current step, in the order of the calls.
+// Systems added after initialization are initialized immediately,
+// and removed systems are finalized.
+//
+// Systems that declare their data access run concurrently, see [ParallelSystem].
+//
+// For pausing, single-stepping and time-scaling, see [Scheduler.Frame] and [Speed].
+// For a fixed timestep decoupled from the render rate, see [Scheduler.Advance] and [FixedStep].
+//
+// Example:
+//
+//	scheduler := systems.New()
+//	scheduler.AddSystem(&InitializerSystem{Count: 100})
+//	scheduler.AddSystem(&PosUpdaterSystem{})
+//	scheduler.Run(100)
+type Scheduler struct {
+	World       ecs.World // The world the systems operate on.
+	speed       *Speed
+	fixed       *FixedStep
+	preUpdate   []func(w *ecs.World)
+	phases      []Phase
+	systems     []System
+	info        []systemInfo
+	batch       []int
+	flushMode   FlushMode
+	pending     []pendingOp
+	step        uint64
+	initialized bool
+	finalized   bool
+	updating    bool
+}
+
+// systemInfo holds the phase, the data access, the run criteria, the command buffer and the timing of a scheduled system.
+type systemInfo struct {
+	systemTiming
+	Phase     Phase
+	Access    *Access
+	Criteria  []Criterion
+	CmdSystem CommandSystem
+	Buffer    *ecs.CommandBuffer
+}
+
+// systemTiming holds timing statistics of a system.
+type systemTiming struct {
+	Runs  int
+	Last  time.Duration
+	Total time.Duration
+	Max   time.Duration
+}
+
+// pendingOp is a system addition or removal during an update.
+type pendingOp struct {
+	System System
+	Other  System
+	After  bool
+	Remove bool
+	Phase  Phase
+}
+
+// New creates a new [Scheduler] with a new [ecs.World], created from an optional [ecs.Config].
+//
+// Adds a [Speed] resource to the world, see [Scheduler.Frame],
+// and a [FixedStep] resource with a timestep of 1/60 second, see [Scheduler.Advance].
+// Installs an [ecs.StatsExtension] that adds timing statistics of the systems to [ecs.World.Stats],
+// see [Scheduler.SystemStats].
+func New(config ...ecs.Config) *Scheduler {
+	s := &Scheduler{
+		World:  ecs.NewWorld(config...),
+		speed:  NewSpeed(),
+		fixed:  NewFixedStep(time.Second / 60),
+		phases: defaultPhases(),
+	}
+	ecs.AddResource(&s.World, s.speed)
+	ecs.AddResource(&s.World, s.fixed)
+	if err := s.World.Use(&statsExtension{scheduler: s}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// AddSystem adds a [System] to the end of the [Update] phase.
+// See [Scheduler.AddSystemTo] for adding systems to other phases.
+//
+// Panics if the system is already added, or if the scheduler is finalized.
+func (s *Scheduler) AddSystem(sys System) {
+	s.addSystem(sys, nil, true, Update)
+}
+
+// AddSystemBefore adds a [System] directly before another system, in the same [Phase].
+//
+// Panics if the system is already added, if the other system is not in the scheduler,
+// or if the scheduler is finalized.
+func (s *Scheduler) AddSystemBefore(sys System, before System) {
+	s.addSystem(sys, before, false, "")
+}
+
+// AddSystemAfter adds a [System] directly after another system, in the same [Phase].
+//
+// Panics if the system is already added, if the other system is not in the scheduler,
+// or if the scheduler is finalized.
+func (s *Scheduler) AddSystemAfter(sys System, after System) {
+	s.addSystem(sys, after, true, "")
+}
+
+// RemoveSystem removes a [System] from the scheduler, and finalizes it if the scheduler is initialized.
+//
+// Panics if the system is not in the scheduler.
+func (s *Scheduler) RemoveSystem(sys System) {
+	if s.updating {
+		s.pending = append(s.pending, pendingOp{System: sys, Remove: true})
+		return
+	}
+	s.removeSystem(sys)
+}
+
+// Systems returns the scheduled systems of all phases, in update order.
+//
+// The returned slice must not be modified.
+func (s *Scheduler) Systems() []System {
+	return s.systems
+}
+
+// Step returns the number of completed update steps.
+func (s *Scheduler) Step() uint64 {
+	return s.step
+}
+
+// Initialize all systems, in schedule order.
+// Before initializing a system, injects resources into its tagged fields, see [System].
+//
+// Panics if the scheduler is already initialized.
+func (s *Scheduler) Initialize() {
+	if s.initialized {
+		panic("scheduler is already initialized")
+	}
+	s.initialized = true
+	for i := range s.systems {
+		s.initializeSystem(i)
+	}
+}
+
+// Update all systems once, in schedule order, and advance the world's tick with [ecs.World.Tick].
+// Before the systems, stores the previous state of components marked with [Interpolate].
+// After each [Phase], flushes the world's command buffer from [ecs.World.Commands].
+// The command buffers of [CommandSystem] instances are flushed according to the [FlushMode].
+// Consecutive [ParallelSystem] instances with non-conflicting [Access] run concurrently.
+// Systems with unmet run criteria are skipped, see [Scheduler.RunIf].
+//
+// Panics if the scheduler is not initialized, or if it is finalized.
+func (s *Scheduler) Update() {
+	if !s.initialized {
+		panic("scheduler is not initialized")
+	}
+	s.checkFinalized()
+
+	for _, fn := range s.preUpdate {
+		fn(&s.World)
+	}
+
+	s.updating = true
+	batch := s.batch[:0]
+	phaseStart := 0
+	_, profiling := s.World.ProfileContext()
+	for i := range s.systems {
+		info := &s.info[i]
+		if s.shouldRun(i) {
+			if info.Access == nil || !s.fitsBatch(batch, info.Access) {
+				s.runBatch(batch)
+				batch = batch[:0]
+			}
+			if info.Access == nil {
+				start := time.Now()
+				if profiling {
+					s.profileSystem(i)
+				} else {
+					s.updateSystem(i)
+				}
+				info.record(time.Since(start))
+			} else {
+				batch = append(batch, i)
+			}
+		}
+		if i == len(s.systems)-1 || s.info[i+1].Phase != info.Phase {
+			s.runBatch(batch)
+			batch = batch[:0]
+			s.flushPhase(phaseStart, i+1)
+			phaseStart = i + 1
+		}
+	}
+	s.batch = batch
+	s.updating = false
+
+	s.World.Tick()
+	s.step++
+
+	for _, op := range s.pending {
+		if op.Remove {
+			s.removeSystem(op.System)
+		} else {
+			s.addSystem(op.System, op.Other, op.After, op.Phase)
+		}
+	}
+	s.pending = s.pending[:0]
+}
+
+// Frame runs the number of update steps for one frame, as determined by the [Speed] resource.
+// Returns the number of steps run.
+//
+// Use this in interactive applications and debug UIs, where each rendered frame calls Frame.
+// When paused, only single steps requested with [Speed.Step] are run.
+//
+// The scheduler keeps its [Speed] also when the resource is removed from the world, e.g. by [ecs.World.Reset].
+// Get it with [Scheduler.Speed] in that case.
+//
+// Panics if the scheduler is not initialized, or if it is finalized.
+func (s *Scheduler) Frame() int {
+	if !s.initialized {
+		panic("scheduler is not initialized")
+	}
+	s.checkFinalized()
+	steps := s.speed.next()
+	for i := 0; i < steps; i++ {
+		s.Update()
+	}
+	return steps
+}
+
+// Speed returns the scheduler's [Speed] controller.
+func (s *Scheduler) Speed() *Speed {
+	return s.speed
+}
+
+// Finalize all systems, in schedule order.
+//
+// Panics if the scheduler is not initialized, or if it is already finalized.
+func (s *Scheduler) Finalize() {
+	if !s.initialized {
+		panic("scheduler is not initialized")
+	}
+	s.checkFinalized()
+	s.finalized = true
+	for _, sys := range s.systems {
+		sys.Finalize(&s.World)
+	}
+}
+
+// Run initializes the systems if required, runs the given number of update steps, and finalizes the systems.
+func (s *Scheduler) Run(steps int) {
+	if !s.initialized {
+		s.Initialize()
+	}
+	for i := 0; i < steps; i++ {
+		s.Update()
+	}
+	s.Finalize()
+}
+
+// addSystem inserts a system relative to another system, or at the end of the given phase if other is nil.
+func (s *Scheduler) addSystem(sys System, other System, after bool, phase Phase) {
+	s.checkFinalized()
+	if s.updating {
+		s.pending = append(s.pending, pendingOp{System: sys, Other: other, After: after, Phase: phase})
+		return
+	}
+	if s.index(sys) >= 0 {
+		panic("system is already added to the scheduler")
+	}
+
+	var idx int
+	if other != nil {
+		idx = s.index(other)
+		if idx < 0 {
+			panic("reference system is not in the scheduler")
+		}
+		phase = s.info[idx].Phase
+		if after {
+			idx++
+		}
+	} else {
+		phaseIdx := s.phaseIndex(phase)
+		for idx < len(s.systems) && s.phaseIndex(s.info[idx].Phase) <= phaseIdx {
+			idx++
+		}
+	}
+	s.systems = append(s.systems, nil)
+	copy(s.systems[idx+1:], s.systems[idx:])
+	s.systems[idx] = sys
+	s.info = append(s.info, systemInfo{})
+	copy(s.info[idx+1:], s.info[idx:])
+	s.info[idx] = systemInfo{Phase: phase}
+	if par, ok := sys.(ParallelSystem); ok {
+		access := par.Access(&s.World)
+		s.info[idx].Access = &access
+	} else if cmd, ok := sys.(CommandSystem); ok {
+		s.info[idx].CmdSystem = cmd
+		s.info[idx].Buffer = ecs.NewCommandBuffer(&s.World)
+	}
+
+	if s.initialized {
+		s.initializeSystem(idx)
+	}
+}
+
+// removeSystem removes a system, and finalizes it if the scheduler is initialized.
+func (s *Scheduler) removeSystem(sys System) {
+	idx := s.index(sys)
+	if idx < 0 {
+		panic("system is not in the scheduler")
+	}
+	s.systems = append(s.systems[:idx], s.systems[idx+1:]...)
+	s.info = append(s.info[:idx], s.info[idx+1:]...)
+	if s.initialized && !s.finalized {
+		sys.Finalize(&s.World)
+	}
+}
+
+// index returns the index of a system, or -1 if it is not in the scheduler.
+func (s *Scheduler) index(sys System) int {
+	for i, other := range s.systems {
+		if other == sys {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkFinalized panics if the scheduler is finalized.
+func (s *Scheduler) checkFinalized() {
+	if s.finalized {
+		panic("scheduler is already finalized")
+	}
+}
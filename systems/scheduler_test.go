@@ -0,0 +1,117 @@
+package systems_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordSystem struct {
+	Name string
+	Log  *[]string
+}
+
+func (s *recordSystem) Initialize(w *ecs.World) { *s.Log = append(*s.Log, "init "+s.Name) }
+func (s *recordSystem) Update(w *ecs.World)     { *s.Log = append(*s.Log, "update "+s.Name) }
+func (s *recordSystem) Finalize(w *ecs.World)   { *s.Log = append(*s.Log, "final "+s.Name) }
+
+type callbackSystem struct {
+	recordSystem
+	OnUpdate func(w *ecs.World)
+}
+
+func (s *callbackSystem) Update(w *ecs.World) {
+	s.recordSystem.Update(w)
+	s.OnUpdate(w)
+}
+
+func TestScheduler(t *testing.T) {
+	log := []string{}
+	a := &recordSystem{Name: "A", Log: &log}
+	b := &recordSystem{Name: "B", Log: &log}
+	c := &recordSystem{Name: "C", Log: &log}
+	d := &recordSystem{Name: "D", Log: &log}
+
+	s := systems.New()
+	s.AddSystem(a)
+	s.AddSystem(b)
+	s.AddSystemBefore(c, b)
+	assert.Equal(t, []systems.System{a, c, b}, s.Systems())
+
+	s.Run(2)
+	assert.Equal(t, []string{
+		"init A", "init C", "init B",
+		"update A", "update C", "update B",
+		"update A", "update C", "update B",
+		"final A", "final C", "final B",
+	}, log)
+	assert.Equal(t, uint64(2), s.Step())
+	assert.Equal(t, uint64(2), s.World.CurrentTick())
+
+	assert.PanicsWithValue(t, "scheduler is already finalized", func() { s.Update() })
+	assert.PanicsWithValue(t, "scheduler is already finalized", func() { s.AddSystem(d) })
+	assert.PanicsWithValue(t, "scheduler is already finalized", func() { s.Finalize() })
+	assert.PanicsWithValue(t, "scheduler is already initialized", func() { s.Initialize() })
+}
+
+func TestSchedulerAddRemove(t *testing.T) {
+	log := []string{}
+	a := &recordSystem{Name: "A", Log: &log}
+	b := &recordSystem{Name: "B", Log: &log}
+	c := &recordSystem{Name: "C", Log: &log}
+
+	s := systems.New()
+	assert.PanicsWithValue(t, "scheduler is not initialized", func() { s.Update() })
+	assert.PanicsWithValue(t, "scheduler is not initialized", func() { s.Finalize() })
+
+	s.AddSystem(a)
+	assert.PanicsWithValue(t, "system is already added to the scheduler", func() { s.AddSystem(a) })
+	assert.PanicsWithValue(t, "reference system is not in the scheduler", func() { s.AddSystemAfter(b, c) })
+	assert.PanicsWithValue(t, "system is not in the scheduler", func() { s.RemoveSystem(b) })
+
+	s.Initialize()
+	s.AddSystemAfter(b, a)
+	assert.Equal(t, []string{"init A", "init B"}, log)
+
+	s.RemoveSystem(a)
+	assert.Equal(t, []string{"init A", "init B", "final A"}, log)
+	assert.Equal(t, []systems.System{b}, s.Systems())
+
+	log = log[:0]
+	cb := &callbackSystem{recordSystem: recordSystem{Name: "CB", Log: &log}}
+	cb.OnUpdate = func(w *ecs.World) {
+		s.AddSystemBefore(c, cb)
+		s.RemoveSystem(b)
+		s.RemoveSystem(cb)
+	}
+	s.AddSystem(cb)
+	s.Update()
+	assert.Equal(t, []string{"init CB", "update B", "update CB", "init C", "final B", "final CB"}, log)
+	assert.Equal(t, []systems.System{c}, s.Systems())
+}
+
+func TestSchedulerWorld(t *testing.T) {
+	s := systems.New(ecs.NewConfig().WithCapacityIncrement(32))
+
+	count := 0
+	cb := &callbackSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	cb.OnUpdate = func(w *ecs.World) {
+		w.NewEntity()
+		count = w.Stats().Entities.Used
+	}
+	s.AddSystem(cb)
+	s.Run(10)
+	assert.Equal(t, 10, count)
+}
+
+func ExampleScheduler() {
+	log := []string{}
+	s := systems.New()
+	s.AddSystem(&recordSystem{Name: "A", Log: &log})
+	s.Run(1)
+	fmt.Println(log)
+	// Output: [init A update A final A]
+}
@@ -0,0 +1,53 @@
+package systems
+
+// Speed is a resource for controlling the simulation speed of a [Scheduler].
+//
+// Each [Scheduler] adds a Speed to its world's resources,
+// so that systems and debug UIs can access it with [github.com/mlange-42/arche/ecs.GetResource].
+// It is evaluated by [Scheduler.Frame], while [Scheduler.Update] and [Scheduler.Run] ignore it.
+//
+// The time scale is given in update steps per frame.
+// Fractional scales are accumulated over frames, so that e.g. a scale of 0.25
+// results in one step every fourth frame. This makes the speed control fully deterministic.
+type Speed struct {
+	Paused  bool    // Whether the simulation is paused.
+	Scale   float64 // Time scale, in update steps per frame. Must not be negative.
+	pending int     // Requested single steps.
+	acc     float64 // Accumulated fractional steps.
+}
+
+// NewSpeed creates a new [Speed] with a time scale of 1 step per frame, not paused.
+func NewSpeed() *Speed {
+	return &Speed{Scale: 1}
+}
+
+// Step requests the given number of single update steps, to be run on the next [Scheduler.Frame].
+// Steps requested are run regardless of pausing.
+// This allows to advance a paused simulation step by step.
+func (s *Speed) Step(steps int) {
+	if steps < 0 {
+		panic("number of steps must be >= 0")
+	}
+	s.pending += steps
+}
+
+// Pending returns the number of requested single steps not yet run.
+func (s *Speed) Pending() int {
+	return s.pending
+}
+
+// next returns the number of steps to run for the next frame.
+func (s *Speed) next() int {
+	steps := s.pending
+	s.pending = 0
+	if s.Paused {
+		return steps
+	}
+	if s.Scale < 0 {
+		panic("invalid time scale, must be >= 0")
+	}
+	s.acc += s.Scale
+	scaled := int(s.acc)
+	s.acc -= float64(scaled)
+	return steps + scaled
+}
@@ -0,0 +1,53 @@
+package systems_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeed(t *testing.T) {
+	log := []string{}
+	s := systems.New()
+	s.AddSystem(&recordSystem{Name: "A", Log: &log})
+
+	speed := ecs.GetResource[systems.Speed](&s.World)
+	assert.Equal(t, s.Speed(), speed)
+	assert.PanicsWithValue(t, "scheduler is not initialized", func() { s.Frame() })
+
+	s.Initialize()
+	assert.Equal(t, 1, s.Frame())
+	assert.Equal(t, uint64(1), s.Step())
+
+	speed.Scale = 2.5
+	assert.Equal(t, 2, s.Frame())
+	assert.Equal(t, 3, s.Frame())
+	assert.Equal(t, uint64(6), s.Step())
+
+	speed.Paused = true
+	assert.Equal(t, 0, s.Frame())
+	speed.Step(2)
+	assert.Equal(t, 2, speed.Pending())
+	assert.Equal(t, 2, s.Frame())
+	assert.Equal(t, 0, speed.Pending())
+	assert.Equal(t, 0, s.Frame())
+	assert.Equal(t, uint64(8), s.Step())
+	assert.Equal(t, uint64(8), s.World.CurrentTick())
+
+	speed.Paused = false
+	speed.Scale = 0.25
+	steps := 0
+	for i := 0; i < 8; i++ {
+		steps += s.Frame()
+	}
+	assert.Equal(t, 2, steps)
+
+	speed.Scale = -1
+	assert.PanicsWithValue(t, "invalid time scale, must be >= 0", func() { s.Frame() })
+	assert.PanicsWithValue(t, "number of steps must be >= 0", func() { speed.Step(-1) })
+
+	s.Finalize()
+	assert.PanicsWithValue(t, "scheduler is already finalized", func() { s.Frame() })
+}
@@ -0,0 +1,65 @@
+package systems
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/ecs/stats"
+)
+
+// statsExtension adds the timing statistics of a scheduler's systems to [ecs.World.Stats].
+type statsExtension struct {
+	scheduler *Scheduler
+}
+
+// Install the extension. Does nothing.
+func (e *statsExtension) Install(w *ecs.World) error {
+	return nil
+}
+
+// Stats adds the timing statistics of the scheduler's systems, in update order.
+func (e *statsExtension) Stats(s *stats.World) {
+	s.Systems = append(s.Systems, e.scheduler.SystemStats()...)
+}
+
+// SystemStats returns timing statistics of all systems, in update order.
+// Systems that were removed are not included.
+//
+// The statistics are also included in [ecs.World.Stats] of the scheduler's world.
+func (s *Scheduler) SystemStats() []stats.System {
+	result := make([]stats.System, len(s.systems))
+	for i, sys := range s.systems {
+		info := &s.info[i]
+		result[i] = stats.System{
+			Name:  systemName(sys),
+			Phase: string(info.Phase),
+			Runs:  info.Runs,
+			Last:  info.Last,
+			Max:   info.Max,
+		}
+		if info.Runs > 0 {
+			result[i].Avg = info.Total / time.Duration(info.Runs)
+		}
+	}
+	return result
+}
+
+// record records the duration of a system update.
+func (t *systemTiming) record(d time.Duration) {
+	t.Runs++
+	t.Last = d
+	t.Total += d
+	if d > t.Max {
+		t.Max = d
+	}
+}
+
+// systemName returns the name of a system's type, without pointer indirection.
+func systemName(sys System) string {
+	tp := reflect.TypeOf(sys)
+	for tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+	}
+	return tp.Name()
+}
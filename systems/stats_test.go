@@ -0,0 +1,44 @@
+package systems_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/arche/ecs"
+	"github.com/mlange-42/arche/systems"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerStats(t *testing.T) {
+	s := systems.New()
+	a := &recordSystem{Name: "A", Log: &[]string{}}
+	b := &parallelSystem{recordSystem: recordSystem{Log: &[]string{}}}
+	b.OnUpdate = func(v *ecs.View) {}
+	s.AddSystem(a)
+	s.AddSystemTo(systems.Render, b)
+
+	st := s.SystemStats()
+	assert.Equal(t, 2, len(st))
+	assert.Equal(t, "recordSystem", st[0].Name)
+	assert.Equal(t, "Update", st[0].Phase)
+	assert.Equal(t, 0, st[0].Runs)
+
+	s.Initialize()
+	for i := 0; i < 3; i++ {
+		s.Update()
+	}
+	st = s.SystemStats()
+	assert.Equal(t, 3, st[0].Runs)
+	assert.Equal(t, "parallelSystem", st[1].Name)
+	assert.Equal(t, "Render", st[1].Phase)
+	assert.Equal(t, 3, st[1].Runs)
+	assert.GreaterOrEqual(t, st[1].Max, st[1].Last)
+	assert.GreaterOrEqual(t, st[1].Max, st[1].Avg)
+
+	worldStats := s.World.Stats()
+	assert.Equal(t, st, worldStats.Systems)
+	assert.True(t, strings.Contains(worldStats.String(), "System -- Name: recordSystem, Phase: Update, Runs: 3"))
+
+	s.RemoveSystem(a)
+	assert.Equal(t, 1, len(s.World.Stats().Systems))
+}
@@ -0,0 +1,34 @@
+package systems
+
+import "github.com/mlange-42/arche/ecs"
+
+// System is the interface for ECS systems.
+//
+// See [Scheduler] for running systems.
+// Systems are identified by equality, so implementations should be pointer types.
+//
+// # Resource injection
+//
+// Before a [Scheduler] initializes a system, it sets exported pointer fields
+// with tag `arche:"resource"` to the world's resource of the field's type.
+// This avoids calls to [ecs.GetResource] in every system.
+// Option "read" declares read-only access, and option "optional" leaves the field nil if there is no such resource.
+// For a [ParallelSystem], injected resources are added to its [Access].
+//
+// Example:
+//
+//	type MoveSystem struct {
+//		Time   *Time    `arche:"resource,read"`
+//		Bounds *Bounds  `arche:"resource"`
+//		Debug  *Overlay `arche:"resource,optional"`
+//	}
+//
+// The scheduler panics if a required resource is not present.
+type System interface {
+	// Initialize the system. Called once, before the first update.
+	Initialize(w *ecs.World)
+	// Update the system. Called once per step.
+	Update(w *ecs.World)
+	// Finalize the system. Called once, after the last update, or when the system is removed.
+	Finalize(w *ecs.World)
+}